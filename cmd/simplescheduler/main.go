@@ -21,10 +21,16 @@ package main
 import (
 	"flag"
 	"os"
+
+	"github.com/apache/yunikorn-core/pkg/common"
 )
 
 var (
-	endpoint = flag.String("endpoint", "tcp://localhost:3333", "YuniKorn endpoint")
+	endpoint    = flag.String("endpoint", "tcp://localhost:3333", "YuniKorn endpoint")
+	tlsCert     = flag.String("tls-cert", "", "TLS certificate file, enables TLS together with the key")
+	tlsKey      = flag.String("tls-key", "", "TLS private key file")
+	tlsClientCA = flag.String("tls-client-ca", "", "CA file to verify client certificates")
+	tlsRequired = flag.Bool("tls-required", false, "refuse to start without TLS configured")
 )
 
 func main() {
@@ -35,5 +41,9 @@ func main() {
 
 func handle() {
 	scheduler := &SimpleScheduler{}
-	scheduler.Run(*endpoint)
+	scheduler.Run(*endpoint, common.TLSFiles{
+		CertFile:     *tlsCert,
+		KeyFile:      *tlsKey,
+		ClientCAFile: *tlsClientCA,
+	}, *tlsRequired)
 }
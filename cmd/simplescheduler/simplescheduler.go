@@ -25,17 +25,43 @@ import (
 	"time"
 
 	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
+const grpcTLSCallbackID = "grpc-tls"
+
 type SimpleScheduler struct {
 	si.UnimplementedSchedulerServer
 }
 
-func (scheduler *SimpleScheduler) Run(endpoint string) {
+// Run starts the gRPC server. The TLS files from the command line take precedence over the ConfigMap.
+// The transport is decided before the server starts: a server that requires TLS never starts without it.
+func (scheduler *SimpleScheduler) Run(endpoint string, tlsFiles common.TLSFiles, tlsRequired bool) {
+	fromConfigMap := false
+	if !tlsFiles.Enabled() {
+		tlsFiles = configs.GetGRPCTLSFiles()
+		fromConfigMap = tlsFiles.Enabled()
+	}
+	if !tlsFiles.Enabled() {
+		if tlsRequired {
+			log.Fatalf("TLS required but no certificate and key configured")
+		}
+		log.Printf("TLS not configured, serving plaintext")
+	}
 	// Create gRPC servers
 	ss := newSchedulerServer()
 	s := common.NewNonBlockingGRPCServer()
+	if tlsFiles.Enabled() {
+		s = common.NewNonBlockingGRPCServerWithTLS(tlsFiles)
+	}
+	if fromConfigMap {
+		// certificates configured in the ConfigMap are reloaded on change
+		configs.AddConfigMapCallback(grpcTLSCallbackID, func() {
+			s.UpdateTLSFiles(configs.GetGRPCTLSFiles())
+		})
+		defer configs.RemoveConfigMapCallback(grpcTLSCallbackID)
+	}
 	s.Start(endpoint, ss)
 	s.Wait()
 }
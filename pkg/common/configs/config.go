@@ -57,7 +57,7 @@ type SchedulerConfig struct {
 // - a list of placement rule definition objects
 // - a list of users specifying limits on the partition
 // - the preemption configuration for the partition
-// - user group resolver type (os, ldap, "")
+// - user group resolver type (os, ldap, file, "")
 type PartitionConfig struct {
 	Name              string
 	Queues            []QueueConfig
@@ -68,8 +68,10 @@ type PartitionConfig struct {
 	UserGroupResolver UserGroupResolver         `yaml:",omitempty" json:",omitempty"`
 }
 
+// UserGroupResolver defines the type of resolver and the mapping file used by the file resolver
 type UserGroupResolver struct {
 	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
 }
 
 // PartitionPreemptionConfig defines global flags for both preemption types
@@ -2280,3 +2280,48 @@ partitions:
 	// check if the user group resolver is set to empty
 	assert.Equal(t, "", config.Partitions[0].UserGroupResolver.Type)
 }
+
+// TestUserGroupResolverFileConfig: tests the file user group resolver configuration
+func TestUserGroupResolverFileConfig(t *testing.T) {
+	data := `
+partitions:
+  -
+    name: default
+    usergroupresolver:
+      type: file
+      file: /etc/yunikorn/groups.yaml
+    queues:
+      - name: root
+`
+	config, err := CreateConfig(data)
+	assert.NilError(t, err)
+	assert.Equal(t, "file", config.Partitions[0].UserGroupResolver.Type)
+	assert.Equal(t, "/etc/yunikorn/groups.yaml", config.Partitions[0].UserGroupResolver.File)
+
+	// file resolver without a file
+	data = `
+partitions:
+  -
+    name: default
+    usergroupresolver:
+      type: file
+    queues:
+      - name: root
+`
+	_, err = CreateConfig(data)
+	assert.ErrorContains(t, err, "user group resolver type file requires a mapping file")
+
+	// file set for a different resolver
+	data = `
+partitions:
+  -
+    name: default
+    usergroupresolver:
+      type: os
+      file: /etc/group
+    queues:
+      - name: root
+`
+	_, err = CreateConfig(data)
+	assert.ErrorContains(t, err, "mapping file /etc/group is only supported by user group resolver type file")
+}
@@ -21,17 +21,30 @@ package configs
 import (
 	"time"
 
+	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
 )
 
 const (
 	// prefixes
-	PrefixEvent  = "event."
-	PrefixHealth = "health."
+	PrefixEvent      = "event."
+	PrefixHealth     = "health."
+	PrefixREST       = "rest."
+	PrefixGRPC       = "grpc."
+	PrefixTrace      = "tracing."
+	PrefixMetric     = "metrics."
+	PrefixSummary    = "appSummary."
+	PrefixOverload   = "overload."
+	PrefixScheduling = "scheduling."
 
 	HealthCheckInterval = PrefixHealth + "checkInterval"
 
+	// health check thresholds
+	HealthSchedulingStallTimeout = PrefixHealth + "schedulingStallTimeout" // time without a scheduling cycle
+	HealthEventQueueThreshold    = PrefixHealth + "eventQueueThreshold"    // percentage of an RM event queue in use
+	HealthEventPublisherTimeout  = PrefixHealth + "eventPublisherTimeout"  // time without an event publisher run
+
 	// events
 	CMEventTrackingEnabled    = PrefixEvent + "trackingEnabled"    // Application Tracking
 	CMEventRequestCapacity    = PrefixEvent + "requestCapacity"    // Request Capacity
@@ -40,15 +53,86 @@ const (
 	CMMaxEventStreamsPerHost  = PrefixEvent + "maxStreamsPerHost"
 	CMRESTResponseSize        = PrefixEvent + "RESTResponseSize"
 
+	// REST authentication
+	CMRESTAuthMode       = PrefixREST + "auth.mode"       // none, token or mtls
+	CMRESTAuthTokenFile  = PrefixREST + "auth.tokenFile"  // token file used in token mode
+	CMRESTAuthAdminGroup = PrefixREST + "auth.adminGroup" // group with access to all data and the debug endpoints
+
+	// TLS, certificate and key files are required to enable TLS, the client CA enables client certificate verification
+	CMRESTTLSCertFile     = PrefixREST + "tls.certFile"
+	CMRESTTLSKeyFile      = PrefixREST + "tls.keyFile"
+	CMRESTTLSClientCAFile = PrefixREST + "tls.clientCAFile"
+	CMGRPCTLSCertFile     = PrefixGRPC + "tls.certFile"
+	CMGRPCTLSKeyFile      = PrefixGRPC + "tls.keyFile"
+	CMGRPCTLSClientCAFile = PrefixGRPC + "tls.clientCAFile"
+
+	// tracing
+	CMTraceExporter     = PrefixTrace + "exporter"     // none, otlp or file
+	CMTraceOTLPEndpoint = PrefixTrace + "otlpEndpoint" // host:port of the OTLP gRPC collector
+	CMTraceOTLPInsecure = PrefixTrace + "otlpInsecure" // use plain text to connect to the collector
+	CMTraceFile         = PrefixTrace + "file"         // file spans are written to by the file exporter
+	CMTraceSampleRatio  = PrefixTrace + "sampleRatio"  // ratio of scheduling cycles and RM calls traced
+
+	// latency metrics cardinality guard
+	CMMetricsLatencyUserLabel = PrefixMetric + "latency.userLabel" // label the latency metrics with the user
+	CMMetricsLatencyMaxQueues = PrefixMetric + "latency.maxQueues" // number of queues labelled before overflowing
+	CMMetricsLatencyMaxUsers  = PrefixMetric + "latency.maxUsers"  // number of users labelled before overflowing
+
+	// preemption metrics cardinality guard, shared by the preemptor and victim queue labels
+	CMMetricsPreemptionMaxQueues = PrefixMetric + "preemption.maxQueues" // number of queues labelled before overflowing
+
+	// internal metrics history
+	CMMetricsHistoryInterval  = PrefixMetric + "history.interval"  // sampling interval of the history
+	CMMetricsHistoryRetention = PrefixMetric + "history.retention" // time a sample is kept in the history
+
+	// node attribute used to group the nodes in the node utilisation distribution, not grouped if empty
+	CMNodeUtilizationGroupBy = PrefixMetric + "nodeUtilization.groupBy"
+
+	// application summary export
+	CMAppSummaryFile   = PrefixSummary + "file"   // JSON lines file the summaries are appended to, empty to disable
+	CMAppSummaryEvents = PrefixSummary + "events" // send the summaries through the event system
+
+	// run an independent scheduling loop per partition instead of one loop over all partitions
+	CMSchedulingParallelPartitions = PrefixScheduling + "parallelPartitions"
+
+	// batch allocation: allocate multiple asks per scheduling cycle, a batch size of 1 disables the batch mode
+	CMSchedulingBatchSize       = PrefixScheduling + "batchSize"       // maximum number of allocations in one cycle
+	CMSchedulingBatchTimeBudget = PrefixScheduling + "batchTimeBudget" // maximum time spent allocating in one cycle
+
+	// overload protection of the allocation event queue: new asks are rejected or delayed, releases are always accepted
+	CMOverloadMode      = PrefixOverload + "mode"      // none, reject or delay
+	CMOverloadThreshold = PrefixOverload + "threshold" // percentage of the queue capacity in use that triggers the overload mode
+	CMOverloadMaxDelay  = PrefixOverload + "maxDelay"  // longest time new asks are delayed in delay mode
+
 	// defaults
-	DefaultHealthCheckInterval     = 30 * time.Second
-	DefaultEventTrackingEnabled    = true
-	DefaultEventRequestCapacity    = 1000
-	DefaultEventRingBufferCapacity = 100000
-	DefaultEventChannelSize        = 100000
-	DefaultMaxStreams              = uint64(100)
-	DefaultMaxStreamsPerHost       = uint64(15)
-	DefaultRESTResponseSize        = uint64(10000)
+	DefaultHealthCheckInterval        = 30 * time.Second
+	DefaultSchedulingStallTimeout     = time.Minute
+	DefaultEventQueueThreshold        = uint64(90)
+	DefaultEventPublisherTimeout      = time.Minute
+	DefaultEventTrackingEnabled       = true
+	DefaultEventRequestCapacity       = 1000
+	DefaultEventRingBufferCapacity    = 100000
+	DefaultEventChannelSize           = 100000
+	DefaultMaxStreams                 = uint64(100)
+	DefaultMaxStreamsPerHost          = uint64(15)
+	DefaultRESTResponseSize           = uint64(10000)
+	DefaultRESTAuthMode               = "none"
+	DefaultTraceExporter              = "none"
+	DefaultTraceOTLPEndpoint          = "localhost:4317"
+	DefaultTraceSampleRatio           = 1.0
+	DefaultMetricsLatencyUserLabel    = false
+	DefaultMetricsLatencyMaxQueues    = uint64(100)
+	DefaultMetricsLatencyMaxUsers     = uint64(100)
+	DefaultMetricsPreemptionMaxQueues = uint64(100)
+	DefaultMetricsHistoryInterval     = time.Minute
+	DefaultMetricsHistoryRetention    = 24 * time.Hour
+	DefaultAppSummaryEvents           = false
+	DefaultOverloadMode               = "none"
+	DefaultParallelPartitions         = false
+	DefaultSchedulingBatchSize        = uint64(1)
+	DefaultBatchTimeBudget            = 100 * time.Millisecond
+	DefaultOverloadThreshold          = uint64(80)
+	DefaultOverloadMaxDelay           = 5 * time.Second
 )
 
 var ConfigContext *SchedulerConfigContext
@@ -123,6 +207,26 @@ func SetConfigMap(newConfigMap map[string]string) {
 	configMap = newConfigMap
 }
 
+// GetRESTTLSFiles returns the TLS files for the REST service from the ConfigMap
+func GetRESTTLSFiles() common.TLSFiles {
+	configMap := GetConfigMap()
+	return common.TLSFiles{
+		CertFile:     configMap[CMRESTTLSCertFile],
+		KeyFile:      configMap[CMRESTTLSKeyFile],
+		ClientCAFile: configMap[CMRESTTLSClientCAFile],
+	}
+}
+
+// GetGRPCTLSFiles returns the TLS files for the gRPC scheduler server from the ConfigMap
+func GetGRPCTLSFiles() common.TLSFiles {
+	configMap := GetConfigMap()
+	return common.TLSFiles{
+		CertFile:     configMap[CMGRPCTLSCertFile],
+		KeyFile:      configMap[CMGRPCTLSKeyFile],
+		ClientCAFile: configMap[CMGRPCTLSClientCAFile],
+	}
+}
+
 func processConfigMapCallbacks() {
 	for _, callback := range getConfigMapCallbacks() {
 		callback()
@@ -52,6 +52,9 @@ const (
 	PreemptionDelay                          = "preemption.delay"
 	QuotaPreemptionDelay                     = "quota.preemption.delay"
 
+	// user group resolver that requires a mapping file
+	UserGroupResolverFile = "file"
+
 	// app sort priority values
 	ApplicationSortPriorityEnabled  = "enabled"
 	ApplicationSortPriorityDisabled = "disabled"
@@ -106,9 +109,9 @@ type placementStaticPath struct {
 }
 
 // Check the ACL
-func checkACL(acl string) error {
+func checkACL(aclStr string) error {
 	// trim any white space
-	acl = strings.TrimSpace(acl)
+	acl := strings.TrimSpace(aclStr)
 	// handle special cases: deny and wildcard
 	if len(acl) == 0 || acl == common.Wildcard {
 		return nil
@@ -119,6 +122,47 @@ func checkACL(acl string) error {
 	if len(fields) > 2 {
 		return fmt.Errorf("multiple spaces found in ACL: '%s'", acl)
 	}
+	return checkACLDeny(aclStr)
+}
+
+// Check the deny entries in the ACL, the start of the ACL is not trimmed as a leading space means no users are listed
+func checkACLDeny(acl string) error {
+	fields := strings.Split(strings.TrimRight(acl, common.Space), common.Space)
+	if len(fields) > 2 {
+		// extra padding is allowed by checkACL, do not fail on it here
+		fields = strings.Fields(acl)
+	}
+	if err := checkACLDenyEntries(fields[0], "user", UserRegExp); err != nil {
+		return err
+	}
+	if len(fields) == 2 {
+		return checkACLDenyEntries(fields[1], "group", GroupRegExp)
+	}
+	return nil
+}
+
+// Check that denied names are valid and not also allowed
+func checkACLDenyEntries(list, kind string, nameRegExp *regexp.Regexp) error {
+	allowed := make(map[string]bool)
+	denied := make(map[string]bool)
+	for _, entry := range strings.Split(list, common.Separator) {
+		if name, found := strings.CutPrefix(entry, common.DenyPrefix); found {
+			if name == common.Wildcard {
+				return fmt.Errorf("wildcard cannot be denied in ACL, %s list: '%s'", kind, list)
+			}
+			if !nameRegExp.MatchString(name) {
+				return fmt.Errorf("invalid denied %s '%s' in ACL", kind, name)
+			}
+			denied[name] = true
+		} else if entry != "" {
+			allowed[entry] = true
+		}
+	}
+	for name := range denied {
+		if allowed[name] {
+			return fmt.Errorf("%s '%s' is both allowed and denied in ACL", kind, name)
+		}
+	}
 	return nil
 }
 
@@ -628,6 +672,18 @@ func checkNodeSortingPolicy(partition *PartitionConfig) error {
 	return nil
 }
 
+// Check the user group resolver: the file resolver needs a mapping file
+func checkUserGroupResolver(partition *PartitionConfig) error {
+	ugr := partition.UserGroupResolver
+	if ugr.Type == UserGroupResolverFile && ugr.File == "" {
+		return fmt.Errorf("user group resolver type %s requires a mapping file", UserGroupResolverFile)
+	}
+	if ugr.Type != UserGroupResolverFile && ugr.File != "" {
+		return fmt.Errorf("mapping file %s is only supported by user group resolver type %s", ugr.File, UserGroupResolverFile)
+	}
+	return nil
+}
+
 // Check the queue names configured for compliance and uniqueness
 // - no duplicate names at each branched level in the tree
 // - queue name is alphanumeric (case ignore) with - and _
@@ -781,6 +837,10 @@ func Validate(newConfig *SchedulerConfig) error {
 		if err != nil {
 			return err
 		}
+		err = checkUserGroupResolver(&partition)
+		if err != nil {
+			return err
+		}
 
 		err = checkQueueMaxApplications(partition.Queues[0])
 		if err != nil {
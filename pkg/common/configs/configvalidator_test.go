@@ -2199,6 +2199,40 @@ func TestCheckQueuesStructure(t *testing.T) {
 	}
 }
 
+func TestCheckACL(t *testing.T) {
+	testCases := []struct {
+		name             string
+		acl              string
+		expectedErrorMsg string
+	}{
+		{"empty", "", ""},
+		{"wildcard", "*", ""},
+		{"users and groups", "user1,user2 group1", ""},
+		{"padded", " user1 group1 ", ""},
+		{"multiple spaces", "user1 group1 extra", "multiple spaces found in ACL"},
+		{"denied user", "*,!svc-legacy", ""},
+		{"denied group", " engineering,!contractors", ""},
+		{"denied user and group", "user1,!user2 *,!contractors", ""},
+		{"denied wildcard user", "!*", "wildcard cannot be denied in ACL, user list: '!*'"},
+		{"denied wildcard group", " group1,!*", "wildcard cannot be denied in ACL, group list: 'group1,!*'"},
+		{"empty denied user", "user1,!", "invalid denied user '' in ACL"},
+		{"invalid denied user", "!1user", "invalid denied user '1user' in ACL"},
+		{"invalid denied group", " !group@domain", "invalid denied group 'group@domain' in ACL"},
+		{"allowed and denied user", "user1,!user1", "user 'user1' is both allowed and denied in ACL"},
+		{"allowed and denied group", " group1,!group1", "group 'group1' is both allowed and denied in ACL"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkACL(tc.acl)
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg)
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
+
 func TestCheckQueues(t *testing.T) { //nolint:funlen
 	testCases := []struct {
 		name             string
@@ -2229,6 +2263,17 @@ func TestCheckQueues(t *testing.T) { //nolint:funlen
 			level:            0,
 			expectedErrorMsg: "multiple spaces found in ACL: 'submit group extra'",
 		},
+		{
+			name: "Invalid deny entry for SubmitACL",
+			queue: &QueueConfig{
+				Name:      "validQueue",
+				AdminACL:  "admin",
+				SubmitACL: "*,!*",
+				Queues:    []QueueConfig{{Name: "validSubQueue"}},
+			},
+			level:            0,
+			expectedErrorMsg: "wildcard cannot be denied in ACL",
+		},
 		{
 			name: "Duplicate Child Queue Names",
 			queue: &QueueConfig{
@@ -18,10 +18,13 @@
 
 package common
 
+import "time"
+
 const (
 	Empty = ""
 
 	Wildcard              = "*"
+	DenyPrefix            = "!"
 	Separator             = ","
 	Space                 = " "
 	AnonymousUser         = "nobody"
@@ -39,6 +42,11 @@ const (
 	LdapBindPassword      = "BindPassword"
 	LdapInsecure          = "Insecure"
 	LdapSSL               = "SSL"
+	LdapPoolSize          = "PoolSize"
+	LdapNegativeCacheTTL  = "NegativeCacheTTL"
+	LdapFailoverInterval  = "FailoverInterval"
+	// KeyExpectedDuration allocation tag key (in the YuniKorn domain) with the expected run time of the allocation
+	KeyExpectedDuration = "expectedDuration"
 )
 
 const (
@@ -52,6 +60,11 @@ const (
 	DefaultLdapInsecure     = false
 	DefaultLdapSSL          = false
 	DefaultLdapUserUID      = "1211"
+	DefaultLdapPoolSize     = 5
+	// DefaultLdapNegativeCacheTTL time a failed or empty lookup is cached by the LDAP resolver
+	DefaultLdapNegativeCacheTTL = 30 * time.Second
+	// DefaultLdapFailoverInterval time an unreachable LDAP server is skipped before it is tried again
+	DefaultLdapFailoverInterval = 60 * time.Second
 )
 
 var (
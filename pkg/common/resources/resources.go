@@ -671,6 +671,12 @@ func FairnessRatio(left, right, total *Resource) float64 {
 	return ratio
 }
 
+// CompareShares compares two lists of shares as returned by GetShares.
+// This returns the same value as CompUsageRatio does for the resources the shares were calculated for.
+func CompareShares(lshares, rshares []float64) int {
+	return compareShares(lshares, rshares)
+}
+
 // Compare the shares and return the compared value
 // 0 for equal shares
 // 1 if the left share is larger
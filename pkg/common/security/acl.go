@@ -34,10 +34,29 @@ import (
 var userNameRegExp = regexp.MustCompile("^[_a-zA-Z][a-zA-Z0-9_.@-]*[$]?$")
 var groupRegExp = regexp.MustCompile("^[_a-zA-Z][a-zA-Z0-9_-]*$")
 
+// ACL defines the users and groups that are allowed or denied access.
+// Deny entries take precedence over allow entries and the wildcard.
 type ACL struct {
-	users      map[string]bool
-	groups     map[string]bool
-	allAllowed bool
+	users        map[string]bool
+	groups       map[string]bool
+	deniedUsers  map[string]bool
+	deniedGroups map[string]bool
+	allAllowed   bool
+}
+
+// splitDenied splits the entries in the list into the allowed and denied entries.
+// Denied entries have the common.DenyPrefix removed.
+func splitDenied(list []string) ([]string, []string) {
+	allowed := make([]string, 0, len(list))
+	denied := make([]string, 0)
+	for _, entry := range list {
+		if name, found := strings.CutPrefix(entry, common.DenyPrefix); found {
+			denied = append(denied, name)
+		} else {
+			allowed = append(allowed, entry)
+		}
+	}
+	return allowed, denied
 }
 
 // the ACL allows all access, set the flag
@@ -50,6 +69,16 @@ func (a *ACL) setAllAllowed(part string) {
 // If the silence flag is set to true, the function will not log when setting the users.
 func (a *ACL) setUsers(userList []string, silence bool) {
 	a.users = make(map[string]bool)
+	a.deniedUsers = make(map[string]bool)
+	userList, deniedList := splitDenied(userList)
+	for _, user := range deniedList {
+		if userNameRegExp.MatchString(user) {
+			a.deniedUsers[user] = true
+		} else if !silence {
+			log.Log(log.Security).Info("ignoring denied user in ACL definition",
+				zap.String("user", user))
+		}
+	}
 	// special case if the user list is just the wildcard
 	if len(userList) == 1 && userList[0] == common.Wildcard {
 		if !silence {
@@ -78,6 +107,16 @@ func (a *ACL) setUsers(userList []string, silence bool) {
 // If the silence flag is set to true, the function will not log when setting the groups.
 func (a *ACL) setGroups(groupList []string, silence bool) {
 	a.groups = make(map[string]bool)
+	a.deniedGroups = make(map[string]bool)
+	groupList, deniedList := splitDenied(groupList)
+	for _, group := range deniedList {
+		if groupRegExp.MatchString(group) {
+			a.deniedGroups[group] = true
+		} else if !silence {
+			log.Log(log.Security).Info("ignoring denied group in ACL",
+				zap.String("group", group))
+		}
+	}
 	// special case if the wildcard was already set
 	if a.allAllowed {
 		if !silence {
@@ -131,8 +170,11 @@ func NewACL(aclStr string, silence bool) (ACL, error) {
 	return acl, nil
 }
 
-// Check if the user has access
+// Check if the user has access: an explicit deny of the user or one of its groups always refuses access
 func (a ACL) CheckAccess(userObj UserGroup) bool {
+	if a.IsDenied(userObj) {
+		return false
+	}
 	// shortcut allow all
 	if a.allAllowed {
 		return true
@@ -150,3 +192,16 @@ func (a ACL) CheckAccess(userObj UserGroup) bool {
 	}
 	return false
 }
+
+// IsDenied returns true if the user or one of its groups is explicitly denied
+func (a ACL) IsDenied(userObj UserGroup) bool {
+	if a.deniedUsers[userObj.User] {
+		return true
+	}
+	for _, group := range userObj.Groups {
+		if a.deniedGroups[group] {
+			return true
+		}
+	}
+	return false
+}
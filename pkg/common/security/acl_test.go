@@ -21,6 +21,7 @@ package security
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/apache/yunikorn-core/pkg/common"
@@ -235,6 +236,46 @@ func TestACLAccess(t *testing.T) {
 			UserGroup{User: "user1", Groups: []string{"group1"}},
 			false,
 		},
+		{
+			"*,!svc-legacy",
+			UserGroup{User: "user1", Groups: []string{"group1"}},
+			true,
+		},
+		{
+			"*,!svc-legacy",
+			UserGroup{User: "svc-legacy", Groups: []string{"group1"}},
+			false,
+		},
+		{
+			" engineering,!contractors",
+			UserGroup{User: "user1", Groups: []string{"engineering"}},
+			true,
+		},
+		{
+			" engineering,!contractors",
+			UserGroup{User: "user1", Groups: []string{"engineering", "contractors"}},
+			false,
+		},
+		{
+			"user1 engineering,!contractors",
+			UserGroup{User: "user1", Groups: []string{"contractors"}},
+			false,
+		},
+		{
+			"user1,!user2 *",
+			UserGroup{User: "user2", Groups: []string{"group1"}},
+			false,
+		},
+		{
+			" *,!contractors",
+			UserGroup{User: "user2", Groups: []string{"group1"}},
+			true,
+		},
+		{
+			" *,!contractors",
+			UserGroup{User: "user2", Groups: []string{"group1", "contractors"}},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("vistor %v, acl %s", tt.visitor, tt.acl), func(t *testing.T) {
@@ -248,3 +289,49 @@ func TestACLAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestACLDenyCreate(t *testing.T) {
+	tests := []struct {
+		input        string
+		allAllowed   bool
+		users        []string
+		deniedUsers  []string
+		groups       []string
+		deniedGroups []string
+	}{
+		{"!user1", false, nil, []string{"user1"}, nil, nil},
+		{"*,!user1,!user2", true, nil, []string{"user1", "user2"}, nil, nil},
+		{"user1,!user2 group1,!group2", false, []string{"user1"}, []string{"user2"}, []string{"group1"}, []string{"group2"}},
+		{" *,!group2", true, nil, nil, nil, []string{"group2"}},
+		{"*,!user1 !group2", true, nil, []string{"user1"}, nil, []string{"group2"}},
+		{"!#user1 !dotted.group", false, nil, nil, nil, nil},
+	}
+	toMap := func(list []string) map[string]bool {
+		m := make(map[string]bool)
+		for _, entry := range list {
+			m[entry] = true
+		}
+		return m
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NewACL(tt.input, false)
+			if err != nil {
+				t.Fatalf("parsing failed for string: %s", tt.input)
+			}
+			if got.allAllowed != tt.allAllowed {
+				t.Errorf("allAllowed expect: %v, got %v", tt.allAllowed, got.allAllowed)
+			}
+			for name, list := range map[string][]map[string]bool{
+				"users":        {toMap(tt.users), got.users},
+				"deniedUsers":  {toMap(tt.deniedUsers), got.deniedUsers},
+				"groups":       {toMap(tt.groups), got.groups},
+				"deniedGroups": {toMap(tt.deniedGroups), got.deniedGroups},
+			} {
+				if !reflect.DeepEqual(list[0], list[1]) && (len(list[0]) != 0 || len(list[1]) != 0) {
+					t.Errorf("%s expect: %v, got %v", name, list[0], list[1])
+				}
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -70,6 +71,7 @@ func (v *LdapValidator) ValidateConfig(config *LdapConfig) bool {
 	v.validateReturnAttr(config.ReturnAttr)
 	v.validateBindUser(config.BindUser)
 	v.validateBindPassword(config.BindPassword)
+	v.validatePool(config)
 
 	// Consistency checks
 	v.validateConsistency(config)
@@ -81,22 +83,34 @@ func (v *LdapValidator) ValidateConfig(config *LdapConfig) bool {
 	return !v.hasErrors()
 }
 
-// validateHost validates the LDAP host
+// validateHost validates the comma separated list of LDAP hosts, each host can have a port
 func (v *LdapValidator) validateHost(host string) {
 	if host == "" {
 		v.addIssue("Host", "Host cannot be empty", ValidationError)
 		return
 	}
 
-	// Check if it's an IP address
-	if net.ParseIP(host) != nil {
-		return // Valid IP address
-	}
-
-	// Check if it's a valid hostname
 	hostnameRegex := regexp.MustCompile(`^(([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]*[a-zA-Z0-9])\.)*([A-Za-z0-9]|[A-Za-z0-9][A-Za-z0-9\-]*[A-Za-z0-9])$`)
-	if !hostnameRegex.MatchString(host) {
-		v.addIssue("Host", fmt.Sprintf("Invalid hostname format: %s", host), ValidationWarning)
+	for _, server := range strings.Split(host, common.Separator) {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			v.addIssue("Host", "Host list contains an empty entry", ValidationError)
+			continue
+		}
+		if h, p, err := net.SplitHostPort(server); err == nil {
+			server = h
+			if port, err := strconv.Atoi(p); err != nil || port < 1 || port > 65535 {
+				v.addIssue("Host", fmt.Sprintf("Invalid port for host %s: %s", h, p), ValidationError)
+			}
+		}
+		// Check if it's an IP address
+		if net.ParseIP(server) != nil {
+			continue // Valid IP address
+		}
+		// Check if it's a valid hostname
+		if !hostnameRegex.MatchString(server) {
+			v.addIssue("Host", fmt.Sprintf("Invalid hostname format: %s", server), ValidationWarning)
+		}
 	}
 }
 
@@ -214,6 +228,19 @@ func (v *LdapValidator) validateBindPassword(bindPassword string) {
 	// We don't check for password complexity here as it depends on the LDAP server policy
 }
 
+// validatePool validates the connection pool and cache settings, zero values use the defaults
+func (v *LdapValidator) validatePool(config *LdapConfig) {
+	if config.PoolSize < 0 {
+		v.addIssue("PoolSize", fmt.Sprintf("PoolSize cannot be negative, got: %d", config.PoolSize), ValidationError)
+	}
+	if config.NegativeCacheTTL < 0 {
+		v.addIssue("NegativeCacheTTL", fmt.Sprintf("NegativeCacheTTL cannot be negative, got: %s", config.NegativeCacheTTL), ValidationError)
+	}
+	if config.FailoverInterval < 0 {
+		v.addIssue("FailoverInterval", fmt.Sprintf("FailoverInterval cannot be negative, got: %s", config.FailoverInterval), ValidationError)
+	}
+}
+
 // validateConsistency performs cross-field validation
 func (v *LdapValidator) validateConsistency(config *LdapConfig) {
 	// Check SSL and port consistency
@@ -299,6 +326,10 @@ func ValidateSecretValue(key, value string) (interface{}, error) {
 		return validateBindPasswordValue(value)
 	case common.LdapInsecure, common.LdapSSL:
 		return validateBoolValue(value)
+	case common.LdapPoolSize:
+		return validatePoolSizeValue(value)
+	case common.LdapNegativeCacheTTL, common.LdapFailoverInterval:
+		return validateDurationValue(value)
 	default:
 		return nil, fmt.Errorf("unknown LDAP secret key: %s", key)
 	}
@@ -379,3 +410,25 @@ func validateBoolValue(value string) (bool, error) {
 	}
 	return boolValue, nil
 }
+
+func validatePoolSizeValue(value string) (int, error) {
+	size, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid pool size: %s", err)
+	}
+	if size < 1 {
+		return 0, fmt.Errorf("pool size must be at least 1, got: %d", size)
+	}
+	return size, nil
+}
+
+func validateDurationValue(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %s", err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("duration cannot be negative, got: %s", duration)
+	}
+	return duration, nil
+}
@@ -20,6 +20,7 @@ package security
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -610,6 +611,30 @@ func TestValidateHost(t *testing.T) {
 			expectWarning: true,
 			expectError:   false,
 		},
+		{
+			name:          "Valid host list",
+			host:          "ldap1.example.com, ldap2.example.com:1389,192.168.1.1:636",
+			expectWarning: false,
+			expectError:   false,
+		},
+		{
+			name:          "Host list with empty entry",
+			host:          "ldap1.example.com,,ldap2.example.com",
+			expectWarning: false,
+			expectError:   true,
+		},
+		{
+			name:          "Host list with invalid port",
+			host:          "ldap1.example.com,ldap2.example.com:99999",
+			expectWarning: false,
+			expectError:   true,
+		},
+		{
+			name:          "Host list with invalid hostname",
+			host:          "ldap1.example.com,ldap_2.example.com",
+			expectWarning: true,
+			expectError:   false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -719,6 +744,14 @@ func TestValidateSecretValue(t *testing.T) {
 		{"Valid bindPassword", common.LdapBindPassword, "password", false},
 		{"Valid insecure", common.LdapInsecure, "true", false},
 		{"Valid SSL", common.LdapSSL, "false", false},
+		{"Valid pool size", common.LdapPoolSize, "10", false},
+		{"Invalid pool size", common.LdapPoolSize, "0", true},
+		{"Invalid pool size format", common.LdapPoolSize, "ten", true},
+		{"Valid negative cache TTL", common.LdapNegativeCacheTTL, "45s", false},
+		{"Disabled negative cache TTL", common.LdapNegativeCacheTTL, "0s", false},
+		{"Invalid negative cache TTL", common.LdapNegativeCacheTTL, "-5s", true},
+		{"Valid failover interval", common.LdapFailoverInterval, "2m", false},
+		{"Invalid failover interval", common.LdapFailoverInterval, "soon", true},
 		{"Invalid key", unknown, "value", true},
 	}
 
@@ -912,6 +945,31 @@ func TestValidatePort(t *testing.T) {
 	}
 }
 
+func TestValidatePool(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      LdapConfig
+		errorFields []string
+	}{
+		{"defaults", LdapConfig{}, nil},
+		{"valid", LdapConfig{PoolSize: 10, NegativeCacheTTL: time.Minute, FailoverInterval: time.Minute}, nil},
+		{"negative pool size", LdapConfig{PoolSize: -1}, []string{"PoolSize"}},
+		{"negative durations", LdapConfig{NegativeCacheTTL: -time.Second, FailoverInterval: -time.Second}, []string{"NegativeCacheTTL", "FailoverInterval"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewLdapValidator()
+			validator.validatePool(&tt.config)
+			var fields []string
+			for _, issue := range validator.issues {
+				assert.Equal(t, ValidationError, issue.Level)
+				fields = append(fields, issue.Field)
+			}
+			assert.DeepEqual(t, tt.errorFields, fields)
+		})
+	}
+}
+
 // TestLogIssues tests the logIssues method
 func TestLogIssues(t *testing.T) {
 	validator := NewLdapValidator()
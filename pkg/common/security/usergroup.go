@@ -55,6 +55,7 @@ type UserGroupCache struct {
 	lookup        func(userName string) (*user.User, error)
 	lookupGroupID func(gid string) (*user.Group, error)
 	groupIds      func(osUser *user.User) ([]string, error)
+	closer        func() // optional: releases resources held by the resolver on stop
 	stop          chan struct{}
 }
 
@@ -71,14 +72,16 @@ const (
 	ldapType = "ldap"
 	testType = "test"
 	osType   = "os"
+	fileType = "file"
 )
 
 // GetUserGroupCache returns the resolver for the user and group info.
-// Current setup allows three resolvers:
+// Current setup allows these resolvers:
 // * NO resolver: default, no user or group resolution just return the info (k8s use case)
 // * OS resolver: uses the OS libraries to resolve user and group memberships
 // * Test resolver: fake resolution for testing
 // * Ldap resolver: uses the LDAP protocol to resolve user and group memberships
+// * File resolver: uses a local mapping file of users to groups that is reloaded on change
 func GetUserGroupCache(ugr configs.UserGroupResolver, ldapConfigReader ConfigReader, ldapAccess LdapAccess) *UserGroupCache {
 	once.Do(func() {
 		switch ugr.Type {
@@ -94,6 +97,11 @@ func GetUserGroupCache(ugr configs.UserGroupResolver, ldapConfigReader ConfigRea
 			log.Log(log.Security).Info("creating LDAP user group resolver")
 			instance = GetUserGroupCacheLdap(ldapConfigReader, ldapAccess)
 			instance.myType = ldapType
+		case fileType:
+			log.Log(log.Security).Info("creating file user group resolver",
+				zap.String("file", ugr.File))
+			instance = GetUserGroupCacheFile(ugr.File)
+			instance.myType = fileType
 		default:
 			log.Log(log.Security).Info("creating UserGroupCache without resolver")
 			instance = GetUserGroupNoResolve()
@@ -136,8 +144,8 @@ func (c *UserGroupCache) cleanUpCache() {
 	oldest := time.Now().Unix() - poscache
 	oldestFailed := time.Now().Unix() - negcache
 	// clean up the cache so we do not grow out of bounds
-	instance.lock.Lock()
-	defer instance.lock.Unlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	// walk over the entries in the map and delete the expired ones, cleanup based on the resolved time.
 	// Negative cached entries will expire quicker
 	for key, val := range c.ugs {
@@ -147,11 +155,11 @@ func (c *UserGroupCache) cleanUpCache() {
 	}
 }
 
-// resetCache clears the cached content, test use only
+// resetCache clears the cached content
 func (c *UserGroupCache) resetCache() {
 	log.Log(log.Security).Debug("UserGroupCache reset")
-	instance.lock.Lock()
-	defer instance.lock.Unlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	c.ugs = make(map[string]*UserGroup)
 }
 
@@ -256,6 +264,9 @@ func (c *UserGroupCache) Stop() {
 	if !stopped.Load() {
 		log.Log(log.Security).Info("Stopping UserGroupCache background cleanup")
 		close(c.stop)
+		if c.closer != nil {
+			c.closer()
+		}
 		// Clear the cache before resetting the instance
 		c.lock.Lock()
 		c.ugs = make(map[string]*UserGroup)
@@ -0,0 +1,278 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+// This file contains the implementation of the file based resolver for user groups.
+// Two file formats are supported, the format is based on the file extension:
+// * .yaml or .yml: a map of users to their list of groups
+//
+//	users:
+//	  alice:
+//	    - engineering
+//	    - admins
+//
+// * all other files: /etc/group format, one group per line with a comma separated member list
+//
+//	engineering:x:1001:alice,bob
+//
+// The first group listed for a user is used as the primary group of the user.
+
+// interval between checks for changes of the mapping file, var to allow testing
+var fileCheckInterval = 10 * time.Second
+
+type fileResolver struct {
+	path     string
+	modTime  time.Time
+	size     int64
+	users    map[string][]string // user name to groups in file order
+	onReload func()              // called after a changed file was loaded
+	stop     chan struct{}
+
+	locking.RWMutex
+}
+
+// userGroupFile is the YAML representation of the mapping file
+type userGroupFile struct {
+	Users map[string][]string `yaml:"users"`
+}
+
+// GetUserGroupCacheFile returns the cache with a resolver based on a mapping file.
+// The file is checked for changes in the background and reloaded. A file that does not exist or cannot be parsed
+// is logged and leaves the previous mapping in place, which is empty on startup.
+func GetUserGroupCacheFile(path string) *UserGroupCache {
+	fr := newFileResolver(path)
+	if _, err := fr.reload(); err != nil {
+		log.Log(log.Security).Error("Unable to load user group mapping file, all lookups will fail until the file is fixed",
+			zap.String("file", path),
+			zap.Error(err))
+	}
+	cache := &UserGroupCache{
+		ugs:           map[string]*UserGroup{},
+		interval:      cleanerInterval * time.Second,
+		lookup:        fr.lookupUser,
+		lookupGroupID: fr.lookupGroupID,
+		groupIds:      fr.lookupGroupIds,
+		closer:        fr.close,
+		stop:          make(chan struct{}),
+	}
+	// changes must be visible without waiting for the cached entries to expire
+	fr.onReload = cache.resetCache
+	go fr.watch()
+	return cache
+}
+
+func newFileResolver(path string) *fileResolver {
+	return &fileResolver{
+		path:  path,
+		users: make(map[string][]string),
+		stop:  make(chan struct{}),
+	}
+}
+
+// watch checks the file for changes until the resolver is closed
+func (fr *fileResolver) watch() {
+	for {
+		select {
+		case <-fr.stop:
+			return
+		case <-time.After(fileCheckInterval):
+			changed, err := fr.reload()
+			if err != nil {
+				log.Log(log.Security).Warn("Failed to reload user group mapping file, keeping previous mapping",
+					zap.String("file", fr.path),
+					zap.Error(err))
+				continue
+			}
+			if changed && fr.onReload != nil {
+				fr.onReload()
+			}
+		}
+	}
+}
+
+// reload reads the file if the modification time or size has changed since the last load.
+// Returns true if a new mapping was loaded.
+func (fr *fileResolver) reload() (bool, error) {
+	info, err := os.Stat(fr.path)
+	if err != nil {
+		return false, err
+	}
+	fr.RLock()
+	unchanged := info.ModTime().Equal(fr.modTime) && info.Size() == fr.size
+	fr.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	content, err := os.ReadFile(fr.path)
+	if err != nil {
+		return false, err
+	}
+	var users map[string][]string
+	switch strings.ToLower(filepath.Ext(fr.path)) {
+	case ".yaml", ".yml":
+		users, err = parseUserGroupYaml(content)
+	default:
+		users, err = parseGroupFile(content)
+	}
+	if err != nil {
+		return false, err
+	}
+	fr.Lock()
+	defer fr.Unlock()
+	fr.users = users
+	fr.modTime = info.ModTime()
+	fr.size = info.Size()
+	log.Log(log.Security).Info("Loaded user group mapping file",
+		zap.String("file", fr.path),
+		zap.Int("users", len(users)))
+	return true, nil
+}
+
+// parseGroupFile parses the content in /etc/group format: name:password:gid:member1,member2
+func parseGroupFile(content []byte) (map[string][]string, error) {
+	users := make(map[string][]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 fields separated by ':', got %d", lineNo, len(fields))
+		}
+		group := strings.TrimSpace(fields[0])
+		if !configs.GroupRegExp.MatchString(group) {
+			return nil, fmt.Errorf("line %d: invalid group name %q", lineNo, group)
+		}
+		for _, member := range strings.Split(fields[3], common.Separator) {
+			member = strings.TrimSpace(member)
+			if member == "" {
+				continue
+			}
+			if err := addUserGroup(users, member, group); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// parseUserGroupYaml parses the YAML mapping of users to their groups
+func parseUserGroupYaml(content []byte) (map[string][]string, error) {
+	ugf := &userGroupFile{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(ugf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	users := make(map[string][]string)
+	for userName, groups := range ugf.Users {
+		for _, group := range groups {
+			if !configs.GroupRegExp.MatchString(group) {
+				return nil, fmt.Errorf("user %s: invalid group name %q", userName, group)
+			}
+			if err := addUserGroup(users, userName, group); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return users, nil
+}
+
+// addUserGroup adds the group to the user, duplicate groups are ignored
+func addUserGroup(users map[string][]string, userName, group string) error {
+	if !configs.UserRegExp.MatchString(userName) {
+		return fmt.Errorf("invalid user name %q", userName)
+	}
+	for _, existing := range users[userName] {
+		if existing == group {
+			return nil
+		}
+	}
+	users[userName] = append(users[userName], group)
+	return nil
+}
+
+// lookupUser returns the user with the first group as the primary group.
+// A user that is not in the file fails the lookup.
+func (fr *fileResolver) lookupUser(userName string) (*user.User, error) {
+	fr.RLock()
+	defer fr.RUnlock()
+	groups, ok := fr.users[userName]
+	if !ok || len(groups) == 0 {
+		return nil, fmt.Errorf("user %s not found in mapping file %s", userName, fr.path)
+	}
+	return &user.User{
+		Uid:      "-1",
+		Gid:      groups[0],
+		Username: userName,
+	}, nil
+}
+
+// lookupGroupID echoes the group: the mapping file uses group names as the ID
+func (fr *fileResolver) lookupGroupID(gid string) (*user.Group, error) {
+	group := user.Group{Gid: gid}
+	group.Name = gid
+	return &group, nil
+}
+
+// lookupGroupIds returns all groups of the user from the mapping file
+func (fr *fileResolver) lookupGroupIds(osUser *user.User) ([]string, error) {
+	fr.RLock()
+	defer fr.RUnlock()
+	groups, ok := fr.users[osUser.Username]
+	if !ok {
+		return nil, fmt.Errorf("user %s not found in mapping file %s", osUser.Username, fr.path)
+	}
+	result := make([]string, len(groups))
+	copy(result, groups)
+	return result, nil
+}
+
+// close stops the file watcher
+func (fr *fileResolver) close() {
+	close(fr.stop)
+}
@@ -0,0 +1,212 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package security
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+)
+
+const groupFile = `# comment line
+engineering:x:1001:alice,bob
+admins:x:1002:alice
+
+contractors:x:1003:carol, bob
+`
+
+const groupYaml = `users:
+  alice:
+    - engineering
+    - admins
+  bob:
+    - engineering
+    - contractors
+    - engineering
+`
+
+func writeMappingFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(content), 0600)
+	assert.NilError(t, err, "failed to write mapping file")
+	return path
+}
+
+func TestParseGroupFile(t *testing.T) {
+	users, err := parseGroupFile([]byte(groupFile))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string][]string{
+		"alice": {"engineering", "admins"},
+		"bob":   {"engineering", "contractors"},
+		"carol": {"contractors"},
+	}, users)
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"missing fields", "engineering:x:alice", "line 1: expected 4 fields"},
+		{"invalid group", "eng/ineering:x:1001:alice", "line 1: invalid group name"},
+		{"invalid user", "engineering:x:1001:alice\nadmins:x:1002:1bob", "line 2: invalid user name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err = parseGroupFile([]byte(tt.content))
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestParseUserGroupYaml(t *testing.T) {
+	users, err := parseUserGroupYaml([]byte(groupYaml))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string][]string{
+		"alice": {"engineering", "admins"},
+		"bob":   {"engineering", "contractors"},
+	}, users)
+
+	users, err = parseUserGroupYaml([]byte(""))
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(users))
+
+	_, err = parseUserGroupYaml([]byte("groups:\n  engineering: [alice]\n"))
+	assert.ErrorContains(t, err, "field groups not found")
+	_, err = parseUserGroupYaml([]byte("users:\n  alice: [\"eng ineering\"]\n"))
+	assert.ErrorContains(t, err, "invalid group name")
+}
+
+func TestFileResolverLookups(t *testing.T) {
+	for _, name := range []string{"group", "groups.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			content := groupFile
+			if name == "groups.yaml" {
+				content = groupYaml
+			}
+			fr := newFileResolver(writeMappingFile(t, name, content))
+			changed, err := fr.reload()
+			assert.NilError(t, err)
+			assert.Assert(t, changed, "first load should have loaded the mapping")
+
+			var u *user.User
+			u, err = fr.lookupUser("alice")
+			assert.NilError(t, err)
+			assert.Equal(t, "alice", u.Username)
+			assert.Equal(t, "engineering", u.Gid)
+			var groups []string
+			groups, err = fr.lookupGroupIds(u)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, []string{"engineering", "admins"}, groups)
+			var g *user.Group
+			g, err = fr.lookupGroupID("admins")
+			assert.NilError(t, err)
+			assert.Equal(t, "admins", g.Name)
+
+			_, err = fr.lookupUser("unknown")
+			assert.ErrorContains(t, err, "user unknown not found in mapping file")
+			_, err = fr.lookupGroupIds(&user.User{Username: "unknown"})
+			assert.ErrorContains(t, err, "user unknown not found in mapping file")
+
+			// unchanged file is not reloaded
+			changed, err = fr.reload()
+			assert.NilError(t, err)
+			assert.Assert(t, !changed, "unchanged file should not have been reloaded")
+		})
+	}
+}
+
+func TestFileResolverReload(t *testing.T) {
+	path := writeMappingFile(t, "groups.yaml", groupYaml)
+	fr := newFileResolver(path)
+	_, err := fr.reload()
+	assert.NilError(t, err)
+
+	// broken content keeps the old mapping
+	err = os.WriteFile(path, []byte("users: [broken"), 0600)
+	assert.NilError(t, err)
+	_, err = fr.reload()
+	assert.Assert(t, err != nil, "broken file should have failed to load")
+	_, err = fr.lookupUser("alice")
+	assert.NilError(t, err, "previous mapping should have been kept")
+
+	// a valid change replaces the mapping
+	err = os.WriteFile(path, []byte("users:\n  dave: [ops]\n"), 0600)
+	assert.NilError(t, err)
+	changed, err := fr.reload()
+	assert.NilError(t, err)
+	assert.Assert(t, changed)
+	_, err = fr.lookupUser("alice")
+	assert.Assert(t, err != nil, "alice should have been removed")
+	u, err := fr.lookupUser("dave")
+	assert.NilError(t, err)
+	assert.Equal(t, "ops", u.Gid)
+
+	// removed file keeps the old mapping
+	err = os.Remove(path)
+	assert.NilError(t, err)
+	_, err = fr.reload()
+	assert.Assert(t, os.IsNotExist(err))
+	_, err = fr.lookupUser("dave")
+	assert.NilError(t, err)
+}
+
+func TestUserGroupCacheFile(t *testing.T) {
+	savedInterval := fileCheckInterval
+	fileCheckInterval = 10 * time.Millisecond
+	defer func() { fileCheckInterval = savedInterval }()
+
+	path := writeMappingFile(t, "group", groupFile)
+	cache := GetUserGroupCacheFile(path)
+	defer cache.closer()
+
+	ug, err := cache.GetUserGroup("bob")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"engineering", "contractors"}, ug.Groups)
+	assert.Equal(t, 1, cache.getUGsize())
+
+	// unknown users are negatively cached like the other resolvers
+	ug, err = cache.GetUserGroup("unknown")
+	assert.Assert(t, err != nil)
+	assert.Assert(t, ug.failed)
+	_, err = cache.GetUserGroup("unknown")
+	assert.ErrorContains(t, err, "user resolution failed, cached data returned")
+
+	// a changed file clears the cache and the new groups are used
+	err = os.WriteFile(path, []byte("engineering:x:1001:alice\nops:x:1004:bob,unknown\n"), 0600)
+	assert.NilError(t, err)
+	// make sure the modification time changes on file systems with a coarse granularity
+	err = os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	assert.NilError(t, err)
+	err = common.WaitForCondition(10*time.Millisecond, 2*time.Second, func() bool {
+		return cache.getUGsize() == 0
+	})
+	assert.NilError(t, err, "cache should have been cleared after the reload")
+	ug, err = cache.GetUserGroup("bob")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"ops"}, ug.Groups)
+	ug, err = cache.GetUserGroup("unknown")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"ops"}, ug.Groups)
+}
@@ -0,0 +1,277 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package security
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+)
+
+// time a caller waits for a free connection slot before giving up, var to allow testing
+var ldapPoolWaitTimeout = 5 * time.Second
+
+// ldapServer is one of the configured LDAP servers with its health state
+type ldapServer struct {
+	url       string
+	downUntil time.Time
+}
+
+// pooledConn is a bound connection and the server it was created for
+type pooledConn struct {
+	conn   *ldap.Conn
+	server string
+}
+
+// ldapConnPool keeps a bounded set of bound LDAP connections.
+// Servers are tried in configuration order. A server that fails to dial or bind is skipped until the
+// failover interval has passed, unless all servers are marked down.
+type ldapConnPool struct {
+	access   LdapAccess
+	config   LdapConfig
+	servers  []*ldapServer
+	idle     []*pooledConn
+	slots    chan struct{} // one entry per connection in use: new connections are only created if none are idle
+	interval time.Duration
+	closed   bool
+
+	locking.Mutex
+}
+
+func newLdapConnPool(access LdapAccess, config LdapConfig) *ldapConnPool {
+	size := config.PoolSize
+	if size <= 0 {
+		size = common.DefaultLdapPoolSize
+	}
+	interval := config.FailoverInterval
+	if interval <= 0 {
+		interval = common.DefaultLdapFailoverInterval
+	}
+	servers := make([]*ldapServer, 0)
+	for _, url := range ldapServerURLs(config) {
+		servers = append(servers, &ldapServer{url: url})
+	}
+	return &ldapConnPool{
+		access:   access,
+		config:   config,
+		servers:  servers,
+		idle:     make([]*pooledConn, 0, size),
+		slots:    make(chan struct{}, size),
+		interval: interval,
+	}
+}
+
+// ldapServerURLs converts the comma separated host list into LDAP URLs.
+// A host without a port uses the configured port.
+func ldapServerURLs(config LdapConfig) []string {
+	scheme := "ldap"
+	if config.useSsl {
+		scheme = "ldaps"
+	}
+	urls := make([]string, 0)
+	for _, host := range strings.Split(config.Host, common.Separator) {
+		host = strings.TrimSpace(host)
+		port := strconv.Itoa(config.Port)
+		if h, p, err := net.SplitHostPort(host); err == nil {
+			host = h
+			port = p
+		}
+		urls = append(urls, fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, port)))
+	}
+	return urls
+}
+
+// search executes the search request on a pooled connection. A connection that fails with a network
+// error is discarded and the search is retried on a new connection. Idle connections could have been
+// closed by the server, which is why a broken connection does not mark the server down: a failing dial does.
+func (p *ldapConnPool) search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	var lastErr error
+	for attempt := 0; attempt <= len(p.servers); attempt++ {
+		pc, err := p.get()
+		if err != nil {
+			return nil, err
+		}
+		var sr *ldap.SearchResult
+		sr, err = p.access.Search(pc.conn, searchRequest)
+		if err == nil {
+			p.put(pc)
+			return sr, nil
+		}
+		lastErr = err
+		p.discard(pc)
+		log.Log(log.Security).Debug("Discarded LDAP connection after search failure",
+			zap.String("address", pc.server),
+			zap.Error(err))
+		if !ldap.IsErrorWithCode(err, ldap.ErrorNetwork) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// get returns an idle connection or creates and binds a new one if the pool is not full.
+// Blocks until a connection slot is available or the wait times out.
+func (p *ldapConnPool) get() (*pooledConn, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-time.After(ldapPoolWaitTimeout):
+		return nil, fmt.Errorf("timeout waiting for a free LDAP connection, pool size %d", cap(p.slots))
+	}
+	p.Lock()
+	if n := len(p.idle); n > 0 {
+		pc := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.updateMetrics()
+		p.Unlock()
+		return pc, nil
+	}
+	p.updateMetrics()
+	p.Unlock()
+	pc, err := p.dial()
+	if err != nil {
+		<-p.slots
+		p.Lock()
+		p.updateMetrics()
+		p.Unlock()
+		return nil, err
+	}
+	return pc, nil
+}
+
+// put returns a healthy connection to the pool
+func (p *ldapConnPool) put(pc *pooledConn) {
+	p.Lock()
+	defer p.Unlock()
+	if p.closed {
+		p.access.Close(pc.conn)
+	} else {
+		p.idle = append(p.idle, pc)
+	}
+	<-p.slots
+	p.updateMetrics()
+}
+
+// discard closes a connection that can no longer be used and frees its slot
+func (p *ldapConnPool) discard(pc *pooledConn) {
+	p.access.Close(pc.conn)
+	p.Lock()
+	defer p.Unlock()
+	<-p.slots
+	p.updateMetrics()
+}
+
+// dial connects and binds to the first available server.
+// Servers that are marked down are only tried if all other servers have failed.
+func (p *ldapConnPool) dial() (*pooledConn, error) {
+	var lastErr error
+	for _, server := range p.candidates() {
+		log.Log(log.Security).Debug("Attempting LDAP connection",
+			zap.String("address", server.url),
+			zap.Bool("ssl", p.config.useSsl),
+			zap.Bool("insecureSkipVerify", p.config.Insecure))
+		conn, err := p.access.DialURL(server.url,
+			ldap.DialWithTLSConfig(&tls.Config{InsecureSkipVerify: p.config.Insecure})) // #nosec G402
+		if err != nil {
+			log.Log(log.Security).Error("Error connecting to LDAP server",
+				zap.String("address", server.url),
+				zap.Error(err))
+			p.markDown(server, err)
+			lastErr = err
+			continue
+		}
+		log.Log(log.Security).Debug("LDAP connection successful, attempting bind",
+			zap.String("bindUser", p.config.BindUser))
+		err = p.access.Bind(conn, p.config.BindUser, p.config.BindPassword)
+		if err != nil {
+			log.Log(log.Security).Error("Failed to bind with LDAP server",
+				zap.String("address", server.url),
+				zap.String("bindDN", p.config.BindUser),
+				zap.Error(err))
+			p.access.Close(conn)
+			p.markDown(server, err)
+			lastErr = err
+			continue
+		}
+		p.markUp(server)
+		return &pooledConn{conn: conn, server: server.url}, nil
+	}
+	return nil, lastErr
+}
+
+// candidates returns the healthy servers in configuration order followed by the servers that are marked down
+func (p *ldapConnPool) candidates() []*ldapServer {
+	p.Lock()
+	defer p.Unlock()
+	now := time.Now()
+	healthy := make([]*ldapServer, 0, len(p.servers))
+	down := make([]*ldapServer, 0)
+	for _, server := range p.servers {
+		if now.Before(server.downUntil) {
+			down = append(down, server)
+		} else {
+			healthy = append(healthy, server)
+		}
+	}
+	return append(healthy, down...)
+}
+
+func (p *ldapConnPool) markDown(server *ldapServer, err error) {
+	p.Lock()
+	defer p.Unlock()
+	server.downUntil = time.Now().Add(p.interval)
+	log.Log(log.Security).Warn("LDAP server marked unhealthy",
+		zap.String("address", server.url),
+		zap.Stringer("retryAfter", p.interval),
+		zap.Error(err))
+	metrics.GetResolverMetrics().IncServerFailure(server.url)
+}
+
+func (p *ldapConnPool) markUp(server *ldapServer) {
+	p.Lock()
+	defer p.Unlock()
+	server.downUntil = time.Time{}
+}
+
+// close closes all idle connections, connections in use are closed when they are returned
+func (p *ldapConnPool) close() {
+	p.Lock()
+	defer p.Unlock()
+	for _, pc := range p.idle {
+		p.access.Close(pc.conn)
+	}
+	p.idle = nil
+	p.closed = true
+	p.updateMetrics()
+}
+
+// updateMetrics publishes the pool state, must be called while holding the lock
+func (p *ldapConnPool) updateMetrics() {
+	metrics.GetResolverMetrics().SetPoolConnections(len(p.idle), len(p.slots))
+}
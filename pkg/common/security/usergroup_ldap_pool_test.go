@@ -0,0 +1,236 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package security
+
+import (
+	"errors"
+	"os/user"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/metrics"
+)
+
+func TestLdapServerURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   LdapConfig
+		expected []string
+	}{
+		{"single host", LdapConfig{Host: "ldap1", Port: 389}, []string{"ldap://ldap1:389"}},
+		{"ssl", LdapConfig{Host: "ldap1", Port: 636, useSsl: true}, []string{"ldaps://ldap1:636"}},
+		{"host list", LdapConfig{Host: "ldap1, ldap2", Port: 389}, []string{"ldap://ldap1:389", "ldap://ldap2:389"}},
+		{"host with port", LdapConfig{Host: "ldap1,ldap2:1389", Port: 389}, []string{"ldap://ldap1:389", "ldap://ldap2:1389"}},
+		{"ipv6 with port", LdapConfig{Host: "[::1]:1389", Port: 389}, []string{"ldap://[::1]:1389"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, tt.expected, ldapServerURLs(tt.config))
+		})
+	}
+}
+
+func TestLdapPoolReuse(t *testing.T) {
+	metrics.GetResolverMetrics().Reset()
+	dials := 0
+	binds := 0
+	closes := 0
+	mockAccess := &LdapAccessMock{
+		DialURLFunc: func(url string, _ ...ldap.DialOpt) (*ldap.Conn, error) {
+			dials++
+			return &ldap.Conn{}, nil
+		},
+		BindFunc: func(conn *ldap.Conn, username, password string) error {
+			binds++
+			return nil
+		},
+		CloseFunc: func(conn *ldap.Conn) {
+			closes++
+		},
+		SearchResult: &ldap.SearchResult{},
+	}
+	pool := newLdapConnPool(mockAccess, LdapConfig{Host: "ldap1", Port: 389, PoolSize: 2})
+	for i := 0; i < 5; i++ {
+		_, err := pool.search(&ldap.SearchRequest{})
+		assert.NilError(t, err)
+	}
+	assert.Equal(t, 1, dials, "bound connection should have been reused")
+	assert.Equal(t, 1, binds, "bound connection should have been reused")
+	assert.Equal(t, 1, len(pool.idle))
+	idle, err := metrics.GetResolverMetrics().GetPoolConnections(metrics.PoolConnectionIdle)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, idle)
+	active, err := metrics.GetResolverMetrics().GetPoolConnections(metrics.PoolConnectionActive)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, active)
+
+	// non network failure: connection is discarded but not retried
+	mockAccess.Error = errors.New("search error")
+	_, err = pool.search(&ldap.SearchRequest{})
+	assert.Error(t, err, "search error")
+	assert.Equal(t, 1, closes)
+	assert.Equal(t, 0, len(pool.idle))
+
+	// closing the pool closes the idle connections
+	mockAccess.Error = nil
+	_, err = pool.search(&ldap.SearchRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, dials)
+	pool.close()
+	assert.Equal(t, 2, closes)
+	assert.Equal(t, 0, len(pool.idle))
+}
+
+func TestLdapPoolBounded(t *testing.T) {
+	saved := ldapPoolWaitTimeout
+	ldapPoolWaitTimeout = 10 * time.Millisecond
+	defer func() { ldapPoolWaitTimeout = saved }()
+
+	pool := newLdapConnPool(newMockLdapAccess(&ldap.SearchResult{}, nil), LdapConfig{Host: "ldap1", Port: 389, PoolSize: 2})
+	pc1, err := pool.get()
+	assert.NilError(t, err)
+	pc2, err := pool.get()
+	assert.NilError(t, err)
+	_, err = pool.get()
+	assert.ErrorContains(t, err, "timeout waiting for a free LDAP connection")
+	pool.put(pc1)
+	pc3, err := pool.get()
+	assert.NilError(t, err)
+	assert.Equal(t, pc1, pc3, "idle connection should have been returned")
+	pool.put(pc2)
+	pool.put(pc3)
+	assert.Equal(t, 2, len(pool.idle))
+}
+
+func TestLdapPoolFailover(t *testing.T) {
+	metrics.GetResolverMetrics().Reset()
+	var dialed []string
+	ldap1Down := true
+	mockAccess := &LdapAccessMock{
+		DialURLFunc: func(url string, _ ...ldap.DialOpt) (*ldap.Conn, error) {
+			dialed = append(dialed, url)
+			if url == "ldap://ldap1:389" && ldap1Down {
+				return nil, errors.New("connection refused")
+			}
+			return &ldap.Conn{}, nil
+		},
+		SearchResult: &ldap.SearchResult{},
+	}
+	pool := newLdapConnPool(mockAccess, LdapConfig{Host: "ldap1,ldap2", Port: 389, PoolSize: 1, FailoverInterval: time.Minute})
+	pc, err := pool.get()
+	assert.NilError(t, err)
+	assert.Equal(t, "ldap://ldap2:389", pc.server)
+	assert.DeepEqual(t, []string{"ldap://ldap1:389", "ldap://ldap2:389"}, dialed)
+	failures, err := metrics.GetResolverMetrics().GetServerFailures("ldap://ldap1:389")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, failures)
+
+	// the failed server is skipped while it is marked down
+	pool.discard(pc)
+	dialed = nil
+	ldap1Down = false
+	pc, err = pool.get()
+	assert.NilError(t, err)
+	assert.Equal(t, "ldap://ldap2:389", pc.server)
+	assert.DeepEqual(t, []string{"ldap://ldap2:389"}, dialed)
+
+	// after the interval the server is used again
+	pool.discard(pc)
+	pool.servers[0].downUntil = time.Now().Add(-time.Second)
+	dialed = nil
+	pc, err = pool.get()
+	assert.NilError(t, err)
+	assert.Equal(t, "ldap://ldap1:389", pc.server)
+	pool.put(pc)
+
+	// a broken idle connection is replaced
+	mockAccess.SearchFunc = func(conn *ldap.Conn, _ *ldap.SearchRequest) (*ldap.SearchResult, error) {
+		if conn == pc.conn {
+			return nil, ldap.NewError(ldap.ErrorNetwork, errors.New("connection closed"))
+		}
+		return &ldap.SearchResult{}, nil
+	}
+	_, err = pool.search(&ldap.SearchRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(pool.idle))
+	assert.Assert(t, pool.idle[0].conn != pc.conn, "broken connection should not be returned to the pool")
+
+	// all servers down: all are tried and the last error is returned
+	mockAccess.DialURLFunc = func(url string, _ ...ldap.DialOpt) (*ldap.Conn, error) {
+		return nil, errors.New("dial error " + url)
+	}
+	pool.idle = nil
+	_, err = pool.get()
+	assert.Error(t, err, "dial error ldap://ldap2:389")
+}
+
+func TestLdapNegativeCache(t *testing.T) {
+	metrics.GetResolverMetrics().Reset()
+	searches := 0
+	mockAccess := &LdapAccessMock{
+		SearchFunc: func(_ *ldap.Conn, _ *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			searches++
+			return nil, errors.New("ldap error")
+		},
+	}
+	lu := newLdapLookup(LdapConfig{Filter: "(cn=%s)", NegativeCacheTTL: time.Minute}, mockAccess)
+	u := &user.User{Username: "testuser"}
+	_, err := lu.LDAPLookupGroupIds(u)
+	assert.Error(t, err, "ldap error")
+	_, err = lu.LDAPLookupGroupIds(u)
+	assert.Error(t, err, "ldap error")
+	assert.Equal(t, 1, searches, "failure should have been served from the negative cache")
+	hits, err := metrics.GetResolverMetrics().GetLookups(metrics.LookupNegativeCacheHit)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, hits)
+	failed, err := metrics.GetResolverMetrics().GetLookups(metrics.LookupFailure)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, failed)
+	entries, err := metrics.GetResolverMetrics().GetNegativeCacheEntries()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, entries)
+
+	// expired entries are looked up again and replaced
+	lu.misses["testuser"] = ldapMiss{expires: time.Now().Add(-time.Second)}
+	mockAccess.SearchFunc = func(_ *ldap.Conn, _ *ldap.SearchRequest) (*ldap.SearchResult, error) {
+		searches++
+		return &ldap.SearchResult{}, nil
+	}
+	groups, err := lu.LDAPLookupGroupIds(u)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(groups))
+	assert.Equal(t, 2, searches)
+	// user without entries is a miss as well
+	groups, err = lu.LDAPLookupGroupIds(u)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(groups))
+	assert.Equal(t, 2, searches)
+
+	// negative caching disabled
+	lu = newLdapLookup(LdapConfig{Filter: "(cn=%s)"}, mockAccess)
+	_, err = lu.LDAPLookupGroupIds(u)
+	assert.NilError(t, err)
+	_, err = lu.LDAPLookupGroupIds(u)
+	assert.NilError(t, err)
+	assert.Equal(t, 4, searches)
+	assert.Equal(t, 0, len(lu.misses))
+}
@@ -19,7 +19,6 @@
 package security
 
 import (
-	"crypto/tls"
 	"fmt"
 	"os"
 	"os/user"
@@ -31,7 +30,9 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 )
 
 // This file contains the implementation of the LDAP resolver for user groups
@@ -39,6 +40,25 @@ import (
 type LdapLookup struct {
 	config LdapConfig
 	access LdapAccess
+	pool   *ldapConnPool
+	misses map[string]ldapMiss // negative cache: kept separate from the positive entries in the UserGroupCache
+
+	locking.Mutex
+}
+
+// ldapMiss is a cached failed or empty group lookup
+type ldapMiss struct {
+	expires time.Time
+	err     error
+}
+
+func newLdapLookup(config LdapConfig, access LdapAccess) *LdapLookup {
+	return &LdapLookup{
+		config: config,
+		access: access,
+		pool:   newLdapConnPool(access, config),
+		misses: make(map[string]ldapMiss),
+	}
 }
 
 // LdapAccess defines the interface for LDAP operations
@@ -148,6 +168,15 @@ func (configReaderImpl) ReadLdapConfig() (*LdapConfig, error) {
 	if ssl, ok := validSecrets[common.LdapSSL].(bool); ok {
 		ldapConf.useSsl = ssl
 	}
+	if poolSize, ok := validSecrets[common.LdapPoolSize].(int); ok {
+		ldapConf.PoolSize = poolSize
+	}
+	if negativeTTL, ok := validSecrets[common.LdapNegativeCacheTTL].(time.Duration); ok {
+		ldapConf.NegativeCacheTTL = negativeTTL
+	}
+	if failover, ok := validSecrets[common.LdapFailoverInterval].(time.Duration); ok {
+		ldapConf.FailoverInterval = failover
+	}
 
 	// Validate the entire configuration
 	validator := NewLdapValidator()
@@ -206,6 +235,10 @@ func getDefaultLdapConfig() *LdapConfig {
 		BindPassword: common.DefaultLdapBindPassword,
 		Insecure:     common.DefaultLdapInsecure,
 		useSsl:       common.DefaultLdapSSL,
+
+		PoolSize:         common.DefaultLdapPoolSize,
+		NegativeCacheTTL: common.DefaultLdapNegativeCacheTTL,
+		FailoverInterval: common.DefaultLdapFailoverInterval,
 	}
 }
 
@@ -234,7 +267,7 @@ func GetLdapAccess() LdapAccess {
 
 // LDAPResolverConfig holds the configuration for the LDAP resolver
 type LdapConfig struct {
-	Host         string
+	Host         string // comma separated list of servers in failover order, each with an optional port
 	Port         int
 	BaseDN       string
 	Filter       string
@@ -244,6 +277,10 @@ type LdapConfig struct {
 	BindPassword string
 	Insecure     bool
 	useSsl       bool
+
+	PoolSize         int           // maximum number of bound connections kept open
+	NegativeCacheTTL time.Duration // time to cache failed lookups, zero disables negative caching
+	FailoverInterval time.Duration // time an unreachable server is skipped
 }
 
 func GetUserGroupCacheLdap(reader ConfigReader, access LdapAccess) *UserGroupCache {
@@ -259,10 +296,7 @@ func GetUserGroupCacheLdap(reader ConfigReader, access LdapAccess) *UserGroupCac
 		panic("LDAP configuration not found or invalid")
 	}
 
-	ldapLookup := &LdapLookup{
-		config: *config,
-		access: access,
-	}
+	ldapLookup := newLdapLookup(*config, access)
 
 	return &UserGroupCache{
 		ugs:           map[string]*UserGroup{},
@@ -270,12 +304,13 @@ func GetUserGroupCacheLdap(reader ConfigReader, access LdapAccess) *UserGroupCac
 		lookup:        ldapLookup.LdapLookupUser,
 		lookupGroupID: ldapLookup.LdapLookupGroupID,
 		groupIds:      ldapLookup.LDAPLookupGroupIds,
+		closer:        ldapLookup.close,
 		stop:          make(chan struct{}),
 	}
 }
 
 // Default linux behaviour: a user is member of the primary group with the same name
-func (LdapLookup) LdapLookupUser(userName string) (*user.User, error) {
+func (*LdapLookup) LdapLookupUser(userName string) (*user.User, error) {
 	log.Log(log.Security).Debug("Performing LDAP user lookup",
 		zap.String("username", userName),
 		zap.String("defaultUID", common.DefaultLdapUserUID))
@@ -286,7 +321,7 @@ func (LdapLookup) LdapLookupUser(userName string) (*user.User, error) {
 	}, nil
 }
 
-func (LdapLookup) LdapLookupGroupID(gid string) (*user.Group, error) {
+func (*LdapLookup) LdapLookupGroupID(gid string) (*user.Group, error) {
 	log.Log(log.Security).Debug("Looking up LDAP group ID",
 		zap.String("groupID", gid))
 	group := user.Group{Gid: gid}
@@ -294,14 +329,33 @@ func (LdapLookup) LdapLookupGroupID(gid string) (*user.Group, error) {
 	return &group, nil
 }
 
-func (lu LdapLookup) LDAPLookupGroupIds(osUser *user.User) ([]string, error) {
-	sr, err := ldapSearch(lu.access, lu.config, osUser.Username)
+// LDAPLookupGroupIds returns the groups of the user from LDAP.
+// Failed lookups and users without any entry are cached in the negative cache. A fresh negative cache entry
+// is returned without contacting the LDAP server.
+func (lu *LdapLookup) LDAPLookupGroupIds(osUser *user.User) ([]string, error) {
+	if miss, ok := lu.getMiss(osUser.Username); ok {
+		metrics.GetResolverMetrics().IncLookupNegativeCacheHit()
+		log.Log(log.Security).Debug("LDAP group lookup served from negative cache",
+			zap.String("user", osUser.Username),
+			zap.Time("expires", miss.expires))
+		return nil, miss.err
+	}
+	start := time.Now()
+	sr, err := ldapSearch(lu.pool, lu.config, osUser.Username)
+	metrics.GetResolverMetrics().ObserveLookupLatency(start)
 	if err != nil {
 		log.Log(log.Security).Error("Failed to connect to LDAP for group lookup",
 			zap.String("user", osUser.Username),
 			zap.Error(err))
+		metrics.GetResolverMetrics().IncLookupFailure()
+		lu.addMiss(osUser.Username, err)
 		return nil, err
 	}
+	metrics.GetResolverMetrics().IncLookupSuccess()
+	if len(sr.Entries) == 0 {
+		lu.addMiss(osUser.Username, nil)
+		return nil, nil
+	}
 
 	var groups []string
 	for _, entry := range sr.Entries {
@@ -318,42 +372,49 @@ func (lu LdapLookup) LDAPLookupGroupIds(osUser *user.User) ([]string, error) {
 	return groups, nil
 }
 
-// ldapSearch performs an LDAP search for the specified username
-// This replaces the old LDAPConn_Bind function with a more testable approach
-func ldapSearch(ldapAccess LdapAccess, ldapConf LdapConfig, userName string) (*ldap.SearchResult, error) {
-	var ldapUri string
-	if ldapConf.useSsl {
-		ldapUri = "ldaps"
-	} else {
-		ldapUri = "ldap"
+// getMiss returns the negative cache entry for the user if it has not expired
+func (lu *LdapLookup) getMiss(userName string) (ldapMiss, bool) {
+	lu.Lock()
+	defer lu.Unlock()
+	miss, ok := lu.misses[userName]
+	if !ok {
+		return ldapMiss{}, false
 	}
-
-	ldapaddr := fmt.Sprintf("%s://%s:%d", ldapUri, ldapConf.Host, ldapConf.Port)
-	log.Log(log.Security).Debug("Attempting LDAP connection",
-		zap.String("address", ldapaddr),
-		zap.Bool("ssl", ldapConf.useSsl),
-		zap.Bool("insecureSkipVerify", ldapConf.Insecure))
-
-	l, err := ldapAccess.DialURL(ldapaddr,
-		ldap.DialWithTLSConfig(&tls.Config{InsecureSkipVerify: ldapConf.Insecure})) // #nosec G402
-	if err != nil {
-		log.Log(log.Security).Error("Error connecting to LDAP server",
-			zap.String("address", ldapaddr),
-			zap.Error(err))
-		return nil, err
+	if time.Now().After(miss.expires) {
+		delete(lu.misses, userName)
+		metrics.GetResolverMetrics().SetNegativeCacheEntries(len(lu.misses))
+		return ldapMiss{}, false
 	}
-	defer ldapAccess.Close(l)
+	return miss, true
+}
 
-	log.Log(log.Security).Debug("LDAP connection successful, attempting bind",
-		zap.String("bindUser", ldapConf.BindUser))
-	err = ldapAccess.Bind(l, ldapConf.BindUser, ldapConf.BindPassword)
-	if err != nil {
-		log.Log(log.Security).Error("Failed to bind with LDAP server",
-			zap.String("bindDN", ldapConf.BindUser),
-			zap.Error(err))
-		return nil, err
+// addMiss adds the user to the negative cache and removes all expired entries
+func (lu *LdapLookup) addMiss(userName string, err error) {
+	if lu.config.NegativeCacheTTL <= 0 {
+		return
+	}
+	lu.Lock()
+	defer lu.Unlock()
+	now := time.Now()
+	for key, miss := range lu.misses {
+		if now.After(miss.expires) {
+			delete(lu.misses, key)
+		}
 	}
+	lu.misses[userName] = ldapMiss{
+		expires: now.Add(lu.config.NegativeCacheTTL),
+		err:     err,
+	}
+	metrics.GetResolverMetrics().SetNegativeCacheEntries(len(lu.misses))
+}
+
+// close releases the pooled LDAP connections
+func (lu *LdapLookup) close() {
+	lu.pool.close()
+}
 
+// ldapSearch performs an LDAP search for the specified username using a pooled connection
+func ldapSearch(pool *ldapConnPool, ldapConf LdapConfig, userName string) (*ldap.SearchResult, error) {
 	filter := fmt.Sprintf(ldapConf.Filter, userName)
 	log.Log(log.Security).Debug("Executing LDAP search",
 		zap.String("baseDN", ldapConf.BaseDN),
@@ -367,7 +428,7 @@ func ldapSearch(ldapAccess LdapAccess, ldapConf LdapConfig, userName string) (*l
 		ldapConf.ReturnAttr,
 		nil,
 	)
-	sr, err := ldapAccess.Search(l, searchRequest)
+	sr, err := pool.search(searchRequest)
 	if err != nil {
 		log.Log(log.Security).Error("Failed to execute LDAP search query",
 			zap.String("filter", filter),
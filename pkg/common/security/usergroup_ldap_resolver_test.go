@@ -176,7 +176,7 @@ func TestLdapSearch(t *testing.T) {
 		Host: "testhost",
 		Port: 1234,
 	}
-	result, err := ldapSearch(mockAccess, ldapConf, "testuser")
+	result, err := ldapSearch(newLdapConnPool(mockAccess, ldapConf), ldapConf, "testuser")
 
 	// Verify results
 	assert.NilError(t, err)
@@ -195,7 +195,7 @@ func TestLdapSearch(t *testing.T) {
 		Port:   1234,
 		useSsl: true,
 	}
-	_, err = ldapSearch(mockAccess, ldapConf, "testuser")
+	_, err = ldapSearch(newLdapConnPool(mockAccess, ldapConf), ldapConf, "testuser")
 	assert.NilError(t, err)
 	assert.Equal(t, "ldaps://testhost:1234", savedUrl)
 }
@@ -245,7 +245,7 @@ func TestLdapSearchError(t *testing.T) {
 			}
 
 			// Call ldapSearch with the mock access
-			result, err := ldapSearch(mockAccess, LdapConfig{}, "testuser")
+			result, err := ldapSearch(newLdapConnPool(mockAccess, LdapConfig{}), LdapConfig{}, "testuser")
 
 			// Verify error
 			assert.Assert(t, err != nil)
@@ -330,10 +330,7 @@ func TestLDAPLookupGroupIds(t *testing.T) {
 	}
 
 	u := &user.User{Username: "testuser"}
-	lu := &LdapLookup{
-		access: newMockLdapAccess(mockResult, nil),
-		config: LdapConfig{},
-	}
+	lu := newLdapLookup(LdapConfig{}, newMockLdapAccess(mockResult, nil))
 
 	groups, err := lu.LDAPLookupGroupIds(u)
 	assert.NilError(t, err)
@@ -343,10 +340,7 @@ func TestLDAPLookupGroupIds(t *testing.T) {
 
 func TestLDAPLookupGroupIdsError(t *testing.T) {
 	u := &user.User{Username: "testuser"}
-	lu := &LdapLookup{
-		access: newMockLdapAccess(nil, errors.New("ldap error")),
-		config: LdapConfig{},
-	}
+	lu := newLdapLookup(LdapConfig{}, newMockLdapAccess(nil, errors.New("ldap error")))
 	groups, err := lu.LDAPLookupGroupIds(u)
 	assert.Error(t, err, "ldap error")
 	assert.Assert(t, groups == nil)
@@ -587,6 +581,40 @@ func getReadSecretsTestCases() []struct {
 				// No specific validation needed - we're testing the return value
 			},
 		},
+		{
+			name: "Failover servers and pool settings",
+			setupFunc: func(t *testing.T) (string, func()) {
+				tmpDir := t.TempDir()
+				fields := map[string]string{
+					common.LdapHost:             "ldap1.example.com,ldap2.example.com:1389",
+					common.LdapPort:             "389",
+					common.LdapBaseDN:           "dc=example,dc=com",
+					common.LdapFilter:           "(&(objectClass=user)(sAMAccountName=%s))",
+					common.LdapGroupAttr:        "memberOf",
+					common.LdapReturnAttr:       "memberOf",
+					common.LdapBindUser:         "cn=admin,dc=example,dc=com",
+					common.LdapBindPassword:     "password",
+					common.LdapPoolSize:         "8",
+					common.LdapNegativeCacheTTL: "10s",
+					common.LdapFailoverInterval: "2m",
+				}
+				for key, value := range fields {
+					err := os.WriteFile(filepath.Join(tmpDir, key), []byte(value), 0600)
+					assert.NilError(t, err)
+				}
+				origLdapMountPath := common.LdapMountPath
+				common.LdapMountPath = tmpDir
+				return tmpDir, func() { common.LdapMountPath = origLdapMountPath }
+			},
+			expectedResult: true,
+			validateFunc: func(t *testing.T, conf *LdapConfig) {
+				assert.Equal(t, "ldap1.example.com,ldap2.example.com:1389", conf.Host)
+				assert.Equal(t, 8, conf.PoolSize)
+				assert.Equal(t, 10*time.Second, conf.NegativeCacheTTL)
+				assert.Equal(t, 2*time.Minute, conf.FailoverInterval)
+				assert.DeepEqual(t, []string{"ldap://ldap1.example.com:389", "ldap://ldap2.example.com:1389"}, ldapServerURLs(*conf))
+			},
+		},
 	}
 }
 
@@ -73,6 +73,12 @@ var ldapResolver = configs.UserGroupResolver{
 	Type: "ldap",
 }
 
+// UserGroupResolver Config for the file resolver
+var fileResolverConfig = configs.UserGroupResolver{
+	Type: "file",
+	File: "/nonexistent/yunikorn/groups.yaml",
+}
+
 // UserGroupResolver Config for the LDAP resolver
 var defResolver = configs.UserGroupResolver{}
 
@@ -101,6 +107,10 @@ func TestGetUserGroupCache(t *testing.T) {
 			name:     "DefaultResolver",
 			resolver: defResolver,
 		},
+		{
+			name:     "FileResolver",
+			resolver: fileResolverConfig,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -28,7 +28,9 @@ import (
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
@@ -43,16 +45,28 @@ type NonBlockingGRPCServer interface {
 	Stop()
 	// Stops the service forcefully
 	ForceStop()
+	// Updates the TLS files used by a running service
+	UpdateTLSFiles(files TLSFiles)
 }
 
 func NewNonBlockingGRPCServer() NonBlockingGRPCServer {
 	return &nonBlockingGRPCServer{}
 }
 
+// NewNonBlockingGRPCServerWithTLS returns a server that only accepts TLS connections.
+// Clients must present a certificate signed by the client CA if one is configured.
+func NewNonBlockingGRPCServerWithTLS(files TLSFiles) NonBlockingGRPCServer {
+	return &nonBlockingGRPCServer{tlsFiles: files}
+}
+
 // NonBlocking server
 type nonBlockingGRPCServer struct {
-	wg     sync.WaitGroup
-	server *grpc.Server
+	wg       sync.WaitGroup
+	server   *grpc.Server
+	tlsFiles TLSFiles
+	reloader *CertReloader
+
+	locking.Mutex
 }
 
 func (s *nonBlockingGRPCServer) Start(endpoint string, ss si.SchedulerServer) {
@@ -67,10 +81,51 @@ func (s *nonBlockingGRPCServer) Wait() {
 
 func (s *nonBlockingGRPCServer) Stop() {
 	s.server.GracefulStop()
+	s.stopReloader()
 }
 
 func (s *nonBlockingGRPCServer) ForceStop() {
 	s.server.Stop()
+	s.stopReloader()
+}
+
+func (s *nonBlockingGRPCServer) stopReloader() {
+	s.Lock()
+	defer s.Unlock()
+	if s.reloader != nil {
+		s.reloader.Stop()
+		s.reloader = nil
+	}
+}
+
+// UpdateTLSFiles switches the running server to the new certificate files without a restart.
+// The transport cannot change while running: TLS is never disabled, enabling TLS requires a restart.
+func (s *nonBlockingGRPCServer) UpdateTLSFiles(files TLSFiles) {
+	s.Lock()
+	defer s.Unlock()
+	if files == s.tlsFiles {
+		return
+	}
+	switch {
+	case s.reloader == nil && files.Enabled():
+		log.Log(log.RPC).Warn("TLS configured for a running server without TLS, restart required to enable TLS",
+			zap.String("certFile", files.CertFile))
+	case s.reloader == nil:
+		s.tlsFiles = files
+	case !files.Enabled():
+		log.Log(log.RPC).Warn("TLS cannot be disabled for a running server, keeping current certificates",
+			zap.String("certFile", s.tlsFiles.CertFile))
+	default:
+		if err := s.reloader.SetFiles(files); err != nil {
+			log.Log(log.RPC).Error("failed to load new TLS certificates, keeping current certificates",
+				zap.String("certFile", files.CertFile),
+				zap.Error(err))
+			return
+		}
+		s.tlsFiles = files
+		log.Log(log.RPC).Info("TLS certificate files updated",
+			zap.String("certFile", files.CertFile))
+	}
 }
 
 func ParseEndpoint(ep string) (string, string, error) {
@@ -126,15 +181,29 @@ func (s *nonBlockingGRPCServer) serve(endpoint string, ss si.SchedulerServer) {
 			zap.Error(err))
 	}
 
-	server := grpc.NewServer(withServerUnaryInterceptor())
+	opts := []grpc.ServerOption{withServerUnaryInterceptor()}
+	s.Lock()
+	tlsFiles := s.tlsFiles
+	if tlsFiles.Enabled() {
+		s.reloader, err = NewCertReloader(tlsFiles)
+		if err != nil {
+			log.Log(log.RPC).Fatal("failed to load TLS certificates",
+				zap.String("certFile", tlsFiles.CertFile),
+				zap.Error(err))
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.reloader.ServerConfig(true, "h2"))))
+	}
+	server := grpc.NewServer(opts...)
 	s.server = server
+	s.Unlock()
 
 	if ss != nil {
 		si.RegisterSchedulerServer(server, ss)
 	}
 
 	log.Log(log.RPC).Info("listening for connections",
-		zap.Stringer("address", listener.Addr()))
+		zap.Stringer("address", listener.Addr()),
+		zap.Bool("tls", tlsFiles.Enabled()))
 
 	if err = server.Serve(listener); err != nil {
 		log.Log(log.RPC).Fatal("failed to serve", zap.Error(err))
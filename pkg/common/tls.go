@@ -0,0 +1,225 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package common
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+// interval between checks for changed certificate files, var to allow testing
+var certCheckInterval = 10 * time.Second
+
+// TLSFiles defines the files used to configure TLS for a server
+type TLSFiles struct {
+	CertFile     string // PEM encoded certificate chain
+	KeyFile      string // PEM encoded private key
+	ClientCAFile string // optional PEM encoded CA bundle to verify client certificates
+}
+
+// Enabled returns true if a certificate and key are configured
+func (f TLSFiles) Enabled() bool {
+	return f.CertFile != "" && f.KeyFile != ""
+}
+
+// CertReloader keeps the server certificate and client CA pool loaded from disk.
+// The files are checked for changes in the background: a changed certificate is used for new
+// connections without a restart. Files that fail to load leave the previous certificates in place.
+type CertReloader struct {
+	files     TLSFiles
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+	stop      chan struct{}
+
+	locking.RWMutex
+}
+
+// NewCertReloader loads the configured files and starts watching them for changes.
+// Returns an error if TLS is not enabled or the files cannot be loaded.
+func NewCertReloader(files TLSFiles) (*CertReloader, error) {
+	if !files.Enabled() {
+		return nil, errors.New("TLS certificate and key file must both be set")
+	}
+	r := &CertReloader{
+		files:    files,
+		modTimes: make(map[string]time.Time),
+		stop:     make(chan struct{}),
+	}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	go r.watch()
+	return r, nil
+}
+
+// ServerConfig returns the TLS configuration for a server using the current certificates.
+// If a client CA is configured client certificates are verified, requireClientCert decides if a
+// client must present a certificate or if it is only verified when presented.
+// The config is created per connection: the application protocols supported by the server must be
+// passed in as changes made by the server to the returned config are not used.
+func (r *CertReloader) ServerConfig(requireClientCert bool, nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+			r.RLock()
+			defer r.RUnlock()
+			conf := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*r.cert},
+			}
+			if r.clientCAs != nil {
+				conf.ClientCAs = r.clientCAs
+				conf.ClientAuth = tls.VerifyClientCertIfGiven
+				if requireClientCert {
+					conf.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return conf, nil
+		},
+	}
+}
+
+// Stop stops watching the files for changes
+func (r *CertReloader) Stop() {
+	close(r.stop)
+}
+
+func (r *CertReloader) watch() {
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-time.After(certCheckInterval):
+			if _, err := r.reload(); err != nil {
+				log.Log(log.Security).Warn("Failed to reload TLS certificates, keeping previous certificates",
+					zap.String("certFile", r.getFiles().CertFile),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+func (r *CertReloader) getFiles() TLSFiles {
+	r.RLock()
+	defer r.RUnlock()
+	return r.files
+}
+
+// SetFiles switches to a new set of files, used when the configured paths change.
+// The new files are loaded immediately: on failure the previous files and certificates stay in use.
+func (r *CertReloader) SetFiles(files TLSFiles) error {
+	if !files.Enabled() {
+		return errors.New("TLS certificate and key file must both be set")
+	}
+	if r.getFiles() == files {
+		return nil
+	}
+	modTimes, err := getModTimes(files)
+	if err != nil {
+		return err
+	}
+	return r.load(files, modTimes, false)
+}
+
+// reload loads the files if any of them has changed since the last load.
+// Returns true if new certificates were loaded.
+func (r *CertReloader) reload() (bool, error) {
+	files := r.getFiles()
+	modTimes, err := getModTimes(files)
+	if err != nil {
+		return false, err
+	}
+	r.RLock()
+	unchanged := len(modTimes) == len(r.modTimes)
+	for path, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[path]) {
+			unchanged = false
+		}
+	}
+	r.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	if err = r.load(files, modTimes, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// getModTimes returns the modification time of each configured file
+func getModTimes(files TLSFiles) (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, path := range []string{files.CertFile, files.KeyFile, files.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[path] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// load reads the certificates from the files and makes them the current certificates.
+// If onlyCurrent is set the result is dropped when the files were switched while loading.
+func (r *CertReloader) load(files TLSFiles, modTimes map[string]time.Time, onlyCurrent bool) error {
+	cert, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+	if err != nil {
+		return err
+	}
+	var clientCAs *x509.CertPool
+	if files.ClientCAFile != "" {
+		var pem []byte
+		pem, err = os.ReadFile(files.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client CA file %s", files.ClientCAFile)
+		}
+	}
+	r.Lock()
+	defer r.Unlock()
+	if onlyCurrent && r.files != files {
+		return nil
+	}
+	r.files = files
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	log.Log(log.Security).Info("Loaded TLS certificates",
+		zap.String("certFile", files.CertFile),
+		zap.Bool("clientVerification", clientCAs != nil))
+	return nil
+}
@@ -0,0 +1,244 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// writeTestCert writes a self-signed certificate and key for localhost to the directory.
+// Returns the certificate, certificate file and key file.
+func writeTestCert(t *testing.T, dir, name string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	assert.NilError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NilError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return cert, certFile, keyFile
+}
+
+func TestTLSFilesEnabled(t *testing.T) {
+	assert.Assert(t, !TLSFiles{}.Enabled())
+	assert.Assert(t, !TLSFiles{CertFile: "cert"}.Enabled())
+	assert.Assert(t, !TLSFiles{KeyFile: "key", ClientCAFile: "ca"}.Enabled())
+	assert.Assert(t, TLSFiles{CertFile: "cert", KeyFile: "key"}.Enabled())
+}
+
+func TestNewCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	_, certFile, keyFile := writeTestCert(t, dir, "server")
+	_, err := NewCertReloader(TLSFiles{})
+	assert.ErrorContains(t, err, "must both be set")
+	_, err = NewCertReloader(TLSFiles{CertFile: certFile, KeyFile: filepath.Join(dir, "missing")})
+	assert.Assert(t, err != nil, "missing key should fail")
+	_, err = NewCertReloader(TLSFiles{CertFile: keyFile, KeyFile: keyFile})
+	assert.Assert(t, err != nil, "invalid certificate should fail")
+	_, err = NewCertReloader(TLSFiles{CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile})
+	assert.ErrorContains(t, err, "no certificates found in client CA file")
+
+	r, err := NewCertReloader(TLSFiles{CertFile: certFile, KeyFile: keyFile})
+	assert.NilError(t, err)
+	defer r.Stop()
+	conf, err := r.ServerConfig(true).GetConfigForClient(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(conf.Certificates))
+	assert.Equal(t, tls.NoClientCert, conf.ClientAuth, "client certificates should not be checked without CA")
+
+	r2, err := NewCertReloader(TLSFiles{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile})
+	assert.NilError(t, err)
+	defer r2.Stop()
+	conf, err = r2.ServerConfig(true, "h2").GetConfigForClient(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, conf.ClientAuth)
+	assert.DeepEqual(t, []string{"h2"}, conf.NextProtos)
+	conf, err = r2.ServerConfig(false).GetConfigForClient(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, conf.ClientAuth)
+}
+
+func TestCertReload(t *testing.T) {
+	dir := t.TempDir()
+	first, certFile, keyFile := writeTestCert(t, dir, "server")
+	r, err := NewCertReloader(TLSFiles{CertFile: certFile, KeyFile: keyFile})
+	assert.NilError(t, err)
+	defer r.Stop()
+	changed, err := r.reload()
+	assert.NilError(t, err)
+	assert.Assert(t, !changed, "unchanged files should not be reloaded")
+
+	// replace the files and move the modification time to make sure the change is detected
+	second, _, _ := writeTestCert(t, dir, "server")
+	future := time.Now().Add(time.Minute)
+	assert.NilError(t, os.Chtimes(certFile, future, future))
+	assert.NilError(t, os.Chtimes(keyFile, future, future))
+	changed, err = r.reload()
+	assert.NilError(t, err)
+	assert.Assert(t, changed, "changed files should be reloaded")
+	conf, err := r.ServerConfig(false).GetConfigForClient(nil)
+	assert.NilError(t, err)
+	assert.Assert(t, !first.Equal(second))
+	assert.DeepEqual(t, second.Raw, conf.Certificates[0].Certificate[0])
+
+	// broken files keep the previous certificate
+	assert.NilError(t, os.WriteFile(certFile, []byte("broken"), 0o600))
+	_, err = r.reload()
+	assert.Assert(t, err != nil, "broken certificate should fail")
+	conf, err = r.ServerConfig(false).GetConfigForClient(nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, second.Raw, conf.Certificates[0].Certificate[0])
+}
+
+func TestCertReloaderSetFiles(t *testing.T) {
+	dir := t.TempDir()
+	first, certFile, keyFile := writeTestCert(t, dir, "server")
+	second, otherCertFile, otherKeyFile := writeTestCert(t, dir, "other")
+	files := TLSFiles{CertFile: certFile, KeyFile: keyFile}
+	r, err := NewCertReloader(files)
+	assert.NilError(t, err)
+	defer r.Stop()
+
+	assert.ErrorContains(t, r.SetFiles(TLSFiles{}), "must both be set")
+	assert.NilError(t, r.SetFiles(files), "unchanged files should be ignored")
+	err = r.SetFiles(TLSFiles{CertFile: otherCertFile, KeyFile: filepath.Join(dir, "missing")})
+	assert.Assert(t, err != nil, "missing key should fail")
+	assert.Equal(t, files, r.getFiles(), "failed switch should keep the files")
+	conf, err := r.ServerConfig(false).GetConfigForClient(nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, first.Raw, conf.Certificates[0].Certificate[0])
+
+	other := TLSFiles{CertFile: otherCertFile, KeyFile: otherKeyFile}
+	assert.NilError(t, r.SetFiles(other))
+	assert.Equal(t, other, r.getFiles())
+	conf, err = r.ServerConfig(false).GetConfigForClient(nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, second.Raw, conf.Certificates[0].Certificate[0])
+}
+
+func TestUpdateTLSFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, certFile, keyFile := writeTestCert(t, dir, "server")
+	_, otherCertFile, otherKeyFile := writeTestCert(t, dir, "other")
+	files := TLSFiles{CertFile: certFile, KeyFile: keyFile}
+	other := TLSFiles{CertFile: otherCertFile, KeyFile: otherKeyFile}
+
+	// plain text server cannot switch to TLS
+	plain := &nonBlockingGRPCServer{}
+	plain.UpdateTLSFiles(files)
+	assert.Equal(t, TLSFiles{}, plain.tlsFiles)
+
+	reloader, err := NewCertReloader(files)
+	assert.NilError(t, err)
+	s := &nonBlockingGRPCServer{tlsFiles: files, reloader: reloader}
+	defer s.stopReloader()
+	// TLS cannot be turned off
+	s.UpdateTLSFiles(TLSFiles{})
+	assert.Equal(t, files, s.tlsFiles)
+	assert.Equal(t, files, reloader.getFiles())
+	// broken files keep the current files
+	s.UpdateTLSFiles(TLSFiles{CertFile: otherCertFile, KeyFile: certFile})
+	assert.Equal(t, files, s.tlsFiles)
+	assert.Equal(t, files, reloader.getFiles())
+	s.UpdateTLSFiles(other)
+	assert.Equal(t, other, s.tlsFiles)
+	assert.Equal(t, other, reloader.getFiles())
+}
+
+func TestCertReloaderHandshake(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, certFile, keyFile := writeTestCert(t, dir, "server")
+	_, clientCertFile, clientKeyFile := writeTestCert(t, dir, "client")
+	_, otherCertFile, otherKeyFile := writeTestCert(t, dir, "other")
+	r, err := NewCertReloader(TLSFiles{CertFile: certFile, KeyFile: keyFile, ClientCAFile: clientCertFile})
+	assert.NilError(t, err)
+	defer r.Stop()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", r.ServerConfig(true))
+	assert.NilError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			// complete the handshake and close, the result is checked on the client side
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCert)
+	dial := func(certFile, keyFile string) error {
+		conf := &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12}
+		if certFile != "" {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			assert.NilError(t, err)
+			conf.Certificates = []tls.Certificate{cert}
+		}
+		conn, err := tls.Dial("tcp", listener.Addr().String(), conf)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		// TLS 1.3 reports client certificate failures on the first read
+		_, err = conn.Read(make([]byte, 1))
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	assert.NilError(t, dial(clientCertFile, clientKeyFile))
+	assert.Assert(t, dial("", "") != nil, "client without certificate should be rejected")
+	assert.Assert(t, dial(otherCertFile, otherKeyFile) != nil, "client with unknown certificate should be rejected")
+}
@@ -175,6 +175,22 @@ func GetRequiredNodeFromTag(tags map[string]string) string {
 	return ""
 }
 
+// GetExpectedDurationFromTag returns the expected run time of an allocation as set in the tags.
+// The value must be a positive duration (e.g. "10m"), any other value is ignored and zero is returned.
+func GetExpectedDurationFromTag(tags map[string]string) time.Duration {
+	value, ok := tags[interfaceCommon.DomainYuniKorn+KeyExpectedDuration]
+	if !ok || value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Log(log.Utils).Debug("expected duration tag has illegal value, ignoring",
+			zap.String("value", value))
+		return 0
+	}
+	return duration
+}
+
 func IsAllowPreemptSelf(policy *si.PreemptionPolicy) bool {
 	return policy == nil || policy.AllowPreemptSelf
 }
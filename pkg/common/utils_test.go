@@ -151,6 +151,20 @@ func TestGetRequiredNodeFromAsk(t *testing.T) {
 	assert.Equal(t, nodeName, "Node2")
 }
 
+func TestGetExpectedDurationFromTag(t *testing.T) {
+	tag := make(map[string]string)
+	assert.Equal(t, GetExpectedDurationFromTag(nil), time.Duration(0))
+	assert.Equal(t, GetExpectedDurationFromTag(tag), time.Duration(0))
+	tag[common.DomainYuniKorn+KeyExpectedDuration] = "invalid"
+	assert.Equal(t, GetExpectedDurationFromTag(tag), time.Duration(0))
+	tag[common.DomainYuniKorn+KeyExpectedDuration] = "-5m"
+	assert.Equal(t, GetExpectedDurationFromTag(tag), time.Duration(0))
+	tag[common.DomainYuniKorn+KeyExpectedDuration] = "0s"
+	assert.Equal(t, GetExpectedDurationFromTag(tag), time.Duration(0))
+	tag[common.DomainYuniKorn+KeyExpectedDuration] = "90s"
+	assert.Equal(t, GetExpectedDurationFromTag(tag), 90*time.Second)
+}
+
 func TestIsAllowPreemptSelf(t *testing.T) {
 	assert.Check(t, IsAllowPreemptSelf(nil), "Nil policy should allow preempt of self")
 	assert.Check(t, IsAllowPreemptSelf(&si.PreemptionPolicy{AllowPreemptSelf: true}), "Preempt self should be allowed if policy allows")
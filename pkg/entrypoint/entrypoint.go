@@ -100,7 +100,7 @@ func startAllServicesWithParameters(opts startupOptions) *ServiceContext {
 	if opts.metricsHistorySize != 0 {
 		log.Log(log.Entrypoint).Info("creating InternalMetricsHistory")
 		imHistory = history.NewInternalMetricsHistory(opts.metricsHistorySize)
-		metricsCollector := metrics.NewInternalMetricsCollector(imHistory)
+		metricsCollector := metrics.NewInternalMetricsCollector(imHistory, sched.GetClusterContext().GetResourceHistorySample)
 		metricsCollector.StartService()
 		context.MetricsCollector = metricsCollector
 	}
@@ -36,6 +36,7 @@ type eventPublisher struct {
 	pushEventInterval time.Duration
 	stopCh            chan struct{}
 	stopped           atomic.Bool
+	lastRun           atomic.Int64 // unix nano of the last completed push, used to detect a stuck publisher
 }
 
 func createShimPublisher(store *EventStore) *eventPublisher {
@@ -55,6 +56,7 @@ func (sp *eventPublisher) start() {
 		return
 	}
 	sp.stopCh = make(chan struct{})
+	sp.lastRun.Store(time.Now().UnixNano())
 	go func() {
 		for {
 			select {
@@ -70,6 +72,7 @@ func (sp *eventPublisher) start() {
 						eventPlugin.SendEvent(messages)
 					}
 				}
+				sp.lastRun.Store(time.Now().UnixNano())
 			}
 		}
 	}()
@@ -86,6 +89,14 @@ func (sp *eventPublisher) stop() {
 	sp.stopCh = nil
 }
 
+// getLastRun returns the time the publisher last finished pushing events, zero if it is not running.
+func (sp *eventPublisher) getLastRun() time.Time {
+	if sp.stopped.Load() {
+		return time.Time{}
+	}
+	return time.Unix(0, sp.lastRun.Load())
+}
+
 func (sp *eventPublisher) getEventStore() *EventStore {
 	return sp.store
 }
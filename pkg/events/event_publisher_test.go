@@ -101,6 +101,20 @@ func TestNoFillWithoutEventPluginRegistered(t *testing.T) {
 	assert.NilError(t, err, "the Publisher should erase the store even if no EventPlugin registered")
 }
 
+func TestPublisherLastRun(t *testing.T) {
+	publisher := createShimPublisher(newEventStore(1000))
+	assert.Assert(t, publisher.getLastRun().IsZero(), "stopped publisher should not have a last run")
+	publisher.pushEventInterval = time.Millisecond
+	publisher.start()
+	defer publisher.stop()
+	started := publisher.getLastRun()
+	assert.Assert(t, !started.IsZero(), "started publisher should have a last run")
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return publisher.getLastRun().After(started)
+	})
+	assert.NilError(t, err, "last run should be updated by the publisher")
+}
+
 // we push an event to the publisher, and check that the same event
 // is published by observing the mocked EventPlugin
 func TestPublisherSendsEvent(t *testing.T) {
@@ -78,6 +78,10 @@ type EventSystem interface {
 
 	// GetEventStreams returns the current active event streams.
 	GetEventStreams() []EventStreamData
+
+	// GetPublisherLastRun returns the time the shim publisher last finished pushing events.
+	// A zero time is returned if the publisher is not running.
+	GetPublisherLastRun() time.Time
 }
 
 // GetEventSystem returns the event system instance. Initialization happens during the first call.
@@ -189,6 +193,16 @@ func (ec *EventSystemImpl) GetEventStreams() []EventStreamData {
 	return ec.streaming.GetEventStreams()
 }
 
+// GetPublisherLastRun returns the time the shim publisher last finished pushing events. See the interface for details.
+func (ec *EventSystemImpl) GetPublisherLastRun() time.Time {
+	ec.RLock()
+	defer ec.RUnlock()
+	if ec.publisher == nil {
+		return time.Time{}
+	}
+	return ec.publisher.getLastRun()
+}
+
 // AddEvent adds an event record to the event system. See the interface for details.
 func (ec *EventSystemImpl) AddEvent(event *si.EventRecord) {
 	if event != nil {
@@ -19,6 +19,8 @@
 package mock
 
 import (
+	"time"
+
 	"github.com/apache/yunikorn-core/pkg/events"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
@@ -59,6 +61,10 @@ func (m *EventSystem) GetEventStreams() []events.EventStreamData {
 	return nil
 }
 
+func (m *EventSystem) GetPublisherLastRun() time.Time {
+	return time.Time{}
+}
+
 func NewEventSystem() *EventSystem {
 	return &EventSystem{Events: make([]*si.EventRecord, 0), enabled: true}
 }
@@ -0,0 +1,146 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MaxLevelOverrideTTL limits the time a temporary level override can be active
+const MaxLevelOverrideTTL = 24 * time.Hour
+
+// LoggerInfo describes a defined logger and its effective level.
+// The expiry is only set if the level of the logger itself is temporarily overridden.
+type LoggerInfo struct {
+	Name   string
+	Level  string
+	Expiry time.Time
+}
+
+type levelOverride struct {
+	level  zapcore.Level
+	expiry time.Time
+	timer  *time.Timer
+}
+
+// overrides and the last configuration are needed to rebuild the loggers when an override is set or expires
+var (
+	overrides    = make(map[string]*levelOverride)
+	lastConfig   map[string]string
+	overrideLock sync.Mutex
+)
+
+// GetLoggers returns all defined loggers with their effective level, ordered by logger id.
+func GetLoggers() []LoggerInfo {
+	once.Do(initLogger)
+	overrideLock.Lock()
+	defer overrideLock.Unlock()
+	conf := currentLoggerConfig.Load()
+	result := make([]LoggerInfo, 0, len(loggers))
+	for i, handle := range loggers {
+		info := LoggerInfo{
+			Name:  handle.name,
+			Level: conf.levels[i].CapitalString(),
+		}
+		if override, ok := overrides[handle.name]; ok {
+			info.Expiry = override.expiry
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+// SetLoggerLevel temporarily sets the level of a defined logger and its child loggers without an override or
+// configured level. The level reverts to the configured level once the TTL expires. Setting a level for a logger
+// with an active override replaces the override and its expiry. Returns the expiry time of the override.
+func SetLoggerLevel(name, level string, ttl time.Duration) (time.Time, error) {
+	if !isDefinedLogger(name) {
+		return time.Time{}, fmt.Errorf("unknown logger: %s", name)
+	}
+	levelRef := parseLevel(level)
+	if levelRef == nil {
+		return time.Time{}, fmt.Errorf("invalid log level: %s", level)
+	}
+	if ttl <= 0 || ttl > MaxLevelOverrideTTL {
+		return time.Time{}, fmt.Errorf("TTL must be larger than 0 and at most %s: %s", MaxLevelOverrideTTL, ttl)
+	}
+	once.Do(initLogger)
+	overrideLock.Lock()
+	defer overrideLock.Unlock()
+	if current, ok := overrides[name]; ok {
+		current.timer.Stop()
+	}
+	override := &levelOverride{
+		level:  *levelRef,
+		expiry: time.Now().Add(ttl),
+	}
+	override.timer = time.AfterFunc(ttl, func() {
+		expireOverride(name, override)
+	})
+	overrides[name] = override
+	initLoggingConfig(lastConfig)
+	Log(Core).Info("Logger level temporarily overridden",
+		zap.String("logger", name),
+		zap.Stringer("level", override.level),
+		zap.Time("expiry", override.expiry))
+	return override.expiry, nil
+}
+
+// ResetLoggerLevel removes the temporary level override of the logger.
+// Returns false if the logger has no override.
+func ResetLoggerLevel(name string) bool {
+	once.Do(initLogger)
+	overrideLock.Lock()
+	defer overrideLock.Unlock()
+	override, ok := overrides[name]
+	if !ok {
+		return false
+	}
+	override.timer.Stop()
+	delete(overrides, name)
+	initLoggingConfig(lastConfig)
+	Log(Core).Info("Logger level override removed", zap.String("logger", name))
+	return true
+}
+
+// expireOverride removes the override if it has not been replaced or removed since it was set
+func expireOverride(name string, override *levelOverride) {
+	overrideLock.Lock()
+	defer overrideLock.Unlock()
+	if overrides[name] != override {
+		return
+	}
+	delete(overrides, name)
+	initLoggingConfig(lastConfig)
+	Log(Core).Info("Logger level override expired", zap.String("logger", name))
+}
+
+func isDefinedLogger(name string) bool {
+	for _, handle := range loggers {
+		if handle.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package log
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gotest.tools/v3/assert"
+)
+
+func getLoggerLevel(t *testing.T, name string) LoggerInfo {
+	for _, info := range GetLoggers() {
+		if info.Name == name {
+			return info
+		}
+	}
+	t.Fatalf("logger not found: %s", name)
+	return LoggerInfo{}
+}
+
+func TestSetLoggerLevel(t *testing.T) {
+	UpdateLoggingConfig(map[string]string{"log.level": "INFO"})
+	defer UpdateLoggingConfig(map[string]string{})
+
+	expiry, err := SetLoggerLevel(SchedPreemption.name, "debug", time.Minute)
+	assert.NilError(t, err)
+	assert.Assert(t, expiry.After(time.Now()), "expiry should be in the future")
+	defer ResetLoggerLevel(SchedPreemption.name)
+
+	info := getLoggerLevel(t, SchedPreemption.name)
+	assert.Equal(t, "DEBUG", info.Level)
+	assert.Equal(t, expiry, info.Expiry)
+	// child loggers inherit the override, parents do not
+	info = getLoggerLevel(t, SchedQuotaChangePreemption.name)
+	assert.Equal(t, "DEBUG", info.Level)
+	assert.Assert(t, info.Expiry.IsZero(), "child logger is not overridden itself")
+	assert.Equal(t, "INFO", getLoggerLevel(t, Scheduler.name).Level)
+	assert.Assert(t, Log(SchedPreemption).Core().Enabled(zapcore.DebugLevel), "debug should be enabled")
+	assert.Assert(t, !Log(Scheduler).Core().Enabled(zapcore.DebugLevel), "debug should not be enabled")
+
+	// a config update keeps the override
+	UpdateLoggingConfig(map[string]string{"log.level": "WARN", "log.core.scheduler.preemption.level": "ERROR"})
+	assert.Equal(t, "DEBUG", getLoggerLevel(t, SchedPreemption.name).Level)
+	assert.Equal(t, "WARN", getLoggerLevel(t, Scheduler.name).Level)
+
+	// reset reverts to the configured level
+	assert.Assert(t, ResetLoggerLevel(SchedPreemption.name), "override should have been removed")
+	assert.Assert(t, !ResetLoggerLevel(SchedPreemption.name), "override should not exist")
+	info = getLoggerLevel(t, SchedPreemption.name)
+	assert.Equal(t, "ERROR", info.Level)
+	assert.Assert(t, info.Expiry.IsZero(), "no expiry expected without override")
+}
+
+func TestLoggerLevelExpiry(t *testing.T) {
+	UpdateLoggingConfig(map[string]string{})
+	_, err := SetLoggerLevel(REST.name, "DEBUG", 50*time.Millisecond)
+	assert.NilError(t, err)
+	assert.Equal(t, "DEBUG", getLoggerLevel(t, REST.name).Level)
+	// the log package cannot use the common wait helpers: import cycle
+	deadline := time.Now().Add(time.Second)
+	for getLoggerLevel(t, REST.name).Level != "INFO" {
+		assert.Assert(t, time.Now().Before(deadline), "override did not expire")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// replacing an override extends it: the first timer must not remove the second override
+	_, err = SetLoggerLevel(REST.name, "DEBUG", 50*time.Millisecond)
+	assert.NilError(t, err)
+	_, err = SetLoggerLevel(REST.name, "WARN", time.Minute)
+	assert.NilError(t, err)
+	defer ResetLoggerLevel(REST.name)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "WARN", getLoggerLevel(t, REST.name).Level)
+}
+
+func TestSetLoggerLevelInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		logger string
+		level  string
+		ttl    time.Duration
+	}{
+		{"unknown logger", "core.unknown", "DEBUG", time.Minute},
+		{"empty logger", "", "DEBUG", time.Minute},
+		{"invalid level", REST.name, "verbose", time.Minute},
+		{"zero TTL", REST.name, "DEBUG", 0},
+		{"TTL too long", REST.name, "DEBUG", MaxLevelOverrideTTL + time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SetLoggerLevel(tt.logger, tt.level, tt.ttl)
+			assert.Assert(t, err != nil, "expected error")
+		})
+	}
+}
@@ -98,6 +98,7 @@ var loggers = []*LoggerHandle{
 // structure to hold all current logger configuration state
 type loggerConfig struct {
 	loggers []*zap.Logger
+	levels  []zapcore.Level
 }
 
 // tracks the currently used set of loggers; replaced completely whenever configuration changes
@@ -231,8 +232,12 @@ func GetZapConfigs() *zap.Config {
 // UpdateLoggingConfig is used to reconfigure logging. This uses config keys of the form log.{logger}.level={level}.
 // The default level is set by log.level={level}. The {level} value can be either numeric (-1 through 5), or
 // textual (DEBUG, INFO, WARN, ERROR, DPANIC, PANIC, or ERROR). See zapcore documentation for more details.
+// Temporary per-logger overrides set via SetLoggerLevel are applied on top of the configuration.
 func UpdateLoggingConfig(config map[string]string) {
 	once.Do(initLogger)
+	overrideLock.Lock()
+	defer overrideLock.Unlock()
+	lastConfig = config
 	initLoggingConfig(config)
 }
 
@@ -280,6 +285,11 @@ func initLoggingConfig(config map[string]string) {
 		}
 	}
 
+	// temporary overrides take precedence over the configuration
+	for name, override := range overrides {
+		levelMap[name] = override.level
+	}
+
 	// compute the finest log level necessary to allow all loggers to succeed
 	minLevel := zapcore.InvalidLevel - 1
 	for _, v := range levelMap {
@@ -287,10 +297,12 @@ func initLoggingConfig(config map[string]string) {
 	}
 
 	// create each configured logger and initialize the overall configuration
+	levels := make([]zapcore.Level, len(loggers))
 	for i := 0; i < len(loggers); i++ {
 		zapLoggers[i] = createLogger(levelMap, loggers[i].name)
+		levels[i] = loggerLevel(levelMap, loggers[i].name)
 	}
-	newLoggerConfig := loggerConfig{loggers: zapLoggers}
+	newLoggerConfig := loggerConfig{loggers: zapLoggers, levels: levels}
 
 	// update the root zap logger level
 	zapConfigs.Level.SetLevel(minLevel)
@@ -0,0 +1,137 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+const (
+	// names of the inbound scheduler queues and the outbound RM proxy queue
+	EventQueueAllocation = "allocation"
+	EventQueueNode       = "node"
+	EventQueueInfra      = "infra"
+	EventQueueRMProxy    = "rmproxy"
+
+	// actions taken on asks or events that could not be enqueued normally
+	EventQueueRejected = "rejected"
+	EventQueueDelayed  = "delayed"
+	EventQueueDropped  = "dropped"
+)
+
+// EventQueueMetrics to declare the depth of the RM event queues, the time spent enqueueing events
+// and the events affected by the overload protection.
+type EventQueueMetrics struct {
+	depth       *prometheus.GaugeVec
+	enqueueWait *prometheus.HistogramVec
+	overload    *prometheus.CounterVec
+}
+
+func initEventQueueMetrics() *EventQueueMetrics {
+	q := &EventQueueMetrics{}
+
+	q.depth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "event_queue_depth",
+			Help:      "Number of events waiting in the RM event queue. Queue includes `allocation`, `node`, `infra` and `rmproxy`.",
+		}, []string{"queue"})
+
+	q.enqueueWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "event_queue_enqueue_wait_seconds",
+			Help:      "Time the sender waited before the event was added to the RM event queue, including overload delays, in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 10, 6), // 0.1ms up to 10s
+		}, []string{"queue"})
+
+	q.overload = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "event_queue_overload_total",
+			Help:      "Total number of asks `rejected` or `delayed` by the overload mode and events `dropped` on a full queue.",
+		}, []string{"queue", "action"})
+
+	var metricsList = []prometheus.Collector{
+		q.depth,
+		q.enqueueWait,
+		q.overload,
+	}
+	for _, metric := range metricsList {
+		if err := prometheus.Register(metric); err != nil {
+			log.Log(log.Metrics).Warn("failed to register metrics collector", zap.Error(err))
+		}
+	}
+	return q
+}
+
+// Reset all metrics that implement the Reset functionality.
+// should only be used in tests
+func (q *EventQueueMetrics) Reset() {
+	q.depth.Reset()
+	q.enqueueWait.Reset()
+	q.overload.Reset()
+}
+
+func (q *EventQueueMetrics) SetDepth(queue string, depth int) {
+	q.depth.WithLabelValues(queue).Set(float64(depth))
+}
+
+func (q *EventQueueMetrics) GetDepth(queue string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := q.depth.WithLabelValues(queue).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
+func (q *EventQueueMetrics) ObserveEnqueueWait(queue string, start time.Time) {
+	q.enqueueWait.WithLabelValues(queue).Observe(time.Since(start).Seconds())
+}
+
+func (q *EventQueueMetrics) GetEnqueueWaitCount(queue string) (int, error) {
+	return getHistogramCount(q.enqueueWait, queue)
+}
+
+func (q *EventQueueMetrics) IncOverload(queue, action string) {
+	q.overload.WithLabelValues(queue, action).Inc()
+}
+
+func (q *EventQueueMetrics) AddOverload(queue, action string, count int) {
+	q.overload.WithLabelValues(queue, action).Add(float64(count))
+}
+
+func (q *EventQueueMetrics) GetOverload(queue, action string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := q.overload.WithLabelValues(queue, action).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
@@ -0,0 +1,57 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestEventQueueMetrics(t *testing.T) {
+	qm := GetEventQueueMetrics()
+	qm.Reset()
+	defer qm.Reset()
+
+	qm.SetDepth(EventQueueAllocation, 10)
+	qm.SetDepth(EventQueueRMProxy, 3)
+	depth, err := qm.GetDepth(EventQueueAllocation)
+	assert.NilError(t, err)
+	assert.Equal(t, 10, depth)
+	depth, err = qm.GetDepth(EventQueueRMProxy)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, depth)
+
+	qm.ObserveEnqueueWait(EventQueueNode, time.Now().Add(-time.Millisecond))
+	qm.ObserveEnqueueWait(EventQueueNode, time.Now())
+	count, err := qm.GetEnqueueWaitCount(EventQueueNode)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count)
+
+	qm.IncOverload(EventQueueAllocation, EventQueueRejected)
+	qm.IncOverload(EventQueueAllocation, EventQueueRejected)
+	qm.IncOverload(EventQueueAllocation, EventQueueDelayed)
+	rejected, err := qm.GetOverload(EventQueueAllocation, EventQueueRejected)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, rejected)
+	delayed, err := qm.GetOverload(EventQueueAllocation, EventQueueDelayed)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, delayed)
+}
@@ -0,0 +1,116 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+// FragmentationMetrics to declare the largest available resource on a single node and the fragmentation index
+// per partition and resource type.
+type FragmentationMetrics struct {
+	largestAvailable *prometheus.GaugeVec
+	fragmentation    *prometheus.GaugeVec
+	// resource types set by the last update of each partition, used to remove stale resources
+	resources map[string]map[string]bool
+	lock      locking.Mutex
+}
+
+func initFragmentationMetrics() *FragmentationMetrics {
+	f := &FragmentationMetrics{
+		resources: make(map[string]map[string]bool),
+	}
+	f.largestAvailable = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "largest_available_resource",
+			Help:      "Largest amount of the resource type available on a single node, by partition and resource type.",
+		}, []string{"partition", "resource"})
+	f.fragmentation = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "fragmentation_index",
+			Help:      "Fragmentation index of the available resource type (1 - largest / total available), by partition and resource type.",
+		}, []string{"partition", "resource"})
+	var metricsList = []prometheus.Collector{
+		f.largestAvailable,
+		f.fragmentation,
+	}
+	for _, metric := range metricsList {
+		if err := prometheus.Register(metric); err != nil {
+			log.Log(log.Metrics).Warn("failed to register metrics collector", zap.Error(err))
+		}
+	}
+	return f
+}
+
+// Reset all metrics that implement the Reset functionality.
+// should only be used in tests
+func (f *FragmentationMetrics) Reset() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.largestAvailable.Reset()
+	f.fragmentation.Reset()
+	f.resources = make(map[string]map[string]bool)
+}
+
+// Update replaces the largest available resource and fragmentation index values of the partition.
+// Both maps are keyed by the resource type.
+func (f *FragmentationMetrics) Update(partition string, largest map[string]int64, fragmentation map[string]float64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	current := make(map[string]bool)
+	for resource, value := range largest {
+		f.largestAvailable.WithLabelValues(partition, resource).Set(float64(value))
+		f.fragmentation.WithLabelValues(partition, resource).Set(fragmentation[resource])
+		current[resource] = true
+	}
+	for resource := range f.resources[partition] {
+		if !current[resource] {
+			f.largestAvailable.DeleteLabelValues(partition, resource)
+			f.fragmentation.DeleteLabelValues(partition, resource)
+		}
+	}
+	f.resources[partition] = current
+}
+
+func (f *FragmentationMetrics) GetLargestAvailable(partition, resource string) (float64, error) {
+	metricDto := &dto.Metric{}
+	err := f.largestAvailable.WithLabelValues(partition, resource).Write(metricDto)
+	if err == nil {
+		return *metricDto.Gauge.Value, nil
+	}
+	return -1, err
+}
+
+func (f *FragmentationMetrics) GetFragmentationIndex(partition, resource string) (float64, error) {
+	metricDto := &dto.Metric{}
+	err := f.fragmentation.WithLabelValues(partition, resource).Write(metricDto)
+	if err == nil {
+		return *metricDto.Gauge.Value, nil
+	}
+	return -1, err
+}
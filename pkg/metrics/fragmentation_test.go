@@ -0,0 +1,63 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/v3/assert"
+)
+
+func TestFragmentationUpdate(t *testing.T) {
+	fm := GetFragmentationMetrics()
+	fm.Reset()
+	defer fm.Reset()
+
+	fm.Update("default", map[string]int64{"memory": 60, "vcore": 6000}, map[string]float64{"memory": 0.25, "vcore": 0.5})
+	fm.Update("gpu", map[string]int64{"nvidia.com/gpu": 4}, map[string]float64{"nvidia.com/gpu": 0})
+	largest, err := fm.GetLargestAvailable("default", "vcore")
+	assert.NilError(t, err)
+	assert.Equal(t, 6000.0, largest)
+	index, err := fm.GetFragmentationIndex("default", "memory")
+	assert.NilError(t, err)
+	assert.Equal(t, 0.25, index)
+	assertFragmentationSeries(t, 3)
+
+	// memory is gone: its values are removed, the other partition is not touched
+	fm.Update("default", map[string]int64{"vcore": 2000}, map[string]float64{"vcore": 0})
+	largest, err = fm.GetLargestAvailable("default", "vcore")
+	assert.NilError(t, err)
+	assert.Equal(t, 2000.0, largest)
+	assertFragmentationSeries(t, 2)
+}
+
+func assertFragmentationSeries(t *testing.T, expected int) {
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	assert.NilError(t, err)
+	found := 0
+	for _, metric := range mfs {
+		switch metric.GetName() {
+		case "yunikorn_scheduler_largest_available_resource", "yunikorn_scheduler_fragmentation_index":
+			assert.Equal(t, expected, len(metric.Metric), "unexpected series for %s", metric.GetName())
+			found++
+		}
+	}
+	assert.Equal(t, 2, found, "fragmentation metrics not found")
+}
@@ -19,13 +19,22 @@
 package history
 
 import (
+	"sort"
 	"time"
 
 	"github.com/apache/yunikorn-core/pkg/locking"
 )
 
+const (
+	// resource series metrics
+	MetricAllocated       = "allocated"
+	MetricPending         = "pending"
+	MetricGuaranteed      = "guaranteed"
+	MetricNodeUtilization = "nodeUtilization" // mean utilisation of the nodes in the partition, percentage per resource
+)
+
 // This class collects basic information about the cluster
-// for the web UI's front page.
+// for the web UI's front page and trend data for small deployments.
 // For more detailed metrics collection use Prometheus.
 type InternalMetricsHistory struct {
 	records []*MetricsRecord
@@ -41,6 +50,33 @@ type MetricsRecord struct {
 	Timestamp         time.Time
 	TotalApplications int
 	TotalContainers   int
+	Resources         map[SeriesKey]map[string]int64
+}
+
+// SeriesKey identifies a resource time series. The queue is empty for the partition level series.
+type SeriesKey struct {
+	Partition string
+	Queue     string
+	Metric    string
+}
+
+// matches returns true if all non-empty fields of the filter are equal to the key.
+// The partition level series are only matched if the filter has no queue set.
+func (k SeriesKey) matches(filter SeriesKey) bool {
+	return (filter.Partition == "" || filter.Partition == k.Partition) &&
+		(filter.Queue == "" || filter.Queue == k.Queue) &&
+		(filter.Metric == "" || filter.Metric == k.Metric)
+}
+
+// ResourceSeries is a resource time series ordered by the time of addition
+type ResourceSeries struct {
+	Key    SeriesKey
+	Points []*ResourcePoint
+}
+
+type ResourcePoint struct {
+	Timestamp time.Time
+	Resources map[string]int64
 }
 
 func NewInternalMetricsHistory(limit int) *InternalMetricsHistory {
@@ -51,14 +87,19 @@ func NewInternalMetricsHistory(limit int) *InternalMetricsHistory {
 }
 
 func (h *InternalMetricsHistory) Store(totalApplications, totalContainers int) {
+	h.StoreRecord(&MetricsRecord{
+		Timestamp:         time.Now(),
+		TotalApplications: totalApplications,
+		TotalContainers:   totalContainers,
+	})
+}
+
+// StoreRecord adds the record to the history, replacing the oldest record if the limit is reached
+func (h *InternalMetricsHistory) StoreRecord(record *MetricsRecord) {
 	h.Lock()
 	defer h.Unlock()
 
-	h.records[h.pointer] = &MetricsRecord{
-		time.Now(),
-		totalApplications,
-		totalContainers,
-	}
+	h.records[h.pointer] = record
 	h.pointer++
 	if h.pointer == h.limit {
 		h.pointer = 0
@@ -70,7 +111,10 @@ func (h *InternalMetricsHistory) Store(totalApplications, totalContainers int) {
 func (h *InternalMetricsHistory) GetRecords() []*MetricsRecord {
 	h.RLock()
 	defer h.RUnlock()
+	return h.getRecords()
+}
 
+func (h *InternalMetricsHistory) getRecords() []*MetricsRecord {
 	returnRecords := make([]*MetricsRecord, h.limit-h.pointer)
 	copy(returnRecords, h.records[h.pointer:])
 	returnRecords = append(returnRecords, h.records[:h.pointer]...)
@@ -82,3 +126,66 @@ func (h *InternalMetricsHistory) GetLimit() int {
 	defer h.RUnlock()
 	return h.limit
 }
+
+// SetLimit changes the number of records kept in the history.
+// The latest records are kept if the limit is lowered.
+func (h *InternalMetricsHistory) SetLimit(limit int) {
+	h.Lock()
+	defer h.Unlock()
+	if limit <= 0 || limit == h.limit {
+		return
+	}
+	current := h.getRecords()
+	if len(current) > limit {
+		current = current[len(current)-limit:]
+	}
+	records := make([]*MetricsRecord, limit)
+	// keep the records at the end of the ring: the oldest (nil) values come first
+	copy(records[limit-len(current):], current)
+	h.records = records
+	h.limit = limit
+	h.pointer = 0
+}
+
+// Query returns the resource series matching the filter with the points recorded after the since time.
+// The series are sorted by partition, queue and metric.
+func (h *InternalMetricsHistory) Query(filter SeriesKey, since time.Time) []*ResourceSeries {
+	h.RLock()
+	defer h.RUnlock()
+
+	seriesMap := make(map[SeriesKey]*ResourceSeries)
+	for _, record := range h.getRecords() {
+		if record == nil || !record.Timestamp.After(since) {
+			continue
+		}
+		for key, value := range record.Resources {
+			if !key.matches(filter) {
+				continue
+			}
+			series, ok := seriesMap[key]
+			if !ok {
+				series = &ResourceSeries{Key: key}
+				seriesMap[key] = series
+			}
+			series.Points = append(series.Points, &ResourcePoint{
+				Timestamp: record.Timestamp,
+				Resources: value,
+			})
+		}
+	}
+	result := make([]*ResourceSeries, 0, len(seriesMap))
+	for _, series := range seriesMap {
+		result = append(result, series)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		left, right := result[i].Key, result[j].Key
+		if left.Partition != right.Partition {
+			return left.Partition < right.Partition
+		}
+		if left.Queue != right.Queue {
+			return left.Queue < right.Queue
+		}
+		return left.Metric < right.Metric
+	})
+	return result
+}
@@ -20,6 +20,7 @@ package history
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 )
@@ -66,3 +67,86 @@ func TestHistoricalClusterInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestSetLimit(t *testing.T) {
+	hpInfo := NewInternalMetricsHistory(3)
+	hpInfo.Store(1, 1)
+	hpInfo.Store(2, 2)
+
+	// growing keeps all records and the order
+	hpInfo.SetLimit(4)
+	assert.Equal(t, 4, hpInfo.GetLimit())
+	records := hpInfo.GetRecords()
+	assert.Equal(t, 2, countNils(records))
+	assert.Equal(t, 1, records[2].TotalApplications)
+	assert.Equal(t, 2, records[3].TotalApplications)
+	hpInfo.Store(3, 3)
+	hpInfo.Store(4, 4)
+	hpInfo.Store(5, 5)
+
+	// shrinking keeps the latest records
+	hpInfo.SetLimit(2)
+	records = hpInfo.GetRecords()
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, 4, records[0].TotalApplications)
+	assert.Equal(t, 5, records[1].TotalApplications)
+	hpInfo.Store(6, 6)
+	records = hpInfo.GetRecords()
+	assert.Equal(t, 5, records[0].TotalApplications)
+	assert.Equal(t, 6, records[1].TotalApplications)
+
+	// invalid limit is ignored
+	hpInfo.SetLimit(0)
+	assert.Equal(t, 2, hpInfo.GetLimit())
+}
+
+func TestQuery(t *testing.T) {
+	hpInfo := NewInternalMetricsHistory(3)
+	start := time.Now()
+	partAlloc := SeriesKey{Partition: "default", Metric: MetricAllocated}
+	queueAlloc := SeriesKey{Partition: "default", Queue: "root.a", Metric: MetricAllocated}
+	queuePending := SeriesKey{Partition: "default", Queue: "root.a", Metric: MetricPending}
+	otherAlloc := SeriesKey{Partition: "gpu", Metric: MetricAllocated}
+	for i := int64(1); i <= 4; i++ {
+		hpInfo.StoreRecord(&MetricsRecord{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Resources: map[SeriesKey]map[string]int64{
+				partAlloc:    {"memory": i * 10},
+				queueAlloc:   {"memory": i},
+				queuePending: {"memory": i * 2},
+				otherAlloc:   {"vcore": i},
+			},
+		})
+	}
+	// the old style records have no resources
+	hpInfo.Store(1, 1)
+
+	tests := []struct {
+		name   string
+		filter SeriesKey
+		since  time.Time
+		keys   []SeriesKey
+		points int
+	}{
+		{"all", SeriesKey{}, time.Time{}, []SeriesKey{partAlloc, queueAlloc, queuePending, otherAlloc}, 2},
+		{"partition", SeriesKey{Partition: "gpu"}, time.Time{}, []SeriesKey{otherAlloc}, 2},
+		{"queue", SeriesKey{Queue: "root.a"}, time.Time{}, []SeriesKey{queueAlloc, queuePending}, 2},
+		{"metric", SeriesKey{Partition: "default", Metric: MetricAllocated}, time.Time{}, []SeriesKey{partAlloc, queueAlloc}, 2},
+		{"since", SeriesKey{Queue: "root.a", Metric: MetricPending}, start.Add(3 * time.Minute), []SeriesKey{queuePending}, 1},
+		{"no match", SeriesKey{Metric: MetricGuaranteed}, time.Time{}, []SeriesKey{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := hpInfo.Query(tt.filter, tt.since)
+			assert.Equal(t, len(tt.keys), len(series))
+			for i, s := range series {
+				assert.Equal(t, tt.keys[i], s.Key)
+				assert.Equal(t, tt.points, len(s.Points))
+			}
+		})
+	}
+	// points are ordered oldest to newest
+	series := hpInfo.Query(queueAlloc, time.Time{})
+	assert.Equal(t, int64(3), series[0].Points[0].Resources["memory"])
+	assert.Equal(t, int64(4), series[0].Points[1].Resources["memory"])
+}
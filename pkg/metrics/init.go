@@ -31,6 +31,8 @@ const (
 	SchedulerSubsystem = "scheduler"
 	// EventSubsystem - subsystem name used by event cache
 	EventSubsystem = "event"
+	// ResolverSubsystem - subsystem name used by the user group resolver
+	ResolverSubsystem = "resolver"
 	// MetricNameInvalidByteReplacement byte used to replace invalid bytes in prometheus metric names
 	MetricNameInvalidByteReplacement = '_'
 )
@@ -39,21 +41,33 @@ var once sync.Once
 var m *Metrics
 
 type Metrics struct {
-	scheduler *SchedulerMetrics
-	queues    map[string]*QueueMetrics
-	event     *EventMetrics
-	runtime   *RuntimeMetrics
-	lock      locking.RWMutex
+	scheduler  *SchedulerMetrics
+	queues     map[string]*QueueMetrics
+	event      *EventMetrics
+	runtime    *RuntimeMetrics
+	resolver   *ResolverMetrics
+	latency    *LatencyMetrics
+	preemption *PreemptionMetrics
+	nodeUtil   *NodeUtilizationMetrics
+	fragment   *FragmentationMetrics
+	eventQueue *EventQueueMetrics
+	lock       locking.RWMutex
 }
 
 func init() {
 	once.Do(func() {
 		m = &Metrics{
-			scheduler: InitSchedulerMetrics(),
-			queues:    make(map[string]*QueueMetrics),
-			event:     initEventMetrics(),
-			lock:      locking.RWMutex{},
-			runtime:   initRuntimeMetrics(),
+			scheduler:  InitSchedulerMetrics(),
+			queues:     make(map[string]*QueueMetrics),
+			event:      initEventMetrics(),
+			lock:       locking.RWMutex{},
+			runtime:    initRuntimeMetrics(),
+			resolver:   initResolverMetrics(),
+			latency:    initLatencyMetrics(),
+			preemption: initPreemptionMetrics(),
+			nodeUtil:   initNodeUtilizationMetrics(),
+			fragment:   initFragmentationMetrics(),
+			eventQueue: initEventQueueMetrics(),
 		}
 	})
 }
@@ -67,6 +81,12 @@ func Reset() {
 		qm.Reset()
 	}
 	m.runtime.Reset()
+	m.resolver.Reset()
+	m.latency.Reset()
+	m.preemption.Reset()
+	m.nodeUtil.Reset()
+	m.fragment.Reset()
+	m.eventQueue.Reset()
 }
 
 func GetSchedulerMetrics() *SchedulerMetrics {
@@ -101,6 +121,30 @@ func GetRuntimeMetrics() *RuntimeMetrics {
 	return m.runtime
 }
 
+func GetResolverMetrics() *ResolverMetrics {
+	return m.resolver
+}
+
+func GetLatencyMetrics() *LatencyMetrics {
+	return m.latency
+}
+
+func GetPreemptionMetrics() *PreemptionMetrics {
+	return m.preemption
+}
+
+func GetNodeUtilizationMetrics() *NodeUtilizationMetrics {
+	return m.nodeUtil
+}
+
+func GetFragmentationMetrics() *FragmentationMetrics {
+	return m.fragment
+}
+
+func GetEventQueueMetrics() *EventQueueMetrics {
+	return m.eventQueue
+}
+
 // Format metric name based on the definition of metric name in prometheus, as per
 // https://prometheus.io/docs/concepts/data_model/#metric-names-and-labels
 func formatMetricName(metricName string) string {
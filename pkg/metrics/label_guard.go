@@ -0,0 +1,65 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// labelGuard limits the number of values recorded for a label to guard the cardinality of a metric.
+// Once the limit is reached new values are recorded under LabelOverflow and counted in the overflow metric.
+// Lowering the limit does not remove the values already tracked.
+// The guard is not locked: the metrics using it must serialise the calls.
+type labelGuard struct {
+	label    string
+	limit    int
+	tracked  map[string]bool
+	overflow *prometheus.CounterVec
+}
+
+func newLabelGuard(label string, limit int, overflow *prometheus.CounterVec) *labelGuard {
+	return &labelGuard{
+		label:    label,
+		limit:    limit,
+		tracked:  make(map[string]bool),
+		overflow: overflow,
+	}
+}
+
+// value returns the value if it is already tracked or the limit is not reached, the overflow label otherwise
+func (g *labelGuard) value(value string) string {
+	if g.tracked[value] {
+		return value
+	}
+	if len(g.tracked) >= g.limit {
+		g.overflow.WithLabelValues(g.label).Inc()
+		return LabelOverflow
+	}
+	g.tracked[value] = true
+	return value
+}
+
+func (g *labelGuard) setLimit(limit int) {
+	g.limit = limit
+}
+
+// reset forgets the tracked values, should only be used in tests
+func (g *labelGuard) reset() {
+	g.tracked = make(map[string]bool)
+}
@@ -0,0 +1,175 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+const (
+	// LabelOverflow replaces the queue or user label once the configured number of values is reached
+	LabelOverflow = "_other"
+)
+
+// LatencyMetrics to declare the ask wait and application time to run metrics per queue and user.
+// The number of queue and user label values is limited to guard the cardinality of the metrics:
+// once the limit is reached new queues or users are recorded under the LabelOverflow value.
+// The user label is only set if enabled in the config map, it is empty otherwise.
+type LatencyMetrics struct {
+	askWait          *prometheus.HistogramVec
+	appTimeToRun     *prometheus.HistogramVec
+	userLabel        bool
+	queues           *labelGuard
+	users            *labelGuard
+	overflowDetected *prometheus.CounterVec
+	lock             locking.RWMutex
+}
+
+func initLatencyMetrics() *LatencyMetrics {
+	l := &LatencyMetrics{
+		userLabel: configs.DefaultMetricsLatencyUserLabel,
+	}
+
+	l.askWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "ask_wait_seconds",
+			Help:      "Time an ask waited from creation until it was allocated, in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 4, 10), // 100ms up to about 7h
+		}, []string{"queue", "user"})
+
+	l.appTimeToRun = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "application_time_to_run_seconds",
+			Help:      "Time from application submission until the application was running, in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 4, 10),
+		}, []string{"queue", "user"})
+
+	l.overflowDetected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "latency_label_overflow_total",
+			Help:      "Total number of latency observations recorded under the overflow label. Label includes `queue` and `user`.",
+		}, []string{"label"})
+	l.queues = newLabelGuard("queue", int(configs.DefaultMetricsLatencyMaxQueues), l.overflowDetected)
+	l.users = newLabelGuard("user", int(configs.DefaultMetricsLatencyMaxUsers), l.overflowDetected)
+
+	var metricsList = []prometheus.Collector{
+		l.askWait,
+		l.appTimeToRun,
+		l.overflowDetected,
+	}
+	for _, metric := range metricsList {
+		if err := prometheus.Register(metric); err != nil {
+			log.Log(log.Metrics).Warn("failed to register metrics collector", zap.Error(err))
+		}
+	}
+	configs.AddConfigMapCallback("metrics-latency", func() {
+		l.configure(configs.GetConfigMap())
+	})
+	return l
+}
+
+// configure updates the cardinality guard from the config map.
+// Lowering a limit does not remove the label values already recorded.
+func (l *LatencyMetrics) configure(configMap map[string]string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.userLabel = common.GetConfigurationBool(configMap, configs.CMMetricsLatencyUserLabel, configs.DefaultMetricsLatencyUserLabel)
+	l.queues.setLimit(int(common.GetConfigurationUint(configMap, configs.CMMetricsLatencyMaxQueues, configs.DefaultMetricsLatencyMaxQueues)))
+	l.users.setLimit(int(common.GetConfigurationUint(configMap, configs.CMMetricsLatencyMaxUsers, configs.DefaultMetricsLatencyMaxUsers)))
+}
+
+// Reset all metrics that implement the Reset functionality.
+// should only be used in tests
+func (l *LatencyMetrics) Reset() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.askWait.Reset()
+	l.appTimeToRun.Reset()
+	l.overflowDetected.Reset()
+	l.queues.reset()
+	l.users.reset()
+}
+
+// labels returns the label values for the queue and user after applying the cardinality guard
+func (l *LatencyMetrics) labels(queue, user string) (string, string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	queue = l.queues.value(queue)
+	if !l.userLabel {
+		return queue, ""
+	}
+	return queue, l.users.value(user)
+}
+
+func (l *LatencyMetrics) ObserveAskWait(queue, user string, wait time.Duration) {
+	queueLabel, userLabel := l.labels(queue, user)
+	l.askWait.WithLabelValues(queueLabel, userLabel).Observe(wait.Seconds())
+}
+
+func (l *LatencyMetrics) ObserveApplicationTimeToRun(queue, user string, timeToRun time.Duration) {
+	queueLabel, userLabel := l.labels(queue, user)
+	l.appTimeToRun.WithLabelValues(queueLabel, userLabel).Observe(timeToRun.Seconds())
+}
+
+// GetAskWaitCount returns the number of ask wait observations for the label values
+func (l *LatencyMetrics) GetAskWaitCount(queue, user string) (int, error) {
+	return getHistogramCount(l.askWait, queue, user)
+}
+
+// GetApplicationTimeToRunCount returns the number of application time to run observations for the label values
+func (l *LatencyMetrics) GetApplicationTimeToRunCount(queue, user string) (int, error) {
+	return getHistogramCount(l.appTimeToRun, queue, user)
+}
+
+func (l *LatencyMetrics) GetLabelOverflow(label string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := l.overflowDetected.WithLabelValues(label).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
+
+func getHistogramCount(histogram *prometheus.HistogramVec, labels ...string) (int, error) {
+	metricDto := &dto.Metric{}
+	observer, err := histogram.GetMetricWithLabelValues(labels...)
+	if err != nil {
+		return -1, err
+	}
+	err = observer.(prometheus.Metric).Write(metricDto) //nolint:errcheck
+	if err == nil {
+		return int(*metricDto.Histogram.SampleCount), nil
+	}
+	return -1, err
+}
@@ -0,0 +1,98 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+)
+
+func TestLatencyObserve(t *testing.T) {
+	lm := GetLatencyMetrics()
+	lm.Reset()
+	lm.configure(map[string]string{})
+	defer lm.Reset()
+
+	lm.ObserveAskWait("root.a", "alice", 2*time.Second)
+	lm.ObserveAskWait("root.a", "bob", 3*time.Second)
+	lm.ObserveApplicationTimeToRun("root.b", "alice", time.Minute)
+	// user label is off by default
+	count, err := lm.GetAskWaitCount("root.a", "")
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count)
+	count, err = lm.GetApplicationTimeToRunCount("root.b", "")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	verifyHistogram(t, "ask_wait_seconds", 5, 1)
+
+	lm.configure(map[string]string{configs.CMMetricsLatencyUserLabel: "true"})
+	lm.ObserveAskWait("root.a", "alice", time.Second)
+	count, err = lm.GetAskWaitCount("root.a", "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestLatencyCardinalityGuard(t *testing.T) {
+	lm := GetLatencyMetrics()
+	lm.Reset()
+	defer lm.Reset()
+	lm.configure(map[string]string{
+		configs.CMMetricsLatencyUserLabel: "true",
+		configs.CMMetricsLatencyMaxQueues: "2",
+		configs.CMMetricsLatencyMaxUsers:  "1",
+	})
+	defer lm.configure(map[string]string{})
+
+	lm.ObserveAskWait("root.a", "alice", time.Second)
+	lm.ObserveAskWait("root.b", "alice", time.Second)
+	lm.ObserveAskWait("root.c", "alice", time.Second)
+	lm.ObserveAskWait("root.a", "bob", time.Second)
+	lm.ObserveApplicationTimeToRun("root.d", "carol", time.Second)
+
+	tests := []struct {
+		queue string
+		user  string
+		count int
+	}{
+		{"root.a", "alice", 1},
+		{"root.b", "alice", 1},
+		{LabelOverflow, "alice", 1},
+		{"root.a", LabelOverflow, 1},
+		{"root.c", "alice", 0},
+	}
+	for _, tt := range tests {
+		count, err := lm.GetAskWaitCount(tt.queue, tt.user)
+		assert.NilError(t, err)
+		assert.Equal(t, tt.count, count, "unexpected count for queue %s and user %s", tt.queue, tt.user)
+	}
+	count, err := lm.GetApplicationTimeToRunCount(LabelOverflow, LabelOverflow)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count, "queue and user are shared between the metrics")
+
+	overflow, err := lm.GetLabelOverflow("queue")
+	assert.NilError(t, err)
+	assert.Equal(t, 2, overflow)
+	overflow, err = lm.GetLabelOverflow("user")
+	assert.NilError(t, err)
+	assert.Equal(t, 2, overflow)
+}
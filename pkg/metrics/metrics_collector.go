@@ -19,48 +19,75 @@
 package metrics
 
 import (
+	"fmt"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
 	"github.com/apache/yunikorn-core/pkg/metrics/history"
 )
 
+// ResourceSampler returns the current value of the resource series stored in the history
+type ResourceSampler func() map[history.SeriesKey]map[string]int64
+
 // collecting metrics for YuniKorn-internal usage
 // will fill missing values with -1, in case of failures
 type internalMetricsCollector struct {
 	ticker         *time.Ticker
 	stopped        chan struct{}
 	metricsHistory *history.InternalMetricsHistory
+	sampler        ResourceSampler
+	confWatcherID  string
+	// interval used when the config map does not set one
+	defaultInterval time.Duration
+
+	// mutable values require locking
+	interval time.Duration
+	locking.Mutex
 }
 
 type InternalMetricsCollector interface {
 	Stop()
 }
 
-func NewInternalMetricsCollector(hcInfo *history.InternalMetricsHistory) *internalMetricsCollector {
-	return newInternalMetricsCollector(hcInfo, 1*time.Minute)
+// NewInternalMetricsCollector creates a collector that stores a record in the history at the configured interval.
+// The resource sampler is optional, the records only contain the application and container totals without it.
+func NewInternalMetricsCollector(hcInfo *history.InternalMetricsHistory, sampler ResourceSampler) *internalMetricsCollector {
+	return newInternalMetricsCollector(hcInfo, sampler, configs.DefaultMetricsHistoryInterval)
 }
 
 // create a internalMetricsCollector with specify tick duration.
-func newInternalMetricsCollector(hcInfo *history.InternalMetricsHistory, tickerDefault time.Duration) *internalMetricsCollector {
+func newInternalMetricsCollector(hcInfo *history.InternalMetricsHistory, sampler ResourceSampler, tickerDefault time.Duration) *internalMetricsCollector {
 	finished := make(chan struct{})
 	ticker := time.NewTicker(tickerDefault)
 
-	return &internalMetricsCollector{
-		ticker,
-		finished,
-		hcInfo,
+	u := &internalMetricsCollector{
+		ticker:         ticker,
+		stopped:        finished,
+		metricsHistory: hcInfo,
+		sampler:        sampler,
+		interval:       tickerDefault,
+
+		defaultInterval: tickerDefault,
 	}
+	u.confWatcherID = fmt.Sprintf("metrics-collector-%p", u)
+	return u
 }
 
 func (u *internalMetricsCollector) StartService() {
+	u.configure(configs.GetConfigMap())
+	configs.AddConfigMapCallback(u.confWatcherID, func() {
+		u.configure(configs.GetConfigMap())
+	})
 	go func() {
 		log.Log(log.Metrics).Info("Starting internal metrics collector")
 		for {
 			select {
 			case <-u.stopped:
+				u.ticker.Stop()
 				return
 			case <-u.ticker.C:
 				u.store()
@@ -69,6 +96,60 @@ func (u *internalMetricsCollector) StartService() {
 	}()
 }
 
+// configure updates the sampling interval and the history size from the config map.
+// The history keeps retention divided by interval records, with a minimum of one record. The history size
+// is only changed if the interval or the retention is set, the size the history was created with is kept otherwise.
+func (u *internalMetricsCollector) configure(configMap map[string]string) {
+	u.Lock()
+	defer u.Unlock()
+	interval := getDuration(configMap, configs.CMMetricsHistoryInterval, u.defaultInterval)
+	if interval != u.interval {
+		u.interval = interval
+		u.ticker.Reset(interval)
+	}
+	_, intervalSet := configMap[configs.CMMetricsHistoryInterval]
+	_, retentionSet := configMap[configs.CMMetricsHistoryRetention]
+	if !intervalSet && !retentionSet {
+		log.Log(log.Metrics).Info("Internal metrics collector configured",
+			zap.Duration("interval", interval))
+		return
+	}
+	retention := getDuration(configMap, configs.CMMetricsHistoryRetention, configs.DefaultMetricsHistoryRetention)
+	limit := int(retention / interval)
+	if limit < 1 {
+		limit = 1
+	}
+	u.metricsHistory.SetLimit(limit)
+	log.Log(log.Metrics).Info("Internal metrics collector configured",
+		zap.Duration("interval", interval),
+		zap.Duration("retention", retention),
+		zap.Int("records", limit))
+}
+
+func (u *internalMetricsCollector) getInterval() time.Duration {
+	u.Lock()
+	defer u.Unlock()
+	return u.interval
+}
+
+// getDuration returns the positive duration set for the key, the default if not set or invalid
+func getDuration(configMap map[string]string, key string, defaultValue time.Duration) time.Duration {
+	value, ok := configMap[key]
+	if !ok {
+		return defaultValue
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil || result <= 0 {
+		log.Log(log.Metrics).Warn("Failed to parse configuration value, using default",
+			zap.String("key", key),
+			zap.String("value", value),
+			zap.Duration("default", defaultValue),
+			zap.Error(err))
+		return defaultValue
+	}
+	return result
+}
+
 func (u *internalMetricsCollector) store() {
 	log.Log(log.Metrics).Debug("Adding current status to historical partition data")
 
@@ -91,10 +172,19 @@ func (u *internalMetricsCollector) store() {
 			zap.Int("allocatedContainers", allocatedContainers),
 			zap.Int("releasedContainers", releasedContainers))
 	}
-	u.metricsHistory.Store(totalAppsRunning, totalContainersRunning)
+	record := &history.MetricsRecord{
+		Timestamp:         time.Now(),
+		TotalApplications: totalAppsRunning,
+		TotalContainers:   totalContainersRunning,
+	}
+	if u.sampler != nil {
+		record.Resources = u.sampler()
+	}
+	u.metricsHistory.StoreRecord(record)
 }
 
 func (u *internalMetricsCollector) Stop() {
 	log.Log(log.Metrics).Info("Stopping internal metrics collector")
+	configs.RemoveConfigMapCallback(u.confWatcherID)
 	close(u.stopped)
 }
@@ -25,12 +25,13 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/metrics/history"
 )
 
 func TestStop(t *testing.T) {
 	metricsHistory := history.NewInternalMetricsHistory(3)
-	metricsCollector := newInternalMetricsCollector(metricsHistory, 1*time.Second)
+	metricsCollector := newInternalMetricsCollector(metricsHistory, nil, 1*time.Second)
 	metricsCollector.StartService()
 
 	metricsCollector.Stop()
@@ -46,7 +47,7 @@ func TestStop(t *testing.T) {
 
 func TestStartService(t *testing.T) {
 	metricsHistory := history.NewInternalMetricsHistory(3)
-	metricsCollector := newInternalMetricsCollector(metricsHistory, 1*time.Second)
+	metricsCollector := newInternalMetricsCollector(metricsHistory, nil, 1*time.Second)
 	metricsCollector.StartService()
 
 	// wait for the thread to store record
@@ -64,9 +65,20 @@ func TestStartService(t *testing.T) {
 	}
 }
 
+func TestStartServiceConfigure(t *testing.T) {
+	configs.SetConfigMap(map[string]string{configs.CMMetricsHistoryInterval: "30s", configs.CMMetricsHistoryRetention: "1h"})
+	defer configs.SetConfigMap(map[string]string{})
+	metricsHistory := history.NewInternalMetricsHistory(3)
+	metricsCollector := newInternalMetricsCollector(metricsHistory, nil, time.Minute)
+	metricsCollector.StartService()
+	defer metricsCollector.Stop()
+	assert.Equal(t, 30*time.Second, metricsCollector.getInterval(), "config map not applied on start")
+	assert.Equal(t, 120, metricsHistory.GetLimit(), "config map not applied on start")
+}
+
 func TestHistoricalPartitionInfoUpdater(t *testing.T) {
 	metricsHistory := history.NewInternalMetricsHistory(3)
-	metricsCollector := NewInternalMetricsCollector(metricsHistory)
+	metricsCollector := NewInternalMetricsCollector(metricsHistory, nil)
 
 	metrics := GetSchedulerMetrics()
 
@@ -97,3 +109,49 @@ func TestHistoricalPartitionInfoUpdater(t *testing.T) {
 		}
 	}
 }
+
+func TestCollectorConfigure(t *testing.T) {
+	metricsHistory := history.NewInternalMetricsHistory(3)
+	metricsCollector := newInternalMetricsCollector(metricsHistory, nil, time.Minute)
+	defer metricsCollector.ticker.Stop()
+
+	tests := []struct {
+		name      string
+		configMap map[string]string
+		interval  time.Duration
+		limit     int
+	}{
+		{"default keeps history size", map[string]string{}, time.Minute, 3},
+		{"interval", map[string]string{configs.CMMetricsHistoryInterval: "30s"}, 30 * time.Second, 2880},
+		{"retention", map[string]string{configs.CMMetricsHistoryRetention: "1h"}, time.Minute, 60},
+		{"both", map[string]string{configs.CMMetricsHistoryInterval: "10s", configs.CMMetricsHistoryRetention: "5m"}, 10 * time.Second, 30},
+		{"retention below interval", map[string]string{configs.CMMetricsHistoryRetention: "10s"}, time.Minute, 1},
+		{"invalid interval", map[string]string{configs.CMMetricsHistoryInterval: "x", configs.CMMetricsHistoryRetention: "1h"}, time.Minute, 60},
+		{"negative retention", map[string]string{configs.CMMetricsHistoryInterval: "1h", configs.CMMetricsHistoryRetention: "-1h"}, time.Hour, 24},
+		{"keys removed", map[string]string{}, time.Minute, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metricsCollector.configure(tt.configMap)
+			assert.Equal(t, tt.interval, metricsCollector.getInterval())
+			assert.Equal(t, tt.limit, metricsHistory.GetLimit())
+		})
+	}
+}
+
+func TestStoreResourceSample(t *testing.T) {
+	metricsHistory := history.NewInternalMetricsHistory(2)
+	key := history.SeriesKey{Partition: "default", Queue: "root.a", Metric: history.MetricAllocated}
+	sampler := func() map[history.SeriesKey]map[string]int64 {
+		return map[history.SeriesKey]map[string]int64{key: {"memory": 100}}
+	}
+	metricsCollector := newInternalMetricsCollector(metricsHistory, sampler, time.Minute)
+	defer metricsCollector.ticker.Stop()
+	metricsCollector.store()
+
+	series := metricsHistory.Query(history.SeriesKey{}, time.Time{})
+	assert.Equal(t, 1, len(series))
+	assert.Equal(t, key, series[0].Key)
+	assert.Equal(t, 1, len(series[0].Points))
+	assert.Equal(t, int64(100), series[0].Points[0].Resources["memory"])
+}
@@ -0,0 +1,97 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+// NodeUtilizationMetrics to declare the node utilisation distribution per partition, node group and resource type.
+// The node group is the value of the node attribute configured to group the nodes, empty if not grouped.
+type NodeUtilizationMetrics struct {
+	nodes *prometheus.GaugeVec
+	// label values set by the last update of each partition, used to remove stale groups and resources
+	labels map[string]map[string][]string
+	lock   locking.Mutex
+}
+
+func initNodeUtilizationMetrics() *NodeUtilizationMetrics {
+	n := &NodeUtilizationMetrics{
+		labels: make(map[string]map[string][]string),
+	}
+	n.nodes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "node_utilization_nodes",
+			Help:      "Number of nodes in the utilisation range, by partition, node group and resource type.",
+		}, []string{"partition", "group", "resource", "range"})
+	if err := prometheus.Register(n.nodes); err != nil {
+		log.Log(log.Metrics).Warn("failed to register metrics collector", zap.Error(err))
+	}
+	return n
+}
+
+// Reset all metrics that implement the Reset functionality.
+// should only be used in tests
+func (n *NodeUtilizationMetrics) Reset() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.nodes.Reset()
+	n.labels = make(map[string]map[string][]string)
+}
+
+// Update replaces the utilisation distribution of the partition.
+// The usage maps the node group and resource type to the number of nodes in each of the 10 utilisation ranges.
+func (n *NodeUtilizationMetrics) Update(partition string, usage map[string]map[string][]int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	current := make(map[string][]string)
+	for group, resourceUsage := range usage {
+		for resource, buckets := range resourceUsage {
+			for idx, count := range buckets {
+				labels := []string{partition, group, resource, resourceUsageRangeBuckets[idx]}
+				n.nodes.WithLabelValues(labels...).Set(float64(count))
+				current[strings.Join(labels, "\x00")] = labels
+			}
+		}
+	}
+	for key, labels := range n.labels[partition] {
+		if _, ok := current[key]; !ok {
+			n.nodes.DeleteLabelValues(labels...)
+		}
+	}
+	n.labels[partition] = current
+}
+
+func (n *NodeUtilizationMetrics) GetNodes(partition, group, resource string, rangeIdx int) (int, error) {
+	metricDto := &dto.Metric{}
+	err := n.nodes.WithLabelValues(partition, group, resource, resourceUsageRangeBuckets[rangeIdx]).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
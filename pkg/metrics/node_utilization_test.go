@@ -0,0 +1,81 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/v3/assert"
+)
+
+func TestNodeUtilizationUpdate(t *testing.T) {
+	nm := GetNodeUtilizationMetrics()
+	nm.Reset()
+	defer nm.Reset()
+
+	nm.Update("default", map[string]map[string][]int{
+		"zone-a": {"memory": {1, 0, 0, 0, 2, 0, 0, 0, 0, 0}, "vcore": {3, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		"zone-b": {"memory": {0, 0, 0, 0, 0, 0, 0, 0, 0, 1}},
+	})
+	nm.Update("gpu", map[string]map[string][]int{
+		"": {"nvidia.com/gpu": {0, 1, 0, 0, 0, 0, 0, 0, 0, 0}},
+	})
+	tests := []struct {
+		partition string
+		group     string
+		resource  string
+		idx       int
+		expected  int
+	}{
+		{"default", "zone-a", "memory", 0, 1},
+		{"default", "zone-a", "memory", 4, 2},
+		{"default", "zone-a", "vcore", 0, 3},
+		{"default", "zone-b", "memory", 9, 1},
+		{"gpu", "", "nvidia.com/gpu", 1, 1},
+	}
+	for _, tt := range tests {
+		count, err := nm.GetNodes(tt.partition, tt.group, tt.resource, tt.idx)
+		assert.NilError(t, err)
+		assert.Equal(t, tt.expected, count, "unexpected count for %v", tt)
+	}
+	// 10 ranges for each partition, group and resource combination
+	assertNodeUtilizationSeries(t, 40)
+
+	// zone-b is gone: its values are removed, the other partition is not touched
+	nm.Update("default", map[string]map[string][]int{
+		"zone-a": {"memory": {0, 0, 0, 0, 3, 0, 0, 0, 0, 0}},
+	})
+	count, err := nm.GetNodes("default", "zone-a", "memory", 4)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, count)
+	assertNodeUtilizationSeries(t, 20)
+}
+
+func assertNodeUtilizationSeries(t *testing.T, expected int) {
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	assert.NilError(t, err)
+	for _, metric := range mfs {
+		if metric.GetName() == "yunikorn_scheduler_node_utilization_nodes" {
+			assert.Equal(t, expected, len(metric.Metric))
+			return
+		}
+	}
+	t.Fatal("node utilization metric not found")
+}
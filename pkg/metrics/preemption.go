@@ -0,0 +1,244 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+const (
+	// preemption types
+	PreemptionTypeBackfill     = "backfill"
+	PreemptionTypePreemptor    = "preemptor"
+	PreemptionTypeQuotaChange  = "quota_change"
+	PreemptionTypeRequiredNode = "required_node"
+
+	// preemption attempt results
+	PreemptionSuccess         = "success"
+	PreemptionNoGuarantee     = "no_guarantee"
+	PreemptionNoVictims       = "no_victims"
+	PreemptionNotEnough       = "not_enough_victims"
+	PreemptionShortfall       = "shortfall"
+	PreemptionVictimsReleased = "victims_released"
+)
+
+// PreemptionMetrics to declare the preemption outcome metrics.
+// The preemptor queue is the queue that triggered the preemption: the queue of the ask for the preemptor
+// and required node preemption, the leaf queue brought back within its quota for quota change preemption.
+// The number of queue label values is limited to guard the cardinality of the metrics: the preemptor and
+// victim queues share one limit, once reached new queues are recorded under the LabelOverflow value.
+type PreemptionMetrics struct {
+	attempts          *prometheus.CounterVec
+	victims           *prometheus.CounterVec
+	reclaimed         *prometheus.CounterVec
+	victimsPerAttempt *prometheus.HistogramVec
+	predicateFailures *prometheus.CounterVec
+	queues            *labelGuard
+	overflowDetected  *prometheus.CounterVec
+	lock              locking.Mutex
+}
+
+func initPreemptionMetrics() *PreemptionMetrics {
+	p := &PreemptionMetrics{}
+
+	p.attempts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_attempt_total",
+			Help:      "Total number of preemption attempts by preemptor queue and type. Result of the attempt includes `success`, `no_guarantee`, `no_victims`, `not_enough_victims`, `shortfall` and `victims_released`.",
+		}, []string{"queue", "type", "result"})
+
+	p.victims = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_victim_total",
+			Help:      "Total number of allocations preempted by preemptor queue, victim queue and type.",
+		}, []string{"queue", "victim_queue", "type"})
+
+	p.reclaimed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_reclaimed_resource_total",
+			Help:      "Total resources reclaimed by preempting allocations, by preemptor queue, victim queue, type and resource.",
+		}, []string{"queue", "victim_queue", "type", "resource"})
+
+	p.victimsPerAttempt = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_victims_per_attempt",
+			Help:      "Number of allocations preempted by a successful preemption attempt, by type.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 8), // 1 up to 128 victims
+		}, []string{"type"})
+
+	p.predicateFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_predicate_failure_total",
+			Help:      "Total number of failed predicate checks for preemption candidates by preemptor queue.",
+		}, []string{"queue"})
+
+	p.overflowDetected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_label_overflow_total",
+			Help:      "Total number of preemption observations recorded under the overflow label. Label includes `queue`.",
+		}, []string{"label"})
+	p.queues = newLabelGuard("queue", int(configs.DefaultMetricsPreemptionMaxQueues), p.overflowDetected)
+
+	var metricsList = []prometheus.Collector{
+		p.attempts,
+		p.victims,
+		p.reclaimed,
+		p.victimsPerAttempt,
+		p.predicateFailures,
+		p.overflowDetected,
+	}
+	for _, metric := range metricsList {
+		if err := prometheus.Register(metric); err != nil {
+			log.Log(log.Metrics).Warn("failed to register metrics collector", zap.Error(err))
+		}
+	}
+	configs.AddConfigMapCallback("metrics-preemption", func() {
+		p.configure(configs.GetConfigMap())
+	})
+	return p
+}
+
+// configure updates the cardinality guard from the config map.
+// Lowering the limit does not remove the label values already recorded.
+func (p *PreemptionMetrics) configure(configMap map[string]string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.queues.setLimit(int(common.GetConfigurationUint(configMap, configs.CMMetricsPreemptionMaxQueues, configs.DefaultMetricsPreemptionMaxQueues)))
+}
+
+// queueLabels returns the label values for the queues after applying the cardinality guard
+func (p *PreemptionMetrics) queueLabels(queues ...string) []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	labels := make([]string, len(queues))
+	for i, queue := range queues {
+		labels[i] = p.queues.value(queue)
+	}
+	return labels
+}
+
+// Reset all metrics that implement the Reset functionality.
+// should only be used in tests
+func (p *PreemptionMetrics) Reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.attempts.Reset()
+	p.victims.Reset()
+	p.reclaimed.Reset()
+	p.victimsPerAttempt.Reset()
+	p.predicateFailures.Reset()
+	p.overflowDetected.Reset()
+	p.queues.reset()
+}
+
+func (p *PreemptionMetrics) IncAttempt(queue, preemptionType, result string) {
+	labels := p.queueLabels(queue)
+	p.attempts.WithLabelValues(labels[0], preemptionType, result).Inc()
+}
+
+// AddVictim records a preempted allocation and the resources it releases
+func (p *PreemptionMetrics) AddVictim(queue, victimQueue, preemptionType string, resource *resources.Resource) {
+	labels := p.queueLabels(queue, victimQueue)
+	queue, victimQueue = labels[0], labels[1]
+	p.victims.WithLabelValues(queue, victimQueue, preemptionType).Inc()
+	if resource == nil {
+		return
+	}
+	for name, quantity := range resource.Resources {
+		p.reclaimed.WithLabelValues(queue, victimQueue, preemptionType, name).Add(float64(quantity))
+	}
+}
+
+func (p *PreemptionMetrics) ObserveVictimsPerAttempt(preemptionType string, victims int) {
+	p.victimsPerAttempt.WithLabelValues(preemptionType).Observe(float64(victims))
+}
+
+func (p *PreemptionMetrics) AddPredicateFailures(queue string, failures int) {
+	labels := p.queueLabels(queue)
+	p.predicateFailures.WithLabelValues(labels[0]).Add(float64(failures))
+}
+
+func (p *PreemptionMetrics) GetAttempts(queue, preemptionType, result string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := p.attempts.WithLabelValues(queue, preemptionType, result).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
+
+func (p *PreemptionMetrics) GetVictims(queue, victimQueue, preemptionType string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := p.victims.WithLabelValues(queue, victimQueue, preemptionType).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
+
+func (p *PreemptionMetrics) GetReclaimedResource(queue, victimQueue, preemptionType, resource string) (int64, error) {
+	metricDto := &dto.Metric{}
+	err := p.reclaimed.WithLabelValues(queue, victimQueue, preemptionType, resource).Write(metricDto)
+	if err == nil {
+		return int64(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
+
+func (p *PreemptionMetrics) GetVictimsPerAttemptCount(preemptionType string) (int, error) {
+	return getHistogramCount(p.victimsPerAttempt, preemptionType)
+}
+
+func (p *PreemptionMetrics) GetPredicateFailures(queue string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := p.predicateFailures.WithLabelValues(queue).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
+
+func (p *PreemptionMetrics) GetLabelOverflow(label string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := p.overflowDetected.WithLabelValues(label).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
@@ -0,0 +1,123 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestPreemptionAttempts(t *testing.T) {
+	pm := GetPreemptionMetrics()
+	pm.Reset()
+	defer pm.Reset()
+
+	pm.IncAttempt("root.a", PreemptionTypePreemptor, PreemptionSuccess)
+	pm.IncAttempt("root.a", PreemptionTypePreemptor, PreemptionNoVictims)
+	pm.IncAttempt("root.a", PreemptionTypePreemptor, PreemptionNoVictims)
+	pm.IncAttempt("root.b", PreemptionTypeQuotaChange, PreemptionShortfall)
+	tests := []struct {
+		queue          string
+		preemptionType string
+		result         string
+		expected       int
+	}{
+		{"root.a", PreemptionTypePreemptor, PreemptionSuccess, 1},
+		{"root.a", PreemptionTypePreemptor, PreemptionNoVictims, 2},
+		{"root.a", PreemptionTypeRequiredNode, PreemptionNoVictims, 0},
+		{"root.b", PreemptionTypeQuotaChange, PreemptionShortfall, 1},
+	}
+	for _, tt := range tests {
+		value, err := pm.GetAttempts(tt.queue, tt.preemptionType, tt.result)
+		assert.NilError(t, err)
+		assert.Equal(t, tt.expected, value, "unexpected attempts for %s %s %s", tt.queue, tt.preemptionType, tt.result)
+	}
+
+	pm.AddPredicateFailures("root.a", 3)
+	failures, err := pm.GetPredicateFailures("root.a")
+	assert.NilError(t, err)
+	assert.Equal(t, 3, failures)
+}
+
+func TestPreemptionVictims(t *testing.T) {
+	pm := GetPreemptionMetrics()
+	pm.Reset()
+	defer pm.Reset()
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100, "vcore": 2})
+	pm.AddVictim("root.a", "root.b", PreemptionTypePreemptor, res)
+	pm.AddVictim("root.a", "root.b", PreemptionTypePreemptor, res)
+	pm.AddVictim("root.a", "root.c", PreemptionTypePreemptor, nil)
+	pm.ObserveVictimsPerAttempt(PreemptionTypePreemptor, 3)
+
+	victims, err := pm.GetVictims("root.a", "root.b", PreemptionTypePreemptor)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, victims)
+	victims, err = pm.GetVictims("root.a", "root.c", PreemptionTypePreemptor)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, victims)
+	memory, err := pm.GetReclaimedResource("root.a", "root.b", PreemptionTypePreemptor, "memory")
+	assert.NilError(t, err)
+	assert.Equal(t, int64(200), memory)
+	vcore, err := pm.GetReclaimedResource("root.a", "root.b", PreemptionTypePreemptor, "vcore")
+	assert.NilError(t, err)
+	assert.Equal(t, int64(4), vcore)
+	count, err := pm.GetVictimsPerAttemptCount(PreemptionTypePreemptor)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	verifyHistogram(t, "preemption_victims_per_attempt", 3, 0.1)
+}
+
+func TestPreemptionCardinalityGuard(t *testing.T) {
+	pm := GetPreemptionMetrics()
+	pm.Reset()
+	defer pm.Reset()
+	pm.configure(map[string]string{configs.CMMetricsPreemptionMaxQueues: "2"})
+	defer pm.configure(map[string]string{})
+
+	pm.IncAttempt("root.a", PreemptionTypePreemptor, PreemptionSuccess)
+	// preemptor and victim queues share the limit
+	pm.AddVictim("root.a", "root.b", PreemptionTypePreemptor, nil)
+	pm.AddVictim("root.a", "root.c", PreemptionTypePreemptor, nil)
+	pm.IncAttempt("root.d", PreemptionTypePreemptor, PreemptionNotEnough)
+	pm.AddPredicateFailures("root.e", 2)
+
+	attempts, err := pm.GetAttempts("root.a", PreemptionTypePreemptor, PreemptionSuccess)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, attempts)
+	attempts, err = pm.GetAttempts(LabelOverflow, PreemptionTypePreemptor, PreemptionNotEnough)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, attempts)
+	victims, err := pm.GetVictims("root.a", "root.b", PreemptionTypePreemptor)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, victims)
+	victims, err = pm.GetVictims("root.a", LabelOverflow, PreemptionTypePreemptor)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, victims)
+	failures, err := pm.GetPredicateFailures(LabelOverflow)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, failures)
+	overflow, err := pm.GetLabelOverflow("queue")
+	assert.NilError(t, err)
+	assert.Equal(t, 3, overflow)
+}
@@ -38,6 +38,7 @@ const (
 	AppFailed     = "failed"
 	AppRejected   = "rejected"
 	AppResuming   = "resuming"
+	AppSuspended  = "suspended"
 	AppCompleting = "completing"
 	AppCompleted  = "completed"
 	AppExpired    = "expired"
@@ -162,6 +163,12 @@ func (m *QueueMetrics) decQueueApplications(state string) {
 	m.appMetrics.WithLabelValues(state).Dec()
 }
 
+// MoveQueueApplication moves an application in the given state from this queue to the target queue.
+func (m *QueueMetrics) MoveQueueApplication(target *QueueMetrics, state string) {
+	m.decQueueApplications(state)
+	target.incQueueApplications(state)
+}
+
 func (m *QueueMetrics) setQueueResource(state string, resourceName string, value float64) {
 	m.resourceMetricsLabel.WithLabelValues(state, resourceName).Set(value)
 }
@@ -255,6 +262,23 @@ func (m *QueueMetrics) GetQueueApplicationsResuming() (int, error) {
 	return -1, err
 }
 
+func (m *QueueMetrics) IncQueueApplicationsSuspended() {
+	m.incQueueApplications(AppSuspended)
+}
+
+func (m *QueueMetrics) DecQueueApplicationsSuspended() {
+	m.decQueueApplications(AppSuspended)
+}
+
+func (m *QueueMetrics) GetQueueApplicationsSuspended() (int, error) {
+	metricDto := &dto.Metric{}
+	err := m.appMetrics.WithLabelValues(AppSuspended).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
 func (m *QueueMetrics) IncQueueApplicationsFailing() {
 	m.incQueueApplications(AppFailing)
 }
@@ -100,6 +100,23 @@ func TestApplicationsResuming(t *testing.T) {
 	assert.Equal(t, 0, curr)
 }
 
+func TestApplicationsSuspended(t *testing.T) {
+	qm = getQueueMetrics()
+	defer unregisterQueueMetrics()
+
+	qm.IncQueueApplicationsSuspended()
+	verifyAppMetrics(t, "suspended")
+
+	curr, err := qm.GetQueueApplicationsSuspended()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, curr)
+
+	qm.DecQueueApplicationsSuspended()
+	curr, err = qm.GetQueueApplicationsSuspended()
+	assert.NilError(t, err)
+	assert.Equal(t, 0, curr)
+}
+
 func TestApplicationsFailing(t *testing.T) {
 	qm = getQueueMetrics()
 	defer unregisterQueueMetrics()
@@ -0,0 +1,181 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+const (
+	LookupSuccess          = "success"
+	LookupFailure          = "failure"
+	LookupNegativeCacheHit = "negative_cache_hit"
+
+	PoolConnectionIdle   = "idle"
+	PoolConnectionActive = "active"
+)
+
+// ResolverMetrics to declare the user group resolver metrics
+type ResolverMetrics struct {
+	lookups         *prometheus.CounterVec
+	lookupLatency   prometheus.Histogram
+	negativeEntries prometheus.Gauge
+	poolConnections *prometheus.GaugeVec
+	serverFailures  *prometheus.CounterVec
+}
+
+func initResolverMetrics() *ResolverMetrics {
+	r := &ResolverMetrics{}
+
+	r.lookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: ResolverSubsystem,
+			Name:      "lookup_total",
+			Help:      "Total number of group lookups sent to the resolver backend. Result of the lookup includes `success`, `failure` and `negative_cache_hit`.",
+		}, []string{"result"})
+
+	r.lookupLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: ResolverSubsystem,
+			Name:      "lookup_latency_milliseconds",
+			Help:      "Latency of a group lookup against the resolver backend, in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 10, 8), // start from 0.1ms
+		},
+	)
+
+	r.negativeEntries = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: ResolverSubsystem,
+			Name:      "negative_cache_entries",
+			Help:      "Number of failed lookups currently held in the negative cache.",
+		})
+
+	r.poolConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: ResolverSubsystem,
+			Name:      "ldap_pool_connections",
+			Help:      "Number of LDAP connections in the pool. State of the connection includes `idle` and `active`.",
+		}, []string{"state"})
+
+	r.serverFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: ResolverSubsystem,
+			Name:      "ldap_server_failure_total",
+			Help:      "Total number of times an LDAP server was marked unhealthy and skipped for failover.",
+		}, []string{"server"})
+
+	var metricsList = []prometheus.Collector{
+		r.lookups,
+		r.lookupLatency,
+		r.negativeEntries,
+		r.poolConnections,
+		r.serverFailures,
+	}
+	for _, metric := range metricsList {
+		if err := prometheus.Register(metric); err != nil {
+			log.Log(log.Metrics).Warn("failed to register metrics collector", zap.Error(err))
+		}
+	}
+	return r
+}
+
+// Reset all metrics that implement the Reset functionality.
+// should only be used in tests
+func (r *ResolverMetrics) Reset() {
+	r.lookups.Reset()
+	r.negativeEntries.Set(0)
+	r.poolConnections.Reset()
+	r.serverFailures.Reset()
+}
+
+func (r *ResolverMetrics) IncLookupSuccess() {
+	r.lookups.WithLabelValues(LookupSuccess).Inc()
+}
+
+func (r *ResolverMetrics) IncLookupFailure() {
+	r.lookups.WithLabelValues(LookupFailure).Inc()
+}
+
+func (r *ResolverMetrics) IncLookupNegativeCacheHit() {
+	r.lookups.WithLabelValues(LookupNegativeCacheHit).Inc()
+}
+
+func (r *ResolverMetrics) ObserveLookupLatency(start time.Time) {
+	r.lookupLatency.Observe(SinceInSeconds(start))
+}
+
+func (r *ResolverMetrics) SetNegativeCacheEntries(value int) {
+	r.negativeEntries.Set(float64(value))
+}
+
+func (r *ResolverMetrics) SetPoolConnections(idle, active int) {
+	r.poolConnections.WithLabelValues(PoolConnectionIdle).Set(float64(idle))
+	r.poolConnections.WithLabelValues(PoolConnectionActive).Set(float64(active))
+}
+
+func (r *ResolverMetrics) IncServerFailure(server string) {
+	r.serverFailures.WithLabelValues(server).Inc()
+}
+
+func (r *ResolverMetrics) GetLookups(result string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := r.lookups.WithLabelValues(result).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
+
+func (r *ResolverMetrics) GetNegativeCacheEntries() (int, error) {
+	metricDto := &dto.Metric{}
+	err := r.negativeEntries.Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
+func (r *ResolverMetrics) GetPoolConnections(state string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := r.poolConnections.WithLabelValues(state).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
+func (r *ResolverMetrics) GetServerFailures(server string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := r.serverFailures.WithLabelValues(server).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
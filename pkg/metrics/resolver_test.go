@@ -0,0 +1,68 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestResolverLookups(t *testing.T) {
+	rm := GetResolverMetrics()
+	rm.Reset()
+	defer rm.Reset()
+
+	rm.IncLookupSuccess()
+	rm.IncLookupSuccess()
+	rm.IncLookupFailure()
+	rm.IncLookupNegativeCacheHit()
+	for result, expected := range map[string]int{LookupSuccess: 2, LookupFailure: 1, LookupNegativeCacheHit: 1} {
+		value, err := rm.GetLookups(result)
+		assert.NilError(t, err)
+		assert.Equal(t, expected, value, "unexpected lookup count for %s", result)
+	}
+	rm.ObserveLookupLatency(time.Now().Add(-1 * time.Second))
+	verifyHistogram(t, "resolver_lookup_latency_milliseconds", 1, 1)
+}
+
+func TestResolverPool(t *testing.T) {
+	rm := GetResolverMetrics()
+	rm.Reset()
+	defer rm.Reset()
+
+	rm.SetPoolConnections(3, 2)
+	idle, err := rm.GetPoolConnections(PoolConnectionIdle)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, idle)
+	active, err := rm.GetPoolConnections(PoolConnectionActive)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, active)
+
+	rm.SetNegativeCacheEntries(5)
+	entries, err := rm.GetNegativeCacheEntries()
+	assert.NilError(t, err)
+	assert.Equal(t, 5, entries)
+
+	rm.IncServerFailure("ldap://ldap1:389")
+	failures, err := rm.GetServerFailures("ldap://ldap1:389")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, failures)
+}
@@ -59,10 +59,12 @@ type SchedulerMetrics struct {
 	containerAllocation   *prometheus.CounterVec
 	applicationSubmission *prometheus.CounterVec
 	application           *prometheus.GaugeVec
+	appDeadlineAtRisk     prometheus.Gauge
 	node                  *prometheus.GaugeVec
 	nodeResourceUsage     map[string]*prometheus.GaugeVec
 	schedulingLatency     prometheus.Histogram
 	schedulingCycle       prometheus.Histogram
+	partitionCycle        *prometheus.HistogramVec
 	sortingLatency        *prometheus.HistogramVec
 	tryNodeLatency        prometheus.Histogram
 	tryPreemptionLatency  prometheus.Histogram
@@ -102,6 +104,14 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 			Help:      "Total number of applications. State of the application includes `running`, `resuming`, `failing`, `completing`, `completed` and `failed`.",
 		}, []string{"state"})
 
+	s.appDeadlineAtRisk = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "application_deadline_at_risk",
+			Help:      "Number of applications with pending resources that are expected to miss their deadline.",
+		})
+
 	s.node = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: Namespace,
@@ -130,6 +140,15 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 		},
 	)
 
+	s.partitionCycle = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "partition_scheduling_cycle_milliseconds",
+			Help:      "Time taken for a scheduling cycle of a partition scheduled by its own go routine, in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 10, 8),
+		}, []string{"partition"})
+
 	s.sortingLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: Namespace,
@@ -174,11 +193,13 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 		s.containerAllocation,
 		s.applicationSubmission,
 		s.application,
+		s.appDeadlineAtRisk,
 		s.node,
 		s.schedulingLatency,
 		s.sortingLatency,
 		s.tryNodeLatency,
 		s.schedulingCycle,
+		s.partitionCycle,
 		s.tryNodeEvaluation,
 		s.tryPreemptionLatency,
 	}
@@ -195,8 +216,10 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 func (m *SchedulerMetrics) Reset() {
 	m.node.Reset()
 	m.application.Reset()
+	m.appDeadlineAtRisk.Set(0)
 	m.applicationSubmission.Reset()
 	m.containerAllocation.Reset()
+	m.partitionCycle.Reset()
 }
 
 func SinceInSeconds(start time.Time) float64 {
@@ -211,6 +234,19 @@ func (m *SchedulerMetrics) ObserveSchedulingCycle(start time.Time) {
 	m.schedulingCycle.Observe(SinceInSeconds(start))
 }
 
+func (m *SchedulerMetrics) ObservePartitionSchedulingCycle(partition string, start time.Time) {
+	m.partitionCycle.WithLabelValues(partition).Observe(SinceInSeconds(start))
+}
+
+// RemovePartitionSchedulingCycle removes the cycle metric of a partition that is no longer scheduled by its own go routine
+func (m *SchedulerMetrics) RemovePartitionSchedulingCycle(partition string) {
+	m.partitionCycle.DeleteLabelValues(partition)
+}
+
+func (m *SchedulerMetrics) GetPartitionSchedulingCycleCount(partition string) (int, error) {
+	return getHistogramCount(m.partitionCycle, partition)
+}
+
 func (m *SchedulerMetrics) ObserveAppSortingLatency(start time.Time) {
 	m.sortingLatency.WithLabelValues(SortingApp).Observe(SinceInSeconds(start))
 }
@@ -330,6 +366,23 @@ func (m *SchedulerMetrics) GetTotalApplicationsRunning() (int, error) {
 	return -1, err
 }
 
+func (m *SchedulerMetrics) IncApplicationsDeadlineAtRisk() {
+	m.appDeadlineAtRisk.Inc()
+}
+
+func (m *SchedulerMetrics) DecApplicationsDeadlineAtRisk() {
+	m.appDeadlineAtRisk.Dec()
+}
+
+func (m *SchedulerMetrics) GetApplicationsDeadlineAtRisk() (int, error) {
+	metricDto := &dto.Metric{}
+	err := m.appDeadlineAtRisk.Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
 func (m *SchedulerMetrics) IncTotalApplicationsFailing() {
 	m.application.WithLabelValues(AppFailing).Inc()
 }
@@ -367,6 +420,23 @@ func (m *SchedulerMetrics) GetTotalApplicationsResuming() (int, error) {
 	return -1, err
 }
 
+func (m *SchedulerMetrics) IncTotalApplicationsSuspended() {
+	m.application.WithLabelValues(AppSuspended).Inc()
+}
+
+func (m *SchedulerMetrics) DecTotalApplicationsSuspended() {
+	m.application.WithLabelValues(AppSuspended).Dec()
+}
+
+func (m *SchedulerMetrics) GetTotalApplicationsSuspended() (int, error) {
+	metricDto := &dto.Metric{}
+	err := m.application.WithLabelValues(AppSuspended).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
 func (m *SchedulerMetrics) IncTotalApplicationsCompleted() {
 	m.application.WithLabelValues(AppCompleted).Inc()
 }
@@ -137,6 +137,42 @@ func TestSchedulerApplicationsResuming(t *testing.T) {
 	verifyMetric(t, 0, "resuming", "yunikorn_scheduler_application_total", dto.MetricType_GAUGE, "state")
 }
 
+func TestSchedulerApplicationsSuspended(t *testing.T) {
+	sm = getSchedulerMetrics(t)
+	defer unregisterMetrics()
+
+	sm.IncTotalApplicationsSuspended()
+	verifyMetric(t, 1, "suspended", "yunikorn_scheduler_application_total", dto.MetricType_GAUGE, "state")
+
+	curr, err := sm.GetTotalApplicationsSuspended()
+	assert.NilError(t, err)
+	assert.Equal(t, curr, 1)
+
+	sm.DecTotalApplicationsSuspended()
+	verifyMetric(t, 0, "suspended", "yunikorn_scheduler_application_total", dto.MetricType_GAUGE, "state")
+}
+
+func TestSchedulerApplicationsDeadlineAtRisk(t *testing.T) {
+	sm = getSchedulerMetrics(t)
+	defer unregisterMetrics()
+
+	sm.IncApplicationsDeadlineAtRisk()
+	sm.IncApplicationsDeadlineAtRisk()
+	curr, err := sm.GetApplicationsDeadlineAtRisk()
+	assert.NilError(t, err)
+	assert.Equal(t, curr, 2)
+
+	sm.DecApplicationsDeadlineAtRisk()
+	curr, err = sm.GetApplicationsDeadlineAtRisk()
+	assert.NilError(t, err)
+	assert.Equal(t, curr, 1)
+
+	sm.Reset()
+	curr, err = sm.GetApplicationsDeadlineAtRisk()
+	assert.NilError(t, err)
+	assert.Equal(t, curr, 0)
+}
+
 func TestSchedulerApplicationsFailing(t *testing.T) {
 	sm = getSchedulerMetrics(t)
 	defer unregisterMetrics()
@@ -237,6 +273,7 @@ func unregisterMetrics() {
 	prometheus.Unregister(sm.containerAllocation)
 	prometheus.Unregister(sm.applicationSubmission)
 	prometheus.Unregister(sm.application)
+	prometheus.Unregister(sm.appDeadlineAtRisk)
 	prometheus.Unregister(sm.node)
 	prometheus.Unregister(sm.schedulingLatency)
 	prometheus.Unregister(sm.schedulingCycle)
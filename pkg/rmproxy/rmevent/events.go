@@ -19,6 +19,8 @@
 package rmevent
 
 import (
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -27,6 +29,8 @@ type RMUpdateAllocationEvent struct {
 	// The generic UpdateAllocation does not wait for a result,
 	// results are communicated back via the outgoing events.
 	Request *si.AllocationRequest
+	// span of the RM call, stored on the allocations for tracing
+	SpanContext trace.SpanContext
 }
 
 // Incoming UpdateApplication events from the RM to the scheduler (async)
@@ -71,7 +75,8 @@ type Result struct {
 type RMNewAllocationsEvent struct {
 	RmID        string
 	Allocations []*si.Allocation
-	Channel     chan *Result `json:"-"`
+	Channel     chan *Result      `json:"-"`
+	SpanContext trace.SpanContext `json:"-"` // span of the allocation, parent of the RM callback span
 }
 
 type RMApplicationUpdateEvent struct {
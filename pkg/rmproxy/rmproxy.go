@@ -19,9 +19,11 @@
 package rmproxy
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -32,6 +34,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/plugins"
 	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
+	"github.com/apache/yunikorn-core/pkg/tracing"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/api"
 	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
@@ -54,21 +57,25 @@ func (rmp *RMProxy) GetRMEventHandler() handler.EventHandler {
 	return rmp
 }
 
-func enqueueAndCheckFull(queue chan interface{}, ev interface{}) {
+func enqueueAndCheckFull(queue chan interface{}, ev interface{}, start time.Time) {
 	select {
 	case queue <- ev:
+		queueMetrics := metrics.GetEventQueueMetrics()
+		queueMetrics.ObserveEnqueueWait(metrics.EventQueueRMProxy, start)
+		queueMetrics.SetDepth(metrics.EventQueueRMProxy, len(queue))
 		log.Log(log.RMProxy).Debug("enqueue event",
 			zap.Stringer("eventType", reflect.TypeOf(ev)),
 			zap.Any("event", ev),
 			zap.Int("currentQueueSize", len(queue)))
 	default:
+		metrics.GetEventQueueMetrics().IncOverload(metrics.EventQueueRMProxy, metrics.EventQueueDropped)
 		log.Log(log.RMProxy).DPanic("failed to enqueue event",
 			zap.Stringer("event", reflect.TypeOf(ev)))
 	}
 }
 
 func (rmp *RMProxy) HandleEvent(ev interface{}) {
-	enqueueAndCheckFull(rmp.pendingRMEvents, ev)
+	enqueueAndCheckFull(rmp.pendingRMEvents, ev, time.Now())
 }
 
 func NewRMProxy(schedulerEventHandler handler.EventHandler) *RMProxy {
@@ -97,7 +104,14 @@ func (rmp *RMProxy) processAllocationUpdateEvent(event *rmevent.RMNewAllocations
 		response := &si.AllocationResponse{
 			New: event.Allocations,
 		}
-		rmp.triggerUpdateAllocation(event.RmID, response)
+		keys := make([]string, 0, allocationsCount)
+		for _, alloc := range event.Allocations {
+			keys = append(keys, alloc.AllocationKey)
+		}
+		_, span := tracing.StartWithParent(event.SpanContext, "RMCallback.UpdateAllocation",
+			tracing.AttrRM.String(event.RmID),
+			tracing.AttrAllocationKey.StringSlice(keys))
+		tracing.End(span, rmp.triggerUpdateAllocation(event.RmID, response))
 		metrics.GetSchedulerMetrics().AddAllocatedContainers(len(event.Allocations))
 	}
 	// Done, notify channel
@@ -116,9 +130,14 @@ func (rmp *RMProxy) processApplicationUpdateEvent(event *rmevent.RMApplicationUp
 		Accepted: event.AcceptedApplications,
 		Updated:  event.UpdatedApplications,
 	}
+	_, span := tracing.Start(context.Background(), "RMCallback.UpdateApplication",
+		tracing.AttrRM.String(event.RmID),
+		tracing.AttrCount.Int(len(event.RejectedApplications)+len(event.AcceptedApplications)+len(event.UpdatedApplications)))
+	defer span.End()
 	if callback := rmp.GetResourceManagerCallback(event.RmID); callback != nil {
 		if err := callback.UpdateApplication(response); err != nil {
 			rmp.handleUpdateResponseError(event.RmID, err)
+			tracing.RecordError(span, err)
 		}
 	} else {
 		log.Log(log.RMProxy).DPanic("RM is not registered",
@@ -132,7 +151,10 @@ func (rmp *RMProxy) processRMReleaseAllocationEvent(event *rmevent.RMReleaseAllo
 		response := &si.AllocationResponse{
 			Released: event.ReleasedAllocations,
 		}
-		rmp.triggerUpdateAllocation(event.RmID, response)
+		_, span := tracing.Start(context.Background(), "RMCallback.ReleaseAllocation",
+			tracing.AttrRM.String(event.RmID),
+			tracing.AttrCount.Int(allocationsCount))
+		tracing.End(span, rmp.triggerUpdateAllocation(event.RmID, response))
 		metrics.GetSchedulerMetrics().AddReleasedContainers(len(event.ReleasedAllocations))
 	}
 
@@ -143,15 +165,18 @@ func (rmp *RMProxy) processRMReleaseAllocationEvent(event *rmevent.RMReleaseAllo
 	}
 }
 
-func (rmp *RMProxy) triggerUpdateAllocation(rmID string, response *si.AllocationResponse) {
+// triggerUpdateAllocation calls the RM, the error is returned for tracing after it has been handled
+func (rmp *RMProxy) triggerUpdateAllocation(rmID string, response *si.AllocationResponse) error {
 	if callback := rmp.GetResourceManagerCallback(rmID); callback != nil {
 		if err := callback.UpdateAllocation(response); err != nil {
 			rmp.handleUpdateResponseError(rmID, err)
+			return err
 		}
 	} else {
 		log.Log(log.RMProxy).DPanic("RM is not registered",
 			zap.String("rmID", rmID))
 	}
+	return nil
 }
 
 func (rmp *RMProxy) processRMRejectedAllocationEvent(event *rmevent.RMRejectedAllocationEvent) {
@@ -161,7 +186,10 @@ func (rmp *RMProxy) processRMRejectedAllocationEvent(event *rmevent.RMRejectedAl
 	response := &si.AllocationResponse{
 		RejectedAllocations: event.RejectedAllocations,
 	}
-	rmp.triggerUpdateAllocation(event.RmID, response)
+	_, span := tracing.Start(context.Background(), "RMCallback.RejectAllocation",
+		tracing.AttrRM.String(event.RmID),
+		tracing.AttrCount.Int(len(event.RejectedAllocations)))
+	tracing.End(span, rmp.triggerUpdateAllocation(event.RmID, response))
 	metrics.GetSchedulerMetrics().AddRejectedContainers(len(event.RejectedAllocations))
 }
 
@@ -174,9 +202,14 @@ func (rmp *RMProxy) processRMNodeUpdateEvent(event *rmevent.RMNodeUpdateEvent) {
 		Accepted: event.AcceptedNodes,
 	}
 
+	_, span := tracing.Start(context.Background(), "RMCallback.UpdateNode",
+		tracing.AttrRM.String(event.RmID),
+		tracing.AttrCount.Int(len(event.RejectedNodes)+len(event.AcceptedNodes)))
+	defer span.End()
 	if callback := rmp.GetResourceManagerCallback(event.RmID); callback != nil {
 		if err := callback.UpdateNode(response); err != nil {
 			rmp.handleUpdateResponseError(event.RmID, err)
+			tracing.RecordError(span, err)
 		}
 	} else {
 		log.Log(log.RMProxy).DPanic("RM is not registered",
@@ -188,6 +221,7 @@ func (rmp *RMProxy) handleRMEvents() {
 	for {
 		select {
 		case ev := <-rmp.pendingRMEvents:
+			metrics.GetEventQueueMetrics().SetDepth(metrics.EventQueueRMProxy, len(rmp.pendingRMEvents))
 			switch v := ev.(type) {
 			case *rmevent.RMNewAllocationsEvent:
 				rmp.processAllocationUpdateEvent(v)
@@ -208,7 +242,10 @@ func (rmp *RMProxy) handleRMEvents() {
 	}
 }
 
-func (rmp *RMProxy) RegisterResourceManager(request *si.RegisterResourceManagerRequest, callback api.ResourceManagerCallback) (*si.RegisterResourceManagerResponse, error) {
+func (rmp *RMProxy) RegisterResourceManager(request *si.RegisterResourceManagerRequest, callback api.ResourceManagerCallback) (resp *si.RegisterResourceManagerResponse, err error) {
+	_, span := tracing.Start(context.Background(), "RMProxy.RegisterResourceManager",
+		tracing.AttrRM.String(request.RmID))
+	defer func() { tracing.End(span, err) }()
 	rmp.Lock()
 	defer rmp.Unlock()
 	c := make(chan *rmevent.Result)
@@ -262,7 +299,15 @@ func (rmp *RMProxy) GetResourceManagerCallback(rmID string) api.ResourceManagerC
 	return rmp.rmIDToCallback[rmID]
 }
 
-func (rmp *RMProxy) UpdateAllocation(request *si.AllocationRequest) error {
+func (rmp *RMProxy) UpdateAllocation(request *si.AllocationRequest) (err error) {
+	keys := make([]string, 0, len(request.Allocations))
+	for _, alloc := range request.Allocations {
+		keys = append(keys, alloc.AllocationKey)
+	}
+	_, span := tracing.Start(context.Background(), "RMProxy.UpdateAllocation",
+		tracing.AttrRM.String(request.RmID),
+		tracing.AttrAllocationKey.StringSlice(keys))
+	defer func() { tracing.End(span, err) }()
 	if rmp.GetResourceManagerCallback(request.RmID) == nil {
 		return fmt.Errorf("received AllocationRequest, but RmID=\"%s\" not registered", request.RmID)
 	}
@@ -277,11 +322,15 @@ func (rmp *RMProxy) UpdateAllocation(request *si.AllocationRequest) error {
 			rel.PartitionName = common.GetNormalizedPartitionName(rel.PartitionName, request.RmID)
 		}
 	}
-	rmp.schedulerEventHandler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: request})
+	rmp.schedulerEventHandler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: request, SpanContext: span.SpanContext()})
 	return nil
 }
 
-func (rmp *RMProxy) UpdateApplication(request *si.ApplicationRequest) error {
+func (rmp *RMProxy) UpdateApplication(request *si.ApplicationRequest) (err error) {
+	_, span := tracing.Start(context.Background(), "RMProxy.UpdateApplication",
+		tracing.AttrRM.String(request.RmID),
+		tracing.AttrCount.Int(len(request.New)+len(request.Remove)))
+	defer func() { tracing.End(span, err) }()
 	if rmp.GetResourceManagerCallback(request.RmID) == nil {
 		return fmt.Errorf("received ApplicationRequest, but RmID=\"%s\" not registered", request.RmID)
 	}
@@ -300,7 +349,11 @@ func (rmp *RMProxy) UpdateApplication(request *si.ApplicationRequest) error {
 	return nil
 }
 
-func (rmp *RMProxy) UpdateNode(request *si.NodeRequest) error {
+func (rmp *RMProxy) UpdateNode(request *si.NodeRequest) (err error) {
+	_, span := tracing.Start(context.Background(), "RMProxy.UpdateNode",
+		tracing.AttrRM.String(request.RmID),
+		tracing.AttrCount.Int(len(request.Nodes)))
+	defer func() { tracing.End(span, err) }()
 	if rmp.GetResourceManagerCallback(request.RmID) == nil {
 		return fmt.Errorf("received NodeRequest, but RmID=\"%s\" not registered", request.RmID)
 	}
@@ -318,7 +371,10 @@ func (rmp *RMProxy) UpdateNode(request *si.NodeRequest) error {
 }
 
 // Triggers scheduler to reload configuration and apply the changes on-the-fly to the scheduler itself.
-func (rmp *RMProxy) UpdateConfiguration(request *si.UpdateConfigurationRequest) error {
+func (rmp *RMProxy) UpdateConfiguration(request *si.UpdateConfigurationRequest) (err error) {
+	_, span := tracing.Start(context.Background(), "RMProxy.UpdateConfiguration",
+		tracing.AttrRM.String(request.RmID))
+	defer func() { tracing.End(span, err) }()
 	c := make(chan *rmevent.Result)
 	go func() {
 		rmp.schedulerEventHandler.HandleEvent(&rmevent.RMConfigUpdateEvent{
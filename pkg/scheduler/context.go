@@ -19,11 +19,14 @@
 package scheduler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/apache/yunikorn-core/pkg/common"
@@ -35,6 +38,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
 	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
+	"github.com/apache/yunikorn-core/pkg/tracing"
 	"github.com/apache/yunikorn-core/pkg/webservice/dao"
 	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
@@ -42,6 +46,9 @@ import (
 
 const disableReservation = "DISABLE_RESERVATION"
 
+// MaxSchedulingSteps is the maximum number of scheduling cycles run in one step request on a paused partition.
+const MaxSchedulingSteps = 100
+
 type ClusterContext struct {
 	partitions     map[string]*PartitionContext
 	policyGroup    string
@@ -54,6 +61,8 @@ type ClusterContext struct {
 
 	rmInfo    map[string]*RMInformation
 	startTime time.Time
+	// end of the last scheduling cycle in unix nano, updated without holding the lock
+	lastScheduleCycle atomic.Int64
 
 	locking.RWMutex
 
@@ -115,45 +124,199 @@ func (cc *ClusterContext) setEventHandler(rmHandler handler.EventHandler) {
 
 // schedule is the main scheduling routine.
 // Process each partition in the scheduler, walk over each queue and app to check if anything can be scheduled.
-// This can be forked into a go routine per partition if needed to increase parallel allocations.
+// In the parallel partition mode each partition is scheduled by its own go routine using schedulePartition.
 // Returns true if an allocation was able to be scheduled.
 func (cc *ClusterContext) schedule() bool {
 	// schedule each partition defined in the cluster
 	activity := false
 	scheduleCycleStart := time.Now()
 	for _, psc := range cc.GetPartitionMapClone() {
-		// if there are no resources in the partition just skip
-		if psc.root.GetMaxResource() == nil {
-			continue
-		}
-		// a stopped partition does not allocate
-		if psc.isStopped() {
-			continue
+		if cc.schedulePartition(psc) {
+			activity = true
 		}
-		// try reservations first
-		schedulingStart := time.Now()
-		result := psc.tryReservedAllocate()
+	}
+	metrics.GetSchedulerMetrics().ObserveSchedulingCycle(scheduleCycleStart)
+	cc.lastScheduleCycle.Store(time.Now().UnixNano())
+	return activity
+}
+
+// schedulePartition runs one scheduling attempt for the partition.
+// Returns true if an allocation was able to be scheduled.
+func (cc *ClusterContext) schedulePartition(psc *PartitionContext) bool {
+	psc.schedulingLock.Lock()
+	defer psc.schedulingLock.Unlock()
+	// a paused partition only allocates via single steps
+	if psc.IsSchedulingPaused() {
+		return false
+	}
+	return len(cc.runSchedulingCycle(psc)) > 0
+}
+
+// runSchedulingCycle tries reservations, placeholder replacements and regular allocations for the partition.
+// Returns the allocations that were communicated to the RM. The scheduling lock of the partition must be held.
+func (cc *ClusterContext) runSchedulingCycle(psc *PartitionContext) []*objects.AllocationResult {
+	// if there are no resources in the partition just skip
+	if psc.root.GetMaxResource() == nil {
+		return nil
+	}
+	// a stopped partition does not allocate
+	if psc.isStopped() {
+		return nil
+	}
+	// only trace cycles that can allocate: an idle partition would create a span every cycle
+	ctx := context.Background()
+	span := trace.SpanFromContext(ctx)
+	if !resources.IsZero(psc.root.GetPendingResource()) {
+		ctx, span = tracing.Start(ctx, "schedule",
+			tracing.AttrPartition.String(psc.Name))
+	}
+	defer span.End()
+	// try reservations first
+	schedulingStart := time.Now()
+	result := traceAllocate(ctx, "tryReservedAllocate", psc.tryReservedAllocate)
+	if result == nil {
+		// placeholder replacement second
+		result = traceAllocate(ctx, "tryPlaceholderAllocate", psc.tryPlaceholderAllocate)
+		// nothing reserved that can be allocated try normal allocate
 		if result == nil {
-			// placeholder replacement second
-			result = psc.tryPlaceholderAllocate()
-			// nothing reserved that can be allocated try normal allocate
-			if result == nil {
-				result = psc.tryAllocate()
+			if batchSize := getSchedulingBatchSize(); batchSize > 1 {
+				return cc.scheduleBatch(ctx, psc, batchSize, schedulingStart)
 			}
+			result = traceAllocate(ctx, "tryAllocate", psc.tryAllocate)
 		}
-		metrics.GetSchedulerMetrics().ObserveSchedulingLatency(schedulingStart)
-		if result != nil {
-			if result.ResultType == objects.Replaced {
-				// communicate the removal to the RM
-				cc.notifyRMAllocationReleased(psc.RmID, psc.Name, []*objects.Allocation{result.Request.GetRelease()}, si.TerminationType_PLACEHOLDER_REPLACED, "replacing allocationKey: "+result.Request.GetAllocationKey())
-			} else {
-				cc.notifyRMNewAllocation(psc.RmID, result.Request)
-			}
-			activity = true
+	}
+	metrics.GetSchedulerMetrics().ObserveSchedulingLatency(schedulingStart)
+	if result == nil {
+		return nil
+	}
+	setResultAttributes(span, psc, result)
+	if result.ResultType == objects.Replaced {
+		// communicate the removal to the RM
+		cc.notifyRMAllocationReleased(psc.RmID, psc.Name, []*objects.Allocation{result.Request.GetRelease()}, si.TerminationType_PLACEHOLDER_REPLACED, "replacing allocationKey: "+result.Request.GetAllocationKey())
+	} else {
+		cc.notifyRMNewAllocation(psc.RmID, result.Request)
+	}
+	return []*objects.AllocationResult{result}
+}
+
+// scheduleBatch allocates multiple asks in one pass over the sorted queues of the partition.
+// All new allocations are communicated to the RM in one response.
+func (cc *ClusterContext) scheduleBatch(ctx context.Context, psc *PartitionContext, batchSize uint64, schedulingStart time.Time) []*objects.AllocationResult {
+	_, span := tracing.Start(ctx, "tryBatchAllocate")
+	results := psc.tryBatchAllocate(int(batchSize), readBatchTimeBudget(configs.GetConfigMap()))
+	span.End()
+	metrics.GetSchedulerMetrics().ObserveSchedulingLatency(schedulingStart)
+	if len(results) == 0 {
+		return nil
+	}
+	allocs := make([]*objects.Allocation, len(results))
+	for i, result := range results {
+		allocs[i] = result.Request
+	}
+	log.Log(log.SchedContext).Debug("batch allocation finished",
+		zap.String("partition", psc.Name),
+		zap.Int("allocations", len(allocs)),
+		zap.Duration("duration", time.Since(schedulingStart)))
+	cc.notifyRMNewAllocation(psc.RmID, allocs...)
+	return results
+}
+
+// StepPartition runs a number of scheduling cycles on a paused partition and reports the decisions of each cycle.
+// The allocations are communicated to the RM as in a normal scheduling cycle.
+func (cc *ClusterContext) StepPartition(psc *PartitionContext, steps int) ([]*dao.SchedulingStepDAOInfo, error) {
+	if steps < 1 || steps > MaxSchedulingSteps {
+		return nil, fmt.Errorf("number of scheduling steps must be between 1 and %d, requested %d", MaxSchedulingSteps, steps)
+	}
+	psc.schedulingLock.Lock()
+	defer psc.schedulingLock.Unlock()
+	if !psc.IsSchedulingPaused() {
+		return nil, fmt.Errorf("scheduling of partition %s is not paused", psc.Name)
+	}
+	stepInfo := make([]*dao.SchedulingStepDAOInfo, 0, steps)
+	for i := 1; i <= steps; i++ {
+		stepStart := time.Now()
+		results := cc.runSchedulingCycle(psc)
+		step := &dao.SchedulingStepDAOInfo{
+			Step:         i,
+			Reservations: psc.getReservationCount(),
+			Duration:     time.Since(stepStart).Nanoseconds(),
+		}
+		for _, result := range results {
+			step.Decisions = append(step.Decisions, getSchedulingDecision(psc, result))
 		}
+		log.Log(log.SchedContext).Info("partition scheduling step",
+			zap.String("partition", psc.Name),
+			zap.Int("step", i),
+			zap.Int("allocations", len(results)))
+		stepInfo = append(stepInfo, step)
 	}
-	metrics.GetSchedulerMetrics().ObserveSchedulingCycle(scheduleCycleStart)
-	return activity
+	return stepInfo, nil
+}
+
+func getSchedulingDecision(psc *PartitionContext, result *objects.AllocationResult) *dao.SchedulingDecisionDAOInfo {
+	alloc := result.Request
+	decision := &dao.SchedulingDecisionDAOInfo{
+		Result:        result.ResultType.String(),
+		ApplicationID: alloc.GetApplicationID(),
+		AllocationKey: alloc.GetAllocationKey(),
+		NodeID:        result.NodeID,
+		Resource:      alloc.GetAllocatedResource().DAOMap(),
+	}
+	if app := psc.getApplication(alloc.GetApplicationID()); app != nil {
+		decision.QueueName = app.GetQueuePath()
+	}
+	if release := alloc.GetRelease(); result.ResultType == objects.Replaced && release != nil {
+		decision.ReleasedAllocationKey = release.GetAllocationKey()
+	}
+	return decision
+}
+
+func getSchedulingBatchSize() uint64 {
+	return common.GetConfigurationUint(configs.GetConfigMap(), configs.CMSchedulingBatchSize, configs.DefaultSchedulingBatchSize)
+}
+
+func readBatchTimeBudget(configMap map[string]string) time.Duration {
+	value, ok := configMap[configs.CMSchedulingBatchTimeBudget]
+	if !ok {
+		return configs.DefaultBatchTimeBudget
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil || result <= 0 {
+		log.Log(log.SchedContext).Warn("Failed to parse configuration value",
+			zap.String("key", configs.CMSchedulingBatchTimeBudget),
+			zap.String("value", value),
+			zap.Error(err))
+		return configs.DefaultBatchTimeBudget
+	}
+	return result
+}
+
+// traceAllocate wraps one of the allocation attempts of the scheduling cycle in a span
+func traceAllocate(ctx context.Context, name string, allocate func() *objects.AllocationResult) *objects.AllocationResult {
+	_, span := tracing.Start(ctx, name)
+	defer span.End()
+	result := allocate()
+	if result != nil {
+		span.SetAttributes(tracing.AttrResultType.String(result.ResultType.String()))
+	}
+	return result
+}
+
+// setResultAttributes adds the details of the allocation result to the span and links it to the trace of the ask
+func setResultAttributes(span trace.Span, psc *PartitionContext, result *objects.AllocationResult) {
+	if !span.IsRecording() {
+		return
+	}
+	appID := result.Request.GetApplicationID()
+	span.SetAttributes(
+		tracing.AttrApplication.String(appID),
+		tracing.AttrNode.String(result.NodeID),
+		tracing.AttrAllocationKey.String(result.Request.GetAllocationKey()),
+		tracing.AttrResultType.String(result.ResultType.String()))
+	if app := psc.getApplication(appID); app != nil {
+		span.SetAttributes(tracing.AttrQueue.String(app.GetQueuePath()))
+	}
+	tracing.LinkTo(span, result.Request.GetTraceContext())
 }
 
 func (cc *ClusterContext) processRMRegistrationEvent(event *rmevent.RMRegistrationEvent) {
@@ -420,6 +583,15 @@ func (cc *ClusterContext) GetStartTime() time.Time {
 	return cc.startTime
 }
 
+// GetLastScheduleCycle returns the time the last scheduling cycle finished, zero if no cycle has run.
+func (cc *ClusterContext) GetLastScheduleCycle() time.Time {
+	last := cc.lastScheduleCycle.Load()
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
 func (cc *ClusterContext) GetRMInfoMapClone() map[string]*RMInformation {
 	cc.RLock()
 	defer cc.RUnlock()
@@ -711,7 +883,7 @@ func (cc *ClusterContext) updateNode(nodeInfo *si.NodeInfo) {
 func (cc *ClusterContext) handleRMUpdateAllocationEvent(event *rmevent.RMUpdateAllocationEvent) {
 	request := event.Request
 	if len(request.Allocations) != 0 {
-		cc.processAllocations(request)
+		cc.processAllocations(request, event.SpanContext)
 	}
 	if request.Releases != nil {
 		if len(request.Releases.AllocationsToRelease) > 0 {
@@ -720,7 +892,7 @@ func (cc *ClusterContext) handleRMUpdateAllocationEvent(event *rmevent.RMUpdateA
 	}
 }
 
-func (cc *ClusterContext) processAllocations(request *si.AllocationRequest) {
+func (cc *ClusterContext) processAllocations(request *si.AllocationRequest, spanContext trace.SpanContext) {
 	// Send rejected allocations back to RM
 	rejectedAllocs := make([]*si.RejectedAllocation, 0)
 
@@ -744,6 +916,7 @@ func (cc *ClusterContext) processAllocations(request *si.AllocationRequest) {
 		}
 
 		alloc := objects.NewAllocationFromSI(siAlloc)
+		alloc.SetTraceContext(spanContext)
 
 		_, newAlloc, err := partition.UpdateAllocation(alloc)
 		if err != nil {
@@ -794,13 +967,23 @@ func (cc *ClusterContext) processAllocationReleases(releases []*si.AllocationRel
 
 // Create a RM update event to notify RM of new allocations
 // Lock free call, all updates occur via events.
-func (cc *ClusterContext) notifyRMNewAllocation(rmID string, alloc *objects.Allocation) {
+func (cc *ClusterContext) notifyRMNewAllocation(rmID string, allocs ...*objects.Allocation) {
+	if len(allocs) == 0 {
+		return
+	}
+	siAllocs := make([]*si.Allocation, len(allocs))
+	keys := make([]string, len(allocs))
+	for i, alloc := range allocs {
+		siAllocs[i] = alloc.NewSIFromAllocation()
+		keys[i] = alloc.GetAllocationKey()
+	}
 	c := make(chan *rmevent.Result)
-	// communicate the allocation to the RM synchronously
+	// communicate the allocations to the RM synchronously in one response
 	cc.rmEventHandler.HandleEvent(&rmevent.RMNewAllocationsEvent{
-		Allocations: []*si.Allocation{alloc.NewSIFromAllocation()},
+		Allocations: siAllocs,
 		RmID:        rmID,
 		Channel:     c,
+		SpanContext: allocs[0].GetTraceContext(),
 	})
 	// Wait from channel
 	result := <-c
@@ -808,7 +991,7 @@ func (cc *ClusterContext) notifyRMNewAllocation(rmID string, alloc *objects.Allo
 		log.Log(log.SchedContext).Debug("Successfully synced shim on new allocation. response: " + result.Reason)
 	} else {
 		log.Log(log.SchedContext).Info("failed to sync shim on new allocation",
-			zap.String("Allocation key: ", alloc.GetAllocationKey()))
+			zap.Strings("Allocation keys: ", keys))
 	}
 }
 
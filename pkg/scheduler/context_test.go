@@ -19,6 +19,9 @@
 package scheduler
 
 import (
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -30,6 +33,8 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
+	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
+	"github.com/apache/yunikorn-core/pkg/tracing"
 	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
@@ -396,6 +401,210 @@ func TestContext_OnAllocationNotification(t *testing.T) {
 	assert.Assert(t, lastAllocEvent == nil, "unexpected allocation event")
 }
 
+func TestContext_ScheduleBatch(t *testing.T) {
+	context := createTestContext(t, pName)
+	defer context.Stop()
+	configs.SetConfigMap(map[string]string{configs.CMSchedulingBatchSize: "10"})
+	defer configs.SetConfigMap(map[string]string{})
+
+	eventHandler := context.rmEventHandler.(*mockEventHandler) //nolint:errcheck
+	allocEvents := make([]*rmevent.RMNewAllocationsEvent, 0)
+	eventHandler.newAllocHandler = func(event *rmevent.RMNewAllocationsEvent) {
+		allocEvents = append(allocEvents, event)
+		go func() {
+			event.Channel <- &rmevent.Result{Succeeded: true}
+		}()
+	}
+	err := context.addNode(getNodeInfoForAddingNode(), true)
+	assert.NilError(t, err, "unexpected error returned from addNode")
+	partition := context.GetPartition(pName)
+	assert.Assert(t, partition != nil)
+	appReq := &si.ApplicationRequest{
+		New: []*si.AddApplicationRequest{
+			{
+				QueueName:     defQueue,
+				PartitionName: pName,
+				Ugi: &si.UserGroupInformation{
+					User:   "testuser",
+					Groups: []string{"testgroup"},
+				},
+				ApplicationID: appID1,
+			},
+		},
+		RmID: "rm:123",
+	}
+	context.handleRMUpdateApplicationEvent(&rmevent.RMUpdateApplicationEvent{Request: appReq})
+	asks := make([]*si.Allocation, 3)
+	for i := range asks {
+		asks[i] = &si.Allocation{
+			AllocationKey: "alloc-" + strconv.Itoa(i),
+			ResourcePerAlloc: &si.Resource{
+				Resources: map[string]*si.Quantity{
+					"first": {Value: 1},
+				},
+			},
+			ApplicationID: appID1,
+			PartitionName: pName,
+		}
+	}
+	context.handleRMUpdateAllocationEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{Allocations: asks, RmID: "rm:123"}})
+	assert.Equal(t, len(allocEvents), 0, "asks should not have been allocated yet")
+
+	// all asks are allocated in one cycle and communicated in one response
+	assert.Assert(t, context.schedulePartition(partition), "expected activity in the scheduling cycle")
+	assert.Equal(t, len(allocEvents), 1, "expected one batched allocation event")
+	assert.Equal(t, len(allocEvents[0].Allocations), 3, "expected all asks in the batched allocation event")
+	assert.Assert(t, !context.schedulePartition(partition), "no activity expected after the batch")
+	assert.Equal(t, len(allocEvents), 1, "unexpected allocation event")
+}
+
+// readSpans flushes the file exporter and returns the spans written
+func readSpans(t *testing.T, file string) string {
+	tracing.Shutdown()
+	content, err := os.ReadFile(file)
+	assert.NilError(t, err)
+	return string(content)
+}
+
+func TestContext_ScheduleTracing(t *testing.T) {
+	context := createTestContext(t, pName)
+	defer context.Stop()
+	defer tracing.Shutdown()
+	eventHandler := context.rmEventHandler.(*mockEventHandler) //nolint:errcheck
+	eventHandler.newAllocHandler = func(event *rmevent.RMNewAllocationsEvent) {
+		go func() {
+			event.Channel <- &rmevent.Result{Succeeded: true}
+		}()
+	}
+	err := context.addNode(getNodeInfoForAddingNode(), true)
+	assert.NilError(t, err, "unexpected error returned from addNode")
+	partition := context.GetPartition(pName)
+	assert.Assert(t, partition != nil)
+
+	// an idle partition does not create spans
+	file := filepath.Join(t.TempDir(), "idle.json")
+	tracing.Configure(map[string]string{configs.CMTraceExporter: tracing.ExporterFile, configs.CMTraceFile: file})
+	assert.Assert(t, !context.schedulePartition(partition), "no activity expected without asks")
+	assert.Assert(t, !strings.Contains(readSpans(t, file), `"Name":"schedule"`), "idle cycle should not be traced")
+
+	appReq := &si.ApplicationRequest{
+		New: []*si.AddApplicationRequest{
+			{
+				QueueName:     defQueue,
+				PartitionName: pName,
+				Ugi: &si.UserGroupInformation{
+					User:   "testuser",
+					Groups: []string{"testgroup"},
+				},
+				ApplicationID: appID1,
+			},
+		},
+		RmID: "rm:123",
+	}
+	context.handleRMUpdateApplicationEvent(&rmevent.RMUpdateApplicationEvent{Request: appReq})
+	ask := &si.Allocation{
+		AllocationKey: "alloc-1",
+		ResourcePerAlloc: &si.Resource{
+			Resources: map[string]*si.Quantity{
+				"first": {Value: 1},
+			},
+		},
+		ApplicationID: appID1,
+		PartitionName: pName,
+	}
+	context.handleRMUpdateAllocationEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{Allocations: []*si.Allocation{ask}, RmID: "rm:123"}})
+	file = filepath.Join(t.TempDir(), "pending.json")
+	tracing.Configure(map[string]string{configs.CMTraceExporter: tracing.ExporterFile, configs.CMTraceFile: file})
+	assert.Assert(t, context.schedulePartition(partition), "expected activity in the scheduling cycle")
+	assert.Assert(t, strings.Contains(readSpans(t, file), `"Name":"schedule"`), "cycle with pending asks should be traced")
+}
+
+func TestContext_PauseAndStepPartition(t *testing.T) {
+	context := createTestContext(t, pName)
+	defer context.Stop()
+
+	eventHandler := context.rmEventHandler.(*mockEventHandler) //nolint:errcheck
+	allocEvents := make([]*rmevent.RMNewAllocationsEvent, 0)
+	eventHandler.newAllocHandler = func(event *rmevent.RMNewAllocationsEvent) {
+		allocEvents = append(allocEvents, event)
+		go func() {
+			event.Channel <- &rmevent.Result{Succeeded: true}
+		}()
+	}
+	err := context.addNode(getNodeInfoForAddingNode(), true)
+	assert.NilError(t, err, "unexpected error returned from addNode")
+	partition := context.GetPartition(pName)
+	assert.Assert(t, partition != nil)
+
+	_, err = context.StepPartition(partition, 1)
+	assert.ErrorContains(t, err, "is not paused")
+	partition.PauseScheduling()
+	assert.Assert(t, partition.IsSchedulingPaused(), "partition should be paused")
+	assert.Assert(t, !partition.GetSchedulingPausedTime().IsZero(), "paused time should be set")
+
+	// updates from the RM are processed while paused
+	appReq := &si.ApplicationRequest{
+		New: []*si.AddApplicationRequest{
+			{
+				QueueName:     defQueue,
+				PartitionName: pName,
+				Ugi: &si.UserGroupInformation{
+					User:   "testuser",
+					Groups: []string{"testgroup"},
+				},
+				ApplicationID: appID1,
+			},
+		},
+		RmID: "rm:123",
+	}
+	context.handleRMUpdateApplicationEvent(&rmevent.RMUpdateApplicationEvent{Request: appReq})
+	asks := make([]*si.Allocation, 3)
+	for i := range asks {
+		asks[i] = &si.Allocation{
+			AllocationKey: "alloc-" + strconv.Itoa(i),
+			ResourcePerAlloc: &si.Resource{
+				Resources: map[string]*si.Quantity{
+					"first": {Value: 1},
+				},
+			},
+			ApplicationID: appID1,
+			PartitionName: pName,
+		}
+	}
+	context.handleRMUpdateAllocationEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{Allocations: asks, RmID: "rm:123"}})
+	assert.Assert(t, partition.getApplication(appID1) != nil, "application should have been added while paused")
+	assert.Assert(t, !context.schedulePartition(partition), "paused partition should not allocate")
+	assert.Equal(t, len(allocEvents), 0, "asks should not have been allocated while paused")
+
+	_, err = context.StepPartition(partition, 0)
+	assert.ErrorContains(t, err, "number of scheduling steps")
+	_, err = context.StepPartition(partition, MaxSchedulingSteps+1)
+	assert.ErrorContains(t, err, "number of scheduling steps")
+
+	// each step allocates one ask, the last step has nothing left to allocate
+	steps, err := context.StepPartition(partition, 4)
+	assert.NilError(t, err, "stepping a paused partition should not fail")
+	assert.Equal(t, len(steps), 4)
+	for i, step := range steps[:3] {
+		assert.Equal(t, step.Step, i+1)
+		assert.Equal(t, len(step.Decisions), 1, "expected one decision in step %d", i+1)
+		assert.Equal(t, step.Decisions[0].Result, objects.Allocated.String())
+		assert.Equal(t, step.Decisions[0].ApplicationID, appID1)
+		assert.Equal(t, step.Decisions[0].QueueName, defQueue)
+		assert.Equal(t, step.Decisions[0].NodeID, "test-1")
+		assert.DeepEqual(t, step.Decisions[0].Resource, map[string]int64{"first": 1})
+	}
+	assert.Equal(t, len(steps[3].Decisions), 0, "no decision expected in the last step")
+	assert.Equal(t, len(allocEvents), 3, "step allocations should be communicated to the RM")
+	assert.Assert(t, partition.IsSchedulingPaused(), "partition should still be paused after stepping")
+
+	partition.ResumeScheduling()
+	assert.Assert(t, !partition.IsSchedulingPaused(), "partition should be resumed")
+	assert.Assert(t, partition.GetSchedulingPausedTime().IsZero(), "paused time should be cleared")
+	_, err = context.StepPartition(partition, 1)
+	assert.ErrorContains(t, err, "is not paused")
+}
+
 func getNodeInfoForAddingNode() *si.NodeInfo {
 	n := &si.NodeInfo{
 		NodeID:              "test-1",
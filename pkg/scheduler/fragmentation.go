@@ -0,0 +1,119 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"math"
+	"sort"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
+)
+
+// GetFragmentationReport returns the fragmentation of the available resources per resource type.
+// If the shape is not empty the report contains the largest ask of that shape that fits on a single node.
+// Nodes that are not schedulable are not considered. Reserved nodes are part of the available resources and the
+// fragmentation, but are not considered for the largest ask.
+// NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
+func (pc *PartitionContext) GetFragmentationReport(shape *resources.Resource) *dao.FragmentationDAOInfo {
+	report := &dao.FragmentationDAOInfo{
+		Partition: common.GetPartitionNameWithoutClusterID(pc.Name),
+	}
+	total := make(map[string]int64)
+	largest := make(map[string]int64)
+	largestNode := make(map[string]string)
+	var largestAsk *dao.LargestAskDAOInfo
+	if !resources.IsZero(shape) {
+		largestAsk = &dao.LargestAskDAOInfo{Shape: shape.DAOMap()}
+	}
+	for _, node := range pc.GetNodes() {
+		if !node.IsSchedulable() {
+			continue
+		}
+		// the free resources of a reserved node are still part of the partition
+		reserved := node.IsReserved()
+		if reserved {
+			report.ReservedNodes++
+		} else {
+			report.Nodes++
+		}
+		available := node.GetAvailableResource()
+		for name := range node.GetCapacity().Resources {
+			// over allocated nodes have nothing available
+			free := max(int64(available.Resources[name]), 0)
+			total[name] += free
+			if _, ok := largest[name]; !ok || free > largest[name] {
+				largest[name] = free
+				largestNode[name] = node.NodeID
+			}
+		}
+		// a reserved node is only available for the reservation: it cannot take the ask
+		if largestAsk != nil && !reserved {
+			multiple := shapeMultiple(shape, available)
+			if multiple >= 1 {
+				largestAsk.FittingNodes++
+			}
+			if multiple > largestAsk.Multiple {
+				largestAsk.Multiple = multiple
+				largestAsk.NodeID = node.NodeID
+			}
+		}
+	}
+	for name, totalAvailable := range total {
+		info := &dao.ResourceFragmentationDAOInfo{
+			Resource:         name,
+			TotalAvailable:   totalAvailable,
+			LargestAvailable: largest[name],
+		}
+		if totalAvailable > 0 {
+			info.LargestNodeID = largestNode[name]
+			info.Fragmentation = 1 - float64(largest[name])/float64(totalAvailable)
+		}
+		report.Resources = append(report.Resources, info)
+	}
+	sort.Slice(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Resource < report.Resources[j].Resource
+	})
+	if largestAsk != nil && largestAsk.Multiple > 0 {
+		largestAsk.Resource = make(map[string]int64, len(shape.Resources))
+		for name, quantity := range shape.Resources {
+			largestAsk.Resource[name] = int64(math.Floor(float64(quantity) * largestAsk.Multiple))
+		}
+	}
+	report.LargestAsk = largestAsk
+	return report
+}
+
+// shapeMultiple returns how many times the shape fits in the available resource, as a fraction.
+// Resource types with a zero quantity in the shape are ignored, a type not available returns 0.
+func shapeMultiple(shape, available *resources.Resource) float64 {
+	multiple := math.Inf(1)
+	for name, quantity := range shape.Resources {
+		if quantity <= 0 {
+			continue
+		}
+		free := max(float64(available.Resources[name]), 0)
+		multiple = math.Min(multiple, free/float64(quantity))
+	}
+	if math.IsInf(multiple, 1) {
+		return 0
+	}
+	return multiple
+}
@@ -0,0 +1,97 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func TestGetFragmentationReport(t *testing.T) {
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "partition create failed")
+	defer partition.userGroupCache.Stop()
+
+	// empty partition
+	report := partition.GetFragmentationReport(nil)
+	assert.Equal(t, "test", report.Partition)
+	assert.Equal(t, 0, report.Nodes)
+	assert.Equal(t, 0, len(report.Resources))
+	assert.Assert(t, report.LargestAsk == nil, "no shape should not return a largest ask")
+
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 8000, "memory": 100})
+	for _, nodeID := range []string{nodeID1, nodeID2, "node-3", "node-4"} {
+		err = partition.AddNode(objects.NewNode(&si.NodeInfo{
+			NodeID:              nodeID,
+			Attributes:          map[string]string{},
+			SchedulableResource: capacity.ToProto(),
+		}))
+		assert.NilError(t, err)
+	}
+	// node-1: 2 vcore, 60 memory free
+	partition.GetNode(nodeID1).AddAllocation(newAllocation("alloc-1", appID1, nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 6000, "memory": 40})))
+	// node-2: 6 vcore, 20 memory free
+	partition.GetNode(nodeID2).AddAllocation(newAllocation("alloc-2", appID1, nodeID2, resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 2000, "memory": 80})))
+	// node-3: reserved, 8 vcore, 50 memory free: counted in the resources, not for the largest ask
+	partition.GetNode("node-3").AddAllocation(newAllocation("alloc-3", appID1, "node-3", resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 50})))
+	app := newApplication(appID1, "test", "root.default")
+	err = partition.GetNode("node-3").Reserve(app, newAllocationAsk("ask-1", appID1, capacity))
+	assert.NilError(t, err, "reservation failed")
+	// node-4: not schedulable
+	partition.GetNode("node-4").SetSchedulable(false)
+
+	shape := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 4000, "memory": 10})
+	report = partition.GetFragmentationReport(shape)
+	assert.Equal(t, 2, report.Nodes)
+	assert.Equal(t, 1, report.ReservedNodes)
+	assert.Equal(t, 2, len(report.Resources))
+	memory := report.Resources[0]
+	assert.Equal(t, "memory", memory.Resource)
+	assert.Equal(t, int64(130), memory.TotalAvailable)
+	assert.Equal(t, int64(60), memory.LargestAvailable)
+	assert.Equal(t, nodeID1, memory.LargestNodeID)
+	assert.Equal(t, 1-60.0/130.0, memory.Fragmentation)
+	vcore := report.Resources[1]
+	assert.Equal(t, "vcore", vcore.Resource)
+	assert.Equal(t, int64(16000), vcore.TotalAvailable)
+	assert.Equal(t, int64(8000), vcore.LargestAvailable)
+	assert.Equal(t, "node-3", vcore.LargestNodeID)
+	assert.Equal(t, 0.5, vcore.Fragmentation)
+
+	// node-1 fits half the shape (vcore bound), node-2 fits 1.5 times (vcore bound)
+	// node-3 would fit twice but is reserved
+	assert.Assert(t, report.LargestAsk != nil, "shape should return a largest ask")
+	assert.Equal(t, nodeID2, report.LargestAsk.NodeID)
+	assert.Equal(t, 1.5, report.LargestAsk.Multiple)
+	assert.Equal(t, 1, report.LargestAsk.FittingNodes)
+	assert.DeepEqual(t, map[string]int64{"vcore": 6000, "memory": 15}, report.LargestAsk.Resource)
+
+	// shape that does not fit anywhere
+	shape = resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000, "gpu": 1})
+	report = partition.GetFragmentationReport(shape)
+	assert.Equal(t, 0.0, report.LargestAsk.Multiple)
+	assert.Equal(t, 0, report.LargestAsk.FittingNodes)
+	assert.Equal(t, "", report.LargestAsk.NodeID)
+	assert.Assert(t, report.LargestAsk.Resource == nil, "no fit should not return a resource")
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/events"
 	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
 	"github.com/apache/yunikorn-core/pkg/metrics"
@@ -41,6 +42,7 @@ type HealthChecker struct {
 	stopChan *chan struct{}
 	period   time.Duration
 	enabled  bool
+	checks   []*HealthCheck // checks specific to this instance, run after the default checks
 
 	locking.RWMutex
 }
@@ -66,22 +68,43 @@ func (c *HealthChecker) IsEnabled() bool {
 	return c.enabled
 }
 
+// AddHealthCheck adds a check that is only run by this health checker.
+func (c *HealthChecker) AddHealthCheck(check *HealthCheck) {
+	c.Lock()
+	defer c.Unlock()
+	c.checks = append(c.checks, check)
+}
+
+func (c *HealthChecker) getHealthChecks() []*HealthCheck {
+	c.RLock()
+	defer c.RUnlock()
+	checks := defaultHealthChecks(metrics.GetSchedulerMetrics(), c.context)
+	checks = append(checks, c.checks...)
+	return append(checks, getRegisteredHealthChecks()...)
+}
+
 func (c *HealthChecker) readPeriod() time.Duration {
-	value, ok := configs.GetConfigMap()[configs.HealthCheckInterval]
+	result := readHealthDuration(configs.HealthCheckInterval, configs.DefaultHealthCheckInterval)
+	if result < 0 {
+		result = 0
+	}
+	return result
+}
+
+// readHealthDuration returns the duration set in the config map for the key or the default if not set or invalid
+func readHealthDuration(key string, defaultValue time.Duration) time.Duration {
+	value, ok := configs.GetConfigMap()[key]
 	if !ok {
-		return configs.DefaultHealthCheckInterval
+		return defaultValue
 	}
 
 	result, err := time.ParseDuration(value)
 	if err != nil {
 		log.Log(log.SchedHealth).Warn("Failed to parse configuration value",
-			zap.String("key", configs.HealthCheckInterval),
+			zap.String("key", key),
 			zap.String("value", value),
 			zap.Error(err))
-		return configs.DefaultHealthCheckInterval
-	}
-	if result < 0 {
-		result = 0
+		return defaultValue
 	}
 	return result
 }
@@ -167,8 +190,7 @@ func (c *HealthChecker) isRestartNeeded() bool {
 }
 
 func (c *HealthChecker) runOnce() {
-	schedulerMetrics := metrics.GetSchedulerMetrics()
-	result := GetSchedulerHealthStatus(schedulerMetrics, c.context)
+	result := runHealthChecks(c.getHealthChecks())
 	updateSchedulerLastHealthStatus(&result, c.context)
 	if !result.Healthy {
 		for _, v := range result.HealthChecks {
@@ -177,6 +199,7 @@ func (c *HealthChecker) runOnce() {
 			}
 			log.Log(log.SchedHealth).Warn("Scheduler is not healthy",
 				zap.String("name", v.Name),
+				zap.String("severity", v.Severity),
 				zap.String("description", v.Description),
 				zap.String("message", v.DiagnosisMessage))
 		}
@@ -189,21 +212,60 @@ func updateSchedulerLastHealthStatus(latest *dao.SchedulerHealthDAOInfo, schedul
 	schedulerContext.SetLastHealthCheckResult(latest)
 }
 
+// GetSchedulerHealthStatus runs the default and registered health checks.
 func GetSchedulerHealthStatus(metrics *metrics.SchedulerMetrics, schedulerContext *ClusterContext) dao.SchedulerHealthDAOInfo {
-	var healthInfo []dao.HealthCheckInfo
-	healthInfo = append(healthInfo, checkSchedulingErrors(metrics))
-	healthInfo = append(healthInfo, checkFailedNodes(metrics))
-	healthInfo = append(healthInfo, checkSchedulingContext(schedulerContext)...)
-	healthy := true
-	for _, h := range healthInfo {
-		if !h.Succeeded {
-			healthy = false
-			break
-		}
+	checks := defaultHealthChecks(metrics, schedulerContext)
+	return runHealthChecks(append(checks, getRegisteredHealthChecks()...))
+}
+
+// defaultHealthChecks returns the built-in checks that only depend on the metrics and the scheduling context.
+func defaultHealthChecks(metrics *metrics.SchedulerMetrics, schedulerContext *ClusterContext) []*HealthCheck {
+	return []*HealthCheck{
+		{
+			Name:     "Scheduling errors",
+			Severity: SeverityCritical,
+			Probe:    ProbeReadiness,
+			Run: func() []dao.HealthCheckInfo {
+				return []dao.HealthCheckInfo{checkSchedulingErrors(metrics)}
+			},
+		},
+		{
+			Name:     "Failed nodes",
+			Severity: SeverityCritical,
+			Probe:    ProbeReadiness,
+			Run: func() []dao.HealthCheckInfo {
+				return []dao.HealthCheckInfo{checkFailedNodes(metrics)}
+			},
+		},
+		{
+			Name:     "Scheduling context",
+			Severity: SeverityCritical,
+			Probe:    ProbeReadiness,
+			Run: func() []dao.HealthCheckInfo {
+				return checkSchedulingContext(schedulerContext)
+			},
+		},
+		{
+			Name:     "Event publisher",
+			Severity: SeverityCritical,
+			Probe:    ProbeLiveness,
+			Run: func() []dao.HealthCheckInfo {
+				return []dao.HealthCheckInfo{checkEventPublisher(events.GetEventSystem())}
+			},
+		},
 	}
-	return dao.SchedulerHealthDAOInfo{
-		Healthy:      healthy,
-		HealthChecks: healthInfo,
+}
+
+// newSchedulingLoopCheck returns the check for a scheduling loop that has not completed a cycle for too long.
+// Only added when the scheduling loop runs automatically.
+func newSchedulingLoopCheck(schedulerContext *ClusterContext) *HealthCheck {
+	return &HealthCheck{
+		Name:     "Scheduling loop",
+		Severity: SeverityCritical,
+		Probe:    ProbeLiveness,
+		Run: func() []dao.HealthCheckInfo {
+			return []dao.HealthCheckInfo{checkSchedulingLoop(schedulerContext, time.Now())}
+		},
 	}
 }
 func CreateCheckInfo(succeeded bool, name, description, message string) dao.HealthCheckInfo {
@@ -232,6 +294,31 @@ func checkFailedNodes(metrics *metrics.SchedulerMetrics) dao.HealthCheckInfo {
 	return CreateCheckInfo(failedNodes == 0, "Failed nodes", "Check for failed nodes entries in metrics", diagnosisMsg)
 }
 
+func checkEventPublisher(eventSystem events.EventSystem) dao.HealthCheckInfo {
+	const name, description = "Event publisher", "Check if the event publisher pushes events to the shim"
+	lastRun := eventSystem.GetPublisherLastRun()
+	if lastRun.IsZero() {
+		return CreateCheckInfo(true, name, description, "Event publisher is not running")
+	}
+	timeout := readHealthDuration(configs.HealthEventPublisherTimeout, configs.DefaultEventPublisherTimeout)
+	since := time.Since(lastRun)
+	return CreateCheckInfo(since <= timeout, name, description,
+		fmt.Sprintf("Last event push finished %s ago, timeout %s", since.Round(time.Millisecond), timeout))
+}
+
+func checkSchedulingLoop(schedulerContext *ClusterContext, now time.Time) dao.HealthCheckInfo {
+	const name, description = "Scheduling loop", "Check if the scheduling loop completes scheduling cycles"
+	// a loop that got stuck in the first cycle is measured from the start of the scheduler
+	lastCycle := schedulerContext.GetLastScheduleCycle()
+	if lastCycle.IsZero() {
+		lastCycle = schedulerContext.GetStartTime()
+	}
+	timeout := readHealthDuration(configs.HealthSchedulingStallTimeout, configs.DefaultSchedulingStallTimeout)
+	since := now.Sub(lastCycle)
+	return CreateCheckInfo(since <= timeout, name, description,
+		fmt.Sprintf("Last scheduling cycle finished %s ago, timeout %s", since.Round(time.Millisecond), timeout))
+}
+
 func checkSchedulingContext(schedulerContext *ClusterContext) []dao.HealthCheckInfo {
 	// check for negative resources
 	var partitionsWithNegResources []string
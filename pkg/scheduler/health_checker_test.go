@@ -19,6 +19,7 @@
 package scheduler
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -27,8 +28,10 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/events/mock"
 	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -260,3 +263,108 @@ func TestGetSchedulerHealthStatusMetrics(t *testing.T) {
 	healthInfo = GetSchedulerHealthStatus(schedulerMetrics, schedulerContext)
 	assert.Assert(t, !healthInfo.Healthy, "Scheduler should not be healthy")
 }
+
+func TestRegisterHealthCheck(t *testing.T) {
+	metrics.Reset()
+	schedulerContext, err := NewClusterContext("rmID", "policyGroup", []byte(configDefault))
+	assert.NilError(t, err, "Error when load schedulerContext from config")
+
+	assert.ErrorContains(t, RegisterHealthCheck(nil), "must have a name")
+	assert.ErrorContains(t, RegisterHealthCheck(&HealthCheck{Name: "no run"}), "must have a name")
+	succeeded := true
+	warning := &HealthCheck{
+		Name:     "plugin warning",
+		Severity: SeverityWarning,
+		Probe:    ProbeReadiness,
+		Run: func() []dao.HealthCheckInfo {
+			return []dao.HealthCheckInfo{CreateCheckInfo(succeeded, "plugin warning", "warning check", "")}
+		},
+	}
+	assert.NilError(t, RegisterHealthCheck(warning))
+	defer UnregisterHealthCheck("plugin warning")
+	assert.ErrorContains(t, RegisterHealthCheck(warning), "already registered")
+
+	// a failed warning is reported but does not make the scheduler unhealthy
+	succeeded = false
+	healthInfo := GetSchedulerHealthStatus(metrics.GetSchedulerMetrics(), schedulerContext)
+	assert.Assert(t, healthInfo.Healthy, "Scheduler should be healthy")
+	last := healthInfo.HealthChecks[len(healthInfo.HealthChecks)-1]
+	assert.Equal(t, "plugin warning", last.Name)
+	assert.Assert(t, !last.Succeeded, "warning check should have failed")
+	assert.Equal(t, "warning", last.Severity)
+	assert.Equal(t, "readiness", last.Probe)
+
+	// a critical check that panics fails
+	assert.NilError(t, RegisterHealthCheck(&HealthCheck{
+		Name:     "plugin panic",
+		Severity: SeverityCritical,
+		Probe:    ProbeLiveness,
+		Run: func() []dao.HealthCheckInfo {
+			panic("broken check")
+		},
+	}))
+	healthInfo = GetSchedulerHealthStatus(metrics.GetSchedulerMetrics(), schedulerContext)
+	assert.Assert(t, !healthInfo.Healthy, "Scheduler should not be healthy")
+	last = healthInfo.HealthChecks[len(healthInfo.HealthChecks)-1]
+	assert.Equal(t, "plugin panic", last.Name)
+	assert.Equal(t, "critical", last.Severity)
+	assert.Equal(t, "liveness", last.Probe)
+	assert.Equal(t, "panic: broken check", last.DiagnosisMessage)
+
+	assert.Assert(t, UnregisterHealthCheck("plugin panic"), "check should have been unregistered")
+	assert.Assert(t, !UnregisterHealthCheck("plugin panic"), "check was already unregistered")
+	healthInfo = GetSchedulerHealthStatus(metrics.GetSchedulerMetrics(), schedulerContext)
+	assert.Assert(t, healthInfo.Healthy, "Scheduler should be healthy")
+}
+
+func TestCheckSchedulingLoop(t *testing.T) {
+	configs.SetConfigMap(map[string]string{configs.HealthSchedulingStallTimeout: "10s"})
+	defer configs.SetConfigMap(map[string]string{})
+	schedulerContext, err := NewClusterContext("rmID", "policyGroup", []byte(configDefault))
+	assert.NilError(t, err, "Error when load schedulerContext from config")
+
+	// no cycle yet: measured from the start time
+	start := schedulerContext.GetStartTime()
+	assert.Assert(t, checkSchedulingLoop(schedulerContext, start.Add(5*time.Second)).Succeeded, "loop should not be stalled")
+	assert.Assert(t, !checkSchedulingLoop(schedulerContext, start.Add(time.Minute)).Succeeded, "loop should be stalled")
+
+	schedulerContext.schedule()
+	lastCycle := schedulerContext.GetLastScheduleCycle()
+	assert.Assert(t, !lastCycle.IsZero(), "last cycle should be set")
+	assert.Assert(t, checkSchedulingLoop(schedulerContext, lastCycle.Add(5*time.Second)).Succeeded, "loop should not be stalled")
+	assert.Assert(t, !checkSchedulingLoop(schedulerContext, lastCycle.Add(11*time.Second)).Succeeded, "loop should be stalled")
+}
+
+func TestCheckEventPublisher(t *testing.T) {
+	info := checkEventPublisher(mock.NewEventSystem())
+	assert.Assert(t, info.Succeeded, "publisher not running should succeed")
+	assert.Equal(t, "Event publisher is not running", info.DiagnosisMessage)
+
+	configs.SetConfigMap(map[string]string{configs.HealthEventPublisherTimeout: "1ms"})
+	defer configs.SetConfigMap(map[string]string{})
+	info = checkEventPublisher(&stuckEventSystem{EventSystem: mock.NewEventSystem(), lastRun: time.Now().Add(-time.Second)})
+	assert.Assert(t, !info.Succeeded, "stuck publisher should fail")
+}
+
+func TestCheckEventQueues(t *testing.T) {
+	configs.SetConfigMap(map[string]string{configs.HealthEventQueueThreshold: "50"})
+	defer configs.SetConfigMap(map[string]string{})
+	s := NewScheduler()
+	assert.Assert(t, s.checkEventQueues().Succeeded, "empty queues should succeed")
+	for i := 0; i < cap(s.pendingInfraEvents)/2; i++ {
+		s.pendingInfraEvents <- struct{}{}
+	}
+	info := s.checkEventQueues()
+	assert.Assert(t, !info.Succeeded, "saturated infra queue should fail")
+	assert.Assert(t, strings.Contains(info.DiagnosisMessage, `["infra"]`), "unexpected message: %s", info.DiagnosisMessage)
+}
+
+// stuckEventSystem returns a fixed last run of the publisher
+type stuckEventSystem struct {
+	*mock.EventSystem
+	lastRun time.Time
+}
+
+func (s *stuckEventSystem) GetPublisherLastRun() time.Time {
+	return s.lastRun
+}
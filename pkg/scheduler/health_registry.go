@@ -0,0 +1,154 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
+)
+
+// HealthCheckSeverity defines the impact of a failed health check
+type HealthCheckSeverity int
+
+const (
+	// SeverityCritical a failed check marks the scheduler unhealthy and fails the probe of the check
+	SeverityCritical HealthCheckSeverity = iota
+	// SeverityWarning a failed check is reported but does not change the health of the scheduler
+	SeverityWarning
+)
+
+func (s HealthCheckSeverity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "critical"
+}
+
+// HealthProbe defines which probe a health check is part of
+type HealthProbe int
+
+const (
+	// ProbeReadiness the check fails if the scheduler cannot serve requests, only evaluated for readiness
+	ProbeReadiness HealthProbe = iota
+	// ProbeLiveness the check fails if the scheduler needs a restart, evaluated for liveness and readiness
+	ProbeLiveness
+)
+
+func (p HealthProbe) String() string {
+	if p == ProbeLiveness {
+		return "liveness"
+	}
+	return "readiness"
+}
+
+// HealthCheck is a named check run by the health checker.
+// Run returns one or more results, the severity and probe of the check apply to all of them.
+type HealthCheck struct {
+	Name     string
+	Severity HealthCheckSeverity
+	Probe    HealthProbe
+	Run      func() []dao.HealthCheckInfo
+}
+
+var registeredChecks = struct {
+	checks []*HealthCheck
+	locking.RWMutex
+}{}
+
+// RegisterHealthCheck adds a health check, for instance from a plugin, to the checks run by the health checker.
+// The check is run with the built-in checks on the next health check run. Names must be unique.
+func RegisterHealthCheck(check *HealthCheck) error {
+	if check == nil || check.Name == "" || check.Run == nil {
+		return errors.New("health check must have a name and a run function")
+	}
+	registeredChecks.Lock()
+	defer registeredChecks.Unlock()
+	for _, registered := range registeredChecks.checks {
+		if registered.Name == check.Name {
+			return fmt.Errorf("health check %s is already registered", check.Name)
+		}
+	}
+	registeredChecks.checks = append(registeredChecks.checks, check)
+	log.Log(log.SchedHealth).Info("registered health check",
+		zap.String("name", check.Name),
+		zap.Stringer("severity", check.Severity),
+		zap.Stringer("probe", check.Probe))
+	return nil
+}
+
+// UnregisterHealthCheck removes a registered health check, returns false if the check was not registered.
+func UnregisterHealthCheck(name string) bool {
+	registeredChecks.Lock()
+	defer registeredChecks.Unlock()
+	for i, registered := range registeredChecks.checks {
+		if registered.Name == name {
+			registeredChecks.checks = append(registeredChecks.checks[:i], registeredChecks.checks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func getRegisteredHealthChecks() []*HealthCheck {
+	registeredChecks.RLock()
+	defer registeredChecks.RUnlock()
+	checks := make([]*HealthCheck, len(registeredChecks.checks))
+	copy(checks, registeredChecks.checks)
+	return checks
+}
+
+// runHealthChecks runs the checks in order and combines the results.
+// The scheduler is healthy if none of the critical checks failed.
+func runHealthChecks(checks []*HealthCheck) dao.SchedulerHealthDAOInfo {
+	var healthInfo []dao.HealthCheckInfo
+	healthy := true
+	for _, check := range checks {
+		for _, info := range runHealthCheck(check) {
+			info.Severity = check.Severity.String()
+			info.Probe = check.Probe.String()
+			if !info.Succeeded && check.Severity == SeverityCritical {
+				healthy = false
+			}
+			healthInfo = append(healthInfo, info)
+		}
+	}
+	return dao.SchedulerHealthDAOInfo{
+		Healthy:      healthy,
+		HealthChecks: healthInfo,
+	}
+}
+
+// runHealthCheck runs a single check, a check that panics is reported as failed
+func runHealthCheck(check *HealthCheck) (result []dao.HealthCheckInfo) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Log(log.SchedHealth).Error("health check panicked",
+				zap.String("name", check.Name),
+				zap.Any("panic", r))
+			result = []dao.HealthCheckInfo{CreateCheckInfo(false, check.Name, "Health check failed to run", fmt.Sprintf("panic: %v", r))}
+		}
+	}()
+	return check.Run()
+}
@@ -0,0 +1,90 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/metrics/history"
+	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
+)
+
+// GetResourceHistorySample returns the current resource series of all partitions for the internal metrics history.
+// The partition names are stored without the cluster ID, matching the names used by the REST API.
+func (cc *ClusterContext) GetResourceHistorySample() map[history.SeriesKey]map[string]int64 {
+	sample := make(map[history.SeriesKey]map[string]int64)
+	for _, pc := range cc.GetPartitionMapClone() {
+		pc.addResourceHistorySample(sample)
+	}
+	return sample
+}
+
+// addResourceHistorySample adds the partition level and queue level resource series to the sample
+func (pc *PartitionContext) addResourceHistorySample(sample map[history.SeriesKey]map[string]int64) {
+	partitionName := common.GetPartitionNameWithoutClusterID(pc.Name)
+	pc.RLock()
+	root := pc.root
+	pc.RUnlock()
+	if root == nil {
+		return
+	}
+	sample[history.SeriesKey{Partition: partitionName, Metric: history.MetricAllocated}] = root.GetAllocatedResource().DAOMap()
+	sample[history.SeriesKey{Partition: partitionName, Metric: history.MetricPending}] = root.GetPendingResource().DAOMap()
+	sample[history.SeriesKey{Partition: partitionName, Metric: history.MetricNodeUtilization}] = pc.getMeanNodeUtilization()
+	addQueueHistorySample(sample, partitionName, root)
+}
+
+// addQueueHistorySample recursively adds the resource series of the queue and its children to the sample.
+// The guaranteed series is only added if the queue has a guaranteed resource set.
+func addQueueHistorySample(sample map[history.SeriesKey]map[string]int64, partitionName string, queue *objects.Queue) {
+	queuePath := queue.GetQueuePath()
+	sample[history.SeriesKey{Partition: partitionName, Queue: queuePath, Metric: history.MetricAllocated}] = queue.GetAllocatedResource().DAOMap()
+	sample[history.SeriesKey{Partition: partitionName, Queue: queuePath, Metric: history.MetricPending}] = queue.GetPendingResource().DAOMap()
+	if guaranteed := queue.GetGuaranteedResource(); guaranteed != nil {
+		sample[history.SeriesKey{Partition: partitionName, Queue: queuePath, Metric: history.MetricGuaranteed}] = guaranteed.DAOMap()
+	}
+	for _, child := range queue.GetCopyOfChildren() {
+		addQueueHistorySample(sample, partitionName, child)
+	}
+}
+
+// getMeanNodeUtilization returns the mean utilisation percentage per resource type over all schedulable nodes.
+// Only nodes that provide the resource type are part of the mean for that type.
+func (pc *PartitionContext) getMeanNodeUtilization() map[string]int64 {
+	total := make(map[string]int64)
+	count := make(map[string]int64)
+	for _, node := range pc.GetNodes() {
+		if !node.IsSchedulable() {
+			continue
+		}
+		capacity := node.GetCapacity()
+		utilized := node.GetUtilizedResource()
+		for name, quantity := range capacity.Resources {
+			if quantity <= 0 {
+				continue
+			}
+			total[name] += int64(utilized.Resources[name])
+			count[name]++
+		}
+	}
+	mean := make(map[string]int64, len(total))
+	for name, value := range total {
+		mean[name] = value / count[name]
+	}
+	return mean
+}
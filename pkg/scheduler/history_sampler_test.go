@@ -0,0 +1,72 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/metrics/history"
+)
+
+func TestResourceHistorySample(t *testing.T) {
+	setupUGM()
+	partition := createQueuesNodes(t)
+	defer partition.userGroupCache.Stop()
+	partitionName := "test"
+
+	app := newApplication(appID1, "default", "root.parent.sub-leaf")
+	err := partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "2"})
+	assert.NilError(t, err, "failed to create resource")
+	err = app.AddAllocationAsk(newAllocationAsk(allocKey, appID1, res))
+	assert.NilError(t, err, "failed to add ask alloc-1 to app-1")
+	err = app.AddAllocationAsk(newAllocationAsk(allocKey2, appID1, res))
+	assert.NilError(t, err, "failed to add ask alloc-2 to app-1")
+	result := partition.tryAllocate()
+	assert.Assert(t, result != nil, "allocation expected")
+
+	sample := make(map[history.SeriesKey]map[string]int64)
+	partition.addResourceHistorySample(sample)
+	tests := []struct {
+		queue    string
+		metric   string
+		expected map[string]int64
+	}{
+		{"", history.MetricAllocated, map[string]int64{"vcore": 2000}},
+		{"", history.MetricPending, map[string]int64{"vcore": 2000}},
+		// 20% used on one node, nothing on the other node
+		{"", history.MetricNodeUtilization, map[string]int64{"vcore": 10}},
+		{"root", history.MetricAllocated, map[string]int64{"vcore": 2000}},
+		{"root.parent.sub-leaf", history.MetricAllocated, map[string]int64{"vcore": 2000}},
+		{"root.parent.sub-leaf", history.MetricPending, map[string]int64{"vcore": 2000}},
+		{"root.leaf", history.MetricAllocated, map[string]int64{}},
+	}
+	for _, tt := range tests {
+		key := history.SeriesKey{Partition: partitionName, Queue: tt.queue, Metric: tt.metric}
+		value, ok := sample[key]
+		assert.Assert(t, ok, "series not found: %v", key)
+		assert.DeepEqual(t, tt.expected, value)
+	}
+	_, ok := sample[history.SeriesKey{Partition: partitionName, Queue: "root.leaf", Metric: history.MetricGuaranteed}]
+	assert.Assert(t, !ok, "guaranteed series should not be added without a guaranteed resource")
+}
@@ -21,8 +21,11 @@ package scheduler
 import (
 	"time"
 
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/log"
 	"github.com/apache/yunikorn-core/pkg/metrics"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
 )
 
 type nodesResourceUsageMonitor struct {
@@ -55,8 +58,14 @@ func (m *nodesResourceUsageMonitor) start() {
 }
 
 func (m *nodesResourceUsageMonitor) runOnce() {
+	groupBy := configs.GetConfigMap()[configs.CMNodeUtilizationGroupBy]
 	for _, p := range m.cc.GetPartitionMapClone() {
-		usageMap := p.calculateNodesResourceUsage()
+		partitionName := common.GetPartitionNameWithoutClusterID(p.Name)
+		groupUsage := p.calculateNodesResourceUsageByGroup(groupBy)
+		metrics.GetNodeUtilizationMetrics().Update(partitionName, groupUsage)
+		m.updateFragmentation(partitionName, p.GetFragmentationReport(nil))
+		// the ungrouped metrics only need the totals over all groups
+		usageMap := mergeNodeGroups(groupUsage)
 		if len(usageMap) > 0 {
 			for resourceName, usageBuckets := range usageMap {
 				for idx, bucketValue := range usageBuckets {
@@ -67,6 +76,38 @@ func (m *nodesResourceUsageMonitor) runOnce() {
 	}
 }
 
+// updateFragmentation publishes the largest available resource and fragmentation index per resource type
+func (m *nodesResourceUsageMonitor) updateFragmentation(partitionName string, report *dao.FragmentationDAOInfo) {
+	largest := make(map[string]int64, len(report.Resources))
+	fragmentation := make(map[string]float64, len(report.Resources))
+	for _, info := range report.Resources {
+		largest[info.Resource] = info.LargestAvailable
+		fragmentation[info.Resource] = info.Fragmentation
+	}
+	metrics.GetFragmentationMetrics().Update(partitionName, largest, fragmentation)
+}
+
+// mergeNodeGroups adds up the node utilisation distributions of all groups per resource type
+func mergeNodeGroups(groupUsage map[string]map[string][]int) map[string][]int {
+	if len(groupUsage) == 1 {
+		for _, usageMap := range groupUsage {
+			return usageMap
+		}
+	}
+	merged := make(map[string][]int)
+	for _, usageMap := range groupUsage {
+		for resourceName, usageBuckets := range usageMap {
+			if _, ok := merged[resourceName]; !ok {
+				merged[resourceName] = make([]int, len(usageBuckets))
+			}
+			for idx, bucketValue := range usageBuckets {
+				merged[resourceName][idx] += bucketValue
+			}
+		}
+	}
+	return merged
+}
+
 // Stop the node usage monitor.
 func (m *nodesResourceUsageMonitor) stop() {
 	log.Log(log.SchedNodesUsage).Info("Stopping node resource usage monitor")
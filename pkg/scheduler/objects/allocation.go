@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/apache/yunikorn-core/pkg/common"
@@ -51,6 +52,7 @@ type Allocation struct {
 	tags              map[string]string
 	foreign           bool
 	preemptable       bool
+	expectedDuration  time.Duration // expected run time of the allocation, zero if unknown
 
 	// Mutable fields which need protection
 	allocated            bool
@@ -63,6 +65,7 @@ type Allocation struct {
 	askEvents            *schedEvt.AskEvents
 	userQuotaCheckFailed bool
 	headroomCheckFailed  bool
+	traceContext         trace.SpanContext // span of the RM call that added the allocation
 
 	// Fields used once an allocation is bound
 	nodeID                string      // the node this allocation is bound to
@@ -73,6 +76,7 @@ type Allocation struct {
 	release               *Allocation // placeholder to be released for this allocation
 	preempted             bool        // whether this allocation has been marked for preemption
 	instType              string      // the instance type of the node at the time this allocation was bound
+	backfill              bool        // whether this allocation was backfilled on a node reserved for another allocation
 
 	locking.RWMutex
 }
@@ -140,6 +144,7 @@ func NewAllocationFromSI(alloc *si.Allocation) *Allocation {
 		placeholder:       alloc.Placeholder,
 		taskGroupName:     alloc.TaskGroupName,
 		requiredNode:      common.GetRequiredNodeFromTag(alloc.AllocationTags),
+		expectedDuration:  common.GetExpectedDurationFromTag(alloc.AllocationTags),
 		allowPreemptSelf:  alloc.PreemptionPolicy.GetAllowPreemptSelf(),
 		allowPreemptOther: alloc.PreemptionPolicy.GetAllowPreemptOther(),
 		originator:        alloc.Originator,
@@ -438,6 +443,51 @@ func (a *Allocation) GetRequiredNode() string {
 	return a.requiredNode
 }
 
+// GetExpectedDuration returns the expected run time of the allocation, zero if unknown.
+func (a *Allocation) GetExpectedDuration() time.Duration {
+	return a.expectedDuration
+}
+
+// GetExpectedEndTime returns the time the allocation is expected to finish.
+// A zero time is returned if the allocation is not bound or has no expected run time.
+func (a *Allocation) GetExpectedEndTime() time.Time {
+	a.RLock()
+	defer a.RUnlock()
+	if a.expectedDuration == 0 || a.bindTime.IsZero() {
+		return time.Time{}
+	}
+	return a.bindTime.Add(a.expectedDuration)
+}
+
+// IsBackfill returns whether the allocation was backfilled on a node reserved for another allocation.
+func (a *Allocation) IsBackfill() bool {
+	a.RLock()
+	defer a.RUnlock()
+	return a.backfill
+}
+
+// SetBackfill marks the allocation as backfilled on a node reserved for another allocation.
+func (a *Allocation) SetBackfill(backfill bool) {
+	a.Lock()
+	defer a.Unlock()
+	a.backfill = backfill
+}
+
+// GetTraceContext returns the span context of the RM call that added the allocation.
+// The span context is not valid if the call was not traced.
+func (a *Allocation) GetTraceContext() trace.SpanContext {
+	a.RLock()
+	defer a.RUnlock()
+	return a.traceContext
+}
+
+// SetTraceContext sets the span context of the RM call that added the allocation
+func (a *Allocation) SetTraceContext(sc trace.SpanContext) {
+	a.Lock()
+	defer a.Unlock()
+	a.traceContext = sc
+}
+
 // SetRequiredNode sets the required node (used only by testing so lock is not taken)
 func (a *Allocation) SetRequiredNode(node string) {
 	a.requiredNode = node
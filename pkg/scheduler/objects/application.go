@@ -42,6 +42,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
 	schedEvt "github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
 	"github.com/apache/yunikorn-core/pkg/scheduler/ugm"
+	"github.com/apache/yunikorn-core/pkg/tracing"
 	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
@@ -66,6 +67,11 @@ const (
 
 	NotEnoughUserQuota  = "Not enough user quota"
 	NotEnoughQueueQuota = "Not enough queue quota"
+
+	// SuspendMessage is the default message of the suspend state change event and the message of the allocations
+	// released by a suspend. The release uses the TIMEOUT termination type like other core initiated releases that
+	// are not a preemption: the message tells the RM that the release is caused by a suspend.
+	SuspendMessage = "application suspended"
 )
 
 type PlaceholderData struct {
@@ -82,9 +88,12 @@ type StateLogEntry struct {
 }
 
 type Application struct {
-	ApplicationID string            // application ID
-	Partition     string            // partition Name
-	tags          map[string]string // application tags used in scheduling
+	ApplicationID    string            // application ID
+	Partition        string            // partition Name
+	tags             map[string]string // application tags used in scheduling
+	deadline         time.Time         // time the application must be finished by, zero if not set
+	estimatedRuntime time.Duration     // estimated runtime of the application, only used with a deadline
+	askOrder         *taskGroupOrder   // ordering of the asks over task groups, nil for the default ordering
 
 	// Private mutable fields need protection
 	queuePath         string
@@ -112,6 +121,7 @@ type Application struct {
 	gangSchedulingStyle  string                      // gang scheduling style can be hard (after timeout we fail the application), or soft (after timeeout we schedule it as a normal application)
 	startTime            time.Time                   // the time that the application starts running. Default is zero.
 	finishedTime         time.Time                   // the time of finishing this application. the default value is zero time
+	finalSummary         *ApplicationSummary         // summary recorded when the application terminated
 	rejectedMessage      string                      // If the application is rejected, save the rejected message
 	stateLog             []*StateLogEntry            // state log for this application
 	placeholderData      map[string]*PlaceholderData // track placeholder and gang related info
@@ -120,6 +130,7 @@ type Application struct {
 	hasPlaceholderAlloc  bool                        // Whether there is at least one allocated placeholder
 	runnableInQueue      bool                        // whether the application is runnable/schedulable in the queue. Default is true.
 	runnableByUserLimit  bool                        // whether the application is runnable/schedulable based on user/group quota. Default is true.
+	deadlineAtRisk       bool                        // whether the application is expected to miss its deadline
 	backoffDeadline      time.Time                   // no scheduling from this application until this deadline
 
 	rmEventHandler              handler.EventHandler
@@ -171,6 +182,8 @@ func NewApplication(siApp *si.AddApplicationRequest, ugi security.UserGroup, eve
 	}
 	app.gangSchedulingStyle = gangSchedStyle
 	app.execTimeout = placeholderTimeout
+	app.deadline, app.estimatedRuntime = getDeadlineFromTags(siApp.Tags)
+	app.askOrder = getTaskGroupOrderFromTags(siApp.Tags)
 	app.user = ugi
 	app.rmEventHandler = eventHandler
 	app.rmID = rmID
@@ -259,6 +272,10 @@ func (sa *Application) IsResuming() bool {
 	return sa.stateMachine.Is(Resuming.String())
 }
 
+func (sa *Application) IsSuspended() bool {
+	return sa.stateMachine.Is(Suspended.String())
+}
+
 // HandleApplicationEvent handles the state event for the application.
 // The application lock is expected to be held.
 func (sa *Application) HandleApplicationEvent(event applicationEvent) error {
@@ -631,7 +648,7 @@ func (sa *Application) removeAsksInternal(allocKey string, detail si.EventRecord
 	// Change the state to completing.
 	// When the resource trackers are zero we should not expect anything to come in later.
 	hasPlaceHolderAllocations := len(sa.getPlaceholderAllocations()) > 0
-	if resources.IsZero(sa.pending) && resources.IsZero(sa.allocatedResource) && !sa.IsFailing() && !sa.IsCompleting() && !hasPlaceHolderAllocations {
+	if resources.IsZero(sa.pending) && resources.IsZero(sa.allocatedResource) && !sa.IsFailing() && !sa.IsCompleting() && !sa.IsSuspended() && !hasPlaceHolderAllocations {
 		if err := sa.HandleApplicationEvent(CompleteApplication); err != nil {
 			log.Log(log.SchedApplication).Warn("Application state not changed to Completing while updating ask(s)",
 				zap.String("currentState", sa.CurrentState()),
@@ -660,6 +677,7 @@ func (sa *Application) AddAllocationAsk(ask *Allocation) error {
 	}
 	if ask.createTime.Before(sa.submissionTime) {
 		sa.submissionTime = ask.createTime
+		sa.queue.invalidateAppSort(sa.ApplicationID)
 	}
 	delta := ask.GetAllocatedResource().Clone()
 
@@ -731,6 +749,7 @@ func (sa *Application) UpdateAllocationResources(alloc *Allocation, isQuotaPreem
 		sa.allocatedResource = resources.Add(sa.allocatedResource, delta)
 		sa.allocatedResource.Prune()
 		sa.queue.IncAllocatedResource(delta, isQuotaPreemptionEnabled)
+		sa.queue.invalidateAppSort(sa.ApplicationID)
 
 		// update user usage
 		sa.incUserResourceUsage(delta)
@@ -903,6 +922,7 @@ func (sa *Application) RollbackAllocation(allocKey string) (*resources.Resource,
 	res := ask.GetAllocatedResource()
 	sa.allocatedResource = resources.Sub(sa.allocatedResource, res)
 	sa.allocatedResource.Prune()
+	sa.queue.invalidateAppSort(sa.ApplicationID)
 	sa.decUserResourceUsage(res, false)
 	delete(sa.allocations, allocKey)
 
@@ -1149,7 +1169,7 @@ func (sa *Application) canReplace(request *Allocation) bool {
 }
 
 // tryAllocate will perform a regular allocation of a pending request, includes placeholders.
-func (sa *Application) tryAllocate(headRoom *resources.Resource, allowPreemption bool, preemptionDelay time.Duration, preemptAttemptsRemaining *int, nodeIterator func() NodeIterator, fullNodeIterator func() NodeIterator, getNodeFn func(string) *Node) *AllocationResult {
+func (sa *Application) tryAllocate(headRoom *resources.Resource, allowPreemption bool, preemptionDelay time.Duration, preemptAttemptsRemaining *int, nodeIterator func() NodeIterator, fullNodeIterator func() NodeIterator, reservedNodeIterator func() NodeIterator, getNodeFn func(string) *Node) *AllocationResult {
 	sa.Lock()
 	defer sa.Unlock()
 	if len(sa.sortedRequests) == 0 {
@@ -1162,7 +1182,7 @@ func (sa *Application) tryAllocate(headRoom *resources.Resource, allowPreemption
 	// because the len check above guarantees at least one iteration would occur.
 	backoffThreshold := sa.queue.GetMaxAppUnschedAskBackoff()
 	// get all the requests from the app sorted in order
-	for _, request := range sa.sortedRequests {
+	for _, request := range sa.getOrderedRequests() {
 		if backoffThreshold > 0 && unschedulable >= backoffThreshold {
 			log.Log(log.SchedApplication).Info("too many unschedulable asks in the application, waiting",
 				zap.String("application ID", sa.ApplicationID),
@@ -1222,7 +1242,14 @@ func (sa *Application) tryAllocate(headRoom *resources.Resource, allowPreemption
 
 		iterator := nodeIterator()
 		if iterator != nil {
-			if result := sa.tryNodes(request, iterator); result != nil {
+			result := sa.tryNodes(request, iterator)
+			// backfill on an already reserved node is preferred over making a new reservation
+			if result == nil || result.ResultType == Reserved {
+				if backfill := sa.tryBackfill(request, reservedNodeIterator); backfill != nil {
+					return backfill
+				}
+			}
+			if result != nil {
 				// have a candidate return it
 				return result
 			}
@@ -1358,7 +1385,7 @@ func (sa *Application) tryPlaceholderAllocate(nodeIterator func() NodeIterator,
 	var phFit *Allocation
 	var reqFit *Allocation
 	// get all the requests from the app sorted in order
-	for _, request := range sa.sortedRequests {
+	for _, request := range sa.getOrderedRequests() {
 		// skip placeholders they follow standard allocation
 		// this should also be part of a task group just make sure it is
 		if request.IsPlaceholder() || request.GetTaskGroup() == "" || request.IsAllocated() {
@@ -1587,6 +1614,9 @@ func (sa *Application) tryReservedAllocate(headRoom *resources.Resource, nodeIte
 				preemptor.tryPreemption()
 				continue
 			}
+		} else if !reserve.node.CanAllocate(ask.GetAllocatedResource()) {
+			// backfilled allocations running longer than expected should not delay the reservation
+			sa.preemptOverrunBackfill(reserve.node, ask)
 		}
 		// check allocation possibility
 		// we don't care about predicate error messages here
@@ -1641,9 +1671,21 @@ func (sa *Application) tryPreemption(headRoom *resources.Resource, preemptionDel
 	tryPreemptionStart := time.Now()
 	defer metrics.GetSchedulerMetrics().ObserveTryPreemptionLatency(tryPreemptionStart)
 
+	_, span := tracing.StartWithParent(ask.GetTraceContext(), "tryPreemption",
+		tracing.AttrApplication.String(sa.ApplicationID),
+		tracing.AttrQueue.String(sa.queuePath),
+		tracing.AttrAllocationKey.String(ask.GetAllocationKey()))
+	defer span.End()
+
 	// attempt preemption
 	preemptor := NewPreemptor(sa, headRoom, preemptionDelay, ask, iterator, nodesTried)
-	return preemptor.TryPreemption()
+	result, ok := preemptor.TryPreemption()
+	if result != nil {
+		span.SetAttributes(
+			tracing.AttrNode.String(result.NodeID),
+			tracing.AttrResultType.String(result.ResultType.String()))
+	}
+	return result, ok
 }
 
 // tryNodesNoReserve tries all the nodes for a reserved request that have not been tried yet.
@@ -1794,28 +1836,32 @@ func (sa *Application) tryNode(node *Node, ask *Allocation) (*AllocationResult,
 	if err := node.preAllocateConditions(ask); err != nil {
 		return nil, err
 	}
+	return sa.allocateOnNode(node, ask), nil
+}
 
-	// everything OK really allocate
-	if node.TryAddAllocation(ask) {
-		if err := sa.queue.TryIncAllocatedResource(ask.GetAllocatedResource()); err != nil {
-			log.Log(log.SchedApplication).DPanic("queue update failed unexpectedly",
-				zap.Error(err))
-			// revert the node update
-			node.RemoveAllocation(allocationKey)
-			return nil, nil
-		}
-		// mark this alloc as allocated
-		_, err := sa.allocateAsk(ask)
-		if err != nil {
-			log.Log(log.SchedApplication).Warn("allocation of alloc failed unexpectedly",
-				zap.Error(err))
-		}
-		// all is OK, last update for the app
-		result := newAllocatedAllocationResult(node.NodeID, ask)
-		sa.addAllocationInternal(result.ResultType, ask)
-		return result, nil
+// allocateOnNode adds the ask to the node and updates the queue and application.
+// The node and ask must have passed all pre-allocation checks. Returns nil if the ask could not be added.
+func (sa *Application) allocateOnNode(node *Node, ask *Allocation) *AllocationResult {
+	if !node.TryAddAllocation(ask) {
+		return nil
+	}
+	if err := sa.queue.TryIncAllocatedResource(ask.GetAllocatedResource()); err != nil {
+		log.Log(log.SchedApplication).DPanic("queue update failed unexpectedly",
+			zap.Error(err))
+		// revert the node update
+		node.RemoveAllocation(ask.GetAllocationKey())
+		return nil
 	}
-	return nil, nil
+	// mark this alloc as allocated
+	_, err := sa.allocateAsk(ask)
+	if err != nil {
+		log.Log(log.SchedApplication).Warn("allocation of alloc failed unexpectedly",
+			zap.Error(err))
+	}
+	// all is OK, last update for the app
+	result := newAllocatedAllocationResult(node.NodeID, ask)
+	sa.addAllocationInternal(result.ResultType, ask)
+	return result
 }
 
 func (sa *Application) GetQueuePath() string {
@@ -1953,7 +1999,8 @@ func (sa *Application) addAllocationInternal(allocType AllocationResultType, all
 		// already when the last placeholder was allocated
 		// special case COMPLETING: gang with only one placeholder moves to COMPLETING and causes orphaned
 		// allocations
-		if allocType != Replaced || !resources.IsZero(sa.allocatedResource) || sa.IsCompleting() {
+		// a suspended application does not change state when an allocation is added by the RM
+		if (allocType != Replaced || !resources.IsZero(sa.allocatedResource) || sa.IsCompleting()) && !sa.IsSuspended() {
 			// progress the state based on where we are, we should never fail in this case
 			// keep track of a failure in log.
 			if err := sa.HandleApplicationEvent(RunApplication); err != nil {
@@ -1969,6 +2016,7 @@ func (sa *Application) addAllocationInternal(allocType AllocationResultType, all
 	if alloc.createTime.Before(sa.submissionTime) {
 		sa.submissionTime = alloc.createTime
 	}
+	sa.queue.invalidateAppSort(sa.ApplicationID)
 	sa.appEvents.SendNewAllocationEvent(sa.ApplicationID, alloc.allocationKey, alloc.GetAllocatedResource())
 	sa.allocations[alloc.GetAllocationKey()] = alloc
 }
@@ -1985,6 +2033,15 @@ func (sa *Application) decUserResourceUsage(resource *resources.Resource, remove
 	ugm.GetUserManager().DecreaseTrackedResource(sa.queuePath, sa.ApplicationID, resource, sa.user, removeApp)
 }
 
+// untrackUserApplication removes the application from the user and group tracking on the current queue path.
+// The application is removed independent of the tracked usage, which could be zero.
+// No locking must be called while holding the lock
+func (sa *Application) untrackUserApplication() {
+	if ugm.GetUserManager().IsApplicationTracked(sa.ApplicationID, sa.user) {
+		sa.decUserResourceUsage(resources.NewResource(), true)
+	}
+}
+
 // Track used and preempted resources
 func (sa *Application) trackCompletedResource(info *Allocation) {
 	switch {
@@ -2094,7 +2151,7 @@ func (sa *Application) removeAllocationInternal(allocationKey string, releaseTyp
 		if resources.IsZero(sa.allocatedPlaceholder) {
 			sa.clearPlaceholderTimer()
 			sa.hasPlaceholderAlloc = false
-			if (sa.IsCompleting() && sa.stateTimer == nil) || sa.IsFailing() || sa.IsResuming() || sa.hasZeroAllocations() {
+			if (sa.IsCompleting() && sa.stateTimer == nil) || sa.IsFailing() || sa.IsResuming() || (sa.hasZeroAllocations() && !sa.IsSuspended()) {
 				removeApp = true
 				event = CompleteApplication
 				if sa.IsFailing() {
@@ -2114,12 +2171,14 @@ func (sa *Application) removeAllocationInternal(allocationKey string, releaseTyp
 	} else {
 		sa.allocatedResource = resources.Sub(sa.allocatedResource, alloc.GetAllocatedResource())
 		sa.allocatedResource.Prune()
+		sa.queue.invalidateAppSort(sa.ApplicationID)
 
 		// Aggregate the resources used by this alloc to the application's resource tracker
 		sa.trackCompletedResource(alloc)
 
 		// When the resource trackers are zero we should not expect anything to come in later.
-		if sa.hasZeroAllocations() {
+		// A suspended application stays suspended until resumed.
+		if sa.hasZeroAllocations() && !sa.IsSuspended() {
 			removeApp = true
 			event = CompleteApplication
 			eventWarning = "Application state not changed to Completing while removing an allocation"
@@ -2172,6 +2231,7 @@ func (sa *Application) RemoveAllAllocations() []*Allocation {
 	sa.allocatedResource = resources.NewResource()
 	sa.allocatedPlaceholder = resources.NewResource()
 	sa.allocations = make(map[string]*Allocation)
+	sa.queue.invalidateAppSort(sa.ApplicationID)
 
 	// When the resource trackers are zero we should not expect anything to come in later.
 	if resources.IsZero(sa.pending) {
@@ -2206,6 +2266,74 @@ func (sa *Application) FailApplication(failureMessage string) error {
 	return sa.HandleApplicationEventWithInfo(FailApplication, failureMessage)
 }
 
+// SuspendApplication suspends a running application. The application keeps its asks, allocations and tracked
+// usage but is not considered for new allocations until it is resumed. All reservations of the application are
+// removed. If release is set all allocations are released back to the RM, the asks stay pending.
+// The number of reservations removed is returned.
+func (sa *Application) SuspendApplication(release bool, message string) (int, error) {
+	sa.Lock()
+	defer sa.Unlock()
+
+	if !sa.IsRunning() {
+		return 0, fmt.Errorf("application %s is %s, only running applications can be suspended", sa.ApplicationID, sa.CurrentState())
+	}
+	if message == "" {
+		message = SuspendMessage
+	}
+	if err := sa.HandleApplicationEventWithInfo(SuspendApplication, message); err != nil {
+		return 0, err
+	}
+	var unreserved int
+	for _, reserve := range sa.reservations {
+		unreserved += sa.unReserveInternal(reserve)
+	}
+	sa.queue.UnReserve(sa.ApplicationID, unreserved)
+	if !release {
+		return unreserved, nil
+	}
+	toRelease := make([]*Allocation, 0, len(sa.allocations))
+	for _, alloc := range sa.allocations {
+		// skip over allocations that are already being released or preempted
+		if alloc.IsReleased() || alloc.SetReleased(true) != nil {
+			continue
+		}
+		toRelease = append(toRelease, alloc)
+	}
+	log.Log(log.SchedApplication).Info("Application suspended, releasing allocations",
+		zap.String("appID", sa.ApplicationID),
+		zap.Int("releasing", len(toRelease)))
+	sa.notifyRMAllocationReleased(toRelease, si.TerminationType_TIMEOUT, SuspendMessage)
+	return unreserved, nil
+}
+
+// ResumeSuspendedApplication moves a suspended application back to running and makes the pending asks
+// schedulable again. The application cannot be resumed if the queue or the user and group limits do not allow
+// an additional running application. An application without asks and allocations progresses to completing.
+func (sa *Application) ResumeSuspendedApplication(message string) error {
+	sa.Lock()
+	defer sa.Unlock()
+
+	if !sa.IsSuspended() {
+		return fmt.Errorf("application %s is %s, only suspended applications can be resumed", sa.ApplicationID, sa.CurrentState())
+	}
+	if !sa.queue.canRunApp(sa.ApplicationID) || !ugm.GetUserManager().CanRunApp(sa.queuePath, sa.ApplicationID, sa.user) {
+		return fmt.Errorf("application %s cannot be resumed: maximum number of running applications reached", sa.ApplicationID)
+	}
+	if err := sa.HandleApplicationEventWithInfo(ResumeSuspendedApplication, message); err != nil {
+		return err
+	}
+	// the allocations and asks could have been removed while suspended: nothing left to run
+	if sa.hasZeroAllocations() && resources.IsZero(sa.allocatedPlaceholder) {
+		sa.untrackUserApplication()
+		if err := sa.HandleApplicationEvent(CompleteApplication); err != nil {
+			log.Log(log.SchedApplication).Warn("Application state not changed to Completing after resume",
+				zap.String("currentState", sa.CurrentState()),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
 // get a copy of the user details for the application
 func (sa *Application) GetUser() security.UserGroup {
 	sa.RLock()
@@ -2338,6 +2466,7 @@ func (sa *Application) cleanupAsks() {
 	// fire on terminal apps.
 	sa.pendingPriorities = make(map[int32]int)
 	sa.askMaxPriority = configs.MinPriority
+	sa.queue.invalidateAppSort(sa.ApplicationID)
 }
 
 func (sa *Application) cleanupTrackedResource() {
@@ -2367,21 +2496,38 @@ func (sa *Application) getApplicationSummary(rmID string) *ApplicationSummary {
 		ResourceUsage:       sa.usedResource.Clone(),
 		PreemptedResource:   sa.preemptedResource.Clone(),
 		PlaceholderResource: sa.placeholderResource.Clone(),
+		StateLog:            append([]*StateLogEntry(nil), sa.stateLog...),
 	}
 }
 
+// GetFinalSummary returns the summary recorded when the application terminated.
+// Returns nil if the application has not terminated or never ran.
+func (sa *Application) GetFinalSummary() *ApplicationSummary {
+	sa.RLock()
+	defer sa.RUnlock()
+	return sa.finalSummary
+}
+
 // LogAppSummary log the summary details for the application if it has run at any point in time.
 // The application summary only contains correct data when the application is in the Completed state.
 // Logging the data in any other state will show incomplete or inconsistent data.
 // After the data is logged the objects are cleaned up to lower overhead of Completed application tracking.
+// The summary is kept as the final summary of the application and exported through the summary sink.
 func (sa *Application) LogAppSummary(rmID string) {
 	sa.Lock()
-	defer sa.Unlock()
+	var appSummary *ApplicationSummary
 	if !sa.startTime.IsZero() {
-		appSummary := sa.getApplicationSummary(rmID)
+		appSummary = sa.getApplicationSummary(rmID)
 		appSummary.DoLogging()
+		sa.finalSummary = appSummary
 	}
 	sa.cleanupTrackedResource()
+	appEvents := sa.appEvents
+	sa.Unlock()
+	// export outside the lock: it could write to a file
+	if appSummary != nil {
+		summarySink.export(appSummary, appEvents)
+	}
 }
 
 // GetTrackedDAOMap returns the tracked resources type specified in which as a DAO similar to the normal resources.
@@ -0,0 +1,156 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+const (
+	// AppTagTaskGroupSort is the ordering of the asks of the application over its task groups
+	AppTagTaskGroupSort = "application.taskgroup.sort"
+	// AppTagTaskGroupOrder is the comma separated list of task groups used by the ordered task group sort
+	AppTagTaskGroupOrder = "application.taskgroup.order"
+
+	// TaskGroupSortFair interleaves the task groups: the group with the fewest allocations goes first
+	TaskGroupSortFair = "fair"
+	// TaskGroupSortOrdered follows the configured group order: groups not in the list go last
+	TaskGroupSortOrdered = "ordered"
+)
+
+// taskGroupOrder defines how the asks of an application are ordered over its task groups.
+// Within a task group the asks keep the default priority and creation time order.
+// The order is set on creation and never changes.
+type taskGroupOrder struct {
+	fair   bool
+	groups map[string]int // position of the group in the configured order
+}
+
+// getTaskGroupOrderFromTags returns the task group ordering set in the application tags.
+// Returns nil if the default ordering must be used.
+func getTaskGroupOrderFromTags(tags map[string]string) *taskGroupOrder {
+	sortType := strings.ToLower(strings.TrimSpace(tags[AppTagTaskGroupSort]))
+	switch sortType {
+	case "":
+		return nil
+	case TaskGroupSortFair:
+		return &taskGroupOrder{fair: true}
+	case TaskGroupSortOrdered:
+		groups := make(map[string]int)
+		for _, name := range strings.Split(tags[AppTagTaskGroupOrder], ",") {
+			name = strings.TrimSpace(name)
+			if _, ok := groups[name]; !ok && name != "" {
+				groups[name] = len(groups)
+			}
+		}
+		if len(groups) == 0 {
+			log.Log(log.SchedApplication).Warn("ordered task group sort without task groups, using default ordering",
+				zap.String("tag", AppTagTaskGroupOrder),
+				zap.String("value", tags[AppTagTaskGroupOrder]))
+			return nil
+		}
+		return &taskGroupOrder{groups: groups}
+	default:
+		log.Log(log.SchedApplication).Warn("unknown task group sort, using default ordering",
+			zap.String("tag", AppTagTaskGroupSort),
+			zap.String("value", sortType))
+		return nil
+	}
+}
+
+// getOrderedRequests returns the outstanding requests of the application in the order they must be scheduled.
+// Without a task group ordering this is the pre-sorted list, otherwise a new list is returned.
+// NOTE: this is a lock free call, must be called holding the application lock
+func (sa *Application) getOrderedRequests() []*Allocation {
+	if sa.askOrder == nil || len(sa.sortedRequests) < 2 {
+		return sa.sortedRequests
+	}
+	// split the outstanding requests per group, keeping the pre-sorted order within the group
+	// the groups are tracked in the order of their first request
+	var names []string
+	pending := make(map[string][]*Allocation)
+	for _, request := range sa.sortedRequests {
+		if request.IsAllocated() {
+			continue
+		}
+		name := request.GetTaskGroup()
+		if _, ok := pending[name]; !ok {
+			names = append(names, name)
+		}
+		pending[name] = append(pending[name], request)
+	}
+	if len(names) < 2 {
+		return sa.sortedRequests
+	}
+	ordered := make([]*Allocation, 0, len(sa.sortedRequests))
+	if !sa.askOrder.fair {
+		// configured groups in the configured order, all others after that in their original order
+		positions := sa.askOrder.groups
+		sortedNames := make([]string, 0, len(names))
+		for _, name := range names {
+			if _, ok := positions[name]; ok {
+				sortedNames = append(sortedNames, name)
+			}
+		}
+		sort.SliceStable(sortedNames, func(i, j int) bool {
+			return positions[sortedNames[i]] < positions[sortedNames[j]]
+		})
+		for _, name := range names {
+			if _, ok := positions[name]; !ok {
+				sortedNames = append(sortedNames, name)
+			}
+		}
+		for _, name := range sortedNames {
+			ordered = append(ordered, pending[name]...)
+		}
+		return ordered
+	}
+	// interleave the groups: always pick the next request from the group with the fewest allocations,
+	// only real allocations count as placeholders are replaced and do not show progress
+	allocated := make(map[string]int)
+	for _, alloc := range sa.allocations {
+		if !alloc.IsPlaceholder() {
+			allocated[alloc.GetTaskGroup()]++
+		}
+	}
+	for {
+		next := ""
+		found := false
+		for _, name := range names {
+			if len(pending[name]) == 0 {
+				continue
+			}
+			if !found || allocated[name] < allocated[next] {
+				next = name
+				found = true
+			}
+		}
+		if !found {
+			return ordered
+		}
+		ordered = append(ordered, pending[next][0])
+		pending[next] = pending[next][1:]
+		allocated[next]++
+	}
+}
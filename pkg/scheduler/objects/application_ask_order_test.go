@@ -0,0 +1,142 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestGetTaskGroupOrderFromTags(t *testing.T) {
+	assert.Assert(t, getTaskGroupOrderFromTags(nil) == nil, "nil tags should use default order")
+	assert.Assert(t, getTaskGroupOrderFromTags(map[string]string{AppTagTaskGroupSort: "unknown"}) == nil, "unknown sort should use default order")
+	assert.Assert(t, getTaskGroupOrderFromTags(map[string]string{AppTagTaskGroupSort: TaskGroupSortOrdered}) == nil, "ordered sort without groups should use default order")
+	assert.Assert(t, getTaskGroupOrderFromTags(map[string]string{AppTagTaskGroupSort: TaskGroupSortOrdered, AppTagTaskGroupOrder: " , "}) == nil, "ordered sort with empty groups should use default order")
+
+	order := getTaskGroupOrderFromTags(map[string]string{AppTagTaskGroupSort: " Fair "})
+	assert.Assert(t, order != nil && order.fair, "fair sort not set")
+
+	order = getTaskGroupOrderFromTags(map[string]string{AppTagTaskGroupSort: TaskGroupSortOrdered, AppTagTaskGroupOrder: "driver, executor,driver"})
+	assert.Assert(t, order != nil && !order.fair, "ordered sort not set")
+	assert.DeepEqual(t, order.groups, map[string]int{"driver": 0, "executor": 1})
+}
+
+func TestGetOrderedRequests(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	addAsks := func(app *Application) {
+		queue, err := createRootQueue(nil)
+		assert.NilError(t, err, "queue create failed")
+		app.queue = queue
+		// the default order follows the creation time: all tg-1 asks before the tg-2 and tg-3 asks
+		now := time.Now()
+		for i, group := range []string{tg1, tg1, tg1, tg2, tg2, tg3} {
+			ask := newAllocationAskTG("alloc-"+string(rune('a'+i)), app.ApplicationID, group, res)
+			ask.placeholder = false
+			ask.createTime = now.Add(time.Duration(i) * time.Second)
+			assert.NilError(t, app.AddAllocationAsk(ask), "ask should have been added to app")
+		}
+	}
+	keys := func(requests []*Allocation) []string {
+		var result []string
+		for _, request := range requests {
+			result = append(result, request.GetAllocationKey())
+		}
+		return result
+	}
+
+	app := newApplication(appID1, "default", "root.default")
+	addAsks(app)
+	assert.DeepEqual(t, keys(app.getOrderedRequests()), []string{"alloc-a", "alloc-b", "alloc-c", "alloc-d", "alloc-e", "alloc-f"})
+
+	app = newApplicationWithTags(appID1, "default", "root.default", map[string]string{AppTagTaskGroupSort: TaskGroupSortFair})
+	addAsks(app)
+	assert.DeepEqual(t, keys(app.getOrderedRequests()), []string{"alloc-a", "alloc-d", "alloc-f", "alloc-b", "alloc-e", "alloc-c"})
+	// real allocations count towards the progress of the group, placeholders do not
+	app.AddAllocation(newAllocationAll("tg2-alloc", appID1, nodeID1, tg2, res, false, 0))
+	app.AddAllocation(newAllocationAll("tg3-ph", appID1, nodeID1, tg3, res, true, 0))
+	assert.DeepEqual(t, keys(app.getOrderedRequests()), []string{"alloc-a", "alloc-f", "alloc-b", "alloc-d", "alloc-c", "alloc-e"})
+	// allocated asks are skipped
+	assert.Assert(t, app.sortedRequests[0].allocate(), "ask should have been marked allocated")
+	assert.DeepEqual(t, keys(app.getOrderedRequests()), []string{"alloc-b", "alloc-f", "alloc-c", "alloc-d", "alloc-e"})
+
+	app = newApplicationWithTags(appID1, "default", "root.default", map[string]string{AppTagTaskGroupSort: TaskGroupSortOrdered, AppTagTaskGroupOrder: tg3 + "," + tg2})
+	addAsks(app)
+	assert.DeepEqual(t, keys(app.getOrderedRequests()), []string{"alloc-f", "alloc-d", "alloc-e", "alloc-a", "alloc-b", "alloc-c"})
+}
+
+func TestTryAllocateTaskGroupFair(t *testing.T) {
+	setupUGM()
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 10})
+	iterator := getNodeIteratorFn(node)
+	app := newApplicationWithTags(appID1, "default", "root.default", map[string]string{AppTagTaskGroupSort: TaskGroupSortFair})
+	queue, err := createRootQueue(map[string]string{"first": "10"})
+	assert.NilError(t, err, "queue create failed")
+	app.queue = queue
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	now := time.Now()
+	for i, group := range []string{tg1, tg1, tg2, tg2} {
+		ask := newAllocationAskAll("alloc-"+string(rune('a'+i)), appID1, group, res, false, 0)
+		ask.createTime = now.Add(time.Duration(i) * time.Second)
+		assert.NilError(t, app.AddAllocationAsk(ask), "ask should have been added to app")
+	}
+	headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})
+	attempts := 0
+	var allocated []string
+	for range 4 {
+		result := app.tryAllocate(headRoom, false, time.Second, &attempts, iterator, nilNodeIterator, nilNodeIterator, nilGetNode)
+		assert.Assert(t, result != nil && result.ResultType == Allocated, "ask should have been allocated")
+		allocated = append(allocated, result.Request.GetAllocationKey())
+	}
+	assert.DeepEqual(t, allocated, []string{"alloc-a", "alloc-c", "alloc-b", "alloc-d"})
+}
+
+func TestTryPlaceholderAllocateTaskGroupOrdered(t *testing.T) {
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 10})
+	iterator := getNodeIteratorFn(node)
+	getNode := func(nodeID string) *Node {
+		return node
+	}
+	app := newApplicationWithTags(appID1, "default", "root.default", map[string]string{AppTagTaskGroupSort: TaskGroupSortOrdered, AppTagTaskGroupOrder: tg2})
+	queue, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	app.queue = queue
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	for _, group := range []string{tg1, tg2} {
+		ph := newPlaceholderAlloc(appID1, nodeID1, res, group)
+		app.AddAllocation(ph)
+		app.addPlaceholderData(ph)
+	}
+	// the tg-1 ask is older and would be replaced first in the default order
+	ask1 := newAllocationAskAll(aKey, appID1, tg1, res, false, 0)
+	ask1.createTime = time.Now().Add(-time.Minute)
+	assert.NilError(t, app.AddAllocationAsk(ask1), "ask should have been added to app")
+	ask2 := newAllocationAskAll(aKey2, appID1, tg2, res, false, 0)
+	assert.NilError(t, app.AddAllocationAsk(ask2), "ask should have been added to app")
+
+	result := app.tryPlaceholderAllocate(iterator, getNode)
+	assert.Assert(t, result != nil, "placeholder should have been replaced")
+	assert.Equal(t, Replaced, result.ResultType, "result type should be Replaced")
+	assert.Equal(t, ask2, result.Request, "ask of the configured task group should replace first")
+}
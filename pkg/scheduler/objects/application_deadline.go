@@ -0,0 +1,149 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+)
+
+const (
+	// AppTagDeadline is the time, in RFC3339 format, the application must be finished by
+	AppTagDeadline = "application.deadline"
+	// AppTagEstimatedRuntime is the estimated runtime of the application as a duration, i.e. "90m"
+	AppTagEstimatedRuntime = "application.runtime"
+)
+
+// getDeadlineFromTags returns the deadline and estimated runtime set in the application tags.
+// The runtime is ignored if no valid deadline is set.
+func getDeadlineFromTags(tags map[string]string) (time.Time, time.Duration) {
+	value, ok := tags[AppTagDeadline]
+	if !ok || value == "" {
+		return time.Time{}, 0
+	}
+	deadline, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Log(log.SchedApplication).Warn("application deadline tag conversion failure",
+			zap.String("tag", AppTagDeadline),
+			zap.String("value", value),
+			zap.Error(err))
+		return time.Time{}, 0
+	}
+	var runtime time.Duration
+	if value = tags[AppTagEstimatedRuntime]; value != "" {
+		runtime, err = time.ParseDuration(value)
+		if err != nil || runtime < 0 {
+			log.Log(log.SchedApplication).Warn("application runtime tag conversion failure",
+				zap.String("tag", AppTagEstimatedRuntime),
+				zap.String("value", value),
+				zap.Error(err))
+			runtime = 0
+		}
+	}
+	return deadline, runtime
+}
+
+// GetDeadline returns the deadline of the application, zero if not set.
+// The deadline is set on creation and never changes: no locking needed.
+func (sa *Application) GetDeadline() time.Time {
+	return sa.deadline
+}
+
+// getLatestStart returns the latest time the remaining work of the application can start and still finish
+// before the deadline, the slack of the application expressed as an absolute time. The value of a running
+// application moves forward while it runs and must be re-evaluated periodically.
+// Returns zero if the application has no deadline. The application lock is expected to be held.
+func (sa *Application) getLatestStart(now time.Time) time.Time {
+	if sa.deadline.IsZero() {
+		return time.Time{}
+	}
+	return sa.deadline.Add(-sa.remainingRuntime(now))
+}
+
+// remainingRuntime returns the estimated runtime left for the application. The remaining runtime of a
+// running application is the estimated runtime minus the time it has been running.
+// The application lock is expected to be held.
+func (sa *Application) remainingRuntime(now time.Time) time.Duration {
+	if sa.startTime.IsZero() {
+		return sa.estimatedRuntime
+	}
+	return max(sa.estimatedRuntime-now.Sub(sa.startTime), 0)
+}
+
+// IsDeadlineAtRisk returns true if the application was last found to be at risk of missing its deadline.
+func (sa *Application) IsDeadlineAtRisk() bool {
+	sa.RLock()
+	defer sa.RUnlock()
+	return sa.deadlineAtRisk
+}
+
+// checkDeadline updates the deadline risk of the application. An application is at risk when it still has
+// pending resources and the remaining estimated runtime would end after the deadline.
+// An event is sent and the metrics are updated when the risk changes.
+func (sa *Application) checkDeadline(now time.Time) {
+	if sa.deadline.IsZero() {
+		return
+	}
+	sa.Lock()
+	defer sa.Unlock()
+	atRisk := resources.StrictlyGreaterThanZero(sa.pending) && now.Add(sa.remainingRuntime(now)).After(sa.deadline)
+	sa.setDeadlineAtRisk(atRisk)
+}
+
+// isRunningWithDeadline returns true if the application has a deadline and has started running.
+// The latest start of a running application changes over time.
+func (sa *Application) isRunningWithDeadline() bool {
+	if sa.deadline.IsZero() {
+		return false
+	}
+	sa.RLock()
+	defer sa.RUnlock()
+	return !sa.startTime.IsZero()
+}
+
+// clearDeadlineAtRisk removes the deadline risk of the application, used when the application is removed.
+func (sa *Application) clearDeadlineAtRisk() {
+	sa.Lock()
+	defer sa.Unlock()
+	sa.setDeadlineAtRisk(false)
+}
+
+// setDeadlineAtRisk updates the deadline risk, sending an event and updating the metrics on change.
+// The application lock is expected to be held.
+func (sa *Application) setDeadlineAtRisk(atRisk bool) {
+	if sa.deadlineAtRisk == atRisk {
+		return
+	}
+	sa.deadlineAtRisk = atRisk
+	if atRisk {
+		log.Log(log.SchedApplication).Info("application deadline at risk",
+			zap.String("appID", sa.ApplicationID),
+			zap.String("queue", sa.queuePath),
+			zap.Time("deadline", sa.deadline))
+		metrics.GetSchedulerMetrics().IncApplicationsDeadlineAtRisk()
+	} else {
+		metrics.GetSchedulerMetrics().DecApplicationsDeadlineAtRisk()
+	}
+	sa.appEvents.SendDeadlineAtRiskEvent(sa.ApplicationID, atRisk, sa.deadline)
+}
@@ -0,0 +1,210 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+	"github.com/apache/yunikorn-core/pkg/scheduler/ugm"
+)
+
+// MoveToQueue moves the application from its current queue to the target leaf queue.
+// The target queue is validated for ACLs, state, max resources and max applications, the user and group
+// limits are validated against the target queue path. The allocated, pending and preempting resources,
+// the reservations and the running application tracking are all transferred to the target queue.
+// The limits of the target queue are checked while the usage is transferred: no changes are made if one of
+// the checks fails.
+// The partition is responsible for updating the application to queue mapping.
+func (sa *Application) MoveToQueue(target *Queue) error {
+	sa.Lock()
+	defer sa.Unlock()
+
+	source := sa.queue
+	if source == nil || target == nil {
+		return fmt.Errorf("application %s cannot be moved: queue not set", sa.ApplicationID)
+	}
+	if source == target {
+		return fmt.Errorf("application %s is already in queue %s", sa.ApplicationID, target.QueuePath)
+	}
+	if !target.IsLeafQueue() {
+		return fmt.Errorf("application %s cannot be moved: queue %s is not a leaf queue", sa.ApplicationID, target.QueuePath)
+	}
+	if !sa.isMovable() {
+		return fmt.Errorf("application %s cannot be moved in state %s", sa.ApplicationID, sa.CurrentState())
+	}
+	if err := target.CheckAcceptingApplications(); err != nil {
+		return fmt.Errorf("application %s cannot be moved: %w", sa.ApplicationID, err)
+	}
+	if !target.CheckSubmitAccess(sa.user) {
+		return fmt.Errorf("application %s cannot be moved: user %s is not allowed to submit to queue %s", sa.ApplicationID, sa.user.User, target.QueuePath)
+	}
+	if !resources.IsZero(sa.placeholderAsk) {
+		if !target.SupportTaskGroup() {
+			return fmt.Errorf("application %s cannot be moved: queue %s does not support task groups", sa.ApplicationID, target.QueuePath)
+		}
+		if maxQueue := target.GetMaxQueueSet(); maxQueue != nil && !maxQueue.FitInMaxUndef(sa.placeholderAsk) {
+			return fmt.Errorf("application %s cannot be moved: task group request %s larger than max queue allocation %s of queue %s",
+				sa.ApplicationID, sa.placeholderAsk, maxQueue, target.QueuePath)
+		}
+	}
+
+	appID := sa.ApplicationID
+	usage := resources.Add(sa.allocatedResource, sa.allocatedPlaceholder)
+	allocatingAccepted := source.isAllocatingAccepted(appID)
+	running := sa.stateMachine.Is(Running.String())
+	if err := sa.moveUserResourceUsage(target.QueuePath, usage); err != nil {
+		return fmt.Errorf("application %s cannot be moved: %w", appID, err)
+	}
+	// the usage of the common ancestors does not change: only the queues below it are checked and updated
+	ancestor := commonAncestor(source, target)
+	if err := moveQueueUsage(source, target, ancestor, appID, usage, running, allocatingAccepted); err != nil {
+		sa.revertUserResourceUsage(target.QueuePath, usage)
+		return fmt.Errorf("application %s cannot be moved: %w", appID, err)
+	}
+
+	// all checks passed: transfer the remaining tracking from the source to the target queue
+	reserved := source.detachApplication(appID)
+	if !resources.IsZero(sa.pending) {
+		source.decPendingResource(sa.pending)
+		target.incPendingResource(sa.pending)
+	}
+	preempting := resources.NewResource()
+	for _, alloc := range sa.allocations {
+		if alloc.IsPreempted() {
+			preempting.AddTo(alloc.GetAllocatedResource())
+		}
+	}
+	if !resources.IsZero(preempting) {
+		source.DecPreemptingResource(preempting)
+		target.IncPreemptingResource(preempting)
+	}
+	metrics.GetQueueMetrics(source.QueuePath).MoveQueueApplication(metrics.GetQueueMetrics(target.QueuePath), strings.ToLower(sa.CurrentState()))
+
+	sa.queue = target
+	sa.queuePath = target.QueuePath
+	target.attachApplication(sa, reserved, sa.askMaxPriority)
+	source.queueEvents.SendMoveApplicationEvent(source.QueuePath, target.QueuePath, appID)
+
+	log.Log(log.SchedApplication).Info("application moved",
+		zap.String("appID", appID),
+		zap.String("fromQueue", source.QueuePath),
+		zap.String("toQueue", target.QueuePath))
+	return nil
+}
+
+// isMovable returns true if the application is in a state that allows a move to another queue.
+// The application lock is expected to be held.
+func (sa *Application) isMovable() bool {
+	switch sa.CurrentState() {
+	case New.String(), Accepted.String(), Running.String(), Completing.String(), Suspended.String():
+		return true
+	default:
+		return false
+	}
+}
+
+// moveUserResourceUsage moves the user and group tracking of the application to the new queue path. An application
+// that is tracked is moved independent of its usage: a suspended application could have released all allocations.
+// The tracking is restored on the current queue path if the user or group limits of the new queue path do not
+// allow the application.
+// The application lock is expected to be held.
+func (sa *Application) moveUserResourceUsage(queuePath string, usage *resources.Resource) error {
+	tracked := ugm.GetUserManager().IsApplicationTracked(sa.ApplicationID, sa.user)
+	if tracked {
+		sa.decUserResourceUsage(usage, true)
+	}
+	var err error
+	if !ugm.GetUserManager().CanRunApp(queuePath, sa.ApplicationID, sa.user) {
+		err = fmt.Errorf("user %s has reached the maximum applications for queue %s", sa.user.User, queuePath)
+	} else if !resources.IsZero(usage) && !ugm.GetUserManager().Headroom(queuePath, sa.ApplicationID, sa.user).FitInMaxUndef(usage) {
+		err = fmt.Errorf("user %s has no headroom for %s in queue %s", sa.user.User, usage, queuePath)
+	}
+	if tracked {
+		if err != nil {
+			sa.incUserResourceUsage(usage)
+		} else {
+			ugm.GetUserManager().IncreaseTrackedResource(queuePath, sa.ApplicationID, usage, sa.user)
+		}
+	}
+	return err
+}
+
+// revertUserResourceUsage moves the user and group tracking of the application back from the new queue path to
+// the current queue path after a failed move. The limits were checked when the usage was tracked on the current
+// queue path: the tracking is restored without checks.
+// The application lock is expected to be held.
+func (sa *Application) revertUserResourceUsage(queuePath string, usage *resources.Resource) {
+	if !ugm.GetUserManager().IsApplicationTracked(sa.ApplicationID, sa.user) {
+		return
+	}
+	ugm.GetUserManager().DecreaseTrackedResource(queuePath, sa.ApplicationID, usage, sa.user, true)
+	sa.incUserResourceUsage(usage)
+}
+
+// commonAncestor returns the lowest queue that is a parent of, or the same as, both queues.
+func commonAncestor(source, target *Queue) *Queue {
+	ancestors := make(map[*Queue]bool)
+	for queue := source; queue != nil; queue = queue.parent {
+		ancestors[queue] = true
+	}
+	for queue := target; queue != nil; queue = queue.parent {
+		if ancestors[queue] {
+			return queue
+		}
+	}
+	return nil
+}
+
+// moveQueueUsage transfers the allocated resources and the running application tracking of an application from
+// the source to the target queue and their parents up to, but not including, the common ancestor.
+// The max resources and max running applications of the target queues are checked while the target is updated:
+// a concurrent allocation cannot push the target over its limits. All changes are reverted on failure.
+func moveQueueUsage(source, target, ancestor *Queue, appID string, usage *resources.Resource, running, allocatingAccepted bool) error {
+	if !resources.IsZero(usage) {
+		if err := target.tryIncAllocatedResourceBelow(usage, ancestor); err != nil {
+			return err
+		}
+	}
+	tracked := running || allocatingAccepted
+	if tracked {
+		if err := target.tryIncRunningAppsBelow(appID, ancestor, !running); err != nil {
+			_ = target.decAllocatedResourceBelow(usage, ancestor)
+			return err
+		}
+	}
+	if !resources.IsZero(usage) {
+		if err := source.decAllocatedResourceBelow(usage, ancestor); err != nil {
+			if tracked {
+				target.decRunningAppsBelow(appID, ancestor, !running)
+			}
+			_ = target.decAllocatedResourceBelow(usage, ancestor)
+			return err
+		}
+	}
+	if tracked {
+		source.decRunningAppsBelow(appID, ancestor, !running)
+	}
+	return nil
+}
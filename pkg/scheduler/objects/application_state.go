@@ -45,6 +45,8 @@ const (
 	FailApplication
 	ExpireApplication
 	ResumeApplication
+	SuspendApplication
+	ResumeSuspendedApplication
 )
 
 const (
@@ -52,7 +54,7 @@ const (
 )
 
 func (ae applicationEvent) String() string {
-	return [...]string{"runApplication", "rejectApplication", "completeApplication", "failApplication", "expireApplication", "resumeApplication"}[ae]
+	return [...]string{"runApplication", "rejectApplication", "completeApplication", "failApplication", "expireApplication", "resumeApplication", "suspendApplication", "resumeSuspendedApplication"}[ae]
 }
 
 // ----------------------------------
@@ -72,8 +74,11 @@ const (
 	Failed
 	Expired
 	Resuming
+	Suspended
 )
 
+// The scheduler interface does not define a change detail for the suspended state: the state change event
+// of a suspend has no detail and is told apart by its message, which defaults to SuspendMessage.
 var stateEvents = map[string]si.EventRecord_ChangeDetail{
 	New.String():        si.EventRecord_APP_NEW,
 	Accepted.String():   si.EventRecord_APP_ACCEPTED,
@@ -85,10 +90,11 @@ var stateEvents = map[string]si.EventRecord_ChangeDetail{
 	Failed.String():     si.EventRecord_APP_FAILED,
 	Resuming.String():   si.EventRecord_APP_RESUMING,
 	Expired.String():    si.EventRecord_APP_EXPIRED,
+	Suspended.String():  si.EventRecord_DETAILS_NONE,
 }
 
 func (as applicationState) String() string {
-	return [...]string{"New", "Accepted", "Running", "Rejected", "Completing", "Completed", "Failing", "Failed", "Expired", "Resuming"}[as]
+	return [...]string{"New", "Accepted", "Running", "Rejected", "Completing", "Completed", "Failing", "Failed", "Expired", "Resuming", "Suspended"}[as]
 }
 
 func eventDesc() fsm.Events {
@@ -115,7 +121,7 @@ func eventDesc() fsm.Events {
 			Dst:  Completed.String(),
 		}, {
 			Name: FailApplication.String(),
-			Src:  []string{New.String(), Accepted.String(), Running.String()},
+			Src:  []string{New.String(), Accepted.String(), Running.String(), Suspended.String()},
 			Dst:  Failing.String(),
 		}, {
 			Name: FailApplication.String(),
@@ -125,6 +131,14 @@ func eventDesc() fsm.Events {
 			Name: ResumeApplication.String(),
 			Src:  []string{New.String(), Accepted.String()},
 			Dst:  Resuming.String(),
+		}, {
+			Name: SuspendApplication.String(),
+			Src:  []string{Running.String()},
+			Dst:  Suspended.String(),
+		}, {
+			Name: ResumeSuspendedApplication.String(),
+			Src:  []string{Suspended.String()},
+			Dst:  Running.String(),
 		}, {
 			Name: ExpireApplication.String(),
 			Src:  []string{Completed.String(), Failed.String(), Rejected.String()},
@@ -206,6 +220,10 @@ func callbacks() fsm.Callbacks {
 				app.queue.incRunningApps(app.ApplicationID)
 				metrics.GetQueueMetrics(app.queuePath).IncQueueApplicationsRunning()
 				metrics.GetSchedulerMetrics().IncTotalApplicationsRunning()
+				// only the first time the application runs counts, not a return from completing
+				if event.Src == Accepted.String() {
+					metrics.GetLatencyMetrics().ObserveApplicationTimeToRun(app.queuePath, app.user.User, app.startTime.Sub(app.submissionTime))
+				}
 			}
 		},
 		fmt.Sprintf("leave_%s", Running.String()): func(_ context.Context, event *fsm.Event) {
@@ -226,6 +244,16 @@ func callbacks() fsm.Callbacks {
 			metrics.GetQueueMetrics(app.queuePath).DecQueueApplicationsResuming()
 			metrics.GetSchedulerMetrics().DecTotalApplicationsResuming()
 		},
+		fmt.Sprintf("enter_%s", Suspended.String()): func(_ context.Context, event *fsm.Event) {
+			app := event.Args[0].(*Application) //nolint:errcheck
+			metrics.GetQueueMetrics(app.queuePath).IncQueueApplicationsSuspended()
+			metrics.GetSchedulerMetrics().IncTotalApplicationsSuspended()
+		},
+		fmt.Sprintf("leave_%s", Suspended.String()): func(_ context.Context, event *fsm.Event) {
+			app := event.Args[0].(*Application) //nolint:errcheck
+			metrics.GetQueueMetrics(app.queuePath).DecQueueApplicationsSuspended()
+			metrics.GetSchedulerMetrics().DecTotalApplicationsSuspended()
+		},
 		fmt.Sprintf("enter_%s", Failing.String()): func(_ context.Context, event *fsm.Event) {
 			app := event.Args[0].(*Application) //nolint:errcheck
 			metrics.GetQueueMetrics(app.queuePath).IncQueueApplicationsFailing()
@@ -187,6 +187,76 @@ func TestCompletingStateTransition(t *testing.T) {
 	assert.Equal(t, appInfo2.CurrentState(), Completing.String())
 }
 
+func TestAppTimeToRunMetrics(t *testing.T) {
+	queue := createQueue(t, "latency")
+	metrics.GetLatencyMetrics().Reset()
+	defer metrics.GetLatencyMetrics().Reset()
+	app := newApplication("app-00001", "default", "root.latency")
+	app.SetQueue(queue)
+	err := app.HandleApplicationEvent(RunApplication)
+	assertState(t, app, err, Accepted.String())
+	assertTimeToRunCount(t, 0)
+	err = app.HandleApplicationEvent(RunApplication)
+	assertState(t, app, err, Running.String())
+	assertTimeToRunCount(t, 1)
+	// running again after completing is not a new start
+	err = app.HandleApplicationEvent(CompleteApplication)
+	assertState(t, app, err, Completing.String())
+	err = app.HandleApplicationEvent(RunApplication)
+	assertState(t, app, err, Running.String())
+	assertTimeToRunCount(t, 1)
+}
+
+func assertTimeToRunCount(t *testing.T, expected int) {
+	t.Helper()
+	count, err := metrics.GetLatencyMetrics().GetApplicationTimeToRunCount("root.latency", "")
+	assert.NilError(t, err)
+	assert.Equal(t, expected, count, "unexpected application time to run observations")
+}
+
+func TestSuspendStateTransition(t *testing.T) {
+	queue := createQueue(t, "suspend")
+	metrics.GetSchedulerMetrics().Reset()
+	metrics.GetQueueMetrics("root.suspend").Reset()
+	app := newApplication("app-00001", "default", "root.suspend")
+	app.SetQueue(queue)
+
+	// only a running app can be suspended
+	err := app.HandleApplicationEvent(SuspendApplication)
+	assert.Assert(t, err != nil, "error expected new to suspended")
+	err = app.HandleApplicationEvent(RunApplication)
+	assertState(t, app, err, Accepted.String())
+	err = app.HandleApplicationEvent(SuspendApplication)
+	assert.Assert(t, err != nil, "error expected accepted to suspended")
+	err = app.HandleApplicationEvent(RunApplication)
+	assertState(t, app, err, Running.String())
+	assertQueueRunningApps(t, app, 1)
+
+	// Running -> Suspended: not counted as running
+	err = app.HandleApplicationEvent(SuspendApplication)
+	assertState(t, app, err, Suspended.String())
+	assertQueueRunningApps(t, app, 0)
+	assertTotalAppsRunningMetrics(t, 0)
+	assertQueueApplicationsSuspendedMetrics(t, app, 1)
+	err = app.HandleApplicationEvent(RunApplication)
+	assert.Assert(t, err != nil, "error expected suspended to running via run event")
+	assertState(t, app, nil, Suspended.String())
+
+	// Suspended -> Running
+	err = app.HandleApplicationEvent(ResumeSuspendedApplication)
+	assertState(t, app, err, Running.String())
+	assertQueueRunningApps(t, app, 1)
+	assertTotalAppsRunningMetrics(t, 1)
+	assertQueueApplicationsSuspendedMetrics(t, app, 0)
+
+	// Suspended -> Failing
+	err = app.HandleApplicationEvent(SuspendApplication)
+	assertState(t, app, err, Suspended.String())
+	err = app.HandleApplicationEvent(FailApplication)
+	assertState(t, app, err, Failing.String())
+	assertQueueApplicationsSuspendedMetrics(t, app, 0)
+}
+
 func TestFailedStateTransition(t *testing.T) {
 	// failing from all but rejected & completed
 	appInfo := newApplication("app-00001", "default", "root.a")
@@ -601,3 +671,10 @@ func isStateChangeEvent(t *testing.T, app *Application, changeDetail si.EventRec
 	assert.Equal(t, si.EventRecord_SET, record.EventChangeType, "incorrect change type, expected set")
 	assert.Equal(t, changeDetail, record.EventChangeDetail, "incorrect change detail")
 }
+
+func assertQueueApplicationsSuspendedMetrics(t testing.TB, app *Application, expected int) {
+	t.Helper()
+	queueApplicationsSuspended, err := metrics.GetQueueMetrics(app.queuePath).GetQueueApplicationsSuspended()
+	assert.NilError(t, err, "no error expected when getting total suspended application count in queue.")
+	assert.Equal(t, queueApplicationsSuspended, expected, "total suspended application metrics in queue is not as expected.")
+}
@@ -19,11 +19,13 @@
 package objects
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
 )
 
 type ApplicationSummary struct {
@@ -38,6 +40,7 @@ type ApplicationSummary struct {
 	ResourceUsage       *resources.TrackedResource
 	PreemptedResource   *resources.TrackedResource
 	PlaceholderResource *resources.TrackedResource
+	StateLog            []*StateLogEntry
 }
 
 func (as *ApplicationSummary) String() string {
@@ -59,3 +62,33 @@ func (as *ApplicationSummary) String() string {
 func (as *ApplicationSummary) DoLogging() {
 	log.Log(log.SchedAppUsage).Info(fmt.Sprintf("YK_APP_SUMMARY: {%s}", as))
 }
+
+// DAO returns the summary as the structured record used by the REST API, the file sink and the events.
+func (as *ApplicationSummary) DAO() *dao.ApplicationSummaryDAOInfo {
+	stateLog := make([]*dao.StateDAOInfo, 0, len(as.StateLog))
+	for _, entry := range as.StateLog {
+		stateLog = append(stateLog, &dao.StateDAOInfo{
+			Time:             entry.Time.UnixNano(),
+			ApplicationState: entry.ApplicationState,
+		})
+	}
+	return &dao.ApplicationSummaryDAOInfo{
+		ApplicationID:       as.ApplicationID,
+		SubmissionTime:      as.SubmissionTime.UnixMilli(),
+		StartTime:           as.StartTime.UnixMilli(),
+		FinishTime:          as.FinishTime.UnixMilli(),
+		User:                as.User,
+		Queue:               as.Queue,
+		State:               as.State,
+		RmID:                as.RmID,
+		ResourceUsage:       as.ResourceUsage.DAOMap(),
+		PreemptedResource:   as.PreemptedResource.DAOMap(),
+		PlaceholderResource: as.PlaceholderResource.DAOMap(),
+		StateLog:            stateLog,
+	}
+}
+
+// JSON returns the summary as a single line JSON document
+func (as *ApplicationSummary) JSON() ([]byte, error) {
+	return json.Marshal(as.DAO())
+}
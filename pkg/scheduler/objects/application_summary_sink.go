@@ -0,0 +1,121 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/log"
+	schedEvt "github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
+)
+
+var summarySink = newAppSummarySink()
+
+func init() {
+	configs.AddConfigMapCallback("app-summary-sink", func() {
+		summarySink.configure(configs.GetConfigMap())
+	})
+}
+
+// appSummarySink exports the application summaries as JSON documents:
+// appended as one line per summary to the configured file and optionally sent through the event system.
+type appSummarySink struct {
+	path   string
+	file   *os.File
+	events bool
+
+	locking.Mutex
+}
+
+func newAppSummarySink() *appSummarySink {
+	return &appSummarySink{
+		events: configs.DefaultAppSummaryEvents,
+	}
+}
+
+// configure (re)opens the file if the path has changed. A file that cannot be opened disables the file export,
+// opening is retried on the next config update.
+func (s *appSummarySink) configure(configMap map[string]string) {
+	s.Lock()
+	defer s.Unlock()
+	s.events = common.GetConfigurationBool(configMap, configs.CMAppSummaryEvents, configs.DefaultAppSummaryEvents)
+	path := configMap[configs.CMAppSummaryFile]
+	if path == s.path {
+		return
+	}
+	s.close()
+	if path == "" {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		log.Log(log.SchedAppUsage).Warn("Failed to open application summary file, file export disabled",
+			zap.String("file", path),
+			zap.Error(err))
+		return
+	}
+	s.path = path
+	s.file = file
+	log.Log(log.SchedAppUsage).Info("Application summary file export enabled", zap.String("file", path))
+}
+
+// close the current file if any, must be called holding the lock
+func (s *appSummarySink) close() {
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			log.Log(log.SchedAppUsage).Warn("Failed to close application summary file",
+				zap.String("file", s.path),
+				zap.Error(err))
+		}
+	}
+	s.file = nil
+	s.path = ""
+}
+
+// export writes the summary to the file and sends the event if enabled
+func (s *appSummarySink) export(summary *ApplicationSummary, appEvents *schedEvt.ApplicationEvents) {
+	s.Lock()
+	defer s.Unlock()
+	if s.file == nil && !s.events {
+		return
+	}
+	record, err := summary.JSON()
+	if err != nil {
+		log.Log(log.SchedAppUsage).Warn("Failed to marshal application summary",
+			zap.String("appID", summary.ApplicationID),
+			zap.Error(err))
+		return
+	}
+	if s.file != nil {
+		if _, err = s.file.Write(append(record, '\n')); err != nil {
+			log.Log(log.SchedAppUsage).Warn("Failed to write application summary",
+				zap.String("file", s.path),
+				zap.String("appID", summary.ApplicationID),
+				zap.Error(err))
+		}
+	}
+	if s.events && appEvents != nil {
+		appEvents.SendApplicationSummaryEvent(summary.ApplicationID, record)
+	}
+}
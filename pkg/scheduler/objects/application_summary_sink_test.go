@@ -0,0 +1,100 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/events/mock"
+	schedEvt "github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
+)
+
+func TestAppSummarySinkConfigure(t *testing.T) {
+	sink := newAppSummarySink()
+	defer sink.configure(map[string]string{})
+	file := filepath.Join(t.TempDir(), "summary.json")
+
+	sink.configure(map[string]string{configs.CMAppSummaryFile: file, configs.CMAppSummaryEvents: "true"})
+	assert.Equal(t, file, sink.path)
+	assert.Assert(t, sink.file != nil, "file should be opened")
+	assert.Assert(t, sink.events, "events should be enabled")
+	_, err := os.Stat(file)
+	assert.NilError(t, err, "file should have been created")
+
+	// unchanged path keeps the file open
+	opened := sink.file
+	sink.configure(map[string]string{configs.CMAppSummaryFile: file})
+	assert.Equal(t, opened, sink.file)
+	assert.Assert(t, !sink.events, "events should be disabled")
+
+	// file that cannot be opened disables the export
+	sink.configure(map[string]string{configs.CMAppSummaryFile: filepath.Join(t.TempDir(), "missing", "summary.json")})
+	assert.Equal(t, "", sink.path)
+	assert.Assert(t, sink.file == nil, "file should not be opened")
+
+	sink.configure(map[string]string{})
+	assert.Assert(t, sink.file == nil, "file should be closed")
+}
+
+func TestLogAppSummaryExport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "summary.json")
+	summarySink.configure(map[string]string{configs.CMAppSummaryFile: file, configs.CMAppSummaryEvents: "true"})
+	defer summarySink.configure(map[string]string{})
+
+	app := newApplication(appID1, "default", "root.a")
+	eventSystem := mock.NewEventSystem()
+	app.appEvents = schedEvt.NewApplicationEvents(eventSystem)
+	// an application that never ran has no summary
+	app.LogAppSummary("rm-1")
+	assert.Assert(t, app.GetFinalSummary() == nil, "summary should not be recorded")
+	assert.Equal(t, 0, len(eventSystem.Events))
+
+	app.startTime = time.Now()
+	app.finishedTime = time.Now()
+	app.recordState(Completed.String())
+	app.LogAppSummary("rm-1")
+	summary := app.GetFinalSummary()
+	assert.Assert(t, summary != nil, "summary should be recorded")
+	assert.Equal(t, "rm-1", summary.RmID)
+
+	content, err := os.ReadFile(file)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(t, 1, len(lines), "expected one summary line")
+	var record dao.ApplicationSummaryDAOInfo
+	err = json.Unmarshal([]byte(lines[0]), &record)
+	assert.NilError(t, err)
+	assert.Equal(t, appID1, record.ApplicationID)
+	assert.Equal(t, "root.a", record.Queue)
+	assert.Equal(t, app.startTime.UnixMilli(), record.StartTime)
+	assert.Equal(t, 1, len(record.StateLog))
+	assert.Equal(t, Completed.String(), record.StateLog[0].ApplicationState)
+
+	assert.Equal(t, 1, len(eventSystem.Events))
+	assert.Equal(t, "YK_APP_SUMMARY: "+lines[0], eventSystem.Events[0].Message)
+}
@@ -34,12 +34,15 @@ import (
 	"github.com/apache/yunikorn-core/pkg/events"
 	"github.com/apache/yunikorn-core/pkg/events/mock"
 	"github.com/apache/yunikorn-core/pkg/handler"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 	mockCommon "github.com/apache/yunikorn-core/pkg/mock"
 	"github.com/apache/yunikorn-core/pkg/plugins"
 	"github.com/apache/yunikorn-core/pkg/rmproxy"
 	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
 	schedEvt "github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
+	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
 	"github.com/apache/yunikorn-core/pkg/scheduler/ugm"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
 	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
@@ -2297,7 +2300,7 @@ func TestTryAllocateNoRequests(t *testing.T) {
 
 	app := newApplication(appID1, "default", "root.unknown")
 	preemptionAttemptsRemaining := 0
-	result := app.tryAllocate(node.GetAvailableResource(), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result := app.tryAllocate(node.GetAvailableResource(), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Check(t, result == nil, "unexpected result")
 }
 
@@ -2322,7 +2325,7 @@ func TestTryAllocateFit(t *testing.T) {
 	assert.NilError(t, err)
 
 	preemptionAttemptsRemaining := 0
-	result := app.tryAllocate(node.GetAvailableResource(), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result := app.tryAllocate(node.GetAvailableResource(), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 
 	assert.Assert(t, result != nil, "alloc expected")
 	assert.Assert(t, result.Request != nil, "alloc expected")
@@ -2369,18 +2372,18 @@ func TestTryAllocatePreemptQueue(t *testing.T) {
 
 	preemptionAttemptsRemaining := 10
 
-	result1 := app1.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result1 := app1.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result1 != nil, "result1 expected")
 	alloc1 := result1.Request
 	assert.Assert(t, alloc1 != nil, "alloc1 expected")
-	result2 := app1.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result2 := app1.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result2 != nil, "result2 expected")
 	alloc2 := result2.Request
 	assert.Assert(t, alloc2 != nil, "alloc2 expected")
 
 	// preemption max attempts exhausted
 	maxAttemptsExhausted := 0
-	result3 := app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 0}), true, 30*time.Second, &maxAttemptsExhausted, iterator, iterator, getNode)
+	result3 := app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 0}), true, 30*time.Second, &maxAttemptsExhausted, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 == nil, "result3 not expected")
 	assert.Assert(t, !alloc2.IsPreempted(), "alloc2 should not have been preempted")
 	assertAllocationLog(t, ask3, []string{common.PreemptionMaxAttemptsExhausted, common.PreemptionDoesNotHelp})
@@ -2389,7 +2392,7 @@ func TestTryAllocatePreemptQueue(t *testing.T) {
 	maxAttemptsDecrease := 10
 
 	// on first attempt, not enough time has passed
-	result3 = app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 0}), true, 30*time.Second, &maxAttemptsDecrease, iterator, iterator, getNode)
+	result3 = app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 0}), true, 30*time.Second, &maxAttemptsDecrease, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 == nil, "result3 not expected")
 	assert.Assert(t, !alloc2.IsPreempted(), "alloc2 should not have been preempted")
 	assertAllocationLog(t, ask3, []string{common.PreemptionPreconditionsFailed, common.PreemptionDoesNotHelp})
@@ -2397,7 +2400,7 @@ func TestTryAllocatePreemptQueue(t *testing.T) {
 
 	// pass the time and try again
 	ask3.createTime = ask3.createTime.Add(-30 * time.Second)
-	result3 = app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 0}), true, 30*time.Second, &maxAttemptsDecrease, iterator, iterator, getNode)
+	result3 = app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 0}), true, 30*time.Second, &maxAttemptsDecrease, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 != nil && result3.Request != nil && result3.ResultType == Reserved, "alloc3 should be a reservation")
 	assert.Assert(t, alloc2.IsPreempted(), "alloc2 should have been preempted")
 	assert.Equal(t, maxAttemptsDecrease, 9)
@@ -2408,14 +2411,14 @@ func TestTryAllocatePreemptNode(t *testing.T) {
 	preemptionAttemptsRemaining := 10
 
 	// preemption delay not yet passed, so preemption should fail
-	result3 := app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result3 := app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 == nil, "result3 expected")
 	assert.Assert(t, !allocs[1].IsPreempted(), "alloc1 should have been preempted")
 	assertAllocationLog(t, ask3, []string{common.PreemptionPreconditionsFailed, common.PreemptionDoesNotHelp})
 
 	// pass the time and try again
 	ask3.createTime = ask3.createTime.Add(-30 * time.Second)
-	result3 = app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result3 = app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 != nil, "result3 expected")
 	assert.Equal(t, Reserved, result3.ResultType, "expected reservation")
 	alloc3 := result3.Request
@@ -2480,7 +2483,7 @@ func createPreemptNodeTestSetup(t *testing.T) (func() NodeIterator, func(NodeID
 
 	// consume capacity with 'unlimited' app
 	for _, r := range []*resources.Resource{resources.NewResourceFromMap(map[string]resources.Quantity{"first": 40}), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 39})} {
-		result0 := app0.tryAllocate(r, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+		result0 := app0.tryAllocate(r, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 		assert.Assert(t, result0 != nil, "result0 expected")
 		alloc0 := result0.Request
 		assert.Assert(t, alloc0 != nil, "alloc0 expected")
@@ -2491,7 +2494,7 @@ func createPreemptNodeTestSetup(t *testing.T) (func() NodeIterator, func(NodeID
 	allocs := make([]*Allocation, 0)
 	for _, r := range []*resources.Resource{resources.NewResourceFromMap(map[string]resources.Quantity{"first": 28}), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 23})} {
 		var alloc1 *Allocation
-		result1 := app1.tryAllocate(r, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+		result1 := app1.tryAllocate(r, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 		assert.Assert(t, result1 != nil, "result1 expected")
 		alloc1 = result1.Request
 		assert.Assert(t, result1.Request != nil, "alloc1 expected")
@@ -2501,7 +2504,7 @@ func createPreemptNodeTestSetup(t *testing.T) (func() NodeIterator, func(NodeID
 
 	// on first attempt, should see a reservation since we're after the reservation timeout
 	ask3.createTime = ask3.createTime.Add(-10 * time.Second)
-	result3 := app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result3 := app2.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 != nil, "result3 expected")
 	alloc3 := result3.Request
 	assert.Assert(t, alloc3 != nil, "alloc3 not expected")
@@ -2541,7 +2544,7 @@ func TestTryAllocatePreemptNodeWithReservations(t *testing.T) {
 	defer func() {
 		reservationWaitTimeout = defWaitTimeout
 	}()
-	result3 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result3 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 != nil, "result3 expected")
 	assert.Equal(t, Reserved, result3.ResultType, "expected reservation")
 	alloc3 := result3.Request
@@ -2564,13 +2567,13 @@ func TestTryAllocatePreemptNodeWithReservationsWithHighPriority(t *testing.T) {
 	defer func() {
 		reservationWaitTimeout = defWaitTimeout
 	}()
-	result3 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result3 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 == nil, "result3 expected")
 
 	// Set higher priority than the reserved ask priority
 	ask4.priority = math.MaxInt32
 	ask4.preemptCheckTime = ask4.preemptCheckTime.Add(-30 * time.Second)
-	result4 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result4 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result4 != nil, "result3 expected")
 	assert.Equal(t, Reserved, result4.ResultType, "expected reservation")
 	alloc3 := result4.Request
@@ -2598,7 +2601,7 @@ func TestTryAllocatePreemptNodeWithReservationsNotPossibleToCancel(t *testing.T)
 	preemptionAttemptsRemaining := 10
 
 	// on first attempt, should see a reservation on node2 since we're after the reservation timeout
-	result1 := app1.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result1 := app1.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result1 != nil, "result expected")
 	assert.Equal(t, "node2", result1.NodeID, "wrong node assignment")
 	assert.Equal(t, Reserved, result1.ResultType, "expected reservation")
@@ -2616,7 +2619,7 @@ func TestTryAllocatePreemptNodeWithReservationsNotPossibleToCancel(t *testing.T)
 
 	// Set higher priority than the reserved ask priority but no preemption because reserved ask waiting time not exceeded
 	ask4.priority = 1
-	result3 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result3 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result3 == nil, "result3 expected")
 
 	// Ensure reserved ask waiting time exceeds
@@ -2629,7 +2632,7 @@ func TestTryAllocatePreemptNodeWithReservationsNotPossibleToCancel(t *testing.T)
 		reservationWaitTimeout = defWaitTimeout
 	}()
 	ask4.preemptCheckTime = ask4.preemptCheckTime.Add(-30 * time.Second)
-	result4 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result4 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result4 == nil, "result4 expected")
 
 	// Ensure reserved ask waiting time exceeds
@@ -2638,7 +2641,7 @@ func TestTryAllocatePreemptNodeWithReservationsNotPossibleToCancel(t *testing.T)
 	ask3.preemptionTriggered = false
 	ask4.createTime = ask4.createTime.Add(-30 * time.Second)
 	ask4.preemptCheckTime = ask4.preemptCheckTime.Add(-30 * time.Second)
-	result5 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result5 := app3.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 18}), true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result5 != nil, "result5 expected")
 	assert.Equal(t, Reserved, result5.ResultType, "expected reservation")
 	alloc3 := result5.Request
@@ -3074,7 +3077,7 @@ func TestRequestDoesNotFitQueueEvents(t *testing.T) {
 	attempts := 0
 
 	// try to allocate
-	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilGetNode)
+	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 1, len(eventSystem.Events))
 	event := eventSystem.Events[0]
 	assert.Equal(t, si.EventRecord_REQUEST, event.Type)
@@ -3085,14 +3088,14 @@ func TestRequestDoesNotFitQueueEvents(t *testing.T) {
 	assert.Equal(t, "Request 'alloc-0' does not fit in queue 'root.default' (requested map[memory:100 vcores:10], available map[memory:0 vcores:0])", event.Message)
 
 	// second attempt - no new event
-	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilGetNode)
+	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 1, len(eventSystem.Events))
 
 	// third attempt with enough headroom - new event
 	eventSystem.Reset()
 	headroom, err = resources.NewResourceFromConf(map[string]string{"memory": "1000", "vcores": "1000"})
 	assert.NilError(t, err)
-	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilGetNode)
+	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 1, len(eventSystem.Events))
 	event = eventSystem.Events[0]
 	assert.Equal(t, si.EventRecord_REQUEST, event.Type)
@@ -3145,7 +3148,7 @@ func TestRequestDoesNotFitUserQuotaQueueEvents(t *testing.T) {
 	attempts := 0
 
 	// try to allocate
-	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilGetNode)
+	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 1, len(eventSystem.Events))
 	event := eventSystem.Events[0]
 	assert.Equal(t, si.EventRecord_REQUEST, event.Type)
@@ -3156,7 +3159,7 @@ func TestRequestDoesNotFitUserQuotaQueueEvents(t *testing.T) {
 	assert.Equal(t, "Request 'alloc-0' exceeds the available user quota (requested map[memory:100 vcores:10], available map[memory:1 vcores:1])", event.Message)
 
 	// second attempt - no new event
-	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilGetNode)
+	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 1, len(eventSystem.Events))
 
 	// third attempt with enough headroom - new event
@@ -3164,7 +3167,7 @@ func TestRequestDoesNotFitUserQuotaQueueEvents(t *testing.T) {
 	conf.Limits[0].MaxResources = nil
 	err = ugm.GetUserManager().UpdateConfig(conf, "root")
 	assert.NilError(t, err)
-	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilGetNode)
+	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 1, len(eventSystem.Events))
 	event = eventSystem.Events[0]
 	assert.Equal(t, si.EventRecord_REQUEST, event.Type)
@@ -3193,7 +3196,7 @@ func TestAllocationFailures(t *testing.T) {
 	attempts := 0
 
 	// case #1: not enough queue headroom
-	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilGetNode)
+	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 1, len(ask.allocLog))
 	assert.Equal(t, int32(1), ask.allocLog[NotEnoughQueueQuota].Count)
 
@@ -3220,7 +3223,7 @@ func TestAllocationFailures(t *testing.T) {
 	assert.NilError(t, err)
 	headroom, err = resources.NewResourceFromConf(map[string]string{"memory": "1000", "vcores": "1000"})
 	assert.NilError(t, err)
-	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilGetNode)
+	app.tryAllocate(headroom, true, time.Second, &attempts, nilNodeIterator, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 2, len(ask.allocLog))
 	assert.Equal(t, int32(1), ask.allocLog[NotEnoughUserQuota].Count)
 }
@@ -3576,7 +3579,7 @@ func TestPredicateFailedEvents(t *testing.T) {
 
 	app.tryAllocate(headroom, false, time.Second, &attempts, func() NodeIterator {
 		return &testIterator{}
-	}, nilNodeIterator, nilGetNode)
+	}, nilNodeIterator, nilNodeIterator, nilGetNode)
 	assert.Equal(t, 1, len(eventSystem.Events))
 	event := eventSystem.Events[0]
 	assert.Equal(t, si.EventRecord_REQUEST, event.Type)
@@ -3630,7 +3633,7 @@ func TestRequiredNodePreemption(t *testing.T) {
 
 	// allocate ask
 	headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 50})
-	result := app.tryAllocate(headRoom, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result := app.tryAllocate(headRoom, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Equal(t, result.ResultType, Allocated, "could not allocate ask-1")
 	assert.Equal(t, result.Request.allocationKey, "ask-1", "unexpected allocation key")
 
@@ -3642,19 +3645,25 @@ func TestRequiredNodePreemption(t *testing.T) {
 	assert.NilError(t, err, "could not add ask-2")
 
 	// try to allocate ask2 with node being full - expect a reservation
-	result = app.tryAllocate(headRoom, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result = app.tryAllocate(headRoom, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Equal(t, result.ResultType, Reserved, "allocation result is not reserved")
 	assert.Equal(t, result.Request.allocationKey, "ask-2", "unexpected allocation key")
 	err = app.Reserve(node, ask2)
 	assert.NilError(t, err, "reservation failed")
 
 	// preemption
+	metrics.GetPreemptionMetrics().Reset()
+	defer metrics.GetPreemptionMetrics().Reset()
 	assert.Assert(t, app.tryReservedAllocate(headRoom, iterator) == nil, "unexpected result from reserved allocation")
 	assert.Assert(t, ask1.IsPreempted(), "ask1 has not been preempted")
 	assert.Assert(t, ask2.HasTriggeredPreemption(), "ask2 has not triggered preemption")
 	assert.Equal(t, 1, len(releaseEvents), "unexpected number of release events")
 	assert.Equal(t, 1, len(releaseEvents[0].ReleasedAllocations), "unexpected number of release allocations")
 	assert.Equal(t, "ask-1", releaseEvents[0].ReleasedAllocations[0].AllocationKey, "allocation key")
+	assertPreemptionAttempts(t, childQ.QueuePath, metrics.PreemptionTypeRequiredNode, metrics.PreemptionSuccess, 1)
+	observed, err := metrics.GetPreemptionMetrics().GetVictimsPerAttemptCount(metrics.PreemptionTypeRequiredNode)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, observed, "victims of the preemption not recorded")
 
 	// 2nd attempt - no preemption this time
 	releaseEvents = nil
@@ -3709,7 +3718,7 @@ func TestRequiredNodePreemptionFailed(t *testing.T) {
 
 	// allocate ask
 	headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 50})
-	result := app.tryAllocate(headRoom, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result := app.tryAllocate(headRoom, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Equal(t, result.ResultType, Allocated, "could not allocate ask-1")
 	assert.Equal(t, result.Request.allocationKey, "ask-1", "unexpected allocation key")
 
@@ -3721,13 +3730,15 @@ func TestRequiredNodePreemptionFailed(t *testing.T) {
 	assert.NilError(t, err, "could not add ask-2")
 
 	// try to allocate ask2 with node being full - expect a reservation
-	result = app.tryAllocate(headRoom, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result = app.tryAllocate(headRoom, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Equal(t, result.ResultType, Reserved, "allocation result is not reserved")
 	assert.Equal(t, result.Request.allocationKey, "ask-2", "unexpected allocation key")
 	err = app.Reserve(node, ask2)
 	assert.NilError(t, err, "reservation failed")
 
 	// try preemption - should not succeed
+	metrics.GetPreemptionMetrics().Reset()
+	defer metrics.GetPreemptionMetrics().Reset()
 	assert.Assert(t, app.tryReservedAllocate(headRoom, iterator) == nil, "unexpected result from reserved allocation")
 	assert.Assert(t, !ask1.IsPreempted(), "unexpected preemption of ask1")
 	assert.Assert(t, !ask2.HasTriggeredPreemption(), "unexpected preemption triggered from ask2")
@@ -3753,6 +3764,7 @@ func TestRequiredNodePreemptionFailed(t *testing.T) {
 	assert.Assert(t, app.tryReservedAllocate(headRoom, iterator) == nil, "unexpected result from reserved allocation")
 	assert.Equal(t, 1, noEvents, "unexpected number of REQUEST events")
 	assert.Equal(t, int32(4), ask2.allocLog[common.NoVictimForRequiredNode].Count, "incorrect number of entry count")
+	assertPreemptionAttempts(t, childQ.QueuePath, metrics.PreemptionTypeRequiredNode, metrics.PreemptionNoVictims, 4)
 }
 
 type testIterator struct{}
@@ -4531,7 +4543,7 @@ func TestApplicationBackoff(t *testing.T) {
 	preemptionAttemptsRemaining := 0
 	beforeTryAlloc := time.Now()
 	available := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})
-	result := app.tryAllocate(available, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result := app.tryAllocate(available, true, 30*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result == nil)
 	assert.Assert(t, app.GetBackoffDeadline().After(beforeTryAlloc))
 }
@@ -4665,3 +4677,321 @@ func TestCancelMatchingReservations(t *testing.T) {
 	assert.Equal(t, released, 0)
 	assert.Equal(t, remaining, 0)
 }
+
+func TestSuspendResumeApplication(t *testing.T) {
+	setupUGM()
+
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	queue, err := createManagedQueue(root, "a", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	app, testHandler := newApplicationWithHandler(appID1, "default", "root.a")
+	app.SetQueue(queue)
+	queue.AddApplication(app)
+
+	_, err = app.SuspendApplication(false, "")
+	assert.ErrorContains(t, err, "only running applications can be suspended")
+	err = app.ResumeSuspendedApplication("")
+	assert.ErrorContains(t, err, "only suspended applications can be resumed")
+
+	app.SetState(Accepted.String())
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	alloc := newAllocation(appID1, nodeID1, res)
+	app.AddAllocation(alloc)
+	assert.Assert(t, app.IsRunning(), "app should be running after the first allocation")
+	ask := newAllocationAsk(aKey, appID1, res)
+	err = app.AddAllocationAsk(ask)
+	assert.NilError(t, err, "ask should have been added to app")
+	node := newNode(nodeID2, map[string]resources.Quantity{"first": 10})
+	err = app.Reserve(node, ask)
+	assert.NilError(t, err, "reservation should not have failed")
+	assert.Equal(t, len(queue.sortApplications(false)), 1, "app with pending ask should be sorted")
+
+	// suspend keeps the allocations and the asks, removes the reservation
+	unreserved, err := app.SuspendApplication(false, "")
+	assert.NilError(t, err, "suspend should not have failed")
+	assert.Equal(t, unreserved, 1, "expected the reservation to be removed")
+	assert.Assert(t, app.IsSuspended(), "app should be suspended")
+	assert.Assert(t, !app.HasReserved(), "suspended app should not have reservations")
+	assert.Assert(t, resources.Equals(app.GetAllocatedResource(), res), "allocations should be kept")
+	assert.Assert(t, resources.Equals(app.GetPendingResource(), res), "asks should be kept")
+	assert.Equal(t, len(queue.sortApplications(false)), 0, "suspended app should not be sorted")
+	_, err = app.SuspendApplication(false, "")
+	assert.ErrorContains(t, err, "only running applications can be suspended")
+	for _, event := range testHandler.GetEvents() {
+		_, ok := event.(*rmevent.RMReleaseAllocationEvent)
+		assert.Assert(t, !ok, "no release expected when suspending without release")
+	}
+
+	err = app.ResumeSuspendedApplication("")
+	assert.NilError(t, err, "resume should not have failed")
+	assert.Assert(t, app.IsRunning(), "app should be running after resume")
+	assert.Equal(t, len(queue.sortApplications(false)), 1, "resumed app should be sorted")
+
+	// suspend with release sends the allocations back to the RM
+	_, err = app.SuspendApplication(true, "")
+	assert.NilError(t, err, "suspend should not have failed")
+	var found bool
+	for _, event := range testHandler.GetEvents() {
+		if allocRelease, ok := event.(*rmevent.RMReleaseAllocationEvent); ok {
+			assert.Equal(t, len(allocRelease.ReleasedAllocations), 1, "one allocation should have been released")
+			assert.Equal(t, allocRelease.ReleasedAllocations[0].AllocationKey, alloc.GetAllocationKey())
+			assert.Equal(t, allocRelease.ReleasedAllocations[0].TerminationType, si.TerminationType_TIMEOUT, "suspend release should not be a preemption")
+			assert.Equal(t, allocRelease.ReleasedAllocations[0].Message, SuspendMessage)
+			found = true
+		}
+	}
+	assert.Assert(t, found, "release allocation event not found in list")
+	assert.Assert(t, alloc.IsReleased(), "allocation should be marked released")
+
+	// the app stays suspended when everything is removed and completes on resume
+	app.RemoveAllocation(alloc.GetAllocationKey(), si.TerminationType_TIMEOUT)
+	app.RemoveAllocationAsk(aKey)
+	assert.Assert(t, app.IsSuspended(), "app should stay suspended without allocations and asks")
+	err = app.ResumeSuspendedApplication("")
+	assert.NilError(t, err, "resume should not have failed")
+	assert.Assert(t, app.IsCompleting(), "app without allocations and asks should be completing after resume")
+}
+
+func TestMoveSuspendedApplication(t *testing.T) {
+	setupUGM()
+
+	root, err := NewConfiguredQueue(configs.QueueConfig{Name: "root", Parent: true, SubmitACL: "*"}, nil, false, nil)
+	assert.NilError(t, err, "root queue create failed")
+	source, err := createManagedQueue(root, "a", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	target, err := createManagedQueue(root, "b", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	app := newApplication(appID1, "default", "root.a")
+	app.SetQueue(source)
+	source.AddApplication(app)
+	app.SetState(Accepted.String())
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	alloc := newAllocation(appID1, nodeID1, res)
+	app.AddAllocation(alloc)
+	source.IncAllocatedResource(res, false)
+	_, err = app.SuspendApplication(true, "")
+	assert.NilError(t, err, "suspend should not have failed")
+	// the released allocation is confirmed by the RM: the app is still tracked without usage
+	app.RemoveAllocation(alloc.GetAllocationKey(), si.TerminationType_TIMEOUT)
+	err = source.DecAllocatedResource(res)
+	assert.NilError(t, err, "release should not have failed")
+	assert.Assert(t, ugm.GetUserManager().IsApplicationTracked(appID1, app.GetUser()), "app should be tracked without usage")
+
+	err = app.MoveToQueue(target)
+	assert.NilError(t, err, "move should not have failed")
+	userTracker := ugm.GetUserManager().GetUserTracker("testuser")
+	assert.Assert(t, userTracker != nil, "user tracker should exist")
+	var tracked bool
+	for _, child := range userTracker.GetResourceUsageDAOInfo().Queues.Children {
+		assert.Assert(t, child.QueuePath != "root.a" || len(child.RunningApplications) == 0, "app should not be tracked on the source")
+		if child.QueuePath == "root.b" {
+			assert.DeepEqual(t, child.RunningApplications, []string{appID1})
+			tracked = true
+		}
+	}
+	assert.Assert(t, tracked, "app should be tracked on the target")
+
+	// nothing left to run: resume completes the app and removes the tracking
+	err = app.ResumeSuspendedApplication("")
+	assert.NilError(t, err, "resume should not have failed")
+	assert.Assert(t, app.IsCompleting(), "app without allocations and asks should be completing after resume")
+	assert.Assert(t, !ugm.GetUserManager().IsApplicationTracked(appID1, app.GetUser()), "app should not be tracked after resume")
+	assert.Assert(t, ugm.GetUserManager().GetUserTracker("testuser") == nil, "user tracker should have been removed")
+}
+
+func TestMoveToQueue(t *testing.T) {
+	setupUGM()
+
+	root, err := NewConfiguredQueue(configs.QueueConfig{Name: "root", Parent: true, SubmitACL: "*"}, nil, false, nil)
+	assert.NilError(t, err, "root queue create failed")
+	source, err := createManagedQueue(root, "a", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	target, err := createManagedQueue(root, "b", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	full, err := createManagedQueueMaxApps(root, "full", false, nil, 1)
+	assert.NilError(t, err, "queue create failed")
+	small, err := createManagedQueue(root, "small", false, map[string]string{"first": "2"})
+	assert.NilError(t, err, "queue create failed")
+	parent, err := createManagedQueue(root, "parent", true, nil)
+	assert.NilError(t, err, "queue create failed")
+	denied, err := NewConfiguredQueue(configs.QueueConfig{Name: "denied", SubmitACL: "!testuser"}, root, false, nil)
+	assert.NilError(t, err, "queue create failed")
+
+	app := newApplication(appID1, "default", "root.a")
+	app.SetQueue(source)
+	source.AddApplication(app)
+	app.SetState(Accepted.String())
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	alloc := newAllocation(appID1, nodeID1, res)
+	app.AddAllocation(alloc)
+	source.IncAllocatedResource(res, false)
+	assert.Assert(t, app.IsRunning(), "app should be running after the first allocation")
+	ask := newAllocationAsk(aKey, appID1, res)
+	err = app.AddAllocationAsk(ask)
+	assert.NilError(t, err, "ask should have been added to app")
+	node := newNode(nodeID2, map[string]resources.Quantity{"first": 10})
+	err = app.Reserve(node, ask)
+	assert.NilError(t, err, "reservation should not have failed")
+	source.Reserve(appID1)
+
+	// validation failures leave the app untouched
+	err = app.MoveToQueue(source)
+	assert.ErrorContains(t, err, "already in queue")
+	err = app.MoveToQueue(parent)
+	assert.ErrorContains(t, err, "is not a leaf queue")
+	err = app.MoveToQueue(denied)
+	assert.ErrorContains(t, err, "is not allowed to submit")
+	err = app.MoveToQueue(small)
+	assert.ErrorContains(t, err, "do not fit in max resources")
+	full.incRunningApps("other")
+	err = app.MoveToQueue(full)
+	assert.ErrorContains(t, err, "maximum running applications")
+	assert.NilError(t, target.StopQueue(), "stop should not have failed")
+	err = app.MoveToQueue(target)
+	assert.ErrorContains(t, err, "does not accept new applications")
+	assert.NilError(t, target.ResumeQueue(), "resume should not have failed")
+	assert.Equal(t, app.GetQueuePath(), "root.a", "app should not have moved")
+	assert.Assert(t, source.GetApplication(appID1) != nil, "app should still be in the source queue")
+
+	// a failed release on the source reverts the changes on the target
+	err = source.DecAllocatedResource(res)
+	assert.NilError(t, err, "release should not have failed")
+	err = app.MoveToQueue(target)
+	assert.ErrorContains(t, err, "is larger than 'root.a' queue allocation")
+	assert.Assert(t, resources.IsZero(target.GetAllocatedResource()), "target allocated resources should have been reverted")
+	assert.Equal(t, target.runningApps, uint64(0), "target running apps should have been reverted")
+	assert.Equal(t, app.GetQueuePath(), "root.a", "app should not have moved")
+	userTracker := ugm.GetUserManager().GetUserTracker("testuser")
+	assert.Assert(t, userTracker != nil, "user tracker should exist")
+	for _, child := range userTracker.GetResourceUsageDAOInfo().Queues.Children {
+		assert.Assert(t, child.QueuePath != "root.b" || len(child.RunningApplications) == 0, "app should not be tracked on the target")
+	}
+	source.IncAllocatedResource(res, false)
+
+	err = app.MoveToQueue(target)
+	assert.NilError(t, err, "move should not have failed")
+	assert.Equal(t, app.GetQueue(), target, "app queue not updated")
+	assert.Equal(t, app.GetQueuePath(), "root.b", "app queue path not updated")
+	assert.Assert(t, source.GetApplication(appID1) == nil, "app should have been removed from the source queue")
+	assert.Equal(t, target.GetApplication(appID1), app, "app should have been added to the target queue")
+	assert.Assert(t, resources.IsZero(source.GetAllocatedResource()), "source allocated resources not released")
+	assert.Assert(t, resources.IsZero(source.GetPendingResource()), "source pending resources not released")
+	assert.Assert(t, resources.Equals(target.GetAllocatedResource(), res), "target allocated resources not set")
+	assert.Assert(t, resources.Equals(target.GetPendingResource(), res), "target pending resources not set")
+	assert.Assert(t, resources.Equals(root.GetAllocatedResource(), res), "root allocated resources should not change")
+	assert.Assert(t, resources.Equals(root.GetPendingResource(), res), "root pending resources should not change")
+	assert.Equal(t, source.runningApps, uint64(0), "source running apps not updated")
+	assert.Equal(t, target.runningApps, uint64(1), "target running apps not updated")
+	assert.Equal(t, root.runningApps, uint64(2), "root running apps should not change")
+	assert.Equal(t, len(source.GetReservedApps()), 0, "reservation should have been removed from the source")
+	assert.Equal(t, target.GetReservedApps()[appID1], 1, "reservation should have been moved to the target")
+	assert.Equal(t, len(target.sortApplications(false)), 1, "moved app should be sorted in the target")
+
+	userTracker = ugm.GetUserManager().GetUserTracker("testuser")
+	assert.Assert(t, userTracker != nil, "user tracker should exist")
+	var tracked *dao.ResourceUsageDAOInfo
+	for _, child := range userTracker.GetResourceUsageDAOInfo().Queues.Children {
+		assert.Assert(t, child.QueuePath != "root.a" || len(child.RunningApplications) == 0, "app should not be tracked on the source")
+		if child.QueuePath == "root.b" {
+			tracked = child
+		}
+	}
+	assert.Assert(t, tracked != nil, "app should be tracked on the target")
+	assert.DeepEqual(t, tracked.RunningApplications, []string{appID1})
+	assert.Equal(t, tracked.ResourceUsage["first"], int64(5), "usage not tracked on the target")
+
+	app.SetState(Completed.String())
+	err = app.MoveToQueue(source)
+	assert.ErrorContains(t, err, "cannot be moved in state Completed")
+}
+
+func TestGetDeadlineFromTags(t *testing.T) {
+	deadline := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		deadline time.Time
+		runtime  time.Duration
+	}{
+		{"no tags", nil, time.Time{}, 0},
+		{"deadline only", map[string]string{AppTagDeadline: "2030-01-01T12:00:00Z"}, deadline, 0},
+		{"deadline and runtime", map[string]string{AppTagDeadline: "2030-01-01T12:00:00Z", AppTagEstimatedRuntime: "90m"}, deadline, 90 * time.Minute},
+		{"invalid runtime", map[string]string{AppTagDeadline: "2030-01-01T12:00:00Z", AppTagEstimatedRuntime: "long"}, deadline, 0},
+		{"negative runtime", map[string]string{AppTagDeadline: "2030-01-01T12:00:00Z", AppTagEstimatedRuntime: "-1h"}, deadline, 0},
+		{"runtime only", map[string]string{AppTagEstimatedRuntime: "90m"}, time.Time{}, 0},
+		{"invalid deadline", map[string]string{AppTagDeadline: "tomorrow", AppTagEstimatedRuntime: "90m"}, time.Time{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newApplicationWithTags(appID1, "default", "root.a", tt.tags)
+			assert.Assert(t, app.GetDeadline().Equal(tt.deadline), "unexpected deadline %v", app.GetDeadline())
+			assert.Equal(t, app.estimatedRuntime, tt.runtime, "unexpected runtime")
+		})
+	}
+}
+
+func TestCheckDeadline(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	// the deadline risk is checked independent of the sort policy
+	leaf.sortType = policies.FifoSortPolicy
+
+	now := time.Now()
+	tags := map[string]string{
+		AppTagDeadline:         now.Add(time.Hour).Format(time.RFC3339),
+		AppTagEstimatedRuntime: "30m",
+	}
+	app := newApplicationWithTags(appID1, "default", "root.leaf", tags)
+	app.SetQueue(leaf)
+	leaf.AddApplication(app)
+	atRisk, err := metrics.GetSchedulerMetrics().GetApplicationsDeadlineAtRisk()
+	assert.NilError(t, err, "failed to get metric")
+
+	// without pending resources the app is never at risk
+	app.checkDeadline(now.Add(2 * time.Hour))
+	assert.Assert(t, !app.IsDeadlineAtRisk(), "app without pending resources should not be at risk")
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	err = app.AddAllocationAsk(newAllocationAsk(aKey, appID1, res))
+	assert.NilError(t, err, "ask should have been added to app")
+	eventSystem := mock.NewEventSystem()
+	app.appEvents = schedEvt.NewApplicationEvents(eventSystem)
+	app.checkDeadline(now)
+	assert.Assert(t, !app.IsDeadlineAtRisk(), "app should not be at risk with enough time left")
+	app.checkDeadline(now.Add(45 * time.Minute))
+	assert.Assert(t, app.IsDeadlineAtRisk(), "app should be at risk when the runtime does not fit before the deadline")
+	assertDeadlineAtRiskMetric(t, atRisk+1)
+	assert.Equal(t, len(eventSystem.Events), 1, "at risk event not sent")
+	// no change does not send an event
+	app.checkDeadline(now.Add(50 * time.Minute))
+	assert.Equal(t, len(eventSystem.Events), 1, "unexpected event")
+
+	// a running app only needs the remaining runtime
+	app.startTime = now.Add(15 * time.Minute)
+	app.checkDeadline(now.Add(45 * time.Minute))
+	assert.Assert(t, !app.IsDeadlineAtRisk(), "running app should not be at risk with the remaining runtime left")
+	assertDeadlineAtRiskMetric(t, atRisk)
+	assert.Equal(t, len(eventSystem.Events), 2, "no longer at risk event not sent")
+
+	// sorting does not check the deadline, the queue check does, removing the app clears the risk
+	app.startTime = time.Time{}
+	app.deadline = now.Add(time.Minute)
+	assert.Equal(t, len(leaf.sortApplications(false)), 1, "app should be sorted")
+	assert.Assert(t, !app.IsDeadlineAtRisk(), "sorting should not change the risk")
+	root.CheckApplicationDeadlines(now)
+	assert.Assert(t, app.IsDeadlineAtRisk(), "app should be at risk after the queue check")
+	assertDeadlineAtRiskMetric(t, atRisk+1)
+	leaf.RemoveApplication(app)
+	assert.Assert(t, !app.IsDeadlineAtRisk(), "removed app should not be at risk")
+	assertDeadlineAtRiskMetric(t, atRisk)
+}
+
+func assertDeadlineAtRiskMetric(t *testing.T, expected int) {
+	t.Helper()
+	atRisk, err := metrics.GetSchedulerMetrics().GetApplicationsDeadlineAtRisk()
+	assert.NilError(t, err, "failed to get metric")
+	assert.Equal(t, atRisk, expected, "unexpected deadline at risk metric")
+}
@@ -0,0 +1,208 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// Backfill places asks with an expected duration on a node that is reserved for a different allocation. The ask is
+// only placed when it does not delay the reserved allocation: either the reserved allocation still fits after the
+// backfill, or the ask is predicted to finish before the reserved allocation could fit on the node (shadow time).
+// Backfilled allocations that run longer than expected are preempted when they block the reserved allocation.
+
+// preBackfillCheck checks if the ask can be backfilled on this node. The node must have a single normal reservation
+// for a different allocation. No updates are made this only performs the checks.
+func (sn *Node) preBackfillCheck(ask *Allocation, now time.Time) bool {
+	duration := ask.GetExpectedDuration()
+	res := ask.GetAllocatedResource()
+	if duration <= 0 || !resources.StrictlyGreaterThanZero(res) {
+		return false
+	}
+	sn.RLock()
+	defer sn.RUnlock()
+	// required node reservations can preempt and do not wait: only backfill a single normal reservation
+	if len(sn.reservations) != 1 {
+		return false
+	}
+	var reserved *Allocation
+	for _, r := range sn.reservations {
+		reserved = r.alloc
+	}
+	if reserved.GetRequiredNode() != "" || reserved.GetAllocationKey() == ask.GetAllocationKey() {
+		return false
+	}
+	if !sn.availableResource.FitIn(res) {
+		return false
+	}
+	reservedRes := reserved.GetAllocatedResource()
+	// the reserved allocation is not delayed if it still fits after the backfill
+	if resources.Sub(sn.availableResource, res).FitIn(reservedRes) {
+		return true
+	}
+	shadow := sn.getShadowTime(reservedRes, now)
+	if shadow.IsZero() {
+		log.Log(log.SchedNode).Debug("backfill check: no prediction possible for reserved allocation",
+			zap.String("nodeID", sn.NodeID),
+			zap.String("allocationKey", ask.GetAllocationKey()))
+		return false
+	}
+	return !now.Add(duration).After(shadow)
+}
+
+// getShadowTime returns the time at which the requested resources are predicted to be available on the node based
+// on the expected end time of the running allocations. A zero time is returned if no prediction is possible.
+// Allocations without an expected end time, or that have already run past it, are assumed to not finish.
+// NOTE: must be called while holding the node lock
+func (sn *Node) getShadowTime(res *resources.Resource, now time.Time) time.Time {
+	free := sn.availableResource.Clone()
+	if free.FitIn(res) {
+		return now
+	}
+	type ending struct {
+		end      time.Time
+		resource *resources.Resource
+	}
+	endings := make([]ending, 0)
+	for _, alloc := range sn.allocations {
+		if alloc.IsForeign() || alloc.IsPreempted() {
+			continue
+		}
+		end := alloc.GetExpectedEndTime()
+		if end.IsZero() || end.Before(now) {
+			continue
+		}
+		endings = append(endings, ending{end: end, resource: alloc.GetAllocatedResource()})
+	}
+	sort.SliceStable(endings, func(i, j int) bool {
+		return endings[i].end.Before(endings[j].end)
+	})
+	for _, e := range endings {
+		free.AddTo(e.resource)
+		if free.FitIn(res) {
+			return e.end
+		}
+	}
+	return time.Time{}
+}
+
+// getOverrunBackfillAllocations returns the backfilled allocations on the node that have run past their expected
+// end time and are not yet preempted.
+func (sn *Node) getOverrunBackfillAllocations(now time.Time) []*Allocation {
+	sn.RLock()
+	defer sn.RUnlock()
+	var overrun []*Allocation
+	for _, alloc := range sn.allocations {
+		if !alloc.IsBackfill() || alloc.IsPreempted() || alloc.IsReleased() {
+			continue
+		}
+		if end := alloc.GetExpectedEndTime(); !end.IsZero() && end.Before(now) {
+			overrun = append(overrun, alloc)
+		}
+	}
+	return overrun
+}
+
+// tryBackfill tries to place the ask on a node reserved for a different allocation. Asks without an expected
+// duration, that require a specific node or that have a reservation themselves are never backfilled.
+// Only the reserved nodes are checked: the iterator must be limited to the reserved nodes of the partition.
+// NOTE: must be called while holding the application lock
+func (sa *Application) tryBackfill(ask *Allocation, reservedNodeIterator func() NodeIterator) *AllocationResult {
+	if ask.GetExpectedDuration() <= 0 || ask.GetRequiredNode() != "" || sa.reservations[ask.GetAllocationKey()] != nil {
+		return nil
+	}
+	iterator := reservedNodeIterator()
+	if iterator == nil {
+		return nil
+	}
+	now := time.Now()
+	var result *AllocationResult
+	iterator.ForEachNode(func(node *Node) bool {
+		if !node.IsSchedulable() {
+			return true
+		}
+		if !node.preBackfillCheck(ask, now) {
+			return true
+		}
+		if node.preAllocateConditions(ask) != nil {
+			return true
+		}
+		result = sa.allocateOnNode(node, ask)
+		return result == nil
+	})
+	if result != nil {
+		ask.SetBackfill(true)
+		log.Log(log.SchedApplication).Debug("allocation backfilled on reserved node",
+			zap.String("appID", sa.ApplicationID),
+			zap.String("allocationKey", ask.GetAllocationKey()),
+			zap.String("nodeID", result.NodeID),
+			zap.Duration("expectedDuration", ask.GetExpectedDuration()))
+	}
+	return result
+}
+
+// preemptOverrunBackfill preempts the allocations backfilled on the node reserved for the ask that have run past
+// their expected end time. The prediction used to backfill them was wrong and they now delay the reserved ask.
+// NOTE: must be called while holding the application lock
+func (sa *Application) preemptOverrunBackfill(node *Node, ask *Allocation) {
+	victims := node.getOverrunBackfillAllocations(time.Now())
+	if len(victims) == 0 {
+		return
+	}
+	preemptionMetrics := metrics.GetPreemptionMetrics()
+	preempted := make([]*Allocation, 0, len(victims))
+	for _, victim := range victims {
+		if err := victim.MarkPreempted(); err != nil {
+			continue
+		}
+		victimQueuePath := ""
+		if victimQueue := sa.queue.GetQueueByAppID(victim.GetApplicationID()); victimQueue != nil {
+			victimQueue.IncPreemptingResource(victim.GetAllocatedResource())
+			victimQueuePath = victimQueue.QueuePath
+		} else {
+			log.Log(log.SchedApplication).Warn("BUG: Queue not found for backfill preemption victim",
+				zap.String("queue", sa.queuePath),
+				zap.String("victimApplicationID", victim.GetApplicationID()),
+				zap.String("victimAllocationKey", victim.GetAllocationKey()))
+		}
+		victim.SendPreemptedBySchedulerEvent(ask.GetAllocationKey(), sa.ApplicationID, sa.queuePath)
+		preemptionMetrics.AddVictim(sa.queuePath, victimQueuePath, metrics.PreemptionTypeBackfill, victim.GetAllocatedResource())
+		preempted = append(preempted, victim)
+	}
+	if len(preempted) == 0 {
+		return
+	}
+	preemptionMetrics.IncAttempt(sa.queuePath, metrics.PreemptionTypeBackfill, metrics.PreemptionSuccess)
+	preemptionMetrics.ObserveVictimsPerAttempt(metrics.PreemptionTypeBackfill, len(preempted))
+	log.Log(log.SchedApplication).Info("preempting backfilled allocations that exceeded their expected duration",
+		zap.String("appID", sa.ApplicationID),
+		zap.String("allocationKey", ask.GetAllocationKey()),
+		zap.String("nodeID", node.NodeID),
+		zap.Int("victims", len(preempted)))
+	sa.notifyRMAllocationReleased(preempted, si.TerminationType_PREEMPTED_BY_SCHEDULER,
+		"preempting backfilled allocations that exceeded their expected duration to run reserved ask: "+ask.GetAllocationKey())
+}
@@ -0,0 +1,209 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/events/mock"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
+	schedEvt "github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
+	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func newBackfillAsk(allocKey, appID string, res *resources.Resource, duration string) *Allocation {
+	return NewAllocationFromSI(&si.Allocation{
+		AllocationKey:    allocKey,
+		ApplicationID:    appID,
+		PartitionName:    "default",
+		ResourcePerAlloc: res.ToProto(),
+		AllocationTags:   map[string]string{siCommon.DomainYuniKorn + common.KeyExpectedDuration: duration},
+	})
+}
+
+func TestExpectedEndTime(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	ask := newBackfillAsk(aKey, appID1, res, "10m")
+	assert.Equal(t, ask.GetExpectedDuration(), 10*time.Minute)
+	assert.Assert(t, ask.GetExpectedEndTime().IsZero(), "unbound ask should not have an expected end time")
+	bind := time.Now()
+	ask.SetBindTime(bind)
+	assert.Equal(t, ask.GetExpectedEndTime(), bind.Add(10*time.Minute))
+	alloc := newAllocationWithKey(aKey2, appID1, nodeID1, res)
+	assert.Assert(t, alloc.GetExpectedEndTime().IsZero(), "allocation without duration should not have an expected end time")
+	assert.Assert(t, !alloc.IsBackfill(), "allocation should not be marked as backfill")
+	alloc.SetBackfill(true)
+	assert.Assert(t, alloc.IsBackfill(), "allocation should be marked as backfill")
+}
+
+func TestPreBackfillCheck(t *testing.T) {
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 10})
+	node.nodeEvents = schedEvt.NewNodeEvents(mock.NewEventSystemDisabled())
+	app := newApplication(appID1, "default", "root.default")
+	now := time.Now()
+
+	// running allocation expected to finish in 10 minutes
+	running := newAllocationWithKey("running", appID1, nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 6}))
+	running.expectedDuration = 10 * time.Minute
+	running.SetBindTime(now)
+	node.AddAllocation(running)
+
+	small := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2})
+	short := newBackfillAsk("short", appID1, small, "5m")
+	assert.Assert(t, !node.preBackfillCheck(short, now), "node without reservation should not allow backfill")
+
+	// reserved ask needs the running allocation to finish
+	reserved := newAllocationAsk("reserved", appID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 8}))
+	assert.NilError(t, node.Reserve(app, reserved), "reservation failed")
+	assert.Assert(t, node.preBackfillCheck(short, now), "short ask should be backfilled before the shadow time")
+	assert.Assert(t, !node.preBackfillCheck(newBackfillAsk("long", appID1, small, "20m"), now), "long ask should not be backfilled")
+	assert.Assert(t, !node.preBackfillCheck(newAllocationAsk("none", appID1, small), now), "ask without duration should not be backfilled")
+	assert.Assert(t, !node.preBackfillCheck(newBackfillAsk("large", appID1, reserved.GetAllocatedResource(), "1m"), now), "ask that does not fit should not be backfilled")
+	assert.Assert(t, !node.preBackfillCheck(reserved, now), "reserved ask should not be backfilled on its own node")
+
+	// no prediction possible if the running allocation has no expected duration or ran over
+	running.expectedDuration = 0
+	assert.Assert(t, !node.preBackfillCheck(short, now), "backfill without prediction should not be allowed")
+	running.expectedDuration = time.Minute
+	assert.Assert(t, !node.preBackfillCheck(short, now.Add(5*time.Minute)), "backfill after overrun should not be allowed")
+
+	// a backfill that leaves enough room for the reservation is always allowed
+	node.unReserve(reserved)
+	smallReserved := newAllocationAsk("small-reserved", appID1, small)
+	assert.NilError(t, node.Reserve(app, smallReserved), "reservation failed")
+	assert.Assert(t, node.preBackfillCheck(newBackfillAsk("long", appID1, small, "20m"), now), "backfill should be allowed when reservation still fits")
+
+	// required node reservations are not backfilled
+	node.unReserve(smallReserved)
+	required := newAllocationAsk("required", appID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 8}))
+	required.SetRequiredNode(nodeID1)
+	assert.NilError(t, node.Reserve(app, required), "reservation failed")
+	assert.Assert(t, !node.preBackfillCheck(short, now), "required node reservation should not allow backfill")
+}
+
+func TestTryAllocateBackfill(t *testing.T) {
+	setupUGM()
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 10})
+	node.nodeEvents = schedEvt.NewNodeEvents(mock.NewEventSystemDisabled())
+	rootQ, err := createRootQueue(map[string]string{"first": "20"})
+	assert.NilError(t, err)
+	childQ, err := createManagedQueue(rootQ, "default", false, map[string]string{"first": "20"})
+	assert.NilError(t, err)
+
+	running := newAllocationWithKey("running", appID2, nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 6}))
+	running.expectedDuration = 10 * time.Minute
+	running.SetBindTime(time.Now())
+	node.AddAllocation(running)
+	reserveApp := newApplication(appID2, "default", "root.default")
+	reserveApp.SetQueue(childQ)
+	reserved := newAllocationAsk("reserved", appID2, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 8}))
+	assert.NilError(t, node.Reserve(reserveApp, reserved), "reservation failed")
+
+	app := newApplication(appID1, "default", "root.default")
+	app.SetQueue(childQ)
+	small := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2})
+	noHint := newAllocationAsk("no-hint", appID1, small)
+	assert.NilError(t, app.AddAllocationAsk(noHint), "ask should have been added to app")
+
+	// reserved nodes are not part of the normal iterator
+	attempts := 0
+	headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 20})
+	result := app.tryAllocate(headRoom, false, time.Second, &attempts, getNodeIteratorFn(), getNodeIteratorFn(), getNodeIteratorFn(node), nilGetNode)
+	assert.Assert(t, result == nil, "ask without expected duration should not be backfilled")
+
+	app.RemoveAllocationAsk("no-hint")
+	short := newBackfillAsk("short", appID1, small, "5m")
+	assert.NilError(t, app.AddAllocationAsk(short), "ask should have been added to app")
+	result = app.tryAllocate(headRoom, false, time.Second, &attempts, getNodeIteratorFn(), getNodeIteratorFn(), getNodeIteratorFn(node), nilGetNode)
+	assert.Assert(t, result != nil, "short ask should have been backfilled")
+	assert.Equal(t, result.ResultType, Allocated)
+	assert.Equal(t, result.NodeID, nodeID1)
+	assert.Assert(t, short.IsBackfill(), "allocation should be marked as backfill")
+	assert.Assert(t, node.GetAllocation("short") != nil, "allocation should be on the node")
+	assert.Assert(t, node.isReservedForAllocation("reserved"), "reservation should not have been removed")
+}
+
+func TestPreemptOverrunBackfill(t *testing.T) {
+	setupUGM()
+	app := newApplication(appID1, "default", "root.default")
+	var releaseEvents []*rmevent.RMReleaseAllocationEvent
+	app.rmEventHandler = &mockAppEventHandler{
+		callback: func(ev interface{}) {
+			if rmEvent, ok := ev.(*rmevent.RMReleaseAllocationEvent); ok {
+				releaseEvents = append(releaseEvents, rmEvent)
+				go func() {
+					rmEvent.Channel <- &rmevent.Result{
+						Succeeded: true,
+					}
+				}()
+			}
+		},
+	}
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 10})
+	node.nodeEvents = schedEvt.NewNodeEvents(mock.NewEventSystemDisabled())
+	iterator := getNodeIteratorFn(node)
+	appQueueMapping := NewAppQueueMapping()
+	rootQ, err := createRootQueue(map[string]string{"first": "20"})
+	assert.NilError(t, err)
+	childQ, err := createManagedQueueWithAppQueueMapping(rootQ, "default", false, map[string]string{"first": "20"}, appQueueMapping)
+	assert.NilError(t, err)
+	app.SetQueue(childQ)
+	appQueueMapping.AddAppQueueMapping(app.ApplicationID, childQ)
+
+	// backfilled allocation that is still within its expected duration
+	backfill := newBackfillAsk("backfill", appID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 4}), "5m")
+	backfill.askEvents = schedEvt.NewAskEvents(mock.NewEventSystemDisabled())
+	assert.NilError(t, app.AddAllocationAsk(backfill), "could not add backfill ask")
+	assert.Assert(t, app.allocateOnNode(node, backfill) != nil, "backfill ask should have been allocated")
+	backfill.SetBackfill(true)
+	backfill.SetBindTime(time.Now())
+
+	reserved := newAllocationAsk("reserved", appID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 8}))
+	assert.NilError(t, app.AddAllocationAsk(reserved), "could not add reserved ask")
+	assert.NilError(t, app.Reserve(node, reserved), "reservation failed")
+
+	metrics.GetPreemptionMetrics().Reset()
+	defer metrics.GetPreemptionMetrics().Reset()
+	headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 20})
+	assert.Assert(t, app.tryReservedAllocate(headRoom, iterator) == nil, "unexpected result from reserved allocation")
+	assert.Assert(t, !backfill.IsPreempted(), "backfill within expected duration should not be preempted")
+	assert.Equal(t, 0, len(releaseEvents), "unexpected release events")
+
+	// run past the expected end time
+	backfill.SetBindTime(time.Now().Add(-10 * time.Minute))
+	assert.Assert(t, app.tryReservedAllocate(headRoom, iterator) == nil, "unexpected result from reserved allocation")
+	assert.Assert(t, backfill.IsPreempted(), "overrun backfill should have been preempted")
+	assert.Equal(t, 1, len(releaseEvents), "unexpected number of release events")
+	assert.Equal(t, 1, len(releaseEvents[0].ReleasedAllocations), "unexpected number of release allocations")
+	assert.Equal(t, "backfill", releaseEvents[0].ReleasedAllocations[0].AllocationKey, "allocation key")
+	assert.Assert(t, resources.Equals(childQ.GetPreemptingResource(), backfill.GetAllocatedResource()), "preempting resource not tracked")
+	assertPreemptionAttempts(t, childQ.QueuePath, metrics.PreemptionTypeBackfill, metrics.PreemptionSuccess, 1)
+
+	// already preempted: no new release
+	releaseEvents = nil
+	assert.Assert(t, app.tryReservedAllocate(headRoom, iterator) == nil, "unexpected result from reserved allocation")
+	assert.Equal(t, 0, len(releaseEvents), "preempted backfill should not be released again")
+}
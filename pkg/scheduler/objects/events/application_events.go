@@ -20,6 +20,7 @@ package events
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
@@ -27,6 +28,10 @@ import (
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
+// DeadlineReferenceID is the reference of the deadline risk events. The scheduler interface has no change detail
+// for the deadline risk: the reference identifies the events.
+const DeadlineReferenceID = "application.deadline"
+
 type ApplicationEvents struct {
 	eventSystem events.EventSystem
 }
@@ -95,6 +100,17 @@ func (ae *ApplicationEvents) SendNewApplicationEvent(appID string) {
 	ae.eventSystem.AddEvent(event)
 }
 
+// SendApplicationSummaryEvent sends the JSON summary of a terminated application.
+// The message uses the same YK_APP_SUMMARY prefix as the summary log line.
+func (ae *ApplicationEvents) SendApplicationSummaryEvent(appID string, summary []byte) {
+	if !ae.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("YK_APP_SUMMARY: %s", summary)
+	event := events.CreateAppEventRecord(appID, message, common.Empty, si.EventRecord_NONE, si.EventRecord_DETAILS_NONE, nil)
+	ae.eventSystem.AddEvent(event)
+}
+
 func (ae *ApplicationEvents) SendRemoveApplicationEvent(appID string) {
 	if !ae.eventSystem.IsEventTrackingEnabled() {
 		return
@@ -143,6 +159,20 @@ func (ae *ApplicationEvents) SendAppRunnableQuotaEvent(appID string) {
 	ae.eventSystem.AddEvent(event)
 }
 
+func (ae *ApplicationEvents) SendDeadlineAtRiskEvent(appID string, atRisk bool, deadline time.Time) {
+	if !ae.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := "application deadline no longer at risk: " + deadline.Format(time.RFC3339)
+	changeType := si.EventRecord_REMOVE
+	if atRisk {
+		message = "application deadline at risk: " + deadline.Format(time.RFC3339)
+		changeType = si.EventRecord_ADD
+	}
+	event := events.CreateAppEventRecord(appID, message, DeadlineReferenceID, changeType, si.EventRecord_DETAILS_NONE, nil)
+	ae.eventSystem.AddEvent(event)
+}
+
 func NewApplicationEvents(es events.EventSystem) *ApplicationEvents {
 	return &ApplicationEvents{
 		eventSystem: es,
@@ -20,6 +20,7 @@ package events
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -214,6 +215,24 @@ func TestSendNewApplicationEvent(t *testing.T) {
 	assert.Equal(t, "", event.Message)
 }
 
+func TestSendApplicationSummaryEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	appEvents := NewApplicationEvents(eventSystem)
+	appEvents.SendApplicationSummaryEvent(appID, []byte(`{"applicationID":"app-0"}`))
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	appEvents = NewApplicationEvents(eventSystem)
+	appEvents.SendApplicationSummaryEvent(appID, []byte(`{"applicationID":"app-0"}`))
+	assert.Equal(t, 1, len(eventSystem.Events), "event was not generated")
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_APP, event.Type)
+	assert.Equal(t, si.EventRecord_NONE, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "app-0", event.ObjectID)
+	assert.Equal(t, `YK_APP_SUMMARY: {"applicationID":"app-0"}`, event.Message)
+}
+
 func TestSendRemoveApplicationEvent(t *testing.T) {
 	eventSystem := mock.NewEventSystemDisabled()
 	appEvents := NewApplicationEvents(eventSystem)
@@ -320,6 +339,31 @@ func TestSendAppRunnableInQueueEvent(t *testing.T) {
 	assert.Equal(t, "", event.Message)
 }
 
+func TestSendDeadlineAtRiskEvent(t *testing.T) {
+	deadline := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	eventSystem := mock.NewEventSystemDisabled()
+	appEvents := NewApplicationEvents(eventSystem)
+	appEvents.SendDeadlineAtRiskEvent(appID, true, deadline)
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	appEvents = NewApplicationEvents(eventSystem)
+	appEvents.SendDeadlineAtRiskEvent(appID, true, deadline)
+	appEvents.SendDeadlineAtRiskEvent(appID, false, deadline)
+	assert.Equal(t, 2, len(eventSystem.Events), "events were not generated")
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_APP, event.Type)
+	assert.Equal(t, si.EventRecord_ADD, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "app-0", event.ObjectID)
+	assert.Equal(t, DeadlineReferenceID, event.ReferenceID)
+	assert.Equal(t, "application deadline at risk: 2030-01-01T12:00:00Z", event.Message)
+	event = eventSystem.Events[1]
+	assert.Equal(t, si.EventRecord_REMOVE, event.EventChangeType)
+	assert.Equal(t, DeadlineReferenceID, event.ReferenceID)
+	assert.Equal(t, "application deadline no longer at risk: 2030-01-01T12:00:00Z", event.Message)
+}
+
 func TestSendAppRunnableByQuotaEvent(t *testing.T) {
 	eventSystem := mock.NewEventSystemDisabled()
 	appEvents := NewApplicationEvents(eventSystem)
@@ -73,6 +73,20 @@ func (q *QueueEvents) SendRemoveApplicationEvent(queuePath, appID string) {
 	q.eventSystem.AddEvent(event)
 }
 
+// SendMoveApplicationEvent records the move of an application as a remove from the source queue and
+// an add to the target queue. The message of both events references the other queue.
+func (q *QueueEvents) SendMoveApplicationEvent(fromQueue, toQueue, appID string) {
+	if !q.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	event := events.CreateQueueEventRecord(fromQueue, "application moved to "+toQueue, appID, si.EventRecord_REMOVE,
+		si.EventRecord_QUEUE_APP, nil)
+	q.eventSystem.AddEvent(event)
+	event = events.CreateQueueEventRecord(toQueue, "application moved from "+fromQueue, appID, si.EventRecord_ADD,
+		si.EventRecord_QUEUE_APP, nil)
+	q.eventSystem.AddEvent(event)
+}
+
 func (q *QueueEvents) SendMaxResourceChangedEvent(queuePath string, maxResource *resources.Resource) {
 	if !q.eventSystem.IsEventTrackingEnabled() {
 		return
@@ -113,6 +127,15 @@ func (q *QueueEvents) SendQuotaPreemptionEvent(queuePath string, results string,
 	q.eventSystem.AddEvent(event)
 }
 
+func (q *QueueEvents) SendStateChangedEvent(queuePath, from, to string) {
+	if !q.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	event := events.CreateQueueEventRecord(queuePath, "queue state: "+from+" -> "+to, common.Empty, si.EventRecord_SET,
+		si.EventRecord_QUEUE_CONFIG, nil)
+	q.eventSystem.AddEvent(event)
+}
+
 func NewQueueEvents(evt events.EventSystem) *QueueEvents {
 	return &QueueEvents{
 		eventSystem: evt,
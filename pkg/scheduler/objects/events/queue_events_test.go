@@ -123,6 +123,32 @@ func TestRemoveApplicationEvent(t *testing.T) {
 	assert.Equal(t, 0, len(event.Resource.Resources))
 }
 
+func TestMoveApplicationEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	nq := NewQueueEvents(eventSystem)
+	nq.SendMoveApplicationEvent(testQueuePath, "root.target", appID)
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	nq = NewQueueEvents(eventSystem)
+	nq.SendMoveApplicationEvent(testQueuePath, "root.target", appID)
+	assert.Equal(t, 2, len(eventSystem.Events), "events were not generated")
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_QUEUE, event.Type)
+	assert.Equal(t, testQueuePath, event.ObjectID)
+	assert.Equal(t, appID, event.ReferenceID)
+	assert.Equal(t, "application moved to root.target", event.Message)
+	assert.Equal(t, si.EventRecord_REMOVE, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_QUEUE_APP, event.EventChangeDetail)
+	event = eventSystem.Events[1]
+	assert.Equal(t, si.EventRecord_QUEUE, event.Type)
+	assert.Equal(t, "root.target", event.ObjectID)
+	assert.Equal(t, appID, event.ReferenceID)
+	assert.Equal(t, "application moved from "+testQueuePath, event.Message)
+	assert.Equal(t, si.EventRecord_ADD, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_QUEUE_APP, event.EventChangeDetail)
+}
+
 func TestTypeChangedEvent(t *testing.T) {
 	eventSystem := mock.NewEventSystemDisabled()
 	nq := NewQueueEvents(eventSystem)
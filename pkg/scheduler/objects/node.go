@@ -569,6 +569,7 @@ func (sn *Node) isReservedForAllocation(key string) bool {
 // The reservation is checked against the node resources.
 // If the reservation fails the function returns an error, if the reservation is made it returns nil.
 func (sn *Node) Reserve(app *Application, ask *Allocation) error {
+	defer sn.notifyListeners()
 	sn.Lock()
 	defer sn.Unlock()
 	appReservation := newReservation(sn, app, ask, false)
@@ -625,6 +626,7 @@ func (sn *Node) unReserve(alloc *Allocation) int {
 	if alloc == nil {
 		return 0
 	}
+	defer sn.notifyListeners()
 	sn.Lock()
 	defer sn.Unlock()
 	if _, ok := sn.reservations[alloc.allocationKey]; ok {
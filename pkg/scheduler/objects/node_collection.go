@@ -37,6 +37,10 @@ var acceptAll = func(node *Node) bool {
 	return true
 }
 
+var acceptReserved = func(node *Node) bool {
+	return node.IsReserved()
+}
+
 // NodeCollection represents a collection of nodes for a partition.
 // Implementations of this interface must be internally synchronized to avoid data races.
 type NodeCollection interface {
@@ -47,6 +51,7 @@ type NodeCollection interface {
 	GetNodes() []*Node
 	GetNodeIterator() NodeIterator
 	GetFullNodeIterator() NodeIterator
+	GetReservedNodeIterator() NodeIterator
 	SetNodeSortingPolicy(policy NodeSortingPolicy)
 	GetNodeSortingPolicy() NodeSortingPolicy
 }
@@ -54,6 +59,7 @@ type NodeCollection interface {
 type nodeRef struct {
 	node      *Node   // node reference
 	nodeScore float64 // node score
+	reserved  bool    // node is tracked as reserved
 }
 
 func nodeRefLess(a, b nodeRef) bool {
@@ -73,9 +79,11 @@ type baseNodeCollection struct {
 	nsp         NodeSortingPolicy      // node sorting policy
 	nodes       map[string]*nodeRef    // nodes assigned to this collection
 	sortedNodes *btree.BTreeG[nodeRef] // nodes sorted by score
+	reserved    *btree.BTreeG[nodeRef] // reserved nodes sorted by score
 
 	unreservedIterator *treeIterator
 	fullIterator       *treeIterator
+	reservedIterator   *treeIterator
 
 	locking.RWMutex
 }
@@ -103,9 +111,13 @@ func (nc *baseNodeCollection) AddNode(node *Node) error {
 	nref := nodeRef{
 		node:      node,
 		nodeScore: nc.scoreNode(node),
+		reserved:  node.IsReserved(),
 	}
 	nc.nodes[node.NodeID] = &nref
 	nc.sortedNodes.Set(nref)
+	if nref.reserved {
+		nc.reserved.Set(nref)
+	}
 	return nil
 }
 
@@ -123,6 +135,7 @@ func (nc *baseNodeCollection) RemoveNode(nodeID string) *Node {
 
 	// Remove node from list of tracked nodes
 	nc.sortedNodes.Delete(*nref)
+	nc.reserved.Delete(*nref)
 	delete(nc.nodes, nodeID)
 	nref.node.RemoveListener(nc)
 
@@ -170,6 +183,12 @@ func (nc *baseNodeCollection) GetFullNodeIterator() NodeIterator {
 	return nc.fullIterator
 }
 
+// Create an ordered node iterator for reserved nodes based on the sort policy set for this collection.
+// Only the reserved nodes are walked, the cost does not depend on the size of the collection.
+func (nc *baseNodeCollection) GetReservedNodeIterator() NodeIterator {
+	return nc.reservedIterator
+}
+
 func (nc *baseNodeCollection) cloneSortedNodes() *btree.BTreeG[nodeRef] {
 	nc.Lock()
 	defer nc.Unlock()
@@ -177,6 +196,13 @@ func (nc *baseNodeCollection) cloneSortedNodes() *btree.BTreeG[nodeRef] {
 	return nc.sortedNodes.Copy()
 }
 
+func (nc *baseNodeCollection) cloneReservedNodes() *btree.BTreeG[nodeRef] {
+	nc.Lock()
+	defer nc.Unlock()
+
+	return nc.reserved.Copy()
+}
+
 // Sets the node sorting policy.
 func (nc *baseNodeCollection) SetNodeSortingPolicy(policy NodeSortingPolicy) {
 	nc.Lock()
@@ -185,10 +211,14 @@ func (nc *baseNodeCollection) SetNodeSortingPolicy(policy NodeSortingPolicy) {
 
 	// sortedNodes must be rebuilt since sort ordering is different
 	nc.sortedNodes.Clear()
+	nc.reserved.Clear()
 	for _, nref := range nc.nodes {
 		node := nref.node
 		nref.nodeScore = nc.scoreNode(node)
 		nc.sortedNodes.Set(*nref)
+		if nref.reserved {
+			nc.reserved.Set(*nref)
+		}
 	}
 }
 
@@ -210,10 +240,17 @@ func (nc *baseNodeCollection) NodeUpdated(node *Node) {
 	}
 
 	updatedScore := nc.scoreNode(node)
-	if nref.nodeScore != updatedScore {
-		nc.sortedNodes.Delete(*nref)
-		nref.nodeScore = nc.scoreNode(node)
-		nc.sortedNodes.Set(*nref)
+	reserved := node.IsReserved()
+	if nref.nodeScore == updatedScore && nref.reserved == reserved {
+		return
+	}
+	nc.sortedNodes.Delete(*nref)
+	nc.reserved.Delete(*nref)
+	nref.nodeScore = updatedScore
+	nref.reserved = reserved
+	nc.sortedNodes.Set(*nref)
+	if reserved {
+		nc.reserved.Set(*nref)
 	}
 }
 
@@ -224,13 +261,16 @@ func NewNodeCollection(partition string) NodeCollection {
 		nsp:         NewNodeSortingPolicy(policies.FairSortPolicy.String(), nil),
 		nodes:       make(map[string]*nodeRef),
 		sortedNodes: btree.NewBTreeGOptions(nodeRefLess, btree.Options{Degree: 7, NoLocks: true}), // Degree=7 here is experimentally the most efficient for up to around 5k nodes
+		reserved:    btree.NewBTreeGOptions(nodeRefLess, btree.Options{Degree: 7, NoLocks: true}),
 	}
 
 	unreservedIterator := NewTreeIterator(acceptUnreserved, bsc.cloneSortedNodes)
 	fullIterator := NewTreeIterator(acceptAll, bsc.cloneSortedNodes)
+	reservedIterator := NewTreeIterator(acceptReserved, bsc.cloneReservedNodes)
 
 	bsc.fullIterator = fullIterator
 	bsc.unreservedIterator = unreservedIterator
+	bsc.reservedIterator = reservedIterator
 
 	return bsc
 }
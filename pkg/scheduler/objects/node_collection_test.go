@@ -303,6 +303,47 @@ func TestGetFullNodeIterator(t *testing.T) {
 	assert.Equal(t, nodes[3].NodeID, "node-3", "wrong node 3")
 }
 
+func TestGetReservedNodeIterator(t *testing.T) {
+	nc := NewNodeCollection("test")
+	for i := 1; i <= 4; i++ {
+		node := newNode(fmt.Sprintf("node-%d", i), map[string]resources.Quantity{"vcore": resources.Quantity(10)})
+		if i%2 == 0 {
+			appName := fmt.Sprintf("app-%02d", i)
+			app := newApplication(appName, "default", "root.test")
+			ask := newAllocationAsk(fmt.Sprintf("alloc-%02d", i), appName, resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": resources.Quantity(i)}))
+			assert.NilError(t, node.Reserve(app, ask), "Reserving failed.")
+		}
+		assert.NilError(t, nc.AddNode(node), "Adding another node into BC failed.")
+	}
+	var nodes []*Node
+	nc.GetReservedNodeIterator().ForEachNode(func(node *Node) bool {
+		nodes = append(nodes, node)
+		return true
+	})
+	assert.Equal(t, len(nodes), 2, "wrong length")
+	assert.Equal(t, nodes[0].NodeID, "node-2", "wrong node 0")
+	assert.Equal(t, nodes[1].NodeID, "node-4", "wrong node 1")
+
+	// policy change rebuilds the reserved nodes
+	nc.SetNodeSortingPolicy(NewNodeSortingPolicy(policies.BinPackingPolicy.String(), nil))
+	nodes = nil
+	nc.GetReservedNodeIterator().ForEachNode(func(node *Node) bool {
+		nodes = append(nodes, node)
+		return true
+	})
+	assert.Equal(t, len(nodes), 2, "wrong length after policy change")
+
+	// removed node is no longer returned
+	assert.Assert(t, nc.RemoveNode("node-2") != nil, "node should have been removed")
+	nodes = nil
+	nc.GetReservedNodeIterator().ForEachNode(func(node *Node) bool {
+		nodes = append(nodes, node)
+		return true
+	})
+	assert.Equal(t, len(nodes), 1, "wrong length after remove")
+	assert.Equal(t, nodes[0].NodeID, "node-4", "wrong node after remove")
+}
+
 func TestGetNodeIterator(t *testing.T) {
 	var tests = []struct {
 		name         string
@@ -377,8 +418,8 @@ func TestGetNodeIterator(t *testing.T) {
 	}
 }
 
-// TestNodeIteratorReserveUpdate reservation add or remove should not need a node collection update make sure it works.
-// YUNIKORN-2976 removed the listener notify in Reserve and unReserve
+// TestNodeIteratorReserveUpdate reservation add or remove must be reflected in the iterators.
+// The node score does not change on a reservation, the listener notify only updates the reserved nodes.
 func TestNodeIteratorReserveUpdate(t *testing.T) {
 	nc := NewNodeCollection("test")
 	count := 3
@@ -401,6 +442,14 @@ func TestNodeIteratorReserveUpdate(t *testing.T) {
 	})
 	assert.Equal(t, len(itNodes), count, "wrong length")
 
+	// reserved iterator returns NO nodes
+	itNodes = nil
+	nc.GetReservedNodeIterator().ForEachNode(func(node *Node) bool {
+		itNodes = append(itNodes, node)
+		return true
+	})
+	assert.Equal(t, len(itNodes), 0, "wrong length")
+
 	// add reservation to all nodes
 	app := newApplication(appID0, "default", "root.test")
 	for i, node := range allNodes {
@@ -425,6 +474,14 @@ func TestNodeIteratorReserveUpdate(t *testing.T) {
 	})
 	assert.Equal(t, len(itNodes), 0, "wrong length")
 
+	// reserved iterator returns all nodes
+	itNodes = nil
+	nc.GetReservedNodeIterator().ForEachNode(func(node *Node) bool {
+		itNodes = append(itNodes, node)
+		return true
+	})
+	assert.Equal(t, len(itNodes), count, "wrong length")
+
 	// run over initial list of nodes and remove reservations.
 	// only one reservation so just pick that one
 	for _, node := range allNodes {
@@ -439,4 +496,12 @@ func TestNodeIteratorReserveUpdate(t *testing.T) {
 		return true
 	})
 	assert.Equal(t, len(itNodes), count, "wrong length")
+
+	// reserved iterator returns NO nodes again
+	itNodes = nil
+	nc.GetReservedNodeIterator().ForEachNode(func(node *Node) bool {
+		itNodes = append(itNodes, node)
+		return true
+	})
+	assert.Equal(t, len(itNodes), 0, "wrong length")
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/plugins"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
@@ -417,6 +418,12 @@ func (p *Preemptor) checkPreemptionPredicates(predicateChecks []*si.PreemptionPr
 	// process each batch of checks by sending to the RM
 	batches := batchPreemptionChecks(predicateChecks, preemptCheckConcurrency)
 	var bestResult *predicateCheckResult = nil
+	failures := 0
+	defer func() {
+		if failures > 0 {
+			metrics.GetPreemptionMetrics().AddPredicateFailures(p.queuePath, failures)
+		}
+	}()
 	for _, batch := range batches {
 		var wg sync.WaitGroup
 		ch := make(chan *predicateCheckResult, len(batch))
@@ -440,6 +447,8 @@ func (p *Preemptor) checkPreemptionPredicates(predicateChecks []*si.PreemptionPr
 				} else if result.betterThan(bestResult, p.allocationsByNode) {
 					bestResult = result
 				}
+			} else {
+				failures++
 			}
 		}
 		// if the best resultType we have from this batch meets all our criteria, don't run another batch
@@ -586,6 +595,7 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 	// validate that sufficient capacity can be freed
 	if !p.checkPreemptionQueueGuarantees() {
 		p.ask.LogAllocationFailure(common.PreemptionDoesNotGuarantee, true)
+		p.incAttempt(metrics.PreemptionNoGuarantee)
 		return nil, false
 	}
 
@@ -600,6 +610,7 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 	nodeID, victims, ok := p.tryNodes()
 	if !ok {
 		// no preemption possible
+		p.incAttempt(metrics.PreemptionNoVictims)
 		return nil, false
 	}
 
@@ -607,10 +618,12 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 	extraVictims, ok := p.calculateAdditionalVictims(victims)
 	if !ok {
 		// not enough resources were preempted
+		p.incAttempt(metrics.PreemptionNotEnough)
 		return nil, false
 	}
 	victims = append(victims, extraVictims...)
 	if len(victims) == 0 {
+		p.incAttempt(metrics.PreemptionNoVictims)
 		return nil, false
 	}
 
@@ -648,6 +661,7 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 	if p.ask.GetAllocatedResource().StrictlyGreaterThanOnlyExisting(victimsTotalResource) {
 		// there is shortfall, so preemption doesn't help
 		p.ask.LogAllocationFailure(common.PreemptionShortfall, true)
+		p.incAttempt(metrics.PreemptionShortfall)
 		return nil, false
 	}
 
@@ -670,6 +684,7 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 			}
 			// victim already released, so preemption doesn't help
 			p.ask.LogAllocationFailure(common.PreemptionVictimsReleased, true)
+			p.incAttempt(metrics.PreemptionVictimsReleased)
 			return nil, false
 		}
 		preemptedVictims = append(preemptedVictims, victim)
@@ -677,7 +692,9 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 
 	// preempt the victims
 	for _, victim := range finalVictims {
+		victimQueuePath := ""
 		if victimQueue := p.queue.GetQueueByAppID(victim.GetApplicationID()); victimQueue != nil {
+			victimQueuePath = victimQueue.QueuePath
 			victimQueue.IncPreemptingResource(victim.GetAllocatedResource())
 			log.Log(log.SchedPreemption).Info("Preempting task",
 				zap.String("askApplicationID", p.ask.applicationID),
@@ -696,10 +713,13 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 				zap.String("victimApplicationID", victim.GetApplicationID()),
 				zap.String("victimAllocationKey", victim.GetAllocationKey()))
 		}
+		metrics.GetPreemptionMetrics().AddVictim(p.queuePath, victimQueuePath, metrics.PreemptionTypePreemptor, victim.GetAllocatedResource())
 	}
 
 	// mark ask as having triggered preemption so that we don't preempt again
 	p.ask.MarkTriggeredPreemption()
+	p.incAttempt(metrics.PreemptionSuccess)
+	metrics.GetPreemptionMetrics().ObserveVictimsPerAttempt(metrics.PreemptionTypePreemptor, len(finalVictims))
 
 	// notify RM that victims should be released
 	p.application.notifyRMAllocationReleased(finalVictims, si.TerminationType_PREEMPTED_BY_SCHEDULER,
@@ -714,6 +734,11 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 	return newReservedAllocationResult(nodeID, p.ask), true
 }
 
+// incAttempt records the result of the preemption attempt for the queue of the ask
+func (p *Preemptor) incAttempt(result string) {
+	metrics.GetPreemptionMetrics().IncAttempt(p.queuePath, metrics.PreemptionTypePreemptor, result)
+}
+
 // Duplicate creates a copy of this snapshot into the given map by queue path
 func (qps *QueuePreemptionSnapshot) Duplicate(copy map[string]*QueuePreemptionSnapshot) *QueuePreemptionSnapshot {
 	if qps == nil {
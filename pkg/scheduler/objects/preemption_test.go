@@ -29,6 +29,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	evtMock "github.com/apache/yunikorn-core/pkg/events/mock"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/mock"
 	"github.com/apache/yunikorn-core/pkg/plugins"
 	schedEvt "github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
@@ -177,7 +178,7 @@ func TestCheckPreconditions(t *testing.T) {
 		return node
 	}
 	preemptionAttemptsRemaining := 1
-	result := app.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2}), true, 1*time.Second, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	result := app.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2}), true, 1*time.Second, &preemptionAttemptsRemaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Check(t, result == nil, "unexpected result")
 	assertAllocationLog(t, ask, []string{common.PreemptionPreconditionsFailed, common.PreemptionDoesNotHelp})
 	ask.preemptCheckTime = time.Now().Add(-1 * time.Minute)
@@ -351,6 +352,8 @@ func TestTryPreemption(t *testing.T) {
 	plugin := mock.NewPreemptionPredicatePlugin(nil, nil, preemptions)
 	plugins.RegisterSchedulerPlugin(plugin)
 	defer plugins.UnregisterSchedulerPlugins()
+	metrics.GetPreemptionMetrics().Reset()
+	defer metrics.GetPreemptionMetrics().Reset()
 
 	result, ok := preemptor.TryPreemption()
 	assert.Assert(t, result != nil, "no result")
@@ -360,6 +363,20 @@ func TestTryPreemption(t *testing.T) {
 	assert.Check(t, alloc1.IsPreempted(), "alloc1 not preempted")
 	assert.Check(t, !alloc2.IsPreempted(), "alloc2 preempted")
 	assert.Equal(t, len(ask3.GetAllocationLog()), 0)
+	assertPreemptionAttempts(t, childQ2.QueuePath, metrics.PreemptionTypePreemptor, metrics.PreemptionSuccess, 1)
+	victims, err := metrics.GetPreemptionMetrics().GetVictims(childQ2.QueuePath, childQ1.QueuePath, metrics.PreemptionTypePreemptor)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, victims, "unexpected victim count")
+	reclaimed, err := metrics.GetPreemptionMetrics().GetReclaimedResource(childQ2.QueuePath, childQ1.QueuePath, metrics.PreemptionTypePreemptor, "first")
+	assert.NilError(t, err)
+	assert.Equal(t, int64(5), reclaimed, "unexpected reclaimed resource")
+}
+
+func assertPreemptionAttempts(t *testing.T, queue, preemptionType, result string, expected int) {
+	t.Helper()
+	attempts, err := metrics.GetPreemptionMetrics().GetAttempts(queue, preemptionType, result)
+	assert.NilError(t, err)
+	assert.Equal(t, expected, attempts, "unexpected %s preemption attempts with result %s", preemptionType, result)
 }
 
 func TestTryPreemption_SendEvent(t *testing.T) {
@@ -778,6 +795,8 @@ func TestTryPreemption_VictimReleased_InsufficientResource(t *testing.T) {
 	plugin := mock.NewPreemptionPredicatePlugin(nil, allocs, nil)
 	plugins.RegisterSchedulerPlugin(plugin)
 	defer plugins.UnregisterSchedulerPlugins()
+	metrics.GetPreemptionMetrics().Reset()
+	defer metrics.GetPreemptionMetrics().Reset()
 
 	result, ok := preemptor.TryPreemption()
 
@@ -787,6 +806,8 @@ func TestTryPreemption_VictimReleased_InsufficientResource(t *testing.T) {
 	assert.Check(t, !alloc2.IsPreempted(), "alloc2 preempted")
 	assert.Check(t, !alloc4.IsPreempted(), "alloc2 preempted")
 	assertAllocationLog(t, ask3, []string{common.PreemptionVictimsReleased})
+	assertPreemptionAttempts(t, childQ2.QueuePath, metrics.PreemptionTypePreemptor, metrics.PreemptionVictimsReleased, 1)
+	assertPreemptionAttempts(t, childQ2.QueuePath, metrics.PreemptionTypePreemptor, metrics.PreemptionSuccess, 0)
 }
 
 // TestTryPreemption_VictimsAvailableOnDifferentNodes Test try preemption on queue with simple queue hierarchy. Since Node doesn't have enough resources to accomodate, preemption happens because of node resource constraint.
@@ -2298,7 +2319,7 @@ func Test_PreemptForAppOnReservedNode(t *testing.T) {
 	// preemption turned on with a 1-second delay and at least 1 attempt remaining
 	// unreserve must not block on the node and reserve the node for this preemption
 	// NOTE: deadlock detection in locking fails this test if regressed
-	result := app.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10}), true, 1*time.Second, &remaining, iterator, iterator, getNode)
+	result := app.tryAllocate(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10}), true, 1*time.Second, &remaining, iterator, iterator, nilNodeIterator, getNode)
 	assert.Assert(t, result != nil, "expected and allocation result back")
 	assert.Equal(t, result.ResultType, Reserved, "expected result type to be Reserved")
 
@@ -22,6 +22,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -58,6 +59,8 @@ type Queue struct {
 	children             map[string]*Queue         // Only for direct children, parent queue only
 	childPriorities      map[string]int32          // cached priorities for child queues
 	applications         map[string]*Application   // only for leaf queue
+	sortedApps           *sortedApplications       // incrementally maintained order of the applications, only for leaf queue
+	sortedChildren       *sortedQueues             // cached order of the child queues, only for parent queue
 	appPriorities        map[string]int32          // cached priorities for application
 	reservedApps         map[string]int            // applications reserved within this queue, with reservation count
 	parent               *Queue                    // link back to the parent in the scheduler
@@ -84,6 +87,7 @@ type Queue struct {
 	isManaged                bool                // queue is part of the config, not auto created
 	stateMachine             *fsm.FSM            // the state of the queue for scheduling
 	stateTime                time.Time           // last time the state was updated (needed for cleanup)
+	adminState               bool                // state was set by an administrative operation, not by a config change
 	maxRunningApps           uint64
 	runningApps              uint64
 	allocatingAcceptedApps   map[string]bool
@@ -104,6 +108,8 @@ func newBlankQueue() *Queue {
 		children:                 make(map[string]*Queue),
 		childPriorities:          make(map[string]int32),
 		applications:             make(map[string]*Application),
+		sortedApps:               newSortedApplications(),
+		sortedChildren:           newSortedQueues(),
 		appPriorities:            make(map[string]int32),
 		reservedApps:             make(map[string]int),
 		allocatingAcceptedApps:   make(map[string]bool),
@@ -378,8 +384,8 @@ func (sq *Queue) applyConf(conf configs.QueueConfig, silence bool) (*resources.R
 		sq.isManaged = true
 	}
 
-	// if the queue is marked for removal reverse that state
-	if !sq.IsRunning() {
+	// if the queue is marked for removal reverse that state, a state set by an admin is kept
+	if !sq.IsRunning() && !sq.adminState {
 		err = sq.handleQueueEvent(Start)
 		if err != nil {
 			log.Log(log.SchedQueue).Info("managed queue state change failed",
@@ -593,6 +599,7 @@ func (sq *Queue) setResourcesFromConf(resource configs.Resources) error {
 }
 
 func (sq *Queue) setResources(guaranteedResource, maxResource *resources.Resource) {
+	sq.invalidateQueueSortTree()
 	switch {
 	case resources.StrictlyGreaterThanZero(maxResource):
 		log.Log(log.SchedQueue).Debug("setting max resources",
@@ -695,6 +702,7 @@ func (sq *Queue) resetProperties() {
 func (sq *Queue) UpdateQueueProperties(oldMaxResource *resources.Resource) {
 	sq.Lock()
 	defer sq.Unlock()
+	sq.invalidateQueueSortTree()
 	if common.IsRecoveryQueue(sq.QueuePath) {
 		// recovery queue properties should never be updated
 		sq.sortType = policies.FifoSortPolicy
@@ -808,6 +816,86 @@ func (sq *Queue) CurrentState() string {
 	return sq.stateMachine.Current()
 }
 
+// IsMarkedForRemoval returns true if the queue is draining because it was removed from the configuration.
+// A queue drained by an administrative operation is not removed when it is empty.
+func (sq *Queue) IsMarkedForRemoval() bool {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.IsDraining() && !sq.adminState
+}
+
+// StopQueue stops the queue for maintenance: new applications and asks are refused and
+// the queue and its children are skipped for scheduling.
+func (sq *Queue) StopQueue() error {
+	return sq.setAdminState(Stop)
+}
+
+// DrainQueue drains the queue: new applications and asks are refused, the pending asks of the
+// running applications are still scheduled.
+func (sq *Queue) DrainQueue() error {
+	return sq.setAdminState(Remove)
+}
+
+// ResumeQueue returns a stopped or drained queue to the active state.
+func (sq *Queue) ResumeQueue() error {
+	return sq.setAdminState(Start)
+}
+
+// setAdminState changes the state of the queue as requested by an administrator.
+// A queue that is marked for removal cannot be changed.
+func (sq *Queue) setAdminState(event ObjectEvent) error {
+	sq.Lock()
+	defer sq.Unlock()
+	if sq.IsDraining() && !sq.adminState {
+		return fmt.Errorf("queue %s is marked for removal, state cannot be changed", sq.QueuePath)
+	}
+	from := sq.stateMachine.Current()
+	target := map[ObjectEvent]ObjectState{Start: Active, Stop: Stopped, Remove: Draining}[event]
+	if from == target.String() {
+		return nil
+	}
+	// the state machine only allows stopping or draining an active queue
+	if event != Start && !sq.IsRunning() {
+		if err := sq.handleQueueEvent(Start); err != nil {
+			return err
+		}
+	}
+	if err := sq.handleQueueEvent(event); err != nil {
+		return err
+	}
+	sq.adminState = event != Start
+	log.Log(log.SchedQueue).Info("queue state changed by admin",
+		zap.String("queue", sq.QueuePath),
+		zap.String("from", from),
+		zap.String("to", target.String()))
+	sq.queueEvents.SendStateChangedEvent(sq.QueuePath, from, target.String())
+	return nil
+}
+
+// CheckAcceptingApplications returns an error if the queue or one of its parents does not accept new applications.
+func (sq *Queue) CheckAcceptingApplications() error {
+	for queue := sq; queue != nil; queue = queue.parent {
+		if !queue.IsRunning() {
+			return fmt.Errorf("queue %s is %s and does not accept new applications", queue.QueuePath, strings.ToLower(queue.CurrentState()))
+		}
+	}
+	return nil
+}
+
+// CheckAcceptingAsks returns an error if the queue or one of its parents was stopped or drained by an admin.
+// A queue that is marked for removal still accepts asks from its running applications.
+func (sq *Queue) CheckAcceptingAsks() error {
+	for queue := sq; queue != nil; queue = queue.parent {
+		queue.RLock()
+		closed := queue.adminState
+		queue.RUnlock()
+		if closed {
+			return fmt.Errorf("queue %s is %s and does not accept new asks", queue.QueuePath, strings.ToLower(queue.CurrentState()))
+		}
+	}
+	return nil
+}
+
 // handleQueueEvent processes the state event for the queue.
 // The state machine handles the locking.
 func (sq *Queue) handleQueueEvent(event ObjectEvent) error {
@@ -815,6 +903,7 @@ func (sq *Queue) handleQueueEvent(event ObjectEvent) error {
 	// err is nil the state transition was done
 	if err == nil {
 		sq.stateTime = time.Now()
+		sq.invalidateQueueSortTree()
 		return nil
 	}
 	// handle the same state transition not nil error (limit of fsm).
@@ -891,32 +980,68 @@ func (sq *Queue) GetPreemptionDelay() time.Duration {
 // CheckSubmitAccess checks if the user has access to the queue to submit an application.
 // The check is performed recursively: i.e. access to the parent allows access to this queue.
 // This will check both submitACL and adminACL.
+// A deny in the submitACL of this queue or any parent refuses access, even if an ACL allows it.
 func (sq *Queue) CheckSubmitAccess(user security.UserGroup) bool {
 	if common.IsRecoveryQueue(sq.QueuePath) {
 		// recovery queue can never pass ACL checks
 		return false
 	}
+	if sq.isSubmitDenied(user) {
+		return false
+	}
+	return sq.checkSubmitAllowed(user)
+}
+
+func (sq *Queue) checkSubmitAllowed(user security.UserGroup) bool {
 	sq.RLock()
 	allow := sq.submitACL.CheckAccess(user) || sq.adminACL.CheckAccess(user)
 	sq.RUnlock()
 	if !allow && sq.parent != nil {
-		allow = sq.parent.CheckSubmitAccess(user)
+		allow = sq.parent.checkSubmitAllowed(user)
 	}
 	return allow
 }
 
+func (sq *Queue) isSubmitDenied(user security.UserGroup) bool {
+	sq.RLock()
+	deny := sq.submitACL.IsDenied(user)
+	sq.RUnlock()
+	if !deny && sq.parent != nil {
+		deny = sq.parent.isSubmitDenied(user)
+	}
+	return deny
+}
+
 // CheckAdminAccess checks if the user has access to the queue to perform administrative actions.
 // The check is performed recursively: i.e. access to the parent allows access to this queue.
+// A deny in the adminACL of this queue or any parent refuses access, even if an ACL allows it.
 func (sq *Queue) CheckAdminAccess(user security.UserGroup) bool {
+	if sq.isAdminDenied(user) {
+		return false
+	}
+	return sq.checkAdminAllowed(user)
+}
+
+func (sq *Queue) checkAdminAllowed(user security.UserGroup) bool {
 	sq.RLock()
 	allow := sq.adminACL.CheckAccess(user)
 	sq.RUnlock()
 	if !allow && sq.parent != nil {
-		allow = sq.parent.CheckAdminAccess(user)
+		allow = sq.parent.checkAdminAllowed(user)
 	}
 	return allow
 }
 
+func (sq *Queue) isAdminDenied(user security.UserGroup) bool {
+	sq.RLock()
+	deny := sq.adminACL.IsDenied(user)
+	sq.RUnlock()
+	if !deny && sq.parent != nil {
+		deny = sq.parent.isAdminDenied(user)
+	}
+	return deny
+}
+
 // GetPartitionQueueDAOInfo returns the queue hierarchy as an object for a REST call.
 // Include is false, which means that returns the specified queue object, but does not return the children of the specified queue.
 func (sq *Queue) GetPartitionQueueDAOInfo(include bool) dao.PartitionQueueDAOInfo {
@@ -938,6 +1063,10 @@ func (sq *Queue) GetPartitionQueueDAOInfo(include bool) dao.PartitionQueueDAOInf
 	}
 	queueInfo.QueueName = sq.QueuePath
 	queueInfo.Status = sq.stateMachine.Current()
+	queueInfo.StateChangedByAdmin = sq.adminState
+	if !sq.stateTime.IsZero() {
+		queueInfo.StateTime = sq.stateTime.UnixNano()
+	}
 	queueInfo.PendingResource = sq.pending.DAOMap()
 	queueInfo.MaxResource = sq.maxResource.DAOMap()
 	queueInfo.GuaranteedResource = sq.guaranteedResource.DAOMap()
@@ -1000,6 +1129,7 @@ func (sq *Queue) incPendingResource(delta *resources.Resource) {
 	defer sq.Unlock()
 	sq.pending = resources.Add(sq.pending, delta)
 	sq.updatePendingResourceMetrics()
+	sq.parent.invalidateQueueSort()
 }
 
 // decPendingResource decrements pending resource of this queue and its parents.
@@ -1014,6 +1144,7 @@ func (sq *Queue) decPendingResource(delta *resources.Resource) {
 	// update this queue
 	sq.Lock()
 	defer sq.Unlock()
+	sq.parent.invalidateQueueSort()
 	var err error
 	sq.pending, err = resources.SubErrorNegative(sq.pending, delta)
 	if err != nil {
@@ -1038,6 +1169,7 @@ func (sq *Queue) AddApplication(app *Application) {
 	defer sq.Unlock()
 	appID := app.ApplicationID
 	sq.applications[appID] = app
+	sq.sortedApps.add(app)
 	sq.queueEvents.SendNewApplicationEvent(sq.QueuePath, appID)
 }
 
@@ -1088,9 +1220,11 @@ func (sq *Queue) RemoveApplication(app *Application) {
 	delete(sq.applications, appID)
 	delete(sq.appPriorities, appID)
 	delete(sq.allocatingAcceptedApps, appID)
+	sq.sortedApps.remove(appID)
 	priority := sq.recalculatePriority()
 	sq.Unlock()
 	app.appEvents.SendRemoveApplicationEvent(appID)
+	app.clearDeadlineAtRisk()
 
 	sq.parent.UpdateQueuePriority(sq.Name, priority)
 
@@ -1099,6 +1233,39 @@ func (sq *Queue) RemoveApplication(app *Application) {
 		zap.String("applicationID", appID))
 }
 
+// detachApplication removes an application that is moved to another queue from the list of tracked
+// applications. Contrary to RemoveApplication the resources of the application are not touched: the caller
+// transfers them to the new queue.
+// Returns the number of reservations tracked for the application in this queue.
+func (sq *Queue) detachApplication(appID string) int {
+	sq.Lock()
+	reserved := sq.reservedApps[appID]
+	delete(sq.applications, appID)
+	delete(sq.appPriorities, appID)
+	delete(sq.reservedApps, appID)
+	sq.sortedApps.remove(appID)
+	priority := sq.recalculatePriority()
+	sq.Unlock()
+
+	sq.parent.UpdateQueuePriority(sq.Name, priority)
+	return reserved
+}
+
+// attachApplication adds an application that is moved from another queue to the list of tracked
+// applications. The reservations and priority of the application are carried over from the old queue.
+func (sq *Queue) attachApplication(app *Application, reserved int, priority int32) {
+	appID := app.ApplicationID
+	sq.Lock()
+	sq.applications[appID] = app
+	sq.sortedApps.add(app)
+	if reserved > 0 {
+		sq.reservedApps[appID] = reserved
+	}
+	sq.Unlock()
+
+	sq.UpdateApplicationPriority(appID, priority)
+}
+
 func (sq *Queue) appExists(appID string) bool {
 	sq.RLock()
 	defer sq.RUnlock()
@@ -1152,6 +1319,7 @@ func (sq *Queue) removeChildQueue(name string) {
 	sq.Lock()
 	delete(sq.children, name)
 	delete(sq.childPriorities, name)
+	sq.invalidateQueueSortTree()
 	priority := sq.recalculatePriority()
 	sq.Unlock()
 
@@ -1172,6 +1340,7 @@ func (sq *Queue) addChildQueue(child *Queue) error {
 
 	// no need to lock child as it is a new queue which cannot be accessed yet
 	sq.children[child.Name] = child
+	sq.invalidateQueueSortTree()
 	sq.childPriorities[child.Name] = child.getCurrentPriority()
 
 	if child.isLeaf {
@@ -1222,6 +1391,15 @@ func (sq *Queue) MarkQueueForRemoval() {
 func (sq *Queue) doRemoveQueue() {
 	sq.Lock()
 	defer sq.Unlock()
+	// the removal from the config overrides an admin state: a stopped queue must be activated before draining
+	sq.adminState = false
+	if sq.IsStopped() {
+		if err := sq.handleQueueEvent(Start); err != nil {
+			log.Log(log.SchedQueue).Warn("failed to activate stopped queue for deletion",
+				zap.String("queue", sq.QueuePath),
+				zap.Error(err))
+		}
+	}
 	if err := sq.handleQueueEvent(Remove); err != nil {
 		log.Log(log.SchedQueue).Warn("failed to mark managed queue for deletion",
 			zap.String("queue", sq.QueuePath),
@@ -1308,6 +1486,7 @@ func (sq *Queue) TryIncAllocatedResource(alloc *resources.Resource) error {
 	// all OK update this queue
 	sq.allocatedResource = resources.Add(sq.allocatedResource, alloc)
 	sq.updateAllocatedResourceMetrics()
+	sq.parent.invalidateQueueSort()
 	return nil
 }
 
@@ -1328,6 +1507,7 @@ func (sq *Queue) IncAllocatedResource(alloc *resources.Resource, isQuotaPreempti
 	defer sq.Unlock()
 	sq.allocatedResource = resources.Add(sq.allocatedResource, alloc)
 	sq.updateAllocatedResourceMetrics()
+	sq.parent.invalidateQueueSort()
 
 	// Should apply quota preemption based on the config?
 	if !isQuotaPreemptionEnabled ||
@@ -1353,6 +1533,12 @@ func (sq *Queue) IncAllocatedResource(alloc *resources.Resource, isQuotaPreempti
 func (sq *Queue) allocatedResFits(alloc *resources.Resource) bool {
 	sq.RLock()
 	defer sq.RUnlock()
+	return sq.allocatedResFitsInternal(alloc)
+}
+
+// allocatedResFitsInternal is the unlocked version of allocatedResFits.
+// The queue lock is expected to be held.
+func (sq *Queue) allocatedResFitsInternal(alloc *resources.Resource) bool {
 	// on the root we want to reject a new allocation if it asks for resources not registered
 	// so do not use the "undefined" flag, also handles pruned max for root
 	if sq.isRoot() {
@@ -1400,6 +1586,63 @@ func (sq *Queue) DecAllocatedResource(alloc *resources.Resource) error {
 	// the metrics will not be updated with nil resource, this is not expected.
 	sq.updateAllocatedResourceMetrics()
 	sq.allocatedResource.Prune()
+	sq.parent.invalidateQueueSort()
+	return nil
+}
+
+// tryIncAllocatedResourceBelow increments the allocated resources of the queue and its parents up to, but not
+// including, the ancestor. The fit in the maximum is checked and the allocated resources are updated under the
+// same lock for each queue. All changes are reverted if the resources do not fit in one of the queues.
+// Used when an application moves: the allocated resources of the common ancestors do not change.
+func (sq *Queue) tryIncAllocatedResourceBelow(alloc *resources.Resource, ancestor *Queue) error {
+	if sq == nil || sq == ancestor {
+		return nil
+	}
+	sq.Lock()
+	if !sq.allocatedResFitsInternal(alloc) {
+		sq.Unlock()
+		return fmt.Errorf("allocated resources %s do not fit in max resources of queue %s", alloc, sq.QueuePath)
+	}
+	sq.allocatedResource = resources.Add(sq.allocatedResource, alloc)
+	sq.updateAllocatedResourceMetrics()
+	sq.Unlock()
+	sq.parent.invalidateQueueSort()
+	if err := sq.parent.tryIncAllocatedResourceBelow(alloc, ancestor); err != nil {
+		// only this queue needs to be reverted, the parent reverted its own change
+		_ = sq.decAllocatedResourceBelow(alloc, sq.parent)
+		return err
+	}
+	return nil
+}
+
+// decAllocatedResourceBelow decrements the allocated resources of the queue and its parents up to, but not
+// including, the ancestor. Guard against going below zero resources: all changes are reverted if the
+// resources are larger than the allocated resources of one of the queues.
+// Used when an application moves: the allocated resources of the common ancestors do not change.
+func (sq *Queue) decAllocatedResourceBelow(alloc *resources.Resource, ancestor *Queue) error {
+	if sq == nil || sq == ancestor {
+		return nil
+	}
+	sq.Lock()
+	if !sq.allocatedResource.FitIn(alloc) {
+		sq.Unlock()
+		return fmt.Errorf("released allocation (%v) is larger than '%s' queue allocation (%v)",
+			alloc, sq.QueuePath, sq.allocatedResource)
+	}
+	sq.allocatedResource = resources.Sub(sq.allocatedResource, alloc)
+	sq.updateAllocatedResourceMetrics()
+	sq.allocatedResource.Prune()
+	sq.Unlock()
+	sq.parent.invalidateQueueSort()
+	if err := sq.parent.decAllocatedResourceBelow(alloc, ancestor); err != nil {
+		// only this queue needs to be reverted, the parent reverted its own change
+		sq.Lock()
+		sq.allocatedResource = resources.Add(sq.allocatedResource, alloc)
+		sq.updateAllocatedResourceMetrics()
+		sq.Unlock()
+		sq.parent.invalidateQueueSort()
+		return err
+	}
 	return nil
 }
 
@@ -1446,7 +1689,8 @@ func (sq *Queue) IsPrioritySortEnabled() bool {
 }
 
 // sortApplications returns a sorted shallow copy of the applications in the queue.
-// Applications are sorted using the sorting type of the queue.
+// Applications are sorted using the sorting type of the queue. The order is maintained incrementally:
+// only applications with changed allocations or priorities are re-positioned.
 // Only applications with a pending resource request are considered.
 // Lock free call all locks are taken when needed in called functions
 // If withPlaceholdersOnly is true, then only applications with at least one placeholder allocation are considered.
@@ -1455,34 +1699,63 @@ func (sq *Queue) sortApplications(withPlaceholdersOnly bool) []*Application {
 		return nil
 	}
 
-	apps := sq.GetCopyOfApps()
-	if withPlaceholdersOnly {
-		for key, app := range apps {
-			if !app.HasPlaceholderAllocation() {
-				delete(apps, key)
-			}
+	// get the applications in the order of the sorting policy
+	apps := sq.sortedApps.sorted(sq.getSortType(), sq.IsPrioritySortEnabled(), sq.GetGuaranteedResource())
+	sortedApps := apps[:0]
+	for _, app := range apps {
+		if withPlaceholdersOnly && !app.HasPlaceholderAllocation() {
+			continue
+		}
+		// a suspended app is not considered until resumed
+		if app.IsSuspended() {
+			continue
+		}
+		// Only look at app when pending-res > 0
+		if resources.StrictlyGreaterThanZero(app.GetPendingResource()) {
+			sortedApps = append(sortedApps, app)
 		}
 	}
-	if len(apps) == 0 {
+	if len(sortedApps) == 0 {
 		return nil
 	}
+	return sortedApps
+}
 
-	// sort applications based on the sorting policy
-	return sortApplications(apps, sq.getSortType(), sq.IsPrioritySortEnabled(), sq.GetGuaranteedResource())
+// invalidateAppSort marks the sort keys of the application as changed, the application is
+// re-positioned in the order of the queue on the next sort.
+func (sq *Queue) invalidateAppSort(appID string) {
+	if sq == nil || sq.sortedApps == nil {
+		return
+	}
+	sq.sortedApps.invalidate(appID)
 }
 
 // sortQueues returns a sorted shallow copy of the queues for this parent queue.
 // Only queues with a pending resource request are considered. The queues are sorted using the
-// sorting type for the parent queue.
+// sorting type for the parent queue. The order is cached until a child queue changes, the returned
+// slice is shared and must not be modified.
 // Lock free call all locks are taken when needed in called functions
 func (sq *Queue) sortQueues() []*Queue {
 	if sq.IsLeafQueue() {
 		return nil
 	}
+	treeGeneration := sq.getRoot().sortedChildren.getTreeGeneration()
+	cached, generation, ok := sq.sortedChildren.get(treeGeneration)
+	if ok {
+		return cached
+	}
+	// The sorts are stable: start from the name order so queues that tie get the same order every time
+	children := make([]*Queue, 0)
+	for _, child := range sq.GetCopyOfChildren() {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Name < children[j].Name
+	})
 	// Create a list of the queues with pending resources
 	sortedQueues := make([]*Queue, 0)
 	sortedMaxFairResources := make([]*resources.Resource, 0)
-	for _, child := range sq.GetCopyOfChildren() {
+	for _, child := range children {
 		// a stopped queue cannot be scheduled
 		if child.IsStopped() {
 			continue
@@ -1495,10 +1768,35 @@ func (sq *Queue) sortQueues() []*Queue {
 	}
 	// Sort the queues
 	sortQueue(sortedQueues, sortedMaxFairResources, sq.getSortType(), sq.IsPrioritySortEnabled())
-
+	sq.sortedChildren.set(sortedQueues, generation, treeGeneration)
 	return sortedQueues
 }
 
+// invalidateQueueSort marks the order of the child queues as changed, the order is rebuilt on the next sort.
+// Called by a child queue after a change of its resources or priority.
+func (sq *Queue) invalidateQueueSort() {
+	if sq == nil || sq.sortedChildren == nil {
+		return
+	}
+	sq.sortedChildren.invalidate()
+}
+
+// invalidateQueueSortTree marks the order of all queues in the tree as changed.
+func (sq *Queue) invalidateQueueSortTree() {
+	if root := sq.getRoot(); root.sortedChildren != nil {
+		root.sortedChildren.invalidateTree()
+	}
+}
+
+// getRoot returns the root of the queue tree. The parent is set on creation and never changes.
+func (sq *Queue) getRoot() *Queue {
+	root := sq
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
 // getHeadRoom returns the headroom for the queue. This can never be more than the headroom for the parent.
 // In case there are no nodes in a newly started cluster and no queues have a limit configured this call
 // will return nil.
@@ -1646,6 +1944,7 @@ func (sq *Queue) SetMaxResource(max *resources.Resource) {
 	log.Log(log.SchedQueue).Info("updating root queue max resources",
 		zap.Stringer("current max", sq.maxResource),
 		zap.Stringer("new max", max))
+	sq.invalidateQueueSortTree()
 
 	switch {
 	case resources.StrictlyGreaterThanZero(max):
@@ -1696,13 +1995,25 @@ func (sq *Queue) canRunApp(appID string) bool {
 	return running <= sq.maxRunningApps
 }
 
+// hasRunningAppSlot returns true if this queue, not its parents, has room for one more running application.
+// It takes into account allocatingAcceptedApps
+func (sq *Queue) hasRunningAppSlot() bool {
+	sq.RLock()
+	defer sq.RUnlock()
+	if sq.maxRunningApps == 0 {
+		return true
+	}
+	running := sq.runningApps + uint64(len(sq.allocatingAcceptedApps)+1) //nolint: gosec
+	return running <= sq.maxRunningApps
+}
+
 // TryAllocate tries to allocate a pending requests. This only gets called if there is a pending request
 // on this queue or its children. This is a depth first algorithm: descend into the depth of the queue
 // tree first. Child queues are sorted based on the configured queue sortPolicy. Queues without pending
 // resources are skipped.
 // Applications are sorted based on the application sortPolicy. Applications without pending resources are skipped.
 // Lock free call this all locks are taken when needed in called functions
-func (sq *Queue) TryAllocate(iterator func() NodeIterator, fullIterator func() NodeIterator, getnode func(string) *Node, allowPreemption bool) *AllocationResult {
+func (sq *Queue) TryAllocate(iterator func() NodeIterator, fullIterator func() NodeIterator, reservedIterator func() NodeIterator, getnode func(string) *Node, allowPreemption bool) *AllocationResult {
 	if sq.IsLeafQueue() {
 		// get the headroom
 		headRoom := sq.getHeadRoom()
@@ -1711,35 +2022,14 @@ func (sq *Queue) TryAllocate(iterator func() NodeIterator, fullIterator func() N
 
 		// process the apps (filters out app without pending requests)
 		for _, app := range sq.sortApplications(false) {
-			runnableInQueue := sq.canRunApp(app.ApplicationID)
-			runnableByUserLimit := ugm.GetUserManager().CanRunApp(sq.QueuePath, app.ApplicationID, app.user)
-			app.updateRunnableStatus(runnableInQueue, runnableByUserLimit)
-			if app.IsAccepted() && (!runnableInQueue || !runnableByUserLimit) {
-				continue
-			}
-			deadline := app.GetBackoffDeadline()
-			if !deadline.IsZero() && time.Now().Before(deadline) {
-				continue
-			}
-			result := app.tryAllocate(headRoom, allowPreemption, preemptionDelay, &preemptAttemptsRemaining, iterator, fullIterator, getnode)
-			if result != nil {
-				log.Log(log.SchedQueue).Info("allocation found on queue",
-					zap.String("queueName", sq.QueuePath),
-					zap.String("appID", app.ApplicationID),
-					zap.Stringer("resultType", result.ResultType),
-					zap.Stringer("allocation", result.Request))
-				// if the app is still in Accepted state we're allocating placeholders.
-				// we want to count these apps as running
-				if app.IsAccepted() {
-					sq.setAllocatingAccepted(app.ApplicationID)
-				}
+			if result := sq.tryAllocateApp(app, headRoom, allowPreemption, preemptionDelay, &preemptAttemptsRemaining, iterator, fullIterator, reservedIterator, getnode); result != nil {
 				return result
 			}
 		}
 	} else {
 		// process the child queues (filters out queues without pending requests)
 		for _, child := range sq.sortQueues() {
-			result := child.TryAllocate(iterator, fullIterator, getnode, allowPreemption)
+			result := child.TryAllocate(iterator, fullIterator, reservedIterator, getnode, allowPreemption)
 			if result != nil {
 				return result
 			}
@@ -1748,6 +2038,72 @@ func (sq *Queue) TryAllocate(iterator func() NodeIterator, fullIterator func() N
 	return nil
 }
 
+// TryAllocateBatch keeps allocating from the queue hierarchy in the sorted queue and application order.
+// The queues and applications are sorted once for the whole batch and the headroom of a leaf queue is
+// updated incrementally after each allocation. Each result is passed to commit, the batch stops as soon as
+// commit returns false. Returns false if the batch was stopped.
+func (sq *Queue) TryAllocateBatch(iterator func() NodeIterator, fullIterator func() NodeIterator, reservedIterator func() NodeIterator, getnode func(string) *Node, allowPreemption bool, commit func(*AllocationResult) bool) bool {
+	if !sq.IsLeafQueue() {
+		// process the child queues (filters out queues without pending requests)
+		for _, child := range sq.sortQueues() {
+			if !child.TryAllocateBatch(iterator, fullIterator, reservedIterator, getnode, allowPreemption, commit) {
+				return false
+			}
+		}
+		return true
+	}
+	headRoom := sq.getHeadRoom()
+	preemptionDelay := sq.GetPreemptionDelay()
+	preemptAttemptsRemaining := maxPreemptionsPerQueue
+	for _, app := range sq.sortApplications(false) {
+		// keep allocating from the same application until nothing fits or the result is not an allocation
+		for {
+			result := sq.tryAllocateApp(app, headRoom, allowPreemption, preemptionDelay, &preemptAttemptsRemaining, iterator, fullIterator, reservedIterator, getnode)
+			if result == nil {
+				break
+			}
+			if !commit(result) {
+				return false
+			}
+			if result.ResultType != Allocated {
+				break
+			}
+			headRoom = resources.SubOnlyExisting(headRoom, result.Request.GetAllocatedResource())
+		}
+	}
+	return true
+}
+
+// tryAllocateApp tries to allocate a pending request of the application in this leaf queue.
+// Applications that cannot run in the queue or for the user, or that are backing off, are skipped.
+func (sq *Queue) tryAllocateApp(app *Application, headRoom *resources.Resource, allowPreemption bool, preemptionDelay time.Duration, preemptAttemptsRemaining *int, iterator func() NodeIterator, fullIterator func() NodeIterator, reservedIterator func() NodeIterator, getnode func(string) *Node) *AllocationResult {
+	runnableInQueue := sq.canRunApp(app.ApplicationID)
+	runnableByUserLimit := ugm.GetUserManager().CanRunApp(sq.QueuePath, app.ApplicationID, app.user)
+	app.updateRunnableStatus(runnableInQueue, runnableByUserLimit)
+	if app.IsAccepted() && (!runnableInQueue || !runnableByUserLimit) {
+		return nil
+	}
+	deadline := app.GetBackoffDeadline()
+	if !deadline.IsZero() && time.Now().Before(deadline) {
+		return nil
+	}
+	result := app.tryAllocate(headRoom, allowPreemption, preemptionDelay, preemptAttemptsRemaining, iterator, fullIterator, reservedIterator, getnode)
+	if result == nil {
+		return nil
+	}
+	log.Log(log.SchedQueue).Info("allocation found on queue",
+		zap.String("queueName", sq.QueuePath),
+		zap.String("appID", app.ApplicationID),
+		zap.Stringer("resultType", result.ResultType),
+		zap.Stringer("allocation", result.Request))
+	// if the app is still in Accepted state we're allocating placeholders.
+	// we want to count these apps as running
+	if app.IsAccepted() {
+		sq.setAllocatingAccepted(app.ApplicationID)
+	}
+	return result
+}
+
 func (sq *Queue) TryQuotaPreemption() {
 	if sq.tryAcquirePreemption() {
 		go func() {
@@ -1770,6 +2126,27 @@ func (sq *Queue) TryQuotaPreemption() {
 	}
 }
 
+// CheckApplicationDeadlines updates the deadline risk of the applications in the queue and its children.
+// The risk is checked in all leaf queues, independent of the sort policy. In a leaf queue with the deadline
+// sort policy the sort keys of the running applications are invalidated as their latest start changes while
+// they run.
+// Lock free call this all locks are taken when needed in called functions
+func (sq *Queue) CheckApplicationDeadlines(now time.Time) {
+	if !sq.IsLeafQueue() {
+		for _, child := range sq.GetCopyOfChildren() {
+			child.CheckApplicationDeadlines(now)
+		}
+		return
+	}
+	deadlineSort := sq.getSortType() == policies.DeadlineSortPolicy
+	for appID, app := range sq.GetCopyOfApps() {
+		app.checkDeadline(now)
+		if deadlineSort && app.isRunningWithDeadline() {
+			sq.invalidateAppSort(appID)
+		}
+	}
+}
+
 // TryPlaceholderAllocate tries to replace a placeholders with a real allocation.
 // This only gets called if there is a pending request on this queue or its children.
 // This is a depth first algorithm: descend into the depth of the queue tree first. Child queues are sorted based on
@@ -2070,6 +2447,50 @@ func (sq *Queue) decRunningApps() {
 	}
 }
 
+// tryIncRunningAppsBelow tracks a moved application as running, or as accepted with placeholders allocated, in
+// the queue and its parents up to, but not including, the ancestor. The maximum running applications are
+// checked and the tracking is updated under the same lock for each queue. All changes are reverted if one of
+// the queues has reached the maximum.
+func (sq *Queue) tryIncRunningAppsBelow(appID string, ancestor *Queue, accepted bool) error {
+	if sq == nil || sq == ancestor {
+		return nil
+	}
+	sq.Lock()
+	running := sq.runningApps + uint64(len(sq.allocatingAcceptedApps)+1) //nolint: gosec
+	if sq.maxRunningApps > 0 && running > sq.maxRunningApps {
+		sq.Unlock()
+		return fmt.Errorf("queue %s has reached the maximum running applications", sq.QueuePath)
+	}
+	if accepted {
+		sq.allocatingAcceptedApps[appID] = true
+	} else {
+		sq.runningApps++
+	}
+	sq.Unlock()
+	if err := sq.parent.tryIncRunningAppsBelow(appID, ancestor, accepted); err != nil {
+		// only this queue needs to be reverted, the parent reverted its own change
+		sq.decRunningAppsBelow(appID, sq.parent, accepted)
+		return err
+	}
+	return nil
+}
+
+// decRunningAppsBelow removes the running, or accepted with placeholders allocated, tracking of a moved
+// application from the queue and its parents up to, but not including, the ancestor.
+func (sq *Queue) decRunningAppsBelow(appID string, ancestor *Queue, accepted bool) {
+	if sq == nil || sq == ancestor {
+		return
+	}
+	sq.Lock()
+	if accepted {
+		delete(sq.allocatingAcceptedApps, appID)
+	} else if sq.runningApps > 0 {
+		sq.runningApps--
+	}
+	sq.Unlock()
+	sq.parent.decRunningAppsBelow(appID, ancestor, accepted)
+}
+
 // setAllocatingAccepted tracks the application in accepted state that have placeholders allocated.
 // These applications are considered "running" inside the queue for max running applications' enforcement.
 // For this queue (recursively).
@@ -2085,6 +2506,27 @@ func (sq *Queue) setAllocatingAccepted(appID string) {
 	sq.allocatingAcceptedApps[appID] = true
 }
 
+// isAllocatingAccepted returns true if the application is tracked as an accepted application that has
+// placeholders allocated.
+func (sq *Queue) isAllocatingAccepted(appID string) bool {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.allocatingAcceptedApps[appID]
+}
+
+// unsetAllocatingAccepted removes the tracking of the accepted application for this queue (recursively).
+func (sq *Queue) unsetAllocatingAccepted(appID string) {
+	if sq == nil {
+		return
+	}
+	if sq.parent != nil {
+		sq.parent.unsetAllocatingAccepted(appID)
+	}
+	sq.Lock()
+	defer sq.Unlock()
+	delete(sq.allocatingAcceptedApps, appID)
+}
+
 func (sq *Queue) GetPreemptionPolicy() policies.PreemptionPolicy {
 	sq.RLock()
 	defer sq.RUnlock()
@@ -2350,6 +2792,7 @@ func (sq *Queue) UpdateApplicationPriority(applicationID string, priority int32)
 	if sq == nil || !sq.IsLeafQueue() {
 		return
 	}
+	sq.invalidateAppSort(applicationID)
 	value := sq.updateApplicationPriorityInternal(applicationID, priority)
 	sq.parent.UpdateQueuePriority(sq.Name, value)
 }
@@ -2401,6 +2844,7 @@ func (sq *Queue) recalculatePriority() int32 {
 		curr = max(v, curr)
 	}
 	sq.currentPriority = curr
+	sq.parent.invalidateQueueSort()
 	return priorityValueByPolicy(sq.priorityPolicy, sq.priorityOffset, curr)
 }
 
@@ -35,6 +35,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/common/security"
 	"github.com/apache/yunikorn-core/pkg/events"
 	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/scheduler/objects/template"
@@ -698,6 +699,56 @@ func TestQueueStates(t *testing.T) {
 	}
 }
 
+func TestQueueAdminStates(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	parent, err := createManagedQueue(root, "parent", true, nil)
+	assert.NilError(t, err, "failed to create parent queue")
+	leaf, err := createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	assert.NilError(t, leaf.CheckAcceptingApplications(), "active queue should accept applications")
+	assert.NilError(t, leaf.CheckAcceptingAsks(), "active queue should accept asks")
+
+	// stopping the parent closes the leaf for applications and asks
+	err = parent.StopQueue()
+	assert.NilError(t, err, "failed to stop parent queue")
+	assert.Assert(t, parent.IsStopped(), "parent queue not stopped")
+	assert.Assert(t, !parent.IsMarkedForRemoval(), "stopped queue should not be marked for removal")
+	assert.ErrorContains(t, leaf.CheckAcceptingApplications(), "root.parent is stopped")
+	assert.ErrorContains(t, leaf.CheckAcceptingAsks(), "root.parent is stopped")
+	daoInfo := parent.GetPartitionQueueDAOInfo(false)
+	assert.Equal(t, daoInfo.Status, Stopped.String(), "unexpected status in dao")
+	assert.Assert(t, daoInfo.StateChangedByAdmin, "admin state not shown in dao")
+	assert.Assert(t, daoInfo.StateTime > 0, "state time not shown in dao")
+
+	// a stopped queue can be drained directly
+	err = parent.DrainQueue()
+	assert.NilError(t, err, "failed to drain parent queue")
+	assert.Assert(t, parent.IsDraining(), "parent queue not draining")
+	assert.Assert(t, !parent.IsMarkedForRemoval(), "drained queue should not be marked for removal")
+	assert.ErrorContains(t, leaf.CheckAcceptingAsks(), "root.parent is draining")
+	// a config update does not resume a queue drained by an admin
+	_, err = parent.ApplyConf(configs.QueueConfig{Name: "parent", Parent: true})
+	assert.NilError(t, err, "failed to apply config")
+	assert.Assert(t, parent.IsDraining(), "config update should not change the admin state")
+
+	err = parent.ResumeQueue()
+	assert.NilError(t, err, "failed to resume parent queue")
+	assert.Assert(t, parent.IsRunning(), "parent queue not running")
+	assert.NilError(t, leaf.CheckAcceptingApplications(), "resumed queue should accept applications")
+	assert.NilError(t, leaf.CheckAcceptingAsks(), "resumed queue should accept asks")
+	assert.Assert(t, !parent.GetPartitionQueueDAOInfo(false).StateChangedByAdmin, "admin state not reset on resume")
+
+	// removal from the config overrides the admin state and cannot be changed by an admin
+	err = leaf.StopQueue()
+	assert.NilError(t, err, "failed to stop leaf queue")
+	leaf.MarkQueueForRemoval()
+	assert.Assert(t, leaf.IsMarkedForRemoval(), "leaf queue should be marked for removal")
+	assert.ErrorContains(t, leaf.ResumeQueue(), "marked for removal")
+	assert.ErrorContains(t, leaf.CheckAcceptingApplications(), "root.parent.leaf is draining")
+	assert.NilError(t, leaf.CheckAcceptingAsks(), "queue marked for removal should accept asks of running applications")
+}
+
 // This test must not test the sorter that is underlying.
 // It tests the queue specific parts of the code only.
 func TestSortApplications(t *testing.T) {
@@ -3243,6 +3294,56 @@ func TestQueue_decRunningApps(t *testing.T) {
 	q.decRunningApps()
 }
 
+func TestQueue_tryIncRunningAppsBelow(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	var parent, leaf *Queue
+	parent, err = createManagedQueueMaxApps(root, "parent", true, nil, 1)
+	assert.NilError(t, err, "failed to create parent queue")
+	leaf, err = createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+
+	err = leaf.tryIncRunningAppsBelow("app-1", root, false)
+	assert.NilError(t, err, "first app should fit")
+	assert.Equal(t, leaf.runningApps, uint64(1), "leaf should have 1 app running")
+	assert.Equal(t, parent.runningApps, uint64(1), "parent should have 1 app running")
+	assert.Equal(t, root.runningApps, uint64(0), "ancestor should not have changed")
+	// the parent is full: the leaf change is reverted
+	err = leaf.tryIncRunningAppsBelow("app-2", root, true)
+	assert.ErrorContains(t, err, "queue root.parent has reached the maximum running applications")
+	assert.Equal(t, len(leaf.allocatingAcceptedApps), 0, "leaf change should have been reverted")
+	leaf.decRunningAppsBelow("app-1", root, false)
+	assert.Equal(t, leaf.runningApps, uint64(0), "leaf should have no apps running")
+	assert.Equal(t, parent.runningApps, uint64(0), "parent should have no apps running")
+}
+
+func TestQueue_tryIncAllocatedResourceBelow(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	var parent, leaf *Queue
+	parent, err = createManagedQueue(root, "parent", true, map[string]string{"first": "5"})
+	assert.NilError(t, err, "failed to create parent queue")
+	leaf, err = createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 4})
+
+	err = leaf.tryIncAllocatedResourceBelow(res, root)
+	assert.NilError(t, err, "first allocation should fit")
+	assert.Assert(t, resources.Equals(parent.GetAllocatedResource(), res), "parent allocated resources not updated")
+	assert.Assert(t, resources.IsZero(root.GetAllocatedResource()), "ancestor should not have changed")
+	// the parent is full: the leaf change is reverted
+	err = leaf.tryIncAllocatedResourceBelow(res, root)
+	assert.ErrorContains(t, err, "do not fit in max resources of queue root.parent")
+	assert.Assert(t, resources.Equals(leaf.GetAllocatedResource(), res), "leaf change should have been reverted")
+	// releasing more than allocated on the parent is reverted on the leaf
+	err = leaf.tryIncAllocatedResourceBelow(res, parent)
+	assert.NilError(t, err, "allocation below the parent should not have failed")
+	err = leaf.decAllocatedResourceBelow(resources.Multiply(res, 2), root)
+	assert.ErrorContains(t, err, "is larger than 'root.parent' queue allocation")
+	assert.Assert(t, resources.Equals(leaf.GetAllocatedResource(), resources.Multiply(res, 2)), "leaf change should have been reverted")
+	assert.Assert(t, resources.Equals(parent.GetAllocatedResource(), res), "parent should not have changed")
+}
+
 func TestQueue_setAllocatingAccepted(t *testing.T) {
 	// create the root
 	root, err := createRootQueue(nil)
@@ -3667,3 +3768,47 @@ func TestOverrideAndResetPreemptionTime(t *testing.T) {
 		})
 	}
 }
+
+func TestQueueAccessDeny(t *testing.T) {
+	root, err := NewConfiguredQueue(configs.QueueConfig{
+		Name:      "root",
+		Parent:    true,
+		SubmitACL: "*,!svc-legacy",
+		AdminACL:  " admins",
+	}, nil, false, nil)
+	assert.NilError(t, err)
+	parent, err := NewConfiguredQueue(configs.QueueConfig{
+		Name:     "parent",
+		Parent:   true,
+		AdminACL: "lead,!contractor-lead engineering,!contractors",
+	}, root, false, nil)
+	assert.NilError(t, err)
+	leaf, err := NewConfiguredQueue(configs.QueueConfig{
+		Name:      "leaf",
+		SubmitACL: "svc-legacy engineering,!contractors",
+	}, parent, false, nil)
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name   string
+		user   security.UserGroup
+		submit bool
+		admin  bool
+	}{
+		{"wildcard submit", getUserGroup("user1", []string{"group1"}), true, false},
+		{"denied on root, allowed on leaf", getUserGroup("svc-legacy", []string{"group1"}), false, false},
+		{"engineering", getUserGroup("user1", []string{"engineering"}), true, true},
+		{"contractor in engineering", getUserGroup("user1", []string{"engineering", "contractors"}), false, false},
+		{"root admin denied on parent", getUserGroup("user1", []string{"admins", "contractors"}), false, false},
+		{"root admin", getUserGroup("user1", []string{"admins"}), true, true},
+		{"denied admin user", getUserGroup("contractor-lead", []string{"admins"}), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.submit, leaf.CheckSubmitAccess(tt.user), "unexpected submit access")
+			assert.Equal(t, tt.admin, leaf.CheckAdminAccess(tt.user), "unexpected admin access")
+		})
+	}
+	// denies only apply down the hierarchy
+	assert.Assert(t, root.CheckAdminAccess(getUserGroup("contractor-lead", []string{"admins"})), "deny on child should not affect parent")
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -306,9 +307,12 @@ func (qpc *QuotaPreemptionContext) sortAllocations() {
 // preempt victims on best effort basis. So, preempt victims as close as possible to the required resource.
 // Otherwise, exceeding above the required resources slightly is acceptable for now.
 func (qpc *QuotaPreemptionContext) preemptVictims() {
+	preemptionMetrics := metrics.GetPreemptionMetrics()
+	queuePath := qpc.queue.GetQueuePath()
 	if len(qpc.allocations) == 0 {
 		log.Log(log.SchedQuotaChangePreemption).Warn("BUG: No victims to enforce quota change through preemption",
-			zap.String("queue", qpc.queue.GetQueuePath()))
+			zap.String("queue", queuePath))
+		preemptionMetrics.IncAttempt(queuePath, metrics.PreemptionTypeQuotaChange, metrics.PreemptionNoVictims)
 		return
 	}
 	apps := make(map[*Application][]*Allocation)
@@ -346,6 +350,7 @@ func (qpc *QuotaPreemptionContext) preemptVictims() {
 		}
 	}
 
+	preempted := 0
 	for app, victims := range apps {
 		if len(victims) > 0 {
 			qpc.results.claimedResource = victimsTotalResource
@@ -366,9 +371,17 @@ func (qpc *QuotaPreemptionContext) preemptVictims() {
 					zap.String("nodeID", victim.GetNodeID()))
 				qpc.queue.IncPreemptingResource(victim.GetAllocatedResource())
 				victim.SendPreemptedByQuotaChangeEvent(qpc.queue.GetQueuePath())
+				preemptionMetrics.AddVictim(queuePath, queuePath, metrics.PreemptionTypeQuotaChange, victim.GetAllocatedResource())
+				preempted++
 			}
 			app.notifyRMAllocationReleased(victims, si.TerminationType_PREEMPTED_BY_SCHEDULER,
 				"preempting allocations to enforce new max quota for queue : "+qpc.queue.GetQueuePath())
 		}
 	}
+	if preempted == 0 {
+		preemptionMetrics.IncAttempt(queuePath, metrics.PreemptionTypeQuotaChange, metrics.PreemptionShortfall)
+		return
+	}
+	preemptionMetrics.IncAttempt(queuePath, metrics.PreemptionTypeQuotaChange, metrics.PreemptionSuccess)
+	preemptionMetrics.ObserveVictimsPerAttempt(metrics.PreemptionTypeQuotaChange, preempted)
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/events"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -230,11 +231,13 @@ func TestQuotaChangeTryPreemption(t *testing.T) {
 			assignAllocationsToQueue(asks, leaf)
 			leaf.maxResource = tc.newMax
 			leaf.guaranteedResource = tc.guaranteed
+			metrics.GetPreemptionMetrics().Reset()
 			preemptor := NewQuotaPreemptor(tc.queue)
 			preemptor.allocations = asks
 			preemptor.tryPreemption()
 			assert.Equal(t, len(preemptor.allocations), tc.totalExpectedVictims)
 			assertPreemptedAllocationKeys(t, asks, tc.preemptedKeys)
+			assertQuotaPreemptionMetrics(t, leaf.QueuePath, len(tc.preemptedKeys))
 
 			time.Sleep(500 * time.Millisecond)
 			assertQuotaPreemptionEvent(t, tc.totalExpectedVictims, "Quota Preemption results summary: preemptable resources: "+tc.preemptableResource.String()+", claimed resources: "+tc.claimedResource.String()+", selected victims: "+strconv.Itoa(tc.totalExpectedVictims)+", preempted victims: "+strconv.Itoa(len(tc.preemptedKeys)), eventSystem.Store.CollectEvents())
@@ -247,6 +250,20 @@ func TestQuotaChangeTryPreemption(t *testing.T) {
 	}
 }
 
+func assertQuotaPreemptionMetrics(t *testing.T, queue string, preempted int) {
+	t.Helper()
+	success := 0
+	if preempted > 0 {
+		success = 1
+	}
+	attempts, err := metrics.GetPreemptionMetrics().GetAttempts(queue, metrics.PreemptionTypeQuotaChange, metrics.PreemptionSuccess)
+	assert.NilError(t, err)
+	assert.Equal(t, success, attempts, "unexpected successful quota change preemption attempts")
+	victims, err := metrics.GetPreemptionMetrics().GetVictims(queue, queue, metrics.PreemptionTypeQuotaChange)
+	assert.NilError(t, err)
+	assert.Equal(t, preempted, victims, "unexpected quota change preemption victims")
+}
+
 func TestQuotaChangeTryPreemptionWithDifferentResTypes(t *testing.T) {
 	leaf, err := NewConfiguredQueue(configs.QueueConfig{
 		Name: "leaf",
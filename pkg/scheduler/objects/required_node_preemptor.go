@@ -26,6 +26,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -68,11 +69,13 @@ func (p *PreemptionContext) tryPreemption() {
 
 	// Are there any victims/asks to preempt?
 	victims := p.GetVictims()
+	preemptionMetrics := metrics.GetPreemptionMetrics()
 	if len(victims) > 0 {
 		log.Log(log.SchedRequiredNodePreemption).Info("Found victims for required node preemption",
 			zap.String("ds allocation key", p.requiredAsk.GetAllocationKey()),
 			zap.String("allocation name", p.requiredAsk.GetAllocationName()),
 			zap.Int("no.of victims", len(victims)))
+		preempted := 0
 		for _, victim := range victims {
 			err := victim.MarkPreempted()
 			if err != nil {
@@ -81,8 +84,11 @@ func (p *PreemptionContext) tryPreemption() {
 					zap.String("allocationKey", victim.GetAllocationKey()))
 				continue
 			}
+			preempted++
+			victimQueuePath := ""
 			if victimQueue := p.application.queue.GetQueueByAppID(victim.GetApplicationID()); victimQueue != nil {
 				victimQueue.IncPreemptingResource(victim.GetAllocatedResource())
+				victimQueuePath = victimQueue.QueuePath
 			} else {
 				log.Log(log.SchedRequiredNodePreemption).Warn("BUG: Queue not found for daemon set preemption victim",
 					zap.String("queue", p.application.queue.Name),
@@ -90,11 +96,19 @@ func (p *PreemptionContext) tryPreemption() {
 					zap.String("victimAllocationKey", victim.GetAllocationKey()))
 			}
 			victim.SendPreemptedBySchedulerEvent(p.requiredAsk.GetAllocationKey(), p.requiredAsk.GetApplicationID(), p.application.queuePath)
+			preemptionMetrics.AddVictim(p.application.queuePath, victimQueuePath, metrics.PreemptionTypeRequiredNode, victim.GetAllocatedResource())
 		}
 		p.requiredAsk.MarkTriggeredPreemption()
+		if preempted > 0 {
+			preemptionMetrics.IncAttempt(p.application.queuePath, metrics.PreemptionTypeRequiredNode, metrics.PreemptionSuccess)
+			preemptionMetrics.ObserveVictimsPerAttempt(metrics.PreemptionTypeRequiredNode, preempted)
+		} else {
+			preemptionMetrics.IncAttempt(p.application.queuePath, metrics.PreemptionTypeRequiredNode, metrics.PreemptionVictimsReleased)
+		}
 		p.application.notifyRMAllocationReleased(victims, si.TerminationType_PREEMPTED_BY_SCHEDULER,
 			"preempting allocations to free up resources to run daemon set ask: "+p.requiredAsk.GetAllocationKey())
 	} else {
+		preemptionMetrics.IncAttempt(p.application.queuePath, metrics.PreemptionTypeRequiredNode, metrics.PreemptionNoVictims)
 		p.requiredAsk.LogAllocationFailure(common.NoVictimForRequiredNode, true)
 		p.requiredAsk.SendRequiredNodePreemptionFailedEvent(p.node.NodeID)
 		getRateLimitedReqNodeLog().Info("no victim found for required node preemption",
@@ -0,0 +1,221 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"time"
+
+	"github.com/tidwall/btree"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
+)
+
+// appSortRef is a snapshot of the sort keys of an application stored in the sorted tree.
+// The keys must not change while the reference is in the tree: an update removes the old
+// reference and adds a new one.
+type appSortRef struct {
+	app            *Application
+	appID          string
+	priority       int32     // highest priority of the pending asks
+	submissionTime time.Time // submission time of the application
+	latestStart    time.Time // latest start of the remaining work to meet the deadline, zero if the application has no deadline
+	shares         []float64 // usage shares of the allocated resources compared to the queue guaranteed resources
+}
+
+// appRefLess returns the ordering for the sort policy. The application ID is the last key in all
+// orderings to make it a strict ordering as required by the tree.
+func appRefLess(sortType policies.SortPolicy, considerPriority bool) func(a, b appSortRef) bool {
+	byPriority := func(a, b appSortRef) int {
+		switch {
+		case a.priority > b.priority:
+			return -1
+		case a.priority < b.priority:
+			return 1
+		default:
+			return 0
+		}
+	}
+	byShares := func(a, b appSortRef) int {
+		return resources.CompareShares(a.shares, b.shares)
+	}
+	bySubmissionTime := func(a, b appSortRef) int {
+		return a.submissionTime.Compare(b.submissionTime)
+	}
+	byDeadline := func(a, b appSortRef) int {
+		return compareLatestStart(a.latestStart, b.latestStart)
+	}
+	var keys []func(a, b appSortRef) int
+	switch sortType {
+	case policies.FairSortPolicy:
+		if considerPriority {
+			keys = []func(a, b appSortRef) int{byPriority, byShares}
+		} else {
+			keys = []func(a, b appSortRef) int{byShares, byPriority}
+		}
+	case policies.FifoSortPolicy:
+		if considerPriority {
+			keys = []func(a, b appSortRef) int{byPriority, bySubmissionTime}
+		} else {
+			keys = []func(a, b appSortRef) int{bySubmissionTime, byPriority}
+		}
+	case policies.DeadlineSortPolicy:
+		if considerPriority {
+			keys = []func(a, b appSortRef) int{byPriority, byDeadline, bySubmissionTime}
+		} else {
+			keys = []func(a, b appSortRef) int{byDeadline, byPriority, bySubmissionTime}
+		}
+	}
+	return func(a, b appSortRef) bool {
+		for _, key := range keys {
+			if comp := key(a, b); comp != 0 {
+				return comp < 0
+			}
+		}
+		return a.appID < b.appID
+	}
+}
+
+// sortedApplications maintains the order of the applications in a leaf queue incrementally.
+// Applications are only re-positioned in the order after they are invalidated by an allocation,
+// priority or usage change. The full order is only rebuilt if the sort policy or the guaranteed
+// resources of the queue change.
+//
+// Lock order: the application lock must never be taken while holding the lock of this object.
+// Invalidations are called with the application lock held.
+type sortedApplications struct {
+	sortType         policies.SortPolicy
+	considerPriority bool
+	globalResource   *resources.Resource // resource used to calculate the shares, the guaranteed resource of the queue
+
+	apps       map[string]*Application   // all applications tracked
+	refs       map[string]appSortRef     // references in the tree by application ID
+	invalid    map[string]*Application   // applications that need their sort keys updated
+	sortedApps *btree.BTreeG[appSortRef] // applications sorted by the current policy
+
+	locking.Mutex
+}
+
+func newSortedApplications() *sortedApplications {
+	return &sortedApplications{
+		apps:       make(map[string]*Application),
+		refs:       make(map[string]appSortRef),
+		invalid:    make(map[string]*Application),
+		sortedApps: btree.NewBTreeGOptions(appRefLess(policies.FifoSortPolicy, false), btree.Options{NoLocks: true}),
+	}
+}
+
+// add an application, the application is positioned on the next call to sorted.
+func (s *sortedApplications) add(app *Application) {
+	s.Lock()
+	defer s.Unlock()
+	s.removeInternal(app.ApplicationID)
+	s.apps[app.ApplicationID] = app
+	s.invalid[app.ApplicationID] = app
+}
+
+// remove an application from the order.
+func (s *sortedApplications) remove(appID string) {
+	s.Lock()
+	defer s.Unlock()
+	s.removeInternal(appID)
+}
+
+func (s *sortedApplications) removeInternal(appID string) {
+	if ref, ok := s.refs[appID]; ok {
+		s.sortedApps.Delete(ref)
+		delete(s.refs, appID)
+	}
+	delete(s.apps, appID)
+	delete(s.invalid, appID)
+}
+
+// invalidate marks the sort keys of the application as changed.
+// Called with the application lock held.
+func (s *sortedApplications) invalidate(appID string) {
+	s.Lock()
+	defer s.Unlock()
+	if app, ok := s.apps[appID]; ok {
+		s.invalid[appID] = app
+	}
+}
+
+// sorted returns the applications in the order of the sort policy. The keys of invalidated applications
+// are refreshed before the order is returned. A change in the policy or the global resource rebuilds the
+// complete order.
+func (s *sortedApplications) sorted(sortType policies.SortPolicy, considerPriority bool, globalResource *resources.Resource) []*Application {
+	sortingStart := time.Now()
+	s.Lock()
+	if s.sortType != sortType || s.considerPriority != considerPriority || !resources.Equals(s.globalResource, globalResource) {
+		s.sortType = sortType
+		s.considerPriority = considerPriority
+		s.globalResource = globalResource
+		s.sortedApps = btree.NewBTreeGOptions(appRefLess(sortType, considerPriority), btree.Options{NoLocks: true})
+		s.refs = make(map[string]appSortRef)
+		for appID, app := range s.apps {
+			s.invalid[appID] = app
+		}
+	}
+	invalid := s.invalid
+	s.invalid = make(map[string]*Application)
+	s.Unlock()
+
+	// collecting the keys takes the application lock: do not hold the lock of the order
+	updates := make([]appSortRef, 0, len(invalid))
+	for _, app := range invalid {
+		updates = append(updates, app.getSortRef(globalResource))
+	}
+
+	s.Lock()
+	for _, ref := range updates {
+		// application could have been removed while getting the keys
+		if s.apps[ref.appID] != ref.app {
+			continue
+		}
+		if old, ok := s.refs[ref.appID]; ok {
+			s.sortedApps.Delete(old)
+		}
+		s.refs[ref.appID] = ref
+		s.sortedApps.Set(ref)
+	}
+	sortedApps := make([]*Application, 0, s.sortedApps.Len())
+	s.sortedApps.Scan(func(ref appSortRef) bool {
+		sortedApps = append(sortedApps, ref.app)
+		return true
+	})
+	s.Unlock()
+	metrics.GetSchedulerMetrics().ObserveAppSortingLatency(sortingStart)
+	return sortedApps
+}
+
+// getSortRef returns a snapshot of the sort keys of the application.
+func (sa *Application) getSortRef(globalResource *resources.Resource) appSortRef {
+	sa.RLock()
+	defer sa.RUnlock()
+	return appSortRef{
+		app:            sa,
+		appID:          sa.ApplicationID,
+		priority:       sa.askMaxPriority,
+		submissionTime: sa.submissionTime,
+		latestStart:    sa.getLatestStart(time.Now()),
+		shares:         resources.GetShares(sa.allocatedResource, globalResource),
+	}
+}
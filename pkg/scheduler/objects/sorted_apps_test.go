@@ -0,0 +1,226 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
+)
+
+// newSortTestApps creates applications with unique sort keys so the full sort is deterministic
+func newSortTestApps(count int) map[string]*Application {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+	order := rand.Perm(count)
+	now := time.Now()
+	apps := make(map[string]*Application, count)
+	for i := 0; i < count; i++ {
+		appID := "app-" + strconv.Itoa(i)
+		app := newApplication(appID, "partition", "queue")
+		app.allocatedResource = resources.Multiply(res, int64(order[i]))
+		app.askMaxPriority = int32(i % 3)
+		app.submissionTime = now.Add(time.Duration(order[(i+1)%count]) * time.Second)
+		// every fourth application has no deadline
+		if i%4 != 0 {
+			app.deadline = now.Add(time.Duration(order[(i+2)%count]) * time.Minute)
+			app.estimatedRuntime = time.Duration(i%5) * time.Second
+		}
+		app.pending = res
+		apps[appID] = app
+	}
+	return apps
+}
+
+func assertSameOrder(t *testing.T, expected, actual []*Application, name string) {
+	assert.Equal(t, len(expected), len(actual), "length of list differs, test: %s", name)
+	for i := range expected {
+		assert.Equal(t, expected[i].ApplicationID, actual[i].ApplicationID, "order differs at %d, test: %s", i, name)
+	}
+}
+
+func TestSortedApplications(t *testing.T) {
+	total := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000})
+	tests := []struct {
+		name             string
+		sortType         policies.SortPolicy
+		considerPriority bool
+	}{
+		{"fair", policies.FairSortPolicy, false},
+		{"fair priority", policies.FairSortPolicy, true},
+		{"fifo", policies.FifoSortPolicy, false},
+		{"fifo priority", policies.FifoSortPolicy, true},
+		{"deadline", policies.DeadlineSortPolicy, false},
+		{"deadline priority", policies.DeadlineSortPolicy, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apps := newSortTestApps(100)
+			sorted := newSortedApplications()
+			for _, app := range apps {
+				sorted.add(app)
+			}
+			assertSameOrder(t, sortApps(apps, tt.sortType, tt.considerPriority, total),
+				sorted.sorted(tt.sortType, tt.considerPriority, total), "initial")
+
+			// change the keys of some applications: only invalidated applications are re-positioned
+			res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+			for i := 0; i < 10; i++ {
+				app := apps["app-"+strconv.Itoa(i*7)]
+				app.allocatedResource = resources.Multiply(res, int64(1000+i))
+				app.askMaxPriority = 5
+				app.submissionTime = app.submissionTime.Add(time.Hour)
+				sorted.invalidate(app.ApplicationID)
+			}
+			assertSameOrder(t, sortApps(apps, tt.sortType, tt.considerPriority, total),
+				sorted.sorted(tt.sortType, tt.considerPriority, total), "invalidated")
+
+			// removed applications are not returned
+			sorted.remove("app-0")
+			delete(apps, "app-0")
+			assertSameOrder(t, sortApps(apps, tt.sortType, tt.considerPriority, total),
+				sorted.sorted(tt.sortType, tt.considerPriority, total), "removed")
+		})
+	}
+}
+
+func TestSortedApplicationsRebuild(t *testing.T) {
+	apps := newSortTestApps(50)
+	sorted := newSortedApplications()
+	for _, app := range apps {
+		sorted.add(app)
+	}
+	total := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000})
+	assertSameOrder(t, sortApps(apps, policies.FifoSortPolicy, false, total),
+		sorted.sorted(policies.FifoSortPolicy, false, total), "fifo")
+	// a policy change rebuilds the order
+	assertSameOrder(t, sortApps(apps, policies.FairSortPolicy, false, total),
+		sorted.sorted(policies.FairSortPolicy, false, total), "fair")
+	// a change of the total resource rebuilds the order: shares change for all applications
+	total = resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 10})
+	assertSameOrder(t, sortApps(apps, policies.FairSortPolicy, true, total),
+		sorted.sorted(policies.FairSortPolicy, true, total), "fair priority")
+}
+
+func TestQueueSortApplicationsInvalidation(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	leaf.sortType = policies.FairSortPolicy
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+	for i := 0; i < 3; i++ {
+		app := newApplication("app-"+strconv.Itoa(i), "default", "root.leaf")
+		app.SetQueue(leaf)
+		leaf.AddApplication(app)
+		err = app.AddAllocationAsk(newAllocationAsk("alloc-"+strconv.Itoa(i), app.ApplicationID, res))
+		assert.NilError(t, err, "ask should have been added to app")
+	}
+	list := leaf.sortApplications(false)
+	assertAppListLength(t, list, []string{"app-0", "app-1", "app-2"}, "no allocations")
+
+	// an allocation on app-0 moves it to the end of the fair order
+	app0 := leaf.GetApplication("app-0")
+	app0.AddAllocation(newAllocationWithKey("alloc-a", app0.ApplicationID, nodeID1, resources.Multiply(res, 2)))
+	list = leaf.sortApplications(false)
+	assertAppListLength(t, list, []string{"app-1", "app-2", "app-0"}, "app-0 allocated")
+
+	// a higher priority ask is invalidated and moves app-2 to the front when priorities are considered
+	err = leaf.GetApplication("app-2").AddAllocationAsk(newAllocationAskPriority("alloc-p", "app-2", res, 10))
+	assert.NilError(t, err, "ask should have been added to app")
+	list = leaf.sortApplications(false)
+	assertAppListLength(t, list, []string{"app-2", "app-1", "app-0"}, "app-2 priority")
+
+	// removed applications are no longer sorted
+	leaf.RemoveApplication(leaf.GetApplication("app-1"))
+	list = leaf.sortApplications(false)
+	assertAppListLength(t, list, []string{"app-2", "app-0"}, "app-1 removed")
+}
+
+func TestQueueSortApplicationsDeadline(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	leaf.sortType = policies.DeadlineSortPolicy
+
+	now := time.Now()
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+	for i := 0; i < 2; i++ {
+		tags := map[string]string{
+			AppTagDeadline:         now.Add(time.Duration(i+1) * time.Hour).Format(time.RFC3339),
+			AppTagEstimatedRuntime: "90m",
+		}
+		app := newApplicationWithTags("app-"+strconv.Itoa(i), "default", "root.leaf", tags)
+		app.SetQueue(leaf)
+		leaf.AddApplication(app)
+		err = app.AddAllocationAsk(newAllocationAsk("alloc-"+strconv.Itoa(i), app.ApplicationID, res))
+		assert.NilError(t, err, "ask should have been added to app")
+	}
+	list := leaf.sortApplications(false)
+	assertAppListLength(t, list, []string{"app-0", "app-1"}, "not running")
+
+	// the slack of a running app changes over time: the order only changes after the deadline check
+	app0 := leaf.GetApplication("app-0")
+	app0.Lock()
+	app0.startTime = now.Add(-80 * time.Minute)
+	app0.Unlock()
+	list = leaf.sortApplications(false)
+	assertAppListLength(t, list, []string{"app-0", "app-1"}, "running not checked")
+	root.CheckApplicationDeadlines(now)
+	list = leaf.sortApplications(false)
+	assertAppListLength(t, list, []string{"app-1", "app-0"}, "running checked")
+}
+
+// BenchmarkSortApplications compares rebuilding the order of all applications with the incrementally
+// maintained order when one application changes between the sorts.
+func BenchmarkSortApplications(b *testing.B) {
+	total := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 100000})
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+	for _, sortType := range []policies.SortPolicy{policies.FairSortPolicy, policies.FifoSortPolicy} {
+		apps := newSortTestApps(10000)
+		b.Run(sortType.String()+"/full", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				app := apps["app-"+strconv.Itoa(i%len(apps))]
+				app.allocatedResource = resources.Add(app.allocatedResource, res)
+				sortApps(apps, sortType, true, total)
+			}
+		})
+		sorted := newSortedApplications()
+		for _, app := range apps {
+			sorted.add(app)
+		}
+		b.Run(sortType.String()+"/incremental", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				app := apps["app-"+strconv.Itoa(i%len(apps))]
+				app.allocatedResource = resources.Add(app.allocatedResource, res)
+				sorted.invalidate(app.ApplicationID)
+				sorted.sorted(sortType, true, total)
+			}
+		})
+	}
+}
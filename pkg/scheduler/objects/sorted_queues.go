@@ -0,0 +1,82 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"github.com/apache/yunikorn-core/pkg/locking"
+)
+
+// sortedQueues caches the order of the child queues of a parent queue.
+// The order is rebuilt on the next sort after a child changed its allocated or pending resources or its
+// priority, which invalidates the order of the parent only. Changes to the limits, properties, state or the
+// children of any queue invalidate the order of all queues in the tree: the fair max resource used in the
+// order is inherited from the parents.
+// The generation of the tree is tracked on the root queue.
+type sortedQueues struct {
+	queues         []*Queue // cached order, must not be modified
+	generation     uint64   // incremented when the order of the children is invalidated
+	treeGeneration uint64   // incremented when the order of all queues is invalidated, root queue only
+	sortedAt       uint64   // generation the cached order was built at
+	treeSortedAt   uint64   // tree generation the cached order was built at
+	built          bool
+
+	locking.Mutex
+}
+
+func newSortedQueues() *sortedQueues {
+	return &sortedQueues{}
+}
+
+// get returns the cached order if it is still valid, and the generations to store a new order with.
+func (s *sortedQueues) get(treeGeneration uint64) ([]*Queue, uint64, bool) {
+	s.Lock()
+	defer s.Unlock()
+	return s.queues, s.generation, s.built && s.sortedAt == s.generation && s.treeSortedAt == treeGeneration
+}
+
+// set stores the order if nothing was invalidated while it was built.
+func (s *sortedQueues) set(queues []*Queue, generation, treeGeneration uint64) {
+	s.Lock()
+	defer s.Unlock()
+	if s.generation != generation {
+		return
+	}
+	s.queues = queues
+	s.sortedAt = generation
+	s.treeSortedAt = treeGeneration
+	s.built = true
+}
+
+func (s *sortedQueues) invalidate() {
+	s.Lock()
+	defer s.Unlock()
+	s.generation++
+}
+
+func (s *sortedQueues) invalidateTree() {
+	s.Lock()
+	defer s.Unlock()
+	s.treeGeneration++
+}
+
+func (s *sortedQueues) getTreeGeneration() uint64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.treeGeneration
+}
@@ -0,0 +1,66 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestSortQueuesCache(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	parent, err := createManagedQueue(root, "parent", true, nil)
+	assert.NilError(t, err, "queue create failed")
+	leafA, err := createManagedQueue(parent, "a", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	leafB, err := createManagedQueue(parent, "b", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+	// the fair share needs a limit
+	root.SetMaxResource(resources.Multiply(res, 10))
+
+	assert.Equal(t, 0, len(parent.sortQueues()), "no queues expected without pending resources")
+	leafA.incPendingResource(res)
+	list := parent.sortQueues()
+	assert.Equal(t, queueNames(list), "a", "pending change should invalidate the order")
+	assert.Equal(t, queueNames(root.sortQueues()), "parent", "pending change should invalidate the order of the parents")
+	assert.Assert(t, &list[0] == &parent.sortQueues()[0], "unchanged order should be cached")
+
+	// queues that tie are ordered by name
+	leafB.incPendingResource(res)
+	assert.Equal(t, queueNames(parent.sortQueues()), "a,b", "tied queues should be in name order")
+	// fair sort: an allocation moves the queue to the back
+	leafA.IncAllocatedResource(res, false)
+	assert.Equal(t, queueNames(parent.sortQueues()), "b,a", "allocation should invalidate the order")
+	err = leafA.DecAllocatedResource(res)
+	assert.NilError(t, err, "release failed")
+	leafB.IncAllocatedResource(resources.Multiply(res, 2), false)
+	assert.Equal(t, queueNames(parent.sortQueues()), "a,b", "allocation should invalidate the order")
+
+	// a stopped queue is not scheduled: state changes invalidate the whole tree
+	err = leafA.handleQueueEvent(Stop)
+	assert.NilError(t, err, "stop failed")
+	assert.Equal(t, queueNames(parent.sortQueues()), "b", "state change should invalidate the order")
+	leafB.decPendingResource(res)
+	assert.Equal(t, 0, len(parent.sortQueues()), "pending change should invalidate the order")
+}
@@ -98,93 +98,17 @@ func sortQueuesByFairnessAndPriority(queues []*Queue, fairMaxResources []*resour
 	})
 }
 
-func sortApplications(apps map[string]*Application, sortType policies.SortPolicy, considerPriority bool, globalResource *resources.Resource) []*Application {
-	sortingStart := time.Now()
-	sortedApps := filterOnPendingResources(apps)
-	switch sortType {
-	case policies.FairSortPolicy:
-		if considerPriority {
-			sortApplicationsByPriorityAndFairness(sortedApps, globalResource)
-		} else {
-			sortApplicationsByFairnessAndPriority(sortedApps, globalResource)
-		}
-	case policies.FifoSortPolicy:
-		if considerPriority {
-			sortApplicationsByPriorityAndSubmissionTime(sortedApps)
-		} else {
-			sortApplicationsBySubmissionTimeAndPriority(sortedApps)
-		}
-	}
-	metrics.GetSchedulerMetrics().ObserveAppSortingLatency(sortingStart)
-	return sortedApps
-}
-
-func sortApplicationsByFairnessAndPriority(sortedApps []*Application, globalResource *resources.Resource) {
-	sort.SliceStable(sortedApps, func(i, j int) bool {
-		l := sortedApps[i]
-		r := sortedApps[j]
-		if comp := resources.CompUsageRatio(l.GetAllocatedResource(), r.GetAllocatedResource(), globalResource); comp != 0 {
-			return comp < 0
-		}
-		return l.GetAskMaxPriority() > r.GetAskMaxPriority()
-	})
-}
-
-func sortApplicationsByPriorityAndFairness(sortedApps []*Application, globalResource *resources.Resource) {
-	sort.SliceStable(sortedApps, func(i, j int) bool {
-		l := sortedApps[i]
-		r := sortedApps[j]
-		leftPriority := l.GetAskMaxPriority()
-		rightPriority := r.GetAskMaxPriority()
-		if leftPriority > rightPriority {
-			return true
-		}
-		if leftPriority < rightPriority {
-			return false
-		}
-		return resources.CompUsageRatio(l.GetAllocatedResource(), r.GetAllocatedResource(), globalResource) < 0
-	})
-}
-
-func sortApplicationsBySubmissionTimeAndPriority(sortedApps []*Application) {
-	sort.SliceStable(sortedApps, func(i, j int) bool {
-		l := sortedApps[i]
-		r := sortedApps[j]
-		lSubTime := l.GetSubmissionTime()
-		rSubTime := r.GetSubmissionTime()
-		if lSubTime.Before(rSubTime) {
-			return true
-		}
-		if rSubTime.Before(lSubTime) {
-			return false
-		}
-		return l.GetAskMaxPriority() > r.GetAskMaxPriority()
-	})
-}
-
-func sortApplicationsByPriorityAndSubmissionTime(sortedApps []*Application) {
-	sort.SliceStable(sortedApps, func(i, j int) bool {
-		l := sortedApps[i]
-		r := sortedApps[j]
-		leftPriority := l.GetAskMaxPriority()
-		rightPriority := r.GetAskMaxPriority()
-		if leftPriority > rightPriority {
-			return true
-		}
-		if leftPriority < rightPriority {
-			return false
-		}
-		return l.GetSubmissionTime().Before(r.GetSubmissionTime())
-	})
-}
-
-func filterOnPendingResources(apps map[string]*Application) []*Application {
-	filteredApps := make([]*Application, 0)
-	for _, app := range apps {
-		// Only look at app when pending-res > 0
-		if resources.StrictlyGreaterThanZero(app.GetPendingResource()) {
-			filteredApps = append(filteredApps, app)
-		}
+// compareLatestStart compares the latest start times of two applications. A zero time means no deadline
+// and is sorted after any set time.
+func compareLatestStart(l, r time.Time) int {
+	switch {
+	case l.IsZero() && r.IsZero():
+		return 0
+	case l.IsZero():
+		return 1
+	case r.IsZero():
+		return -1
+	default:
+		return l.Compare(r)
 	}
-	return filteredApps
 }
@@ -173,6 +173,22 @@ func TestSortQueuesNoGuarantees(t *testing.T) {
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair no limit second - priority")
 }
 
+// sortApps returns the applications with pending resources in the order of a newly built sortedApplications,
+// as used by the leaf queue
+func sortApps(apps map[string]*Application, sortType policies.SortPolicy, considerPriority bool, globalResource *resources.Resource) []*Application {
+	sorted := newSortedApplications()
+	for _, app := range apps {
+		sorted.add(app)
+	}
+	list := make([]*Application, 0, len(apps))
+	for _, app := range sorted.sorted(sortType, considerPriority, globalResource) {
+		if resources.StrictlyGreaterThanZero(app.GetPendingResource()) {
+			list = append(list, app)
+		}
+	}
+	return list
+}
+
 func TestSortAppsNoPending(t *testing.T) {
 	var list []*Application
 
@@ -188,27 +204,27 @@ func TestSortAppsNoPending(t *testing.T) {
 	}
 
 	// no apps with pending resources should come back empty
-	list = sortApplications(input, policies.FairSortPolicy, false, nil)
+	list = sortApps(input, policies.FairSortPolicy, false, nil)
 	assertAppListLength(t, list, []string{}, "fair no pending")
-	list = sortApplications(input, policies.FairSortPolicy, true, nil)
+	list = sortApps(input, policies.FairSortPolicy, true, nil)
 	assertAppListLength(t, list, []string{}, "fair no pending - priority")
 
-	list = sortApplications(input, policies.FifoSortPolicy, false, nil)
+	list = sortApps(input, policies.FifoSortPolicy, false, nil)
 	assertAppListLength(t, list, []string{}, "fifo no pending")
-	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
+	list = sortApps(input, policies.FifoSortPolicy, true, nil)
 	assertAppListLength(t, list, []string{}, "fifo no pending - priority")
 
 	// set one app with pending
 	appID := "app-1"
 	input[appID].pending = res
-	list = sortApplications(input, policies.FairSortPolicy, false, nil)
+	list = sortApps(input, policies.FairSortPolicy, false, nil)
 	assertAppListLength(t, list, []string{appID}, "fair one pending")
-	list = sortApplications(input, policies.FairSortPolicy, true, nil)
+	list = sortApps(input, policies.FairSortPolicy, true, nil)
 	assertAppListLength(t, list, []string{appID}, "fair one pending - priority")
 
-	list = sortApplications(input, policies.FifoSortPolicy, false, nil)
+	list = sortApps(input, policies.FifoSortPolicy, false, nil)
 	assertAppListLength(t, list, []string{appID}, "fifo one pending")
-	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
+	list = sortApps(input, policies.FifoSortPolicy, true, nil)
 	assertAppListLength(t, list, []string{appID}, "fifo one pending - priority")
 }
 
@@ -230,14 +246,14 @@ func TestSortAppsFifo(t *testing.T) {
 	}
 
 	// fifo - apps should come back in order created 0, 1, 2, 3
-	list = sortApplications(input, policies.FifoSortPolicy, false, nil)
+	list = sortApps(input, policies.FifoSortPolicy, false, nil)
 	assertAppList(t, list, []int{0, 1, 2, 3}, "fifo simple")
 
 	input["app-1"].askMaxPriority = 3
 	input["app-3"].askMaxPriority = 5
 	input["app-2"].submissionTime = input["app-3"].submissionTime
 	input["app-1"].submissionTime = input["app-3"].submissionTime
-	list = sortApplications(input, policies.FifoSortPolicy, false, nil)
+	list = sortApps(input, policies.FifoSortPolicy, false, nil)
 	/*
 	* apps order: 0, 3, 1, 2
 	* the resultType of app index is [0, 2, 3, 1]
@@ -269,7 +285,7 @@ func TestSortAppsPriorityFifo(t *testing.T) {
 	input["app-3"].askMaxPriority = 4
 
 	// priority - apps should come back in order 1, 3, 0, 2
-	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
+	list = sortApps(input, policies.FifoSortPolicy, true, nil)
 	assertAppList(t, list, []int{2, 0, 3, 1}, "fifo simple")
 }
 
@@ -289,27 +305,27 @@ func TestSortAppsFair(t *testing.T) {
 	}
 	// nil resource: usage based sorting
 	// apps should come back in order: 0, 1, 2, 3
-	list := sortApplications(input, policies.FairSortPolicy, false, nil)
+	list := sortApps(input, policies.FairSortPolicy, false, nil)
 	assertAppList(t, list, []int{0, 1, 2, 3}, "nil total")
 
 	// apps should come back in order: 0, 1, 2, 3
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 0))
+	list = sortApps(input, policies.FairSortPolicy, false, resources.Multiply(res, 0))
 	assertAppList(t, list, []int{0, 1, 2, 3}, "zero total")
 
 	// apps should come back in order: 0, 1, 2, 3
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
+	list = sortApps(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
 	assertAppList(t, list, []int{0, 1, 2, 3}, "no alloc, set total")
 
 	// update allocated resource for app-1
 	input["app-1"].allocatedResource = resources.Multiply(res, 10)
 	// apps should come back in order: 0, 2, 3, 1
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
+	list = sortApps(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
 	assertAppList(t, list, []int{0, 3, 1, 2}, "app-1 allocated")
 
 	// update allocated resource for app-3 to negative (move to head of the list)
 	input["app-3"].allocatedResource = resources.Multiply(res, -10)
 	// apps should come back in order: 3, 0, 2, 1
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
+	list = sortApps(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
 	assertAppList(t, list, []int{1, 3, 2, 0}, "app-1 & app-3 allocated")
 
 	// update allocated resource for app-3 & app-1 where priority of app-3 is higher
@@ -317,7 +333,7 @@ func TestSortAppsFair(t *testing.T) {
 	input["app-1"].askMaxPriority = 2
 	input["app-3"].allocatedResource = resources.Multiply(res, 10)
 	input["app-3"].askMaxPriority = 3
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
+	list = sortApps(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
 	/*
 	*  expected apps order: 0, 2, 3, 1 means
 	*  So resultType of apps indexs is [0, 3, 1, 2]
@@ -346,27 +362,27 @@ func TestSortAppsPriorityFair(t *testing.T) {
 
 	// nil resource: priority then usage based sorting
 	// apps should come back in order: 1, 0, 2, 3
-	list := sortApplications(input, policies.FairSortPolicy, true, nil)
+	list := sortApps(input, policies.FairSortPolicy, true, nil)
 	assertAppList(t, list, []int{1, 0, 2, 3}, "nil total")
 
 	// apps should come back in order: 1, 0, 2, 3
-	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 0))
+	list = sortApps(input, policies.FairSortPolicy, true, resources.Multiply(res, 0))
 	assertAppList(t, list, []int{1, 0, 2, 3}, "zero total")
 
 	// apps should come back in order: 1, 0, 2, 3
-	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
+	list = sortApps(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
 	assertAppList(t, list, []int{1, 0, 2, 3}, "no alloc, set total")
 
 	// update allocated resource for app-2
 	input["app-2"].allocatedResource = resources.Multiply(res, 10)
 	// apps should come back in order: 1, 0, 3, 2
-	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
+	list = sortApps(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
 	assertAppList(t, list, []int{1, 0, 3, 2}, "app-1 allocated")
 
 	// update allocated resource for app-3 to negative (move to head of the list within priority 0)
 	input["app-3"].allocatedResource = resources.Multiply(res, -10)
 	// apps should come back in order: 1, 3, 0, 2
-	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
+	list = sortApps(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
 	assertAppList(t, list, []int{2, 0, 3, 1}, "app-1 & app-3 allocated")
 }
 
@@ -380,6 +396,44 @@ func queueNames(list []*Queue) string {
 
 // list of application and the location of the named applications inside that list
 // place[0] defines the location of the app-0 in the list of applications
+func TestSortAppsDeadline(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{
+		"vcore": resources.Quantity(100)})
+	now := time.Now()
+	input := make(map[string]*Application, 4)
+	for i := 0; i < 4; i++ {
+		num := strconv.Itoa(i)
+		appID := "app-" + num
+		app := newApplication(appID, "partition", "queue")
+		app.pending = res
+		app.submissionTime = time.Unix(int64(i), 0)
+		input[appID] = app
+	}
+
+	// no deadlines: apps should come back in submission order 0, 1, 2, 3
+	list := sortApps(input, policies.DeadlineSortPolicy, false, nil)
+	assertAppList(t, list, []int{0, 1, 2, 3}, "no deadlines")
+
+	// app-3 must start before app-2 to meet its deadline, apps without deadline last
+	input["app-2"].deadline = now.Add(time.Hour)
+	input["app-3"].deadline = now.Add(2 * time.Hour)
+	input["app-3"].estimatedRuntime = 90 * time.Minute
+	list = sortApps(input, policies.DeadlineSortPolicy, false, nil)
+	assertAppList(t, list, []int{2, 3, 1, 0}, "deadlines set")
+
+	// priority is only used before the deadline if considered, otherwise it breaks deadline ties
+	input["app-1"].askMaxPriority = 5
+	list = sortApps(input, policies.DeadlineSortPolicy, false, nil)
+	assertAppList(t, list, []int{3, 2, 1, 0}, "deadline first, priority second")
+	list = sortApps(input, policies.DeadlineSortPolicy, true, nil)
+	assertAppList(t, list, []int{3, 0, 2, 1}, "priority first, deadline second")
+
+	// the latest start of a running app only needs the remaining runtime: app-3 has 10 minutes left
+	input["app-3"].startTime = now.Add(-80 * time.Minute)
+	list = sortApps(input, policies.DeadlineSortPolicy, false, nil)
+	assertAppList(t, list, []int{3, 2, 0, 1}, "running app")
+}
+
 func assertAppList(t *testing.T, list []*Application, place []int, name string) {
 	assert.Equal(t, "app-0", list[place[0]].ApplicationID, "test name: %s", name)
 	assert.Equal(t, "app-1", list[place[1]].ApplicationID, "test name: %s", name)
@@ -414,6 +468,6 @@ func TestSortBySubmissionTime(t *testing.T) {
 		input[appID] = app
 	}
 
-	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
+	list = sortApps(input, policies.FifoSortPolicy, true, nil)
 	assertAppList(t, list, []int{3, 2, 1, 0}, "sort by submission time")
 }
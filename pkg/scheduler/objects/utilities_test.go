@@ -334,7 +334,8 @@ func getNodeIteratorFn(nodes ...*Node) func() NodeIterator {
 	tree := btree.NewBTreeG(nodeRefLess)
 	for _, node := range nodes {
 		tree.Set(nodeRef{
-			node, 1,
+			node:      node,
+			nodeScore: 1,
 		})
 	}
 
@@ -25,6 +25,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/looplab/fsm"
@@ -69,6 +70,12 @@ type PartitionContext struct {
 	foreignAllocs          map[string]*objects.Allocation  // foreign (non-Yunikorn) allocations
 	appQueueMapping        *objects.AppQueueMapping        // appID mapping to queues
 
+	// Scheduling cycles of the partition are serialised by the scheduling lock, not the partition lock.
+	// Pausing the partition takes the scheduling lock: no cycle is running when the pause returns.
+	schedulingLock   locking.Mutex
+	schedulingPaused atomic.Bool  // allocation of asks is paused, updates from the RM are still processed
+	pausedTime       atomic.Int64 // time the scheduling was paused in unix nano
+
 	// The partition write lock must not be held while manipulating an application.
 	// Scheduling is running continuously as a lock free background task. Scheduling an application
 	// acquires a write lock of the application object. While holding the write lock a list of nodes is
@@ -295,6 +302,48 @@ func (pc *PartitionContext) isStopped() bool {
 	return pc.stateMachine.Current() == objects.Stopped.String()
 }
 
+// PauseScheduling stops the allocation of asks in the partition. Waits for a running scheduling cycle to finish.
+// Nodes, applications and asks are still added, updated and removed while the scheduling is paused.
+func (pc *PartitionContext) PauseScheduling() {
+	pc.schedulingLock.Lock()
+	defer pc.schedulingLock.Unlock()
+	if pc.schedulingPaused.Load() {
+		return
+	}
+	pc.pausedTime.Store(time.Now().UnixNano())
+	pc.schedulingPaused.Store(true)
+	log.Log(log.SchedPartition).Info("partition scheduling paused",
+		zap.String("partitionName", pc.Name))
+}
+
+// ResumeScheduling restarts the allocation of asks in the partition after a pause.
+func (pc *PartitionContext) ResumeScheduling() {
+	pc.schedulingLock.Lock()
+	defer pc.schedulingLock.Unlock()
+	if !pc.schedulingPaused.Load() {
+		return
+	}
+	pc.schedulingPaused.Store(false)
+	log.Log(log.SchedPartition).Info("partition scheduling resumed",
+		zap.String("partitionName", pc.Name),
+		zap.Duration("pausedFor", time.Since(pc.GetSchedulingPausedTime())))
+	pc.pausedTime.Store(0)
+}
+
+// IsSchedulingPaused returns true if the allocation of asks is paused for the partition.
+func (pc *PartitionContext) IsSchedulingPaused() bool {
+	return pc.schedulingPaused.Load()
+}
+
+// GetSchedulingPausedTime returns the time the scheduling was paused, zero time if the partition is not paused.
+func (pc *PartitionContext) GetSchedulingPausedTime() time.Time {
+	paused := pc.pausedTime.Load()
+	if paused == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, paused)
+}
+
 // Handle the state event for the partition.
 // The state machine handles the locking.
 func (pc *PartitionContext) handlePartitionEvent(event objects.ObjectEvent) error {
@@ -368,6 +417,10 @@ func (pc *PartitionContext) AddApplication(app *objects.Application) error {
 	if !queue.IsLeafQueue() {
 		return fmt.Errorf("failed to find queue %s for application %s", queueName, appID)
 	}
+	// check the queue: stopped or draining queues do not accept applications
+	if err = queue.CheckAcceptingApplications(); err != nil {
+		return fmt.Errorf("failed to add application %s: %w", appID, err)
+	}
 
 	guaranteedRes := app.GetGuaranteedResource()
 	maxRes := app.GetMaxResource()
@@ -479,6 +532,60 @@ func (pc *PartitionContext) getApplication(appID string) *objects.Application {
 	return pc.applications[appID]
 }
 
+// SuspendApplication suspends a running application in the partition. The partition reservation count is updated
+// for the reservations removed from the application. If release is set all allocations are released back to the RM.
+func (pc *PartitionContext) SuspendApplication(appID string, release bool) error {
+	app := pc.getApplication(appID)
+	if app == nil {
+		return fmt.Errorf("application %s not found in partition %s", appID, pc.Name)
+	}
+	unreserved, err := app.SuspendApplication(release, "application suspended")
+	if err != nil {
+		return err
+	}
+	pc.decReservationCount(unreserved)
+	log.Log(log.SchedPartition).Info("application suspended",
+		zap.String("appID", appID),
+		zap.Bool("release", release),
+		zap.Int("reservationsRemoved", unreserved))
+	return nil
+}
+
+// ResumeApplication resumes a suspended application in the partition.
+func (pc *PartitionContext) ResumeApplication(appID string) error {
+	app := pc.getApplication(appID)
+	if app == nil {
+		return fmt.Errorf("application %s not found in partition %s", appID, pc.Name)
+	}
+	if err := app.ResumeSuspendedApplication("application resumed"); err != nil {
+		return err
+	}
+	log.Log(log.SchedPartition).Info("application resumed",
+		zap.String("appID", appID))
+	return nil
+}
+
+// MoveApplication moves an application to another leaf queue in the partition.
+// The application is re-registered in the application to queue mapping after a successful move.
+// The partition lock is held for the whole move: the target queue cannot be removed by a config reload.
+func (pc *PartitionContext) MoveApplication(appID, queuePath string) error {
+	pc.Lock()
+	defer pc.Unlock()
+	app := pc.applications[appID]
+	if app == nil {
+		return fmt.Errorf("application %s not found in partition %s", appID, pc.Name)
+	}
+	queue := pc.getQueueInternal(queuePath)
+	if queue == nil {
+		return fmt.Errorf("queue %s not found in partition %s", queuePath, pc.Name)
+	}
+	if err := app.MoveToQueue(queue); err != nil {
+		return err
+	}
+	pc.appQueueMapping.AddAppQueueMapping(appID, queue)
+	return nil
+}
+
 func (pc *PartitionContext) getRejectedApplication(appID string) *objects.Application {
 	pc.RLock()
 	defer pc.RUnlock()
@@ -834,13 +941,32 @@ func (pc *PartitionContext) tryAllocate() *objects.AllocationResult {
 		return nil
 	}
 	// try allocating from the root down
-	result := pc.root.TryAllocate(pc.GetNodeIterator, pc.GetFullNodeIterator, pc.GetNode, pc.IsPreemptionEnabled())
+	result := pc.root.TryAllocate(pc.GetNodeIterator, pc.GetFullNodeIterator, pc.GetReservedNodeIterator, pc.GetNode, pc.IsPreemptionEnabled())
 	if result != nil {
 		return pc.allocate(result)
 	}
 	return nil
 }
 
+// Try regular allocation for the partition in batch mode: allocations continue in one pass over the
+// sorted queues until the maximum number of allocations is reached or the time budget runs out.
+// Lock free call this all locks are taken when needed in called functions
+func (pc *PartitionContext) tryBatchAllocate(maxAllocations int, budget time.Duration) []*objects.AllocationResult {
+	if !resources.StrictlyGreaterThanZero(pc.root.GetPendingResource()) {
+		// nothing to do just return
+		return nil
+	}
+	deadline := time.Now().Add(budget)
+	var results []*objects.AllocationResult
+	pc.root.TryAllocateBatch(pc.GetNodeIterator, pc.GetFullNodeIterator, pc.GetReservedNodeIterator, pc.GetNode, pc.IsPreemptionEnabled(), func(result *objects.AllocationResult) bool {
+		if result = pc.allocate(result); result != nil {
+			results = append(results, result)
+		}
+		return len(results) < maxAllocations && time.Now().Before(deadline)
+	})
+	return results
+}
+
 // Try process reservations for the partition
 // Lock free call this all locks are taken when needed in called functions
 func (pc *PartitionContext) tryReservedAllocate() *objects.AllocationResult {
@@ -872,6 +998,9 @@ func (pc *PartitionContext) tryPlaceholderAllocate() *objects.AllocationResult {
 	// try allocating from the root down
 	result := pc.root.TryPlaceholderAllocate(pc.GetNodeIterator, pc.GetNode)
 	if result != nil {
+		if app := pc.getApplication(result.Request.GetApplicationID()); app != nil {
+			observeAskWait(app, result.Request)
+		}
 		log.Log(log.SchedPartition).Info("scheduler replace placeholder processed",
 			zap.String("appID", result.Request.GetApplicationID()),
 			zap.String("allocationKey", result.Request.GetAllocationKey()),
@@ -958,6 +1087,7 @@ func (pc *PartitionContext) allocate(result *objects.AllocationResult) *objects.
 	alloc.SetBindTime(time.Now())
 	alloc.SetNodeID(targetNodeID)
 	alloc.SetInstanceType(targetNode.GetInstanceType())
+	observeAskWait(app, alloc)
 
 	// track the number of allocations
 	pc.updateAllocationCount(1)
@@ -970,11 +1100,21 @@ func (pc *PartitionContext) allocate(result *objects.AllocationResult) *objects.
 		zap.String("allocationKey", result.Request.GetAllocationKey()),
 		zap.Stringer("allocatedResource", result.Request.GetAllocatedResource()),
 		zap.Bool("placeholder", result.Request.IsPlaceholder()),
+		zap.Bool("backfill", result.Request.IsBackfill()),
 		zap.String("targetNode", targetNodeID))
 	// pass the allocation result back to the RM via the cluster context
 	return result
 }
 
+// observeAskWait records the time the ask waited to be allocated. Placeholders are not tracked: for a gang
+// the wait ends when the real ask replaces the placeholder.
+func observeAskWait(app *objects.Application, ask *objects.Allocation) {
+	if ask.IsPlaceholder() {
+		return
+	}
+	metrics.GetLatencyMetrics().ObserveAskWait(app.GetQueuePath(), app.GetUser().User, ask.GetBindTime().Sub(ask.GetCreateTime()))
+}
+
 // Process the reservation in the scheduler
 // Lock free call this must be called holding the context lock
 func (pc *PartitionContext) reserve(app *objects.Application, node *objects.Node, ask *objects.Allocation) {
@@ -1047,6 +1187,12 @@ func (pc *PartitionContext) GetFullNodeIterator() objects.NodeIterator {
 	return pc.nodes.GetFullNodeIterator()
 }
 
+// GetReservedNodeIterator returns a node iterator with only the reserved nodes ordered based on the node sort
+// policy set for this partition.
+func (pc *PartitionContext) GetReservedNodeIterator() objects.NodeIterator {
+	return pc.nodes.GetReservedNodeIterator()
+}
+
 // Updated the allocations counter for the partition
 func (pc *PartitionContext) updateAllocationCount(allocs int) {
 	pc.Lock()
@@ -1226,6 +1372,9 @@ func (pc *PartitionContext) UpdateAllocation(alloc *objects.Allocation) (bool, b
 	if existing == nil {
 		// new request
 		if node == nil {
+			if err := queue.CheckAcceptingAsks(); err != nil {
+				return false, false, fmt.Errorf("failed to add ask %s for application %s: %w", allocationKey, applicationID, err)
+			}
 			log.Log(log.SchedPartition).Info("handling new request",
 				zap.String("partitionName", pc.Name),
 				zap.String("appID", applicationID),
@@ -1425,9 +1574,25 @@ func (pc *PartitionContext) getOrStoreForeignAlloc(alloc *objects.Allocation) bo
 //
 // NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
 func (pc *PartitionContext) calculateNodesResourceUsage() map[string][]int {
+	return pc.calculateNodesResourceUsageByGroup("")[""]
+}
+
+// calculateNodesResourceUsageByGroup returns the number of nodes in each of the 10 utilisation ranges per node group
+// and resource type. The nodes are grouped by the value of the node attribute: nodes without the attribute, or all
+// nodes if the attribute is empty, are in the group "".
+func (pc *PartitionContext) calculateNodesResourceUsageByGroup(attribute string) map[string]map[string][]int {
 	nodesCopy := pc.GetNodes()
-	mapResult := make(map[string][]int)
+	mapResult := make(map[string]map[string][]int)
 	for _, node := range nodesCopy {
+		group := ""
+		if attribute != "" {
+			group = node.GetAttribute(attribute)
+		}
+		groupResult, ok := mapResult[group]
+		if !ok {
+			groupResult = make(map[string][]int)
+			mapResult[group] = groupResult
+		}
 		capacity := node.GetCapacity()
 		allocated := node.GetAllocatedResource()
 		for name, total := range capacity.Resources {
@@ -1436,16 +1601,10 @@ func (pc *PartitionContext) calculateNodesResourceUsage() map[string][]int {
 				// Consider over-allocated node as 100% utilized.
 				v := math.Min(resourceAllocated/float64(total), 1)
 				idx := int(math.Dim(math.Ceil(v*10), 1))
-				if dist, ok := mapResult[name]; !ok {
-					newDist := make([]int, 10)
-					for i := range newDist {
-						newDist[i] = 0
-					}
-					mapResult[name] = newDist
-					mapResult[name][idx]++
-				} else {
-					dist[idx]++
+				if _, ok = groupResult[name]; !ok {
+					groupResult[name] = make([]int, 10)
 				}
+				groupResult[name][idx]++
 			}
 		}
 	}
@@ -110,7 +110,7 @@ func (manager *partitionManager) cleanQueues(queue *objects.Queue) {
 		}
 	}
 	// when we have done the children (or have none) this queue might be removable
-	if queue.IsDraining() || !queue.IsManaged() {
+	if queue.IsMarkedForRemoval() || !queue.IsManaged() {
 		log.Log(log.SchedPartition).Debug("removing queue",
 			zap.String("queueName", queue.QueuePath),
 			zap.String("partitionName", manager.pc.Name))
@@ -102,3 +102,21 @@ func TestRemoveAll(t *testing.T) {
 	assert.Equal(t, 0, len(p.applications))
 	assert.Equal(t, 0, p.nodes.GetNodeCount())
 }
+
+func TestCleanQueuesAdminDrained(t *testing.T) {
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "partition create failed")
+	defer partition.userGroupCache.Stop()
+
+	// a managed queue drained by an admin is kept
+	queue := partition.GetQueue(defQueue)
+	assert.Assert(t, queue != nil, "queue not found")
+	assert.NilError(t, queue.DrainQueue(), "failed to drain queue")
+	partition.partitionManager.cleanQueues(partition.root)
+	assert.Assert(t, partition.GetQueue(defQueue) != nil, "admin drained queue should not have been removed")
+
+	// a managed queue removed from the config is removed
+	queue.MarkQueueForRemoval()
+	partition.partitionManager.cleanQueues(partition.root)
+	assert.Assert(t, partition.GetQueue(defQueue) == nil, "queue marked for removal should have been removed")
+}
@@ -0,0 +1,157 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+)
+
+// partitionScheduler runs the scheduling loop of a single partition in the parallel partition mode.
+// Each loop has its own activity signal so a busy partition does not delay the allocations in other partitions.
+type partitionScheduler struct {
+	cc              *ClusterContext
+	partition       *PartitionContext
+	name            string        // partition name without the cluster ID used in the metrics
+	activityPending chan bool     // activity pending channel
+	stop            chan struct{} // channel to signal stop request
+	done            chan struct{} // closed when the loop has exited
+	lastCycle       atomic.Int64  // end of the last scheduling cycle in unix nano
+}
+
+func newPartitionScheduler(cc *ClusterContext, partition *PartitionContext) *partitionScheduler {
+	ps := &partitionScheduler{
+		cc:              cc,
+		partition:       partition,
+		name:            common.GetPartitionNameWithoutClusterID(partition.Name),
+		activityPending: make(chan bool, 1),
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+	ps.lastCycle.Store(time.Now().UnixNano())
+	return ps
+}
+
+// start the scheduling loop, the loop exits when the partition scheduler or the scheduler is stopped
+func (ps *partitionScheduler) start(schedulerStop <-chan struct{}) {
+	log.Log(log.Scheduler).Info("Starting partition scheduling loop",
+		zap.String("partition", ps.partition.Name))
+	go func() {
+		defer close(ps.done)
+		for {
+			select {
+			case <-ps.stop:
+				return
+			case <-schedulerStop:
+				return
+			case <-ps.activityPending:
+				// activity pending
+			case <-time.After(100 * time.Millisecond):
+				// timeout, run scheduler anyway
+			}
+			cycleStart := time.Now()
+			activity := ps.cc.schedulePartition(ps.partition)
+			metrics.GetSchedulerMetrics().ObservePartitionSchedulingCycle(ps.name, cycleStart)
+			ps.lastCycle.Store(time.Now().UnixNano())
+			if activity {
+				ps.registerActivity()
+			}
+		}
+	}()
+}
+
+// stopAndWait stops the loop and waits for a running scheduling cycle to finish.
+// The partition must not be scheduled by two go routines at the same time.
+func (ps *partitionScheduler) stopAndWait() {
+	log.Log(log.Scheduler).Info("Stopping partition scheduling loop",
+		zap.String("partition", ps.partition.Name))
+	close(ps.stop)
+	<-ps.done
+	metrics.GetSchedulerMetrics().RemovePartitionSchedulingCycle(ps.name)
+}
+
+func (ps *partitionScheduler) registerActivity() {
+	select {
+	case ps.activityPending <- true:
+		// activity registered
+	default:
+		// buffer is full, activity will be processed at the next available opportunity
+	}
+}
+
+func (ps *partitionScheduler) getLastCycle() time.Time {
+	return time.Unix(0, ps.lastCycle.Load())
+}
+
+func isParallelPartitions() bool {
+	return common.GetConfigurationBool(configs.GetConfigMap(), configs.CMSchedulingParallelPartitions, configs.DefaultParallelPartitions)
+}
+
+// syncPartitionSchedulers starts a loop for each new partition and stops the loops of removed partitions.
+// The last scheduling cycle of the cluster is set to the oldest cycle of all loops to detect a stalled partition.
+func (s *Scheduler) syncPartitionSchedulers() {
+	partitions := s.clusterContext.GetPartitionMapClone()
+	s.partitionLoopsLock.Lock()
+	defer s.partitionLoopsLock.Unlock()
+	for name, ps := range s.partitionLoops {
+		if partitions[name] != ps.partition {
+			ps.stopAndWait()
+			delete(s.partitionLoops, name)
+		}
+	}
+	oldest := time.Now()
+	for name, psc := range partitions {
+		ps, ok := s.partitionLoops[name]
+		if !ok {
+			ps = newPartitionScheduler(s.clusterContext, psc)
+			ps.start(s.stop)
+			s.partitionLoops[name] = ps
+		}
+		if last := ps.getLastCycle(); last.Before(oldest) {
+			oldest = last
+		}
+	}
+	s.clusterContext.lastScheduleCycle.Store(oldest.UnixNano())
+}
+
+// stopPartitionSchedulers stops all partition loops, used when the parallel mode is switched off
+func (s *Scheduler) stopPartitionSchedulers() {
+	s.partitionLoopsLock.Lock()
+	defer s.partitionLoopsLock.Unlock()
+	for name, ps := range s.partitionLoops {
+		ps.stopAndWait()
+		delete(s.partitionLoops, name)
+	}
+}
+
+// registerPartitionActivity signals all partition loops
+func (s *Scheduler) registerPartitionActivity() {
+	s.partitionLoopsLock.RLock()
+	defer s.partitionLoopsLock.RUnlock()
+	for _, ps := range s.partitionLoops {
+		ps.registerActivity()
+	}
+}
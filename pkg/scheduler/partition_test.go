@@ -402,6 +402,39 @@ func TestCalculateNodesResourceUsage(t *testing.T) {
 	assert.Equal(t, usageMap["first"][9], 1)
 }
 
+func TestCalculateNodesResourceUsageByGroup(t *testing.T) {
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "partition create failed")
+	defer partition.userGroupCache.Stop()
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 100})
+	zones := map[string]string{nodeID1: "zone-a", nodeID2: "zone-a", "node-3": "zone-b", "node-4": ""}
+	for nodeID, zone := range zones {
+		proto := &si.NodeInfo{
+			NodeID:              nodeID,
+			Attributes:          map[string]string{},
+			SchedulableResource: capacity.ToProto(),
+		}
+		if zone != "" {
+			proto.Attributes["zone"] = zone
+		}
+		err = partition.AddNode(objects.NewNode(proto))
+		assert.NilError(t, err)
+	}
+	partition.GetNode(nodeID1).AddAllocation(newAllocation("key", "appID", nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 50})))
+
+	usageMap := partition.calculateNodesResourceUsageByGroup("zone")
+	assert.Equal(t, 3, len(usageMap), "expected 2 zones and the group without zone")
+	assert.DeepEqual(t, []int{1, 0, 0, 0, 1, 0, 0, 0, 0, 0}, usageMap["zone-a"]["first"])
+	assert.DeepEqual(t, []int{1, 0, 0, 0, 0, 0, 0, 0, 0, 0}, usageMap["zone-b"]["first"])
+	assert.DeepEqual(t, []int{1, 0, 0, 0, 0, 0, 0, 0, 0, 0}, usageMap[""]["first"])
+
+	// not grouped
+	usageMap = partition.calculateNodesResourceUsageByGroup("")
+	assert.Equal(t, 1, len(usageMap))
+	assert.DeepEqual(t, []int{3, 0, 0, 0, 1, 0, 0, 0, 0, 0}, usageMap[""]["first"])
+	assert.DeepEqual(t, usageMap[""], mergeNodeGroups(partition.calculateNodesResourceUsageByGroup("zone")))
+}
+
 // test basic placeholder preemption
 // setup:
 // queue quota max size: 16GB / 16cpu
@@ -1647,6 +1680,8 @@ func TestGetQueue(t *testing.T) {
 
 func TestTryAllocate(t *testing.T) {
 	setupUGM()
+	metrics.GetLatencyMetrics().Reset()
+	defer metrics.GetLatencyMetrics().Reset()
 	partition := createQueuesNodes(t)
 	assert.Assert(t, partition != nil, "partition create failed")
 	defer partition.userGroupCache.Stop()
@@ -1721,6 +1756,124 @@ func TestTryAllocate(t *testing.T) {
 	assert.Equal(t, result.Request.GetAllocationKey(), allocKey, "expected ask alloc-1 to be allocated")
 	assert.Assert(t, resources.IsZero(partition.root.GetPendingResource()), "pending resources should be set to zero")
 	assertUserGroupResourceMaxLimits(t, getTestUserGroup(), resources.Multiply(res, 3), expectedQueuesMaxLimits)
+
+	// the wait of each allocated ask is recorded for its queue
+	count, err := metrics.GetLatencyMetrics().GetAskWaitCount("root.parent.sub-leaf", "")
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count, "ask wait not recorded for app-1")
+	count, err = metrics.GetLatencyMetrics().GetAskWaitCount("root.leaf", "")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count, "ask wait not recorded for app-2")
+}
+
+func TestQueueAdminStateApplicationsAndAsks(t *testing.T) {
+	setupUGM()
+	partition := createQueuesNodes(t)
+	assert.Assert(t, partition != nil, "partition create failed")
+	defer partition.userGroupCache.Stop()
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "1"})
+	assert.NilError(t, err, "failed to create resource")
+	app := newApplication(appID1, "default", "root.parent.sub-leaf")
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	_, _, err = partition.UpdateAllocation(newAllocationAsk(allocKey, appID1, res))
+	assert.NilError(t, err, "failed to add ask alloc-1 to app-1")
+
+	// stopped parent: no new applications or asks, nothing is scheduled
+	parent := partition.GetQueue("root.parent")
+	assert.NilError(t, parent.StopQueue(), "failed to stop queue")
+	// placement skips the queue that does not accept applications
+	err = partition.AddApplication(newApplication(appID2, "default", "root.parent.sub-leaf"))
+	assert.ErrorContains(t, err, "no placement rule matched", "application should have been rejected")
+	_, _, err = partition.UpdateAllocation(newAllocationAsk(allocKey2, appID1, res))
+	assert.ErrorContains(t, err, "root.parent is stopped", "ask should have been rejected")
+	assert.Assert(t, partition.tryAllocate() == nil, "stopped queue should not be scheduled")
+
+	// drained parent: no new asks, the pending asks of the running application are scheduled
+	assert.NilError(t, parent.DrainQueue(), "failed to drain queue")
+	_, _, err = partition.UpdateAllocation(newAllocationAsk(allocKey2, appID1, res))
+	assert.ErrorContains(t, err, "root.parent is draining", "ask should have been rejected")
+	result := partition.tryAllocate()
+	assert.Assert(t, result != nil && result.Request.GetAllocationKey() == allocKey, "pending ask should be scheduled in a draining queue")
+
+	// resumed parent accepts new work again
+	assert.NilError(t, parent.ResumeQueue(), "failed to resume queue")
+	err = partition.AddApplication(newApplication(appID2, "default", "root.parent.sub-leaf"))
+	assert.NilError(t, err, "application should have been accepted")
+	_, _, err = partition.UpdateAllocation(newAllocationAsk(allocKey2, appID1, res))
+	assert.NilError(t, err, "ask should have been accepted")
+}
+
+func TestTryBatchAllocate(t *testing.T) {
+	setupUGM()
+	partition := createQueuesNodes(t)
+	assert.Assert(t, partition != nil, "partition create failed")
+	defer partition.userGroupCache.Stop()
+
+	if results := partition.tryBatchAllocate(10, time.Second); len(results) != 0 {
+		t.Fatalf("empty cluster batch allocate returned allocations: %d", len(results))
+	}
+
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "1"})
+	assert.NilError(t, err, "failed to create resource")
+	app := newApplication(appID1, "default", "root.parent.sub-leaf")
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	err = app.AddAllocationAsk(newAllocationAsk(allocKey, appID1, res))
+	assert.NilError(t, err, "failed to add ask alloc-1 to app-1")
+	err = app.AddAllocationAsk(newAllocationAskPriority(allocKey2, appID1, res, 2))
+	assert.NilError(t, err, "failed to add ask alloc-2 to app-1")
+	app = newApplication(appID2, "default", "root.leaf")
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-2 to partition")
+	err = app.AddAllocationAsk(newAllocationAskPriority(allocKey, appID2, res, 2))
+	assert.NilError(t, err, "failed to add ask alloc-1 to app-2")
+
+	// the batch stays with the sorted queue and app: both asks of app-1 are allocated before app-2
+	results := partition.tryBatchAllocate(2, time.Second)
+	assert.Equal(t, len(results), 2, "batch size not respected")
+	assert.Equal(t, results[0].Request.GetApplicationID(), appID1, "expected application app-1 to be allocated")
+	assert.Equal(t, results[0].Request.GetAllocationKey(), allocKey2, "expected ask alloc-2 to be allocated first")
+	assert.Equal(t, results[1].Request.GetApplicationID(), appID1, "expected application app-1 to be allocated")
+	assert.Equal(t, results[1].Request.GetAllocationKey(), allocKey, "expected ask alloc-1 to be allocated second")
+	for _, result := range results {
+		assert.Equal(t, result.ResultType, objects.Allocated, "result type is not the expected allocated")
+		assert.Assert(t, result.Request.IsAllocated(), "ask should be allocated")
+		assert.Assert(t, result.NodeID != "", "node not set on the result")
+	}
+
+	// the next batch picks up the remainder
+	results = partition.tryBatchAllocate(10, time.Second)
+	assert.Equal(t, len(results), 1, "expected the remaining ask to be allocated")
+	assert.Equal(t, results[0].Request.GetApplicationID(), appID2, "expected application app-2 to be allocated")
+	assert.Assert(t, resources.IsZero(partition.root.GetPendingResource()), "pending resources should be set to zero")
+	assert.Equal(t, len(partition.GetApplication(appID1).GetAllAllocations()), 2, "allocations not tracked on app-1")
+}
+
+func TestTryBatchAllocateHeadroom(t *testing.T) {
+	setupUGM()
+	partition, err := newLimitedPartition(map[string]string{"vcore": "3"})
+	assert.NilError(t, err, "partition create failed")
+	defer partition.userGroupCache.Stop()
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "1"})
+	assert.NilError(t, err, "failed to create resource")
+	err = partition.AddNode(newNodeMaxResource(nodeID1, resources.Multiply(res, 10)))
+	assert.NilError(t, err, "test node add failed unexpected")
+
+	leaf := partition.GetQueue("root.limited")
+	app := newApplication(appID1, "default", "root.limited")
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	for i := 0; i < 5; i++ {
+		err = app.AddAllocationAsk(newAllocationAsk("alloc-"+strconv.Itoa(i), appID1, res))
+		assert.NilError(t, err, "failed to add ask to app-1")
+	}
+
+	// the headroom is updated after each allocation in the batch: only 3 fit in the queue
+	results := partition.tryBatchAllocate(10, time.Second)
+	assert.Equal(t, len(results), 3, "headroom not respected in batch")
+	assert.Assert(t, resources.Equals(leaf.GetAllocatedResource(), resources.Multiply(res, 3)), "unexpected allocated resource on the queue")
+	assert.Equal(t, len(partition.tryBatchAllocate(10, time.Second)), 0, "queue is full, nothing should be allocated")
 }
 
 // allocate ask request with required node
@@ -2578,6 +2731,65 @@ func TestTryAllocateReserve(t *testing.T) {
 	}
 }
 
+func TestTryAllocateBackfill(t *testing.T) {
+	setupUGM()
+	partition := createQueuesNodes(t)
+	assert.Assert(t, partition != nil, "partition create failed")
+	defer partition.userGroupCache.Stop()
+
+	app := newApplication(appID1, "default", "root.parent.sub-leaf")
+	err := partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	durationTag := func(duration string) map[string]string {
+		return map[string]string{siCommon.DomainYuniKorn + common.KeyExpectedDuration: duration}
+	}
+
+	// one node with a running allocation expected to finish in 10 minutes, the other node full
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "6"})
+	assert.NilError(t, err, "failed to create resource")
+	err = app.AddAllocationAsk(newAllocationAskAll("running", appID1, "", res, 0, false, durationTag("10m")))
+	assert.NilError(t, err, "failed to add ask running to app")
+	result := partition.tryAllocate()
+	assert.Assert(t, result != nil && result.ResultType == objects.Allocated, "running ask should have been allocated")
+	reservedNode := partition.GetNode(result.NodeID)
+	res, err = resources.NewResourceFromConf(map[string]string{"vcore": "10"})
+	assert.NilError(t, err, "failed to create resource")
+	err = app.AddAllocationAsk(newAllocationAsk("full", appID1, res))
+	assert.NilError(t, err, "failed to add ask full to app")
+	result = partition.tryAllocate()
+	assert.Assert(t, result != nil && result.ResultType == objects.Allocated, "full ask should have been allocated")
+	assert.Assert(t, result.NodeID != reservedNode.NodeID, "full ask should have been allocated on the other node")
+
+	// reserve the node with the running allocation
+	res, err = resources.NewResourceFromConf(map[string]string{"vcore": "8"})
+	assert.NilError(t, err, "failed to create resource")
+	large := newAllocationAsk("large", appID1, res)
+	err = app.AddAllocationAsk(large)
+	assert.NilError(t, err, "failed to add ask large to app")
+	partition.reserve(app, reservedNode, large)
+	assert.Equal(t, app.NodeReservedForAsk("large"), reservedNode.NodeID, "reservation failure for large ask")
+
+	// an ask that runs longer than the reserved ask has to wait is not backfilled
+	res, err = resources.NewResourceFromConf(map[string]string{"vcore": "2"})
+	assert.NilError(t, err, "failed to create resource")
+	err = app.AddAllocationAsk(newAllocationAskAll("long", appID1, "", res, 0, false, durationTag("20m")))
+	assert.NilError(t, err, "failed to add ask long to app")
+	result = partition.tryAllocate()
+	assert.Assert(t, result == nil, "long ask should not have been backfilled: %s", result)
+
+	// a short ask is backfilled on the reserved node
+	err = app.AddAllocationAsk(newAllocationAskAll("short", appID1, "", res, 0, false, durationTag("5m")))
+	assert.NilError(t, err, "failed to add ask short to app")
+	result = partition.tryAllocate()
+	assert.Assert(t, result != nil && result.Request != nil, "short ask should have been backfilled")
+	assert.Equal(t, result.ResultType, objects.Allocated, "result type is not the expected allocated")
+	assert.Equal(t, result.Request.GetAllocationKey(), "short", "expected ask short to be allocated")
+	assert.Equal(t, result.NodeID, reservedNode.NodeID, "short ask should have been allocated on the reserved node")
+	assert.Assert(t, result.Request.IsBackfill(), "allocation should be marked as backfill")
+	assert.Assert(t, !result.Request.GetExpectedEndTime().IsZero(), "backfill allocation should have an expected end time")
+	assert.Equal(t, app.NodeReservedForAsk("large"), reservedNode.NodeID, "reservation should not have been removed")
+}
+
 func TestTryAllocateWithReserved(t *testing.T) {
 	setupUGM()
 	partition := createQueuesNodes(t)
@@ -5600,3 +5812,101 @@ func TestRemoveAppWithReservations(t *testing.T) {
 	partition.removeApplication(appID1)
 	assert.Equal(t, 0, partition.getReservationCount())
 }
+
+func TestSuspendResumeApplication(t *testing.T) {
+	setupUGM()
+	partition := createQueuesNodes(t)
+	assert.Assert(t, partition != nil, "partition create failed")
+	defer partition.userGroupCache.Stop()
+
+	err := partition.SuspendApplication(appID1, false)
+	assert.ErrorContains(t, err, "not found")
+	err = partition.ResumeApplication(appID1)
+	assert.ErrorContains(t, err, "not found")
+
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "1"})
+	assert.NilError(t, err, "failed to create resource")
+	app := newApplication(appID1, "default", "root.parent.sub-leaf")
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	err = app.AddAllocationAsk(newAllocationAsk(allocKey, appID1, res))
+	assert.NilError(t, err, "failed to add ask alloc-1 to app-1")
+	ask := newAllocationAsk("alloc-2", appID1, res)
+	err = app.AddAllocationAsk(ask)
+	assert.NilError(t, err, "failed to add ask alloc-2 to app-1")
+
+	// an accepted app cannot be suspended
+	err = partition.SuspendApplication(appID1, false)
+	assert.ErrorContains(t, err, "only running applications can be suspended")
+	result := partition.tryAllocate()
+	assert.Assert(t, result != nil && result.ResultType == objects.Allocated, "expected allocation for app-1")
+	assert.Assert(t, app.IsRunning(), "app-1 should be running")
+	node2 := partition.GetNode(nodeID2)
+	partition.reserve(app, node2, ask)
+	assert.Equal(t, partition.getReservationCount(), 1, "expected a reservation")
+
+	// suspended app keeps the allocation and the ask but is not scheduled
+	err = partition.SuspendApplication(appID1, false)
+	assert.NilError(t, err, "suspend should not have failed")
+	assert.Assert(t, app.IsSuspended(), "app-1 should be suspended")
+	assert.Equal(t, partition.getReservationCount(), 0, "reservation should have been removed")
+	assert.Assert(t, resources.Equals(app.GetPendingResource(), res), "ask should still be pending")
+	assert.Assert(t, partition.tryAllocate() == nil, "suspended app should not be allocated")
+	assert.Assert(t, partition.tryReservedAllocate() == nil, "suspended app should not have reserved allocations")
+
+	err = partition.ResumeApplication(appID1)
+	assert.NilError(t, err, "resume should not have failed")
+	assert.Assert(t, app.IsRunning(), "app-1 should be running after resume")
+	result = partition.tryAllocate()
+	assert.Assert(t, result != nil && result.ResultType == objects.Allocated, "expected allocation for resumed app-1")
+	assert.Equal(t, result.Request.GetAllocationKey(), "alloc-2")
+}
+
+func TestMoveApplication(t *testing.T) {
+	setupUGM()
+	partition := createQueuesNodes(t)
+	assert.Assert(t, partition != nil, "partition create failed")
+	defer partition.userGroupCache.Stop()
+
+	err := partition.MoveApplication(appID1, "root.leaf")
+	assert.ErrorContains(t, err, "not found")
+
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "1"})
+	assert.NilError(t, err, "failed to create resource")
+	app1 := newApplication(appID1, "default", "root.parent.sub-leaf")
+	err = partition.AddApplication(app1)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	err = app1.AddAllocationAsk(newAllocationAsk(allocKey, appID1, res))
+	assert.NilError(t, err, "failed to add ask alloc-1 to app-1")
+	app2 := newApplication(appID2, "default", "root.parent.sub-leaf")
+	err = partition.AddApplication(app2)
+	assert.NilError(t, err, "failed to add app-2 to partition")
+	err = app2.AddAllocationAsk(newAllocationAsk("alloc-2", appID2, res))
+	assert.NilError(t, err, "failed to add ask alloc-2 to app-2")
+	for range 2 {
+		result := partition.tryAllocate()
+		assert.Assert(t, result != nil && result.ResultType == objects.Allocated, "expected allocation")
+	}
+
+	err = partition.MoveApplication(appID1, "root.unknown")
+	assert.ErrorContains(t, err, "queue root.unknown not found")
+	err = partition.MoveApplication(appID1, "root.parent")
+	assert.ErrorContains(t, err, "is not a leaf queue")
+
+	err = partition.MoveApplication(appID1, "root.leaf")
+	assert.NilError(t, err, "move should not have failed")
+	leaf := partition.GetQueue("root.leaf")
+	subLeaf := partition.GetQueue("root.parent.sub-leaf")
+	assert.Equal(t, app1.GetQueuePath(), "root.leaf", "app-1 queue path not updated")
+	assert.Equal(t, partition.appQueueMapping.GetQueueByAppId(appID1), leaf, "app-1 queue mapping not updated")
+	assert.Assert(t, resources.Equals(leaf.GetAllocatedResource(), res), "leaf allocated resources not updated")
+	assert.Assert(t, resources.Equals(subLeaf.GetAllocatedResource(), res), "sub-leaf allocated resources not updated")
+
+	// the user limit on root.leaf allows only one application
+	err = partition.MoveApplication(appID2, "root.leaf")
+	assert.ErrorContains(t, err, "has reached the maximum applications")
+	assert.Equal(t, app2.GetQueuePath(), "root.parent.sub-leaf", "app-2 should not have moved")
+	assert.Equal(t, partition.appQueueMapping.GetQueueByAppId(appID2), subLeaf, "app-2 queue mapping should not change")
+	assert.Assert(t, resources.Equals(subLeaf.GetAllocatedResource(), res), "sub-leaf allocated resources should not change")
+	assert.Assert(t, resources.Equals(ugm.GetUserManager().GetUserResources("testuser"), resources.Multiply(res, 2)), "user usage should not change")
+}
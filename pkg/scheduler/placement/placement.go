@@ -188,12 +188,13 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 				queueName = ""
 				continue
 			}
-			// Check if the queue in Draining state, and if so, proceed to the next rule
-			if queue.IsDraining() {
-				log.Log(log.SchedApplication).Debug("Cannot place application in draining queue",
+			// Check if the queue is draining or stopped, and if so, proceed to the next rule
+			if err := queue.CheckAcceptingApplications(); err != nil {
+				log.Log(log.SchedApplication).Debug("Cannot place application in queue",
 					zap.String("queueName", queueName),
 					zap.String("ruleName", checkRule.getName()),
-					zap.String("application", app.ApplicationID))
+					zap.String("application", app.ApplicationID),
+					zap.Error(err))
 				// reset the queue name for the last rule in the chain
 				queueName = ""
 				continue
@@ -31,11 +31,12 @@ const (
 	FifoSortPolicy             SortPolicy = iota // first in first out, submit time
 	FairSortPolicy                               // fair based on usage
 	deprecatedStateAwarePolicy                   // deprecated: now alias for FIFO
+	DeadlineSortPolicy                           // earliest latest start time based on the application deadline
 	Undefined                                    // not initialised or parsing failed
 )
 
 func (s SortPolicy) String() string {
-	return [...]string{"fifo", "fair", "stateaware", "undefined"}[s]
+	return [...]string{"fifo", "fair", "stateaware", "deadline", "undefined"}[s]
 }
 
 func SortPolicyFromString(str string) (SortPolicy, error) {
@@ -45,6 +46,8 @@ func SortPolicyFromString(str string) (SortPolicy, error) {
 		return FifoSortPolicy, nil
 	case FairSortPolicy.String():
 		return FairSortPolicy, nil
+	case DeadlineSortPolicy.String():
+		return DeadlineSortPolicy, nil
 	case deprecatedStateAwarePolicy.String():
 		log.Log(log.Deprecation).Warn("Sort policy 'stateaware' is deprecated; using 'fifo' instead")
 		return FifoSortPolicy, nil
@@ -32,6 +32,7 @@ func TestAppFromString(t *testing.T) {
 		{"EmptyString", "", FifoSortPolicy, false},
 		{"FifoString", "fifo", FifoSortPolicy, false},
 		{"FairString", "fair", FairSortPolicy, false},
+		{"DeadlineString", "deadline", DeadlineSortPolicy, false},
 		{"StatusString", "stateaware", FifoSortPolicy, false},
 		{"UnknownString", "unknown", Undefined, true},
 	}
@@ -57,6 +58,7 @@ func TestAppToString(t *testing.T) {
 		{"FifoString", FifoSortPolicy, "fifo"},
 		{"FairString", FairSortPolicy, "fair"},
 		{"StatusString", deprecatedStateAwarePolicy, "stateaware"},
+		{"DeadlineString", DeadlineSortPolicy, "deadline"},
 		{"DefaultString", Undefined, "undefined"},
 		{"NoneString", someSP, "fifo"},
 	}
@@ -19,19 +19,32 @@
 package scheduler
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/handler"
+	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/plugins"
 	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
+const (
+	// overload modes for new asks when the allocation event queue use is above the threshold
+	OverloadModeReject = "reject"
+	OverloadModeDelay  = "delay"
+)
+
 // Scheduler service that starts the needed sub services
 type Scheduler struct {
 	clusterContext     *ClusterContext  // main context
@@ -42,6 +55,21 @@ type Scheduler struct {
 	stop               chan struct{}    // channel to signal stop request
 	healthChecker      *HealthChecker
 	nodesMonitor       *nodesResourceUsageMonitor
+
+	partitionLoops     map[string]*partitionScheduler // loop per partition in the parallel partition mode
+	partitionLoopsLock locking.RWMutex
+
+	delayedAsks     []*delayedAsks // asks held back by the overload delay mode in arrival order
+	delayedPending  chan struct{}  // signals new delayed asks
+	delayedAsksLock locking.Mutex
+}
+
+// delayedAsks are the new asks from one allocation event held back by the overload delay mode
+type delayedAsks struct {
+	event     *rmevent.RMUpdateAllocationEvent
+	start     time.Time // time the event was received
+	deadline  time.Time // the asks are enqueued at this time even if the queue is still overloaded
+	threshold uint64
 }
 
 func NewScheduler() *Scheduler {
@@ -57,6 +85,8 @@ func NewScheduler() *Scheduler {
 	m.pendingNodeEvents = make(chan interface{}, 100*1000)
 	m.activityPending = make(chan bool, 1)
 	m.stop = make(chan struct{})
+	m.partitionLoops = make(map[string]*partitionScheduler)
+	m.delayedPending = make(chan struct{}, 1)
 	return m
 }
 
@@ -69,6 +99,7 @@ func (s *Scheduler) StartService(handlers handler.EventHandlers, manualSchedule
 	go s.handleAllocEvent()
 	go s.handleInfraEvent()
 	go s.handleNodeEvent()
+	go s.handleDelayedAsks()
 
 	// Start resource monitor if necessary (majorly for testing)
 	s.nodesMonitor = newNodesResourceUsageMonitor(s.clusterContext)
@@ -76,17 +107,24 @@ func (s *Scheduler) StartService(handlers handler.EventHandlers, manualSchedule
 
 	// Start health check periodically
 	s.healthChecker = NewHealthChecker(s.clusterContext)
+	s.healthChecker.AddHealthCheck(s.newEventQueueCheck())
+	if !manualSchedule {
+		s.healthChecker.AddHealthCheck(newSchedulingLoopCheck(s.clusterContext))
+	}
 	s.healthChecker.Start()
 
 	if !manualSchedule {
 		go s.internalSchedule()
 		go s.internalInspectOutstandingRequests()
 		go s.internalQuotaPreemption()
+		go s.internalDeadlineCheck()
 	}
 }
 
 // Internal start scheduling service
+// In the parallel partition mode this loop only manages the partition loops, the mode can change at runtime.
 func (s *Scheduler) internalSchedule() {
+	defer s.stopPartitionSchedulers()
 	for {
 		select {
 		case <-s.stop:
@@ -97,6 +135,11 @@ func (s *Scheduler) internalSchedule() {
 			// timeout, run scheduler anyway
 		}
 
+		if isParallelPartitions() {
+			s.syncPartitionSchedulers()
+			continue
+		}
+		s.stopPartitionSchedulers()
 		if s.clusterContext.schedule() {
 			s.registerActivity()
 		}
@@ -129,26 +172,319 @@ func (s *Scheduler) internalQuotaPreemption() {
 	}
 }
 
+// Internal periodic check of the application deadlines
+func (s *Scheduler) internalDeadlineCheck() {
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(time.Second):
+			s.checkApplicationDeadlines()
+		}
+	}
+}
+
 // HandleEvent is the main entry for handling events from RM proxy, it will dispatch events to different queues based on event type.
 func (s *Scheduler) HandleEvent(ev interface{}) {
-	switch ev.(type) {
-	case *rmevent.RMUpdateAllocationEvent, *rmevent.RMUpdateApplicationEvent:
-		enqueueAndCheckFull(s.pendingAllocEvents, ev)
+	start := time.Now()
+	switch v := ev.(type) {
+	case *rmevent.RMUpdateAllocationEvent:
+		if v = s.handleOverload(v, start); v != nil {
+			enqueueAndCheckFull(s.pendingAllocEvents, metrics.EventQueueAllocation, v, start)
+		}
+	case *rmevent.RMUpdateApplicationEvent:
+		s.removeDelayedApplications(v.Request.GetRemove())
+		enqueueAndCheckFull(s.pendingAllocEvents, metrics.EventQueueAllocation, ev, start)
 	case *rmevent.RMUpdateNodeEvent:
-		enqueueAndCheckFull(s.pendingNodeEvents, ev)
+		enqueueAndCheckFull(s.pendingNodeEvents, metrics.EventQueueNode, ev, start)
 	default:
-		enqueueAndCheckFull(s.pendingInfraEvents, ev)
+		enqueueAndCheckFull(s.pendingInfraEvents, metrics.EventQueueInfra, ev, start)
+	}
+}
+
+// handleOverload applies the overload mode to the new asks in the event if the allocation queue use is above the
+// threshold. Releases and allocations that are already placed on a node are never rejected or delayed.
+// In the delay mode the asks are enqueued later by handleDelayedAsks, new asks also wait while earlier asks are
+// delayed to keep the order. The caller is never blocked.
+// Returns the event to enqueue, nil if nothing is left after removing the asks.
+func (s *Scheduler) handleOverload(event *rmevent.RMUpdateAllocationEvent, start time.Time) *rmevent.RMUpdateAllocationEvent {
+	s.removeDelayedAsks(event.Request.GetReleases().GetAllocationsToRelease())
+	configMap := configs.GetConfigMap()
+	mode := configMap[configs.CMOverloadMode]
+	if mode != OverloadModeReject && mode != OverloadModeDelay {
+		return event
+	}
+	threshold := common.GetConfigurationUint(configMap, configs.CMOverloadThreshold, configs.DefaultOverloadThreshold)
+	if !isQueueOverloaded(s.pendingAllocEvents, threshold) && (mode != OverloadModeDelay || !s.hasDelayedAsks()) {
+		return event
+	}
+	var asks, others []*si.Allocation
+	for _, alloc := range event.Request.Allocations {
+		if alloc.NodeID == "" {
+			asks = append(asks, alloc)
+		} else {
+			others = append(others, alloc)
+		}
+	}
+	if len(asks) == 0 {
+		return event
+	}
+	if mode == OverloadModeDelay {
+		s.delayAsks(&delayedAsks{
+			event: &rmevent.RMUpdateAllocationEvent{
+				Request: &si.AllocationRequest{
+					Allocations: asks,
+					RmID:        event.Request.RmID,
+				},
+				SpanContext: event.SpanContext,
+			},
+			start:     start,
+			deadline:  start.Add(readOverloadMaxDelay(configMap)),
+			threshold: threshold,
+		})
+		metrics.GetEventQueueMetrics().AddOverload(metrics.EventQueueAllocation, metrics.EventQueueDelayed, len(asks))
+	} else {
+		s.rejectAsks(event.Request.RmID, asks)
+		metrics.GetEventQueueMetrics().AddOverload(metrics.EventQueueAllocation, metrics.EventQueueRejected, len(asks))
+	}
+	if len(others) == 0 && len(event.Request.GetReleases().GetAllocationsToRelease()) == 0 {
+		return nil
+	}
+	return &rmevent.RMUpdateAllocationEvent{
+		Request: &si.AllocationRequest{
+			Allocations: others,
+			Releases:    event.Request.Releases,
+			RmID:        event.Request.RmID,
+		},
+		SpanContext: event.SpanContext,
+	}
+}
+
+func (s *Scheduler) delayAsks(delayed *delayedAsks) {
+	s.delayedAsksLock.Lock()
+	s.delayedAsks = append(s.delayedAsks, delayed)
+	s.delayedAsksLock.Unlock()
+	select {
+	case s.delayedPending <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) hasDelayedAsks() bool {
+	s.delayedAsksLock.Lock()
+	defer s.delayedAsksLock.Unlock()
+	return len(s.delayedAsks) > 0
+}
+
+// removeDelayedAsks drops the delayed asks that are released before they were enqueued.
+// A release without an allocation key releases all asks of the application.
+func (s *Scheduler) removeDelayedAsks(releases []*si.AllocationRelease) {
+	if len(releases) == 0 {
+		return
+	}
+	s.delayedAsksLock.Lock()
+	defer s.delayedAsksLock.Unlock()
+	if len(s.delayedAsks) == 0 {
+		return
+	}
+	remaining := s.delayedAsks[:0]
+	for _, delayed := range s.delayedAsks {
+		asks := make([]*si.Allocation, 0, len(delayed.event.Request.Allocations))
+		for _, ask := range delayed.event.Request.Allocations {
+			if !isReleased(ask, releases) {
+				asks = append(asks, ask)
+			}
+		}
+		if len(asks) == 0 {
+			continue
+		}
+		delayed.event.Request.Allocations = asks
+		remaining = append(remaining, delayed)
+	}
+	clear(s.delayedAsks[len(remaining):])
+	s.delayedAsks = remaining
+}
+
+// removeDelayedApplications removes the delayed asks of the removed applications: the asks would be rejected
+// when enqueued after the application is removed.
+func (s *Scheduler) removeDelayedApplications(removes []*si.RemoveApplicationRequest) {
+	if len(removes) == 0 {
+		return
+	}
+	releases := make([]*si.AllocationRelease, 0, len(removes))
+	for _, remove := range removes {
+		// a release without allocation key releases all asks of the application
+		releases = append(releases, &si.AllocationRelease{
+			ApplicationID: remove.ApplicationID,
+			PartitionName: remove.PartitionName,
+		})
+	}
+	s.removeDelayedAsks(releases)
+}
+
+func isReleased(ask *si.Allocation, releases []*si.AllocationRelease) bool {
+	for _, release := range releases {
+		if release.ApplicationID == ask.ApplicationID && (release.AllocationKey == "" || release.AllocationKey == ask.AllocationKey) {
+			return true
+		}
 	}
+	return false
 }
 
-func enqueueAndCheckFull(queue chan interface{}, ev interface{}) {
+// handleDelayedAsks enqueues the delayed asks in arrival order when the queue use drops below the threshold or the
+// maximum delay passed.
+func (s *Scheduler) handleDelayedAsks() {
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.delayedPending:
+		}
+		for {
+			next := s.peekDelayedAsks()
+			if next == nil {
+				break
+			}
+			if !s.waitForQueue(s.pendingAllocEvents, next.threshold, next.deadline) {
+				return
+			}
+			// the asks could have been released while waiting
+			if s.popDelayedAsks(next) {
+				enqueueAndCheckFull(s.pendingAllocEvents, metrics.EventQueueAllocation, next.event, next.start)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) peekDelayedAsks() *delayedAsks {
+	s.delayedAsksLock.Lock()
+	defer s.delayedAsksLock.Unlock()
+	if len(s.delayedAsks) == 0 {
+		return nil
+	}
+	return s.delayedAsks[0]
+}
+
+// popDelayedAsks removes the delayed asks if they are still the first, returns true if removed
+func (s *Scheduler) popDelayedAsks(delayed *delayedAsks) bool {
+	s.delayedAsksLock.Lock()
+	defer s.delayedAsksLock.Unlock()
+	if len(s.delayedAsks) == 0 || s.delayedAsks[0] != delayed {
+		return false
+	}
+	s.delayedAsks[0] = nil
+	s.delayedAsks = s.delayedAsks[1:]
+	return true
+}
+
+// waitForQueue waits until the queue use drops below the threshold or the deadline passed.
+// Returns false if the scheduler is stopped while waiting.
+func (s *Scheduler) waitForQueue(queue chan interface{}, threshold uint64, deadline time.Time) bool {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for isQueueOverloaded(queue, threshold) {
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return true
+		case <-s.stop:
+			return false
+		}
+	}
+	return true
+}
+
+// rejectAsks sends the rejection of the asks back to the RM
+func (s *Scheduler) rejectAsks(rmID string, asks []*si.Allocation) {
+	rejected := make([]*si.RejectedAllocation, 0, len(asks))
+	for _, ask := range asks {
+		rejected = append(rejected, &si.RejectedAllocation{
+			AllocationKey: ask.AllocationKey,
+			ApplicationID: ask.ApplicationID,
+			Reason:        "scheduler overloaded: allocation event queue is above the overload threshold",
+		})
+	}
+	log.Log(log.Scheduler).Warn("scheduler overloaded, rejecting new asks",
+		zap.String("rmID", rmID),
+		zap.Int("asks", len(asks)))
+	if s.clusterContext.rmEventHandler != nil {
+		s.clusterContext.rmEventHandler.HandleEvent(&rmevent.RMRejectedAllocationEvent{
+			RmID:                rmID,
+			RejectedAllocations: rejected,
+		})
+	}
+}
+
+func isQueueOverloaded(queue chan interface{}, threshold uint64) bool {
+	return uint64(len(queue))*100 >= threshold*uint64(cap(queue))
+}
+
+func readOverloadMaxDelay(configMap map[string]string) time.Duration {
+	value, ok := configMap[configs.CMOverloadMaxDelay]
+	if !ok {
+		return configs.DefaultOverloadMaxDelay
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil || result < 0 {
+		log.Log(log.Scheduler).Warn("Failed to parse configuration value",
+			zap.String("key", configs.CMOverloadMaxDelay),
+			zap.String("value", value),
+			zap.Error(err))
+		return configs.DefaultOverloadMaxDelay
+	}
+	return result
+}
+
+// newEventQueueCheck returns the check for RM event queues close to their capacity: events are dropped from a full queue
+func (s *Scheduler) newEventQueueCheck() *HealthCheck {
+	return &HealthCheck{
+		Name:     "RM event queues",
+		Severity: SeverityCritical,
+		Probe:    ProbeReadiness,
+		Run: func() []dao.HealthCheckInfo {
+			return []dao.HealthCheckInfo{s.checkEventQueues()}
+		},
+	}
+}
+
+func (s *Scheduler) checkEventQueues() dao.HealthCheckInfo {
+	threshold := common.GetConfigurationUint(configs.GetConfigMap(), configs.HealthEventQueueThreshold, configs.DefaultEventQueueThreshold)
+	queues := []struct {
+		name  string
+		queue chan interface{}
+	}{
+		{"allocation", s.pendingAllocEvents},
+		{"node", s.pendingNodeEvents},
+		{"infra", s.pendingInfraEvents},
+	}
+	var saturated []string
+	usage := make([]string, 0, len(queues))
+	for _, q := range queues {
+		used, size := len(q.queue), cap(q.queue)
+		usage = append(usage, fmt.Sprintf("%s %d/%d", q.name, used, size))
+		if uint64(used)*100 >= threshold*uint64(size) {
+			saturated = append(saturated, q.name)
+		}
+	}
+	return CreateCheckInfo(len(saturated) == 0, "RM event queues",
+		fmt.Sprintf("Check if the RM event queues are below %d%% of their capacity", threshold),
+		fmt.Sprintf("Saturated queues: %q, usage: %s", saturated, strings.Join(usage, ", ")))
+}
+
+func enqueueAndCheckFull(queue chan interface{}, name string, ev interface{}, start time.Time) {
 	select {
 	case queue <- ev:
+		queueMetrics := metrics.GetEventQueueMetrics()
+		queueMetrics.ObserveEnqueueWait(name, start)
+		queueMetrics.SetDepth(name, len(queue))
 		log.Log(log.Scheduler).Debug("enqueued event",
 			zap.Stringer("eventType", reflect.TypeOf(ev)),
 			zap.Any("event", ev),
 			zap.Int("currentQueueSize", len(queue)))
 	default:
+		metrics.GetEventQueueMetrics().IncOverload(name, metrics.EventQueueDropped)
 		log.Log(log.Scheduler).DPanic("failed to enqueue event",
 			zap.Stringer("event", reflect.TypeOf(ev)))
 	}
@@ -158,6 +494,7 @@ func (s *Scheduler) handleAllocEvent() {
 	for {
 		select {
 		case ev := <-s.pendingAllocEvents:
+			metrics.GetEventQueueMetrics().SetDepth(metrics.EventQueueAllocation, len(s.pendingAllocEvents))
 			switch v := ev.(type) {
 			case *rmevent.RMUpdateAllocationEvent:
 				s.clusterContext.handleRMUpdateAllocationEvent(v)
@@ -178,6 +515,7 @@ func (s *Scheduler) handleInfraEvent() {
 	for {
 		select {
 		case ev := <-s.pendingInfraEvents:
+			metrics.GetEventQueueMetrics().SetDepth(metrics.EventQueueInfra, len(s.pendingInfraEvents))
 			switch v := ev.(type) {
 			case *rmevent.RMPartitionsRemoveEvent:
 				s.clusterContext.removePartitionsByRMID(v)
@@ -200,6 +538,7 @@ func (s *Scheduler) handleNodeEvent() {
 	for {
 		select {
 		case ev := <-s.pendingNodeEvents:
+			metrics.GetEventQueueMetrics().SetDepth(metrics.EventQueueNode, len(s.pendingNodeEvents))
 			switch v := ev.(type) {
 			case *rmevent.RMUpdateNodeEvent:
 				s.clusterContext.handleRMUpdateNodeEvent(v)
@@ -222,6 +561,7 @@ func (s *Scheduler) registerActivity() {
 	default:
 		// buffer is full, activity will be processed at the next available opportunity
 	}
+	s.registerPartitionActivity()
 }
 
 func (s *Scheduler) triggerQuotaPreemption() {
@@ -234,6 +574,14 @@ func (s *Scheduler) triggerQuotaPreemption() {
 	}
 }
 
+// checkApplicationDeadlines updates the deadline risk of the applications in all partitions.
+func (s *Scheduler) checkApplicationDeadlines() {
+	now := time.Now()
+	for _, psc := range s.clusterContext.GetPartitionMapClone() {
+		psc.root.CheckApplicationDeadlines(now)
+	}
+}
+
 // inspect on the outstanding requests for each of the queues,
 // update request state accordingly to shim if needed.
 // this function filters out all outstanding requests that being
@@ -24,8 +24,11 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+	"github.com/apache/yunikorn-core/pkg/rmproxy"
 	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
 	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
@@ -319,3 +322,223 @@ func TestNodeEventsNotBlockedByAllocEvents(t *testing.T) {
 	scheduler.HandleEvent(nodeEv)
 	assert.Equal(t, len(scheduler.pendingNodeEvents), 1, "node event should be queued even when alloc channel is full")
 }
+
+func TestEventQueueMetrics(t *testing.T) {
+	metrics.Reset()
+	defer metrics.Reset()
+	scheduler := NewScheduler()
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{}})
+	scheduler.HandleEvent(&rmevent.RMUpdateNodeEvent{Request: &si.NodeRequest{}})
+	queueMetrics := metrics.GetEventQueueMetrics()
+	depth, err := queueMetrics.GetDepth(metrics.EventQueueAllocation)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, depth)
+	count, err := queueMetrics.GetEnqueueWaitCount(metrics.EventQueueNode)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestOverloadReject(t *testing.T) {
+	metrics.Reset()
+	defer metrics.Reset()
+	configs.SetConfigMap(map[string]string{configs.CMOverloadMode: OverloadModeReject, configs.CMOverloadThreshold: "50"})
+	defer configs.SetConfigMap(map[string]string{})
+	scheduler := NewScheduler()
+	scheduler.pendingAllocEvents = make(chan interface{}, 4)
+	mockRM := rmproxy.NewMockedRMProxy()
+	scheduler.clusterContext.setEventHandler(mockRM)
+	newRequest := func() *si.AllocationRequest {
+		return &si.AllocationRequest{
+			RmID: rmID,
+			Allocations: []*si.Allocation{
+				{AllocationKey: "ask-1", ApplicationID: appID1},
+				{AllocationKey: "alloc-1", ApplicationID: appID1, NodeID: nodeID1},
+			},
+			Releases: &si.AllocationReleasesRequest{
+				AllocationsToRelease: []*si.AllocationRelease{{AllocationKey: "alloc-2", ApplicationID: appID1}},
+			},
+		}
+	}
+
+	// below the threshold nothing is rejected
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: newRequest()})
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: newRequest()})
+	assert.Equal(t, 0, len(mockRM.GetEvents()), "no asks should be rejected")
+	assert.Equal(t, 2, len(scheduler.pendingAllocEvents))
+
+	// at the threshold the ask is rejected, the placed allocation and release are enqueued
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: newRequest()})
+	assert.Equal(t, 1, len(mockRM.GetEvents()), "ask should be rejected")
+	rejected, ok := mockRM.GetEvents()[0].(*rmevent.RMRejectedAllocationEvent)
+	assert.Assert(t, ok, "expected rejected allocation event")
+	assert.Equal(t, 1, len(rejected.RejectedAllocations))
+	assert.Equal(t, "ask-1", rejected.RejectedAllocations[0].AllocationKey)
+	assert.Equal(t, 3, len(scheduler.pendingAllocEvents))
+	var last interface{}
+	for len(scheduler.pendingAllocEvents) > 0 {
+		last = <-scheduler.pendingAllocEvents
+	}
+	request := last.(*rmevent.RMUpdateAllocationEvent).Request //nolint:errcheck
+	assert.Equal(t, 1, len(request.Allocations))
+	assert.Equal(t, "alloc-1", request.Allocations[0].AllocationKey)
+	assert.Equal(t, 1, len(request.Releases.AllocationsToRelease))
+
+	// only asks: nothing is enqueued
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{
+		RmID:        rmID,
+		Allocations: []*si.Allocation{{AllocationKey: "ask-2", ApplicationID: appID1}},
+	}})
+	assert.Equal(t, 2, len(scheduler.pendingAllocEvents))
+	assert.Equal(t, 2, len(mockRM.GetEvents()), "ask should be rejected")
+	count, err := metrics.GetEventQueueMetrics().GetOverload(metrics.EventQueueAllocation, metrics.EventQueueRejected)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestOverloadDelay(t *testing.T) {
+	metrics.Reset()
+	defer metrics.Reset()
+	configs.SetConfigMap(map[string]string{configs.CMOverloadMode: OverloadModeDelay, configs.CMOverloadThreshold: "50", configs.CMOverloadMaxDelay: "50ms"})
+	defer configs.SetConfigMap(map[string]string{})
+	scheduler := NewScheduler()
+	defer close(scheduler.stop)
+	scheduler.pendingAllocEvents = make(chan interface{}, 4)
+	go scheduler.handleDelayedAsks()
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.pendingAllocEvents <- struct{}{}
+	ask := func(key string) *si.Allocation {
+		return &si.Allocation{AllocationKey: key, ApplicationID: appID1}
+	}
+	drain := func() {
+		for len(scheduler.pendingAllocEvents) > 0 {
+			<-scheduler.pendingAllocEvents
+		}
+	}
+
+	// the queue does not drain: asks are enqueued after the maximum delay, the rest of the event right away
+	start := time.Now()
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{
+		RmID: rmID,
+		Allocations: []*si.Allocation{
+			ask("ask-1"),
+			ask("ask-2"),
+			{AllocationKey: "alloc-1", ApplicationID: appID1, NodeID: nodeID1},
+		},
+		Releases: &si.AllocationReleasesRequest{
+			AllocationsToRelease: []*si.AllocationRelease{{AllocationKey: "alloc-2", ApplicationID: appID1}},
+		},
+	}})
+	assert.Assert(t, time.Since(start) < 50*time.Millisecond, "caller should not be blocked")
+	assert.Equal(t, 3, len(scheduler.pendingAllocEvents), "placed allocation and release should be enqueued")
+	assert.Assert(t, scheduler.hasDelayedAsks(), "asks should be delayed")
+	err := common.WaitForCondition(5*time.Millisecond, time.Second, func() bool {
+		return len(scheduler.pendingAllocEvents) == 4
+	})
+	assert.NilError(t, err, "delayed asks not enqueued")
+	assert.Assert(t, time.Since(start) >= 50*time.Millisecond, "asks should have been delayed")
+	var last interface{}
+	for len(scheduler.pendingAllocEvents) > 0 {
+		last = <-scheduler.pendingAllocEvents
+	}
+	request := last.(*rmevent.RMUpdateAllocationEvent).Request //nolint:errcheck
+	assert.Equal(t, 2, len(request.Allocations))
+	assert.Equal(t, "ask-1", request.Allocations[0].AllocationKey)
+	assert.Assert(t, request.Releases == nil, "release should not be delayed")
+
+	// the queue drains while waiting
+	configs.SetConfigMap(map[string]string{configs.CMOverloadMode: OverloadModeDelay, configs.CMOverloadThreshold: "50", configs.CMOverloadMaxDelay: "10s"})
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{RmID: rmID, Allocations: []*si.Allocation{ask("ask-3")}}})
+	assert.Equal(t, 2, len(scheduler.pendingAllocEvents))
+	<-scheduler.pendingAllocEvents
+	err = common.WaitForCondition(5*time.Millisecond, 5*time.Second, func() bool {
+		return len(scheduler.pendingAllocEvents) == 2
+	})
+	assert.NilError(t, err, "ask should be enqueued when the queue drains")
+	assert.Assert(t, !scheduler.hasDelayedAsks(), "no asks should be delayed")
+	drain()
+
+	// released asks are removed from the delayed asks
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{RmID: rmID, Allocations: []*si.Allocation{ask("ask-4"), ask("ask-5")}}})
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{
+		RmID: rmID,
+		Releases: &si.AllocationReleasesRequest{
+			AllocationsToRelease: []*si.AllocationRelease{{AllocationKey: "ask-4", ApplicationID: appID1}},
+		},
+	}})
+	assert.Equal(t, 3, len(scheduler.pendingAllocEvents), "release should be enqueued")
+	delayed := scheduler.peekDelayedAsks()
+	assert.Assert(t, delayed != nil, "ask should still be delayed")
+	assert.Equal(t, 1, len(delayed.event.Request.Allocations))
+	assert.Equal(t, "ask-5", delayed.event.Request.Allocations[0].AllocationKey)
+	scheduler.removeDelayedAsks([]*si.AllocationRelease{{ApplicationID: appID1}})
+	assert.Assert(t, !scheduler.hasDelayedAsks(), "application release should remove all asks")
+	drain()
+
+	// asks of removed applications are removed from the delayed asks
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.pendingAllocEvents <- struct{}{}
+	scheduler.HandleEvent(&rmevent.RMUpdateAllocationEvent{Request: &si.AllocationRequest{RmID: rmID, Allocations: []*si.Allocation{
+		ask("ask-6"),
+		{AllocationKey: "ask-7", ApplicationID: appID2},
+	}}})
+	scheduler.HandleEvent(&rmevent.RMUpdateApplicationEvent{Request: &si.ApplicationRequest{
+		RmID:   rmID,
+		Remove: []*si.RemoveApplicationRequest{{ApplicationID: appID1, PartitionName: "default"}},
+	}})
+	assert.Equal(t, 3, len(scheduler.pendingAllocEvents), "application removal should be enqueued")
+	delayed = scheduler.peekDelayedAsks()
+	assert.Assert(t, delayed != nil, "ask of the other application should still be delayed")
+	assert.Equal(t, 1, len(delayed.event.Request.Allocations))
+	assert.Equal(t, "ask-7", delayed.event.Request.Allocations[0].AllocationKey)
+
+	count, err := metrics.GetEventQueueMetrics().GetOverload(metrics.EventQueueAllocation, metrics.EventQueueDelayed)
+	assert.NilError(t, err)
+	assert.Equal(t, 7, count, "each delayed ask should be counted")
+}
+
+func TestPartitionSchedulers(t *testing.T) {
+	scheduler := NewScheduler()
+	defer close(scheduler.stop)
+	defer metrics.GetSchedulerMetrics().Reset()
+	partition1, err := newBasePartition()
+	assert.NilError(t, err, "unable to create partition: %v", err)
+	defer partition1.userGroupCache.Stop()
+	partition2, err := newBasePartitionNoRootDefault()
+	assert.NilError(t, err, "unable to create partition: %v", err)
+	defer partition2.userGroupCache.Stop()
+	partition2.Name = "test2"
+	scheduler.clusterContext.partitions["test1"] = partition1
+	scheduler.clusterContext.partitions["test2"] = partition2
+
+	configs.SetConfigMap(map[string]string{configs.CMSchedulingParallelPartitions: "true"})
+	defer configs.SetConfigMap(map[string]string{})
+	assert.Assert(t, isParallelPartitions(), "parallel partition mode should be on")
+
+	scheduler.syncPartitionSchedulers()
+	assert.Equal(t, 2, len(scheduler.partitionLoops), "expected a loop per partition")
+	scheduler.registerActivity()
+	err = common.WaitForCondition(10*time.Millisecond, time.Second, func() bool {
+		count1, err1 := metrics.GetSchedulerMetrics().GetPartitionSchedulingCycleCount(partition1.Name)
+		count2, err2 := metrics.GetSchedulerMetrics().GetPartitionSchedulingCycleCount(partition2.Name)
+		return err1 == nil && err2 == nil && count1 > 0 && count2 > 0
+	})
+	assert.NilError(t, err, "partition loops did not run a scheduling cycle")
+
+	// replaced partitions get a new loop, removed partitions are stopped
+	loop1 := scheduler.partitionLoops["test1"]
+	scheduler.clusterContext.partitions["test1"] = partition2
+	delete(scheduler.clusterContext.partitions, "test2")
+	scheduler.syncPartitionSchedulers()
+	assert.Equal(t, 1, len(scheduler.partitionLoops), "removed partition loop not stopped")
+	assert.Assert(t, scheduler.partitionLoops["test1"] != loop1, "replaced partition loop not restarted")
+	assert.Equal(t, scheduler.partitionLoops["test1"].partition, partition2, "loop for wrong partition")
+
+	scheduler.stopPartitionSchedulers()
+	assert.Equal(t, 0, len(scheduler.partitionLoops), "partition loops not stopped")
+}
@@ -0,0 +1,127 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/locking"
+	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+const configDataTwoPartitions = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: a
+  - name: gpu
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: b
+`
+
+// TestParallelPartitionScheduling allocates in two partitions that are each scheduled by their own go routine.
+// With deadlock detection enabled, as in the make test target, lock ordering problems between the loops fail the test.
+func TestParallelPartitionScheduling(t *testing.T) {
+	ms := &mockScheduler{}
+	defer ms.Stop()
+
+	err := ms.Init(configDataTwoPartitions, true, false)
+	assert.NilError(t, err, "RegisterResourceManager failed")
+	// registration replaces the config map: switch on the parallel mode after registration
+	configs.SetConfigMap(map[string]string{configs.CMSchedulingParallelPartitions: "true"})
+	defer configs.SetConfigMap(map[string]string{})
+
+	partitions := map[string]string{"default": "root.a", "gpu": "root.b"}
+	for partitionName := range partitions {
+		nodeID := "node-" + partitionName
+		err = ms.proxy.UpdateNode(&si.NodeRequest{
+			Nodes: []*si.NodeInfo{
+				{
+					NodeID:     nodeID,
+					Attributes: map[string]string{siCommon.NodePartition: partitionName},
+					SchedulableResource: &si.Resource{
+						Resources: map[string]*si.Quantity{
+							"memory": {Value: 100000000},
+							"vcore":  {Value: 20000},
+						},
+					},
+					Action: si.NodeInfo_CREATE,
+				},
+			},
+			RmID: "rm:123",
+		})
+		assert.NilError(t, err, "NodeRequest failed")
+		ms.mockRM.waitForAcceptedNode(t, nodeID, 1000)
+	}
+	for partitionName, queueName := range partitions {
+		appID := "app-" + partitionName
+		err = ms.proxy.UpdateApplication(&si.ApplicationRequest{
+			New: []*si.AddApplicationRequest{{
+				ApplicationID: appID,
+				QueueName:     queueName,
+				PartitionName: partitionName,
+				Ugi:           &si.UserGroupInformation{User: "testuser", Groups: []string{"testgroup"}},
+			}},
+			RmID: "rm:123",
+		})
+		assert.NilError(t, err, "ApplicationRequest failed")
+		ms.mockRM.waitForAcceptedApplication(t, appID, 1000)
+	}
+
+	for partitionName := range partitions {
+		asks := make([]*si.Allocation, 10)
+		for i := range asks {
+			asks[i] = &si.Allocation{
+				AllocationKey: fmt.Sprintf("alloc-%s-%d", partitionName, i),
+				ResourcePerAlloc: &si.Resource{
+					Resources: map[string]*si.Quantity{
+						"memory": {Value: 1000000},
+						"vcore":  {Value: 1000},
+					},
+				},
+				ApplicationID: "app-" + partitionName,
+				PartitionName: partitionName,
+			}
+		}
+		err = ms.proxy.UpdateAllocation(&si.AllocationRequest{
+			Allocations: asks,
+			RmID:        "rm:123",
+		})
+		assert.NilError(t, err, "AllocationRequest failed")
+	}
+
+	ms.mockRM.waitForAllocations(t, 20, 5000)
+	for partitionName := range partitions {
+		app, err := getApplication(ms.scheduler.GetClusterContext().GetPartition("[rm:123]"+partitionName), "app-"+partitionName)
+		assert.NilError(t, err, "application not found")
+		assert.Equal(t, 10, len(app.GetAllAllocations()), "unexpected allocations in partition %s", partitionName)
+	}
+	assert.Assert(t, !locking.IsDeadlockDetected(), "potential deadlock detected")
+}
@@ -200,3 +200,128 @@ func BenchmarkScheduling(b *testing.B) {
 		})
 	}
 }
+
+// benchmarkSchedulingApps schedules one pod for each application, all applications are in the same queue.
+// The application sort order of the queue is maintained incrementally, the cost of sorting the applications
+// must not grow with the number of allocations.
+//
+//nolint:funlen
+func benchmarkSchedulingApps(b *testing.B, numNodes, numApps int, sortPolicy string) {
+	log.UpdateLoggingConfig(map[string]string{"log.level": "WARN"})
+	defer log.UpdateLoggingConfig(nil)
+
+	serviceContext := entrypoint.StartAllServices()
+	defer serviceContext.StopAll()
+	proxy := serviceContext.RMProxy
+
+	configData := fmt.Sprintf(`
+partitions:
+  -
+    name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: a
+            properties:
+              application.sort.policy: %s
+`, sortPolicy)
+	mockRM := newMockRMCallbackHandler()
+	_, err := proxy.RegisterResourceManager(
+		&si.RegisterResourceManagerRequest{
+			RmID:        "rm:123",
+			PolicyGroup: "policygroup",
+			Version:     "0.0.2",
+			Config:      configData,
+			ExtraConfig: map[string]string{
+				"log.level": "WARN",
+			},
+		}, mockRM)
+	assert.NilError(b, err, "RegisterResourceManager failed")
+
+	apps := make(map[string]string, numApps)
+	for i := 0; i < numApps; i++ {
+		apps["app-"+strconv.Itoa(i)] = "root.a"
+	}
+	err = proxy.UpdateApplication(&si.ApplicationRequest{
+		New:  newAddAppRequest(apps),
+		RmID: "rm:123",
+	})
+	assert.NilError(b, err, "UpdateRequest application failed")
+	for appID := range apps {
+		mockRM.waitForAcceptedApplication(b, appID, 5000)
+	}
+
+	requestMem := 10
+	requestVcore := 1
+	numPodsPerNode := numApps/numNodes + 1
+	var newNodes []*si.NodeInfo
+	for i := 0; i < numNodes; i++ {
+		newNodes = append(newNodes, &si.NodeInfo{
+			NodeID:     "node-" + strconv.Itoa(i) + ":1234",
+			Attributes: map[string]string{},
+			SchedulableResource: &si.Resource{
+				Resources: map[string]*si.Quantity{
+					"memory": {Value: int64(requestMem * numPodsPerNode)},
+					"vcore":  {Value: int64(requestVcore * numPodsPerNode)},
+				},
+			},
+			Action: si.NodeInfo_CREATE,
+		})
+	}
+	err = proxy.UpdateNode(&si.NodeRequest{
+		RmID:  "rm:123",
+		Nodes: newNodes,
+	})
+	assert.NilError(b, err, "NodeRequest nodes failed")
+	mockRM.waitForMinAcceptedNodes(b, numNodes, 5000)
+
+	asks := make([]*si.Allocation, 0, numApps)
+	for appID := range apps {
+		asks = append(asks, &si.Allocation{
+			AllocationKey: "alloc-" + appID,
+			ResourcePerAlloc: &si.Resource{
+				Resources: map[string]*si.Quantity{
+					"memory": {Value: int64(requestMem)},
+					"vcore":  {Value: int64(requestVcore)},
+				},
+			},
+			ApplicationID: appID,
+		})
+	}
+
+	b.ResetTimer()
+	startTime := time.Now()
+	err = proxy.UpdateAllocation(&si.AllocationRequest{
+		Allocations: asks,
+		RmID:        "rm:123",
+	})
+	if err != nil {
+		b.Error(err.Error())
+	}
+	mockRM.waitForMinAllocations(b, numApps, 300000)
+	b.StopTimer()
+	duration := time.Since(startTime)
+	b.Logf("Total time to allocate %d apps in %s, %f pods per second", numApps, duration, float64(numApps)/duration.Seconds())
+	b.ReportAllocs()
+	b.ReportMetric(float64(numApps)/duration.Seconds(), "pods/s")
+}
+
+func BenchmarkSchedulingApps(b *testing.B) {
+	tests := []struct {
+		numNodes, numApps int
+		sortPolicy        string
+	}{
+		{numNodes: 500, numApps: 10000, sortPolicy: "fifo"},
+		{numNodes: 500, numApps: 10000, sortPolicy: "fair"},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("%vNodes/%vApps/%s", test.numNodes, test.numApps, test.sortPolicy)
+		b.Run(name, func(b *testing.B) {
+			if b.N > 1 {
+				b.Skip("Single-run benchmark")
+			}
+			benchmarkSchedulingApps(b, test.numNodes, test.numApps, test.sortPolicy)
+		})
+	}
+}
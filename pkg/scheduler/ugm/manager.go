@@ -192,6 +192,12 @@ func (m *Manager) DecreaseTrackedResource(queuePath, applicationID string, usage
 	}
 }
 
+// IsApplicationTracked returns true if the application is tracked for the user, independent of the tracked usage.
+func (m *Manager) IsApplicationTracked(applicationID string, user security.UserGroup) bool {
+	userTracker := m.GetUserTracker(user.User)
+	return userTracker != nil && userTracker.hasGroupForApp(applicationID)
+}
+
 func (m *Manager) GetUserTrackers() []*UserTracker {
 	m.RLock()
 	defer m.RUnlock()
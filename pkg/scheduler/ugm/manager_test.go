@@ -248,6 +248,21 @@ func TestAddRemoveUserAndGroups(t *testing.T) {
 	assert.Assert(t, manager.GetGroupTracker(user.Groups[0]) == nil)
 }
 
+func TestIsApplicationTracked(t *testing.T) {
+	user := security.UserGroup{User: "test", Groups: []string{"test"}}
+	manager := GetUserManager()
+	manager.ClearUserTrackers()
+	manager.ClearGroupTrackers()
+
+	assert.Assert(t, !manager.IsApplicationTracked(TestApp1, user), "app should not be tracked without a user tracker")
+	// an application without usage is still tracked
+	manager.IncreaseTrackedResource(queuePath1, TestApp1, resources.NewResource(), user)
+	assert.Assert(t, manager.IsApplicationTracked(TestApp1, user), "app without usage should be tracked")
+	assert.Assert(t, !manager.IsApplicationTracked(TestApp2, user), "unknown app should not be tracked")
+	manager.DecreaseTrackedResource(queuePath1, TestApp1, resources.NewResource(), user, true)
+	assert.Assert(t, !manager.IsApplicationTracked(TestApp1, user), "removed app should not be tracked")
+}
+
 func TestUpdateConfig(t *testing.T) {
 	setupUGM()
 	// Queue setup:
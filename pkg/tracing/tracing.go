@@ -0,0 +1,260 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+// Tracing of the scheduling cycle and the RM calls with OpenTelemetry.
+// Tracing is disabled by default, all calls use a no-op tracer until an exporter is configured in the ConfigMap.
+// An allocation carries the span context of the RM call that added it. Spans for the allocation, like
+// preemption and the RM callback, use it as the parent so one allocation can be followed end to end.
+
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+
+	tracerName  = "github.com/apache/yunikorn-core"
+	serviceName = "yunikorn-core"
+
+	AttrPartition     = attribute.Key("yunikorn.partition")
+	AttrApplication   = attribute.Key("yunikorn.application")
+	AttrQueue         = attribute.Key("yunikorn.queue")
+	AttrNode          = attribute.Key("yunikorn.node")
+	AttrAllocationKey = attribute.Key("yunikorn.allocation_key")
+	AttrResultType    = attribute.Key("yunikorn.result_type")
+	AttrRM            = attribute.Key("yunikorn.rm")
+	AttrCount         = attribute.Key("yunikorn.count")
+)
+
+// time allowed to flush spans when the provider is replaced
+var shutdownTimeout = 5 * time.Second
+
+var current atomic.Pointer[provider]
+
+// provider wraps the tracer with the resources that need to be released when tracing is reconfigured
+type provider struct {
+	exporter string
+	settings string // the ConfigMap values the provider was created from
+	tracer   trace.Tracer
+	shutdown func(ctx context.Context) error
+}
+
+func init() {
+	current.Store(noopProvider())
+	configs.AddConfigMapCallback("tracing", func() {
+		Configure(configs.GetConfigMap())
+	})
+}
+
+func noopProvider() *provider {
+	return &provider{
+		exporter: ExporterNone,
+		settings: traceSettings(ExporterNone, nil),
+		tracer:   noop.NewTracerProvider().Tracer(tracerName),
+		shutdown: func(context.Context) error { return nil },
+	}
+}
+
+// Configure replaces the tracer based on the ConfigMap settings.
+// An exporter that cannot be created is logged and disables tracing.
+func Configure(configMap map[string]string) {
+	exporter := strings.ToLower(strings.TrimSpace(configMap[configs.CMTraceExporter]))
+	if exporter == "" {
+		exporter = configs.DefaultTraceExporter
+	}
+	settings := traceSettings(exporter, configMap)
+	prev := current.Load()
+	// any ConfigMap update calls this: only replace the provider if the tracing settings have changed
+	if settings == prev.settings {
+		return
+	}
+	next, err := newProvider(exporter, configMap)
+	if err == nil {
+		next.settings = settings
+	} else {
+		log.Log(log.OpenTracing).Error("Failed to configure tracing, tracing disabled",
+			zap.String("exporter", exporter),
+			zap.Error(err))
+		next = noopProvider()
+		next.settings = settings
+	}
+	setProvider(next)
+	log.Log(log.OpenTracing).Info("Tracing configured",
+		zap.String("exporter", next.exporter))
+}
+
+// traceSettings returns the settings used to create a provider, the exporter specific settings are ignored if
+// tracing is disabled
+func traceSettings(exporter string, configMap map[string]string) string {
+	if exporter == ExporterNone {
+		return ExporterNone
+	}
+	return strings.Join([]string{exporter,
+		configMap[configs.CMTraceOTLPEndpoint],
+		configMap[configs.CMTraceOTLPInsecure],
+		configMap[configs.CMTraceFile],
+		configMap[configs.CMTraceSampleRatio]}, "|")
+}
+
+// setProvider replaces the current provider and flushes the previous one
+func setProvider(next *provider) {
+	prev := current.Swap(next)
+	if prev == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := prev.shutdown(ctx); err != nil {
+		log.Log(log.OpenTracing).Warn("Failed to flush spans of previous tracing configuration",
+			zap.Error(err))
+	}
+}
+
+func newProvider(exporter string, configMap map[string]string) (*provider, error) {
+	if exporter == ExporterNone {
+		return noopProvider(), nil
+	}
+	ratio := configs.DefaultTraceSampleRatio
+	if value, ok := configMap[configs.CMTraceSampleRatio]; ok {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return nil, fmt.Errorf("invalid sample ratio %q, must be between 0 and 1", value)
+		}
+		ratio = parsed
+	}
+	var spanExporter sdktrace.SpanExporter
+	var closer func() error
+	switch exporter {
+	case ExporterOTLP:
+		endpoint := configMap[configs.CMTraceOTLPEndpoint]
+		if endpoint == "" {
+			endpoint = configs.DefaultTraceOTLPEndpoint
+		}
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+		if common.GetConfigurationBool(configMap, configs.CMTraceOTLPInsecure, false) {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		// the connection is created lazily: an unavailable collector does not fail the configuration
+		otlp, err := otlptracegrpc.New(context.Background(), opts...)
+		if err != nil {
+			return nil, err
+		}
+		spanExporter = otlp
+	case ExporterFile:
+		path := configMap[configs.CMTraceFile]
+		if path == "" {
+			return nil, fmt.Errorf("file exporter requires %s to be set", configs.CMTraceFile)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		spanExporter = stdout
+		closer = file.Close
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", exporter)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	return &provider{
+		exporter: exporter,
+		tracer:   tp.Tracer(tracerName),
+		shutdown: func(ctx context.Context) error {
+			err := tp.Shutdown(ctx)
+			if closer != nil {
+				if closeErr := closer(); err == nil {
+					err = closeErr
+				}
+			}
+			return err
+		},
+	}, nil
+}
+
+// Shutdown flushes the pending spans and disables tracing
+func Shutdown() {
+	setProvider(noopProvider())
+}
+
+// Start starts a span as a child of the span in the context
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return current.Load().tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartWithParent starts a span as a child of the span context, mostly the span context stored on an allocation.
+// An invalid parent starts a new trace.
+func StartWithParent(parent trace.SpanContext, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx := context.Background()
+	if parent.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, parent)
+	}
+	return Start(ctx, name, attrs...)
+}
+
+// LinkTo adds a link from the span to the span context if it is valid
+func LinkTo(span trace.Span, sc trace.SpanContext) {
+	if sc.IsValid() {
+		span.AddLink(trace.Link{SpanContext: sc})
+	}
+}
+
+// RecordError marks the span as failed with the error
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// End records the error, if any, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		RecordError(span, err)
+	}
+	span.End()
+}
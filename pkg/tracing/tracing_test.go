@@ -0,0 +1,146 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+)
+
+// setRecorder installs a provider that records all spans in memory
+func setRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	setProvider(&provider{
+		exporter: "recorder",
+		settings: "recorder",
+		tracer:   tp.Tracer(tracerName),
+		shutdown: tp.Shutdown,
+	})
+	t.Cleanup(Shutdown)
+	return recorder
+}
+
+func TestConfigure(t *testing.T) {
+	defer Shutdown()
+	file := filepath.Join(t.TempDir(), "spans.json")
+	tests := []struct {
+		name      string
+		configMap map[string]string
+		exporter  string
+	}{
+		{"default", map[string]string{}, ExporterNone},
+		{"none", map[string]string{configs.CMTraceExporter: "none"}, ExporterNone},
+		{"file", map[string]string{configs.CMTraceExporter: "file", configs.CMTraceFile: file}, ExporterFile},
+		{"file upper case", map[string]string{configs.CMTraceExporter: "FILE", configs.CMTraceFile: file}, ExporterFile},
+		{"file without path", map[string]string{configs.CMTraceExporter: "file"}, ExporterNone},
+		{"otlp", map[string]string{configs.CMTraceExporter: "otlp", configs.CMTraceOTLPInsecure: "true"}, ExporterOTLP},
+		{"unknown exporter", map[string]string{configs.CMTraceExporter: "jaeger"}, ExporterNone},
+		{"invalid ratio", map[string]string{configs.CMTraceExporter: "file", configs.CMTraceFile: file, configs.CMTraceSampleRatio: "2"}, ExporterNone},
+		{"ratio not a number", map[string]string{configs.CMTraceExporter: "file", configs.CMTraceFile: file, configs.CMTraceSampleRatio: "x"}, ExporterNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Configure(tt.configMap)
+			assert.Equal(t, tt.exporter, current.Load().exporter)
+		})
+	}
+}
+
+func TestConfigureUnchanged(t *testing.T) {
+	defer Shutdown()
+	configMap := map[string]string{configs.CMTraceExporter: "file", configs.CMTraceFile: filepath.Join(t.TempDir(), "spans.json")}
+	Configure(configMap)
+	first := current.Load()
+	// unrelated changes must not replace the provider
+	configMap[configs.CMEventTrackingEnabled] = "false"
+	Configure(configMap)
+	assert.Equal(t, first, current.Load(), "provider should not be replaced")
+	configMap[configs.CMTraceSampleRatio] = "0.5"
+	Configure(configMap)
+	assert.Assert(t, first != current.Load(), "provider should be replaced")
+}
+
+func TestFileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans.json")
+	Configure(map[string]string{configs.CMTraceExporter: "file", configs.CMTraceFile: file})
+	_, span := Start(context.Background(), "schedule", AttrPartition.String("default"))
+	span.End()
+	// shutdown flushes the batched spans
+	Shutdown()
+	content, err := os.ReadFile(file)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(content), `"Name":"schedule"`), "span not written: %s", content)
+	assert.Assert(t, strings.Contains(string(content), "yunikorn.partition"), "attribute not written: %s", content)
+}
+
+func TestNoopTracer(t *testing.T) {
+	Shutdown()
+	_, span := Start(context.Background(), "schedule")
+	defer span.End()
+	assert.Assert(t, !span.IsRecording(), "span should not be recorded with tracing disabled")
+	assert.Assert(t, !span.SpanContext().IsValid(), "span context should not be valid with tracing disabled")
+}
+
+func TestStartWithParent(t *testing.T) {
+	recorder := setRecorder(t)
+	_, parent := Start(context.Background(), "RMProxy.UpdateAllocation")
+	parent.End()
+
+	_, child := StartWithParent(parent.SpanContext(), "tryPreemption")
+	child.End()
+	_, root := StartWithParent(child.SpanContext().WithTraceID([16]byte{}), "new trace")
+	LinkTo(root, parent.SpanContext())
+	LinkTo(root, child.SpanContext().WithTraceID([16]byte{}))
+	root.End()
+
+	spans := recorder.Ended()
+	assert.Equal(t, 3, len(spans))
+	assert.Equal(t, parent.SpanContext().TraceID(), spans[1].SpanContext().TraceID(), "child should be part of the parent trace")
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[1].Parent().SpanID())
+	assert.Assert(t, parent.SpanContext().TraceID() != spans[2].SpanContext().TraceID(), "invalid parent should start a new trace")
+	assert.Equal(t, 1, len(spans[2].Links()), "only valid span contexts should be linked")
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[2].Links()[0].SpanContext.SpanID())
+}
+
+func TestEnd(t *testing.T) {
+	recorder := setRecorder(t)
+	_, span := Start(context.Background(), "ok")
+	End(span, nil)
+	_, span = Start(context.Background(), "failed")
+	End(span, errors.New("RM not registered"))
+
+	spans := recorder.Ended()
+	assert.Equal(t, 2, len(spans))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "RM not registered", spans[1].Status().Description)
+	assert.Equal(t, 1, len(spans[1].Events()), "error should be recorded as an event")
+}
@@ -0,0 +1,361 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/security"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics/history"
+	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
+)
+
+// This file contains the optional authentication and authorization of the REST calls.
+// Authentication is configured via the config map:
+// * none: all calls are allowed, the default
+// * token: a bearer token in the Authorization header is checked against the token file
+// * mtls: the verified client certificate is used, the common name is the user and the organizational units are the groups
+//
+// The token file contains one token per line: token:user:group1,group2
+// Lines starting with # are ignored.
+//
+// If authentication is enabled, queue and application data is only returned for queues the caller
+// administers, based on the queue AdminACL. This includes the allocations on the nodes and the queue
+// resource history. The debug, event and user and group usage endpoints cover all queues and are only
+// available to members of the configured admin group. Members of the admin group have access to all data.
+
+const (
+	AuthModeNone  = "none"
+	AuthModeToken = "token"
+	AuthModeMTLS  = "mtls"
+
+	Unauthorized = "Authentication required"
+	Forbidden    = "Access denied"
+
+	bearerPrefix = "Bearer "
+)
+
+// routes that are always accessible, used by probes that cannot authenticate
+var publicRoutes = map[string]bool{
+	"/ws/v1/scheduler/healthcheck": true,
+	"/healthz":                     true,
+	"/readyz":                      true,
+}
+
+var restAuth atomic.Pointer[authenticator]
+
+type callerKey struct{}
+
+// caller is the authenticated user of a REST call
+type caller struct {
+	ugi   security.UserGroup
+	admin bool
+}
+
+type authenticator struct {
+	mode       string
+	tokens     map[string]security.UserGroup
+	adminGroup string
+}
+
+func init() {
+	restAuth.Store(&authenticator{mode: AuthModeNone})
+	configs.AddConfigMapCallback("rest-auth", func() {
+		restAuth.Store(newAuthenticator(configs.GetConfigMap()))
+	})
+}
+
+// newAuthenticator creates the authenticator from the config map.
+// A broken configuration denies all calls instead of silently disabling authentication.
+func newAuthenticator(configMap map[string]string) *authenticator {
+	mode := strings.ToLower(strings.TrimSpace(configMap[configs.CMRESTAuthMode]))
+	if mode == "" {
+		mode = configs.DefaultRESTAuthMode
+	}
+	auth := &authenticator{
+		mode:       mode,
+		tokens:     make(map[string]security.UserGroup),
+		adminGroup: strings.TrimSpace(configMap[configs.CMRESTAuthAdminGroup]),
+	}
+	switch mode {
+	case AuthModeNone, AuthModeMTLS:
+	case AuthModeToken:
+		tokenFile := configMap[configs.CMRESTAuthTokenFile]
+		tokens, err := loadTokenFile(tokenFile)
+		if err != nil {
+			log.Log(log.REST).Error("Failed to load REST token file, all authenticated calls will be rejected",
+				zap.String("file", tokenFile),
+				zap.Error(err))
+			break
+		}
+		auth.tokens = tokens
+	default:
+		log.Log(log.REST).Error("Unknown REST authentication mode, all authenticated calls will be rejected",
+			zap.String("key", configs.CMRESTAuthMode),
+			zap.String("mode", mode))
+		auth.mode = AuthModeToken
+	}
+	log.Log(log.REST).Info("REST authentication configured",
+		zap.String("mode", auth.mode),
+		zap.Int("tokens", len(auth.tokens)),
+		zap.String("adminGroup", auth.adminGroup))
+	return auth
+}
+
+// loadTokenFile reads the tokens and the users they belong to
+func loadTokenFile(path string) (map[string]security.UserGroup, error) {
+	if path == "" {
+		return nil, errors.New("no token file configured")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make(map[string]security.UserGroup)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 fields separated by ':', got %d", lineNo, len(fields))
+		}
+		token := strings.TrimSpace(fields[0])
+		userName := strings.TrimSpace(fields[1])
+		if token == "" {
+			return nil, fmt.Errorf("line %d: empty token", lineNo)
+		}
+		if !configs.UserRegExp.MatchString(userName) {
+			return nil, fmt.Errorf("line %d: invalid user name %q", lineNo, userName)
+		}
+		groups := make([]string, 0)
+		for _, group := range strings.Split(fields[2], common.Separator) {
+			group = strings.TrimSpace(group)
+			if group == "" {
+				continue
+			}
+			if !configs.GroupRegExp.MatchString(group) {
+				return nil, fmt.Errorf("line %d: invalid group name %q", lineNo, group)
+			}
+			groups = append(groups, group)
+		}
+		tokens[token] = security.UserGroup{User: userName, Groups: groups}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (a *authenticator) enabled() bool {
+	return a.mode != AuthModeNone
+}
+
+// authenticate returns the user of the request based on the configured mode
+func (a *authenticator) authenticate(r *http.Request) (*security.UserGroup, error) {
+	switch a.mode {
+	case AuthModeToken:
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, bearerPrefix) {
+			return nil, errors.New("missing bearer token")
+		}
+		presented := []byte(strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+		for token, ugi := range a.tokens {
+			if subtle.ConstantTimeCompare([]byte(token), presented) == 1 {
+				return &ugi, nil
+			}
+		}
+		return nil, errors.New("invalid bearer token")
+	case AuthModeMTLS:
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			return nil, errors.New("missing verified client certificate")
+		}
+		subject := r.TLS.VerifiedChains[0][0].Subject
+		if subject.CommonName == "" {
+			return nil, errors.New("client certificate without common name")
+		}
+		groups := make([]string, len(subject.OrganizationalUnit))
+		copy(groups, subject.OrganizationalUnit)
+		return &security.UserGroup{User: subject.CommonName, Groups: groups}, nil
+	default:
+		return nil, fmt.Errorf("unsupported authentication mode %s", a.mode)
+	}
+}
+
+// isAdmin returns true if the user is a member of the admin group
+func (a *authenticator) isAdmin(ugi security.UserGroup) bool {
+	if a.adminGroup == "" {
+		return false
+	}
+	for _, group := range ugi.Groups {
+		if group == a.adminGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// authHandler authenticates the call before passing it on to the handler.
+// Routes marked as admin only, like the debug, event and usage endpoints, are restricted to the admin group.
+func authHandler(inner http.Handler, webRoute route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := restAuth.Load()
+		if !auth.enabled() || publicRoutes[webRoute.Pattern] {
+			inner.ServeHTTP(w, r)
+			return
+		}
+		ugi, err := auth.authenticate(r)
+		if err != nil {
+			log.Log(log.REST).Info("REST call rejected, authentication failed",
+				zap.String("uri", r.RequestURI),
+				zap.String("remoteAddr", r.RemoteAddr),
+				zap.Error(err))
+			writeHeaders(w, r.Method)
+			if auth.mode == AuthModeToken {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			buildJSONErrorResponse(w, Unauthorized, http.StatusUnauthorized)
+			return
+		}
+		admin := auth.isAdmin(*ugi)
+		if webRoute.AdminOnly && !admin {
+			log.Log(log.REST).Info("REST call rejected, user is not an admin",
+				zap.String("uri", r.RequestURI),
+				zap.String("user", ugi.User))
+			writeHeaders(w, r.Method)
+			buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+			return
+		}
+		inner.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), callerKey{}, &caller{ugi: *ugi, admin: admin})))
+	}
+}
+
+// getCaller returns the authenticated caller, nil if authentication is disabled
+func getCaller(r *http.Request) *caller {
+	if c, ok := r.Context().Value(callerKey{}).(*caller); ok {
+		return c
+	}
+	return nil
+}
+
+// canAccessQueue returns true if the caller of the request may see the queue and its applications
+func canAccessQueue(r *http.Request, queue *objects.Queue) bool {
+	c := getCaller(r)
+	if c == nil || c.admin {
+		return true
+	}
+	return queue != nil && queue.CheckAdminAccess(c.ugi)
+}
+
+// canAccessApplication returns true if the caller of the request may see the application.
+// Applications without a queue, like rejected applications, are only visible to admins.
+func canAccessApplication(r *http.Request, app *objects.Application) bool {
+	c := getCaller(r)
+	if c == nil || c.admin {
+		return true
+	}
+	return canAccessQueue(r, app.GetQueue())
+}
+
+// filterQueueDAO removes the queues from the hierarchy the caller cannot access.
+// A queue that is not accessible, but has an accessible child, is kept without any details
+// to preserve the hierarchy.
+func filterQueueDAO(r *http.Request, getQueue func(string) *objects.Queue, info dao.PartitionQueueDAOInfo) (dao.PartitionQueueDAOInfo, bool) {
+	c := getCaller(r)
+	if c == nil || c.admin {
+		return info, true
+	}
+	children := make([]dao.PartitionQueueDAOInfo, 0, len(info.Children))
+	for _, child := range info.Children {
+		if filtered, ok := filterQueueDAO(r, getQueue, child); ok {
+			children = append(children, filtered)
+		}
+	}
+	if canAccessQueue(r, getQueue(info.QueueName)) {
+		info.Children = children
+		return info, true
+	}
+	stub := dao.PartitionQueueDAOInfo{
+		QueueName: info.QueueName,
+		Partition: info.Partition,
+		Parent:    info.Parent,
+		IsLeaf:    info.IsLeaf,
+		IsManaged: info.IsManaged,
+		Children:  children,
+	}
+	return stub, len(children) > 0
+}
+
+// filterNodeDAO removes the allocations of applications the caller cannot access from the node.
+// Foreign allocations and reservations are not linked to an accessible application and are only returned to admins.
+func filterNodeDAO(r *http.Request, getApp func(string) *objects.Application, node *dao.NodeDAOInfo) *dao.NodeDAOInfo {
+	c := getCaller(r)
+	if c == nil || c.admin {
+		return node
+	}
+	allocations := make([]*dao.AllocationDAOInfo, 0, len(node.Allocations))
+	for _, alloc := range node.Allocations {
+		if app := getApp(alloc.ApplicationID); app != nil && canAccessApplication(r, app) {
+			allocations = append(allocations, alloc)
+		}
+	}
+	node.Allocations = allocations
+	node.ForeignAllocations = nil
+	node.Reservations = nil
+	return node
+}
+
+// filterResourceHistory removes the queue series the caller cannot access.
+// The partition level series do not expose queue details and are always returned.
+func filterResourceHistory(r *http.Request, series []*history.ResourceSeries) []*history.ResourceSeries {
+	c := getCaller(r)
+	if c == nil || c.admin {
+		return series
+	}
+	filtered := make([]*history.ResourceSeries, 0, len(series))
+	for _, s := range series {
+		if s.Key.Queue != "" {
+			partition := schedulerContext.Load().GetPartitionWithoutClusterID(s.Key.Partition)
+			if partition == nil || !canAccessQueue(r, partition.GetQueue(s.Key.Queue)) {
+				continue
+			}
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
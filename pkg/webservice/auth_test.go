@@ -0,0 +1,533 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/common/security"
+	"github.com/apache/yunikorn-core/pkg/metrics/history"
+	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
+	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+const configAdminACL = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: a
+            adminacl: "alice"
+            queues:
+              - name: a1
+              - name: a2
+                adminacl: "!alice"
+          - name: b
+            adminacl: "bob"
+`
+
+const tokenFileContent = `
+# test tokens
+admin-token:admin:operators,admins
+alice-token:alice:
+bob-token:bob:dev
+`
+
+func writeTokenFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "tokens")
+	assert.NilError(t, os.WriteFile(path, []byte(tokenFileContent), 0o600))
+	return path
+}
+
+func setRESTAuth(t *testing.T, configMap map[string]string) {
+	restAuth.Store(newAuthenticator(configMap))
+	t.Cleanup(func() {
+		restAuth.Store(&authenticator{mode: AuthModeNone})
+	})
+}
+
+func assertUserGroup(t *testing.T, ugi security.UserGroup, user string, groups []string) {
+	t.Helper()
+	assert.Equal(t, user, ugi.User)
+	assert.DeepEqual(t, groups, ugi.Groups)
+}
+
+func TestLoadTokenFile(t *testing.T) {
+	tokens, err := loadTokenFile(writeTokenFile(t))
+	assert.NilError(t, err)
+	assert.Equal(t, 3, len(tokens))
+	assertUserGroup(t, tokens["admin-token"], "admin", []string{"operators", "admins"})
+	assertUserGroup(t, tokens["alice-token"], "alice", []string{})
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"wrong field count", "token:user", "line 1: expected 3 fields"},
+		{"empty token", ":user:group", "line 1: empty token"},
+		{"invalid user", "token:user!:group", "line 1: invalid user name"},
+		{"invalid group", "# comment\ntoken:user:gr oup", "line 2: invalid group name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tokens")
+			assert.NilError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			_, err = loadTokenFile(path)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+	_, err = loadTokenFile("")
+	assert.ErrorContains(t, err, "no token file configured")
+	_, err = loadTokenFile(filepath.Join(t.TempDir(), "missing"))
+	assert.Assert(t, err != nil, "missing file should fail")
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	auth := newAuthenticator(map[string]string{})
+	assert.Equal(t, AuthModeNone, auth.mode)
+	assert.Assert(t, !auth.enabled())
+
+	auth = newAuthenticator(map[string]string{
+		configs.CMRESTAuthMode:       "Token",
+		configs.CMRESTAuthTokenFile:  writeTokenFile(t),
+		configs.CMRESTAuthAdminGroup: "admins",
+	})
+	assert.Equal(t, AuthModeToken, auth.mode)
+	assert.Equal(t, 3, len(auth.tokens))
+	assert.Equal(t, "admins", auth.adminGroup)
+
+	// broken configurations fail closed
+	auth = newAuthenticator(map[string]string{configs.CMRESTAuthMode: AuthModeToken})
+	assert.Assert(t, auth.enabled())
+	assert.Equal(t, 0, len(auth.tokens))
+	auth = newAuthenticator(map[string]string{configs.CMRESTAuthMode: "unknown"})
+	assert.Assert(t, auth.enabled())
+	assert.Equal(t, 0, len(auth.tokens))
+}
+
+func TestAuthenticate(t *testing.T) {
+	auth := newAuthenticator(map[string]string{
+		configs.CMRESTAuthMode:      AuthModeToken,
+		configs.CMRESTAuthTokenFile: writeTokenFile(t),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/ws/v1/partitions", nil)
+	assert.NilError(t, err)
+	_, err = auth.authenticate(req)
+	assert.ErrorContains(t, err, "missing bearer token")
+	req.Header.Set("Authorization", "Bearer unknown")
+	_, err = auth.authenticate(req)
+	assert.ErrorContains(t, err, "invalid bearer token")
+	req.Header.Set("Authorization", "Bearer bob-token")
+	ugi, err := auth.authenticate(req)
+	assert.NilError(t, err)
+	assertUserGroup(t, *ugi, "bob", []string{"dev"})
+
+	auth = newAuthenticator(map[string]string{configs.CMRESTAuthMode: AuthModeMTLS})
+	_, err = auth.authenticate(req)
+	assert.ErrorContains(t, err, "missing verified client certificate")
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "carol", OrganizationalUnit: []string{"dev", "ops"}}}
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	ugi, err = auth.authenticate(req)
+	assert.NilError(t, err)
+	assertUserGroup(t, *ugi, "carol", []string{"dev", "ops"})
+	cert.Subject.CommonName = ""
+	_, err = auth.authenticate(req)
+	assert.ErrorContains(t, err, "client certificate without common name")
+	// unverified certificates are not accepted
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	_, err = auth.authenticate(req)
+	assert.ErrorContains(t, err, "missing verified client certificate")
+}
+
+func TestAuthHandler(t *testing.T) {
+	var seen *caller
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = getCaller(r)
+		w.WriteHeader(http.StatusOK)
+	})
+	schedulerRoute := route{Name: "Scheduler", Method: "GET", Pattern: "/ws/v1/partitions"}
+	systemRoute := route{Name: "System", Method: "GET", Pattern: "/debug/fullstatedump", AdminOnly: true}
+	healthRoute := route{Name: "Scheduler", Method: "GET", Pattern: "/ws/v1/scheduler/healthcheck"}
+
+	// authentication disabled: no caller
+	resp := &MockResponseWriter{}
+	req, err := http.NewRequest(http.MethodGet, "/debug/fullstatedump", nil)
+	assert.NilError(t, err)
+	authHandler(inner, systemRoute).ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.statusCode)
+	assert.Assert(t, seen == nil, "caller should not be set without authentication")
+
+	setRESTAuth(t, map[string]string{
+		configs.CMRESTAuthMode:       AuthModeToken,
+		configs.CMRESTAuthTokenFile:  writeTokenFile(t),
+		configs.CMRESTAuthAdminGroup: "admins",
+	})
+	tests := []struct {
+		name     string
+		route    route
+		token    string
+		expected int
+		user     string
+		admin    bool
+	}{
+		{"no token", schedulerRoute, "", http.StatusUnauthorized, "", false},
+		{"unknown token", schedulerRoute, "other", http.StatusUnauthorized, "", false},
+		{"user", schedulerRoute, "bob-token", http.StatusOK, "bob", false},
+		{"admin", schedulerRoute, "admin-token", http.StatusOK, "admin", true},
+		{"debug user", systemRoute, "bob-token", http.StatusForbidden, "", false},
+		{"debug admin", systemRoute, "admin-token", http.StatusOK, "admin", true},
+		{"public route", healthRoute, "", http.StatusOK, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			resp = &MockResponseWriter{}
+			req, err = http.NewRequest(http.MethodGet, tt.route.Pattern, nil)
+			assert.NilError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			authHandler(inner, tt.route).ServeHTTP(resp, req)
+			assert.Equal(t, tt.expected, resp.statusCode)
+			switch {
+			case tt.user != "":
+				assert.Assert(t, seen != nil, "caller should have been set")
+				assert.Equal(t, tt.user, seen.ugi.User)
+				assert.Equal(t, tt.admin, seen.admin)
+			case tt.expected == http.StatusOK:
+				assert.Assert(t, seen == nil, "public route should not set the caller")
+			default:
+				assert.Assert(t, seen == nil, "handler should not have been called")
+			}
+			if tt.expected == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", resp.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func withCaller(req *http.Request, user string, admin bool) *http.Request {
+	c := &caller{ugi: security.UserGroup{User: user, Groups: []string{}}, admin: admin}
+	return req.WithContext(context.WithValue(req.Context(), callerKey{}, c))
+}
+
+func TestQueueAuthorization(t *testing.T) {
+	part := setup(t, configAdminACL, 1)
+	defer schedulerContext.Load().Stop()
+	NewWebApp(schedulerContext.Load(), nil)
+	for _, queue := range []string{"root.a.a1", "root.a.a2", "root.b"} {
+		app := newApplication("app-"+queue, part.Name, queue, rmID, security.UserGroup{})
+		assert.NilError(t, part.AddApplication(app))
+	}
+
+	// queue hierarchy is filtered: alice administers root.a, but is denied on root.a.a2
+	req, err := createRequest(t, "/ws/v1/partition/default/queues", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err)
+	resp := &MockResponseWriter{}
+	getPartitionQueues(resp, withCaller(req, "alice", false))
+	var queuesDao dao.PartitionQueueDAOInfo
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &queuesDao), unmarshalError)
+	assert.Equal(t, "root", queuesDao.QueueName)
+	assert.Assert(t, queuesDao.MaxResource == nil && queuesDao.Properties == nil, "root details should not be returned")
+	assert.Equal(t, 1, len(queuesDao.Children))
+	assert.Equal(t, "root.a", queuesDao.Children[0].QueueName)
+	assert.Equal(t, objects.Active.String(), queuesDao.Children[0].Status)
+	assert.Equal(t, 1, len(queuesDao.Children[0].Children))
+	assert.Equal(t, "root.a.a1", queuesDao.Children[0].Children[0].QueueName)
+
+	// nothing accessible: only the root is returned
+	resp = &MockResponseWriter{}
+	getPartitionQueues(resp, withCaller(req, "carol", false))
+	queuesDao = dao.PartitionQueueDAOInfo{}
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &queuesDao), unmarshalError)
+	assert.Equal(t, "root", queuesDao.QueueName)
+	assert.Equal(t, 0, len(queuesDao.Children))
+
+	// admin sees everything
+	resp = &MockResponseWriter{}
+	getPartitionQueues(resp, withCaller(req, "admin", true))
+	queuesDao = dao.PartitionQueueDAOInfo{}
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &queuesDao), unmarshalError)
+	assert.Equal(t, 2, len(queuesDao.Children))
+
+	// single queue and applications
+	tests := []struct {
+		name     string
+		user     string
+		queue    string
+		expected int
+	}{
+		{"allowed", "alice", "root.a.a1", http.StatusOK},
+		{"denied in child", "alice", "root.a.a2", http.StatusForbidden},
+		{"other queue", "alice", "root.b", http.StatusForbidden},
+		{"owner", "bob", "root.b", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]string{"partition": partitionNameWithoutClusterID, "queue": tt.queue}
+			req, err = createRequest(t, "/ws/v1/partition/default/queue/"+tt.queue, params)
+			assert.NilError(t, err)
+			resp = &MockResponseWriter{}
+			getPartitionQueue(resp, withCaller(req, tt.user, false))
+			assertAuthStatus(t, resp, tt.expected)
+
+			resp = &MockResponseWriter{}
+			getQueueApplications(resp, withCaller(req, tt.user, false))
+			assertAuthStatus(t, resp, tt.expected)
+
+			params["application"] = "app-" + tt.queue
+			req, err = createRequest(t, "/ws/v1/partition/default/application/app-"+tt.queue, params)
+			assert.NilError(t, err)
+			resp = &MockResponseWriter{}
+			getApplication(resp, withCaller(req, tt.user, false))
+			assertAuthStatus(t, resp, tt.expected)
+		})
+	}
+
+	// partition applications are filtered
+	req, err = createRequest(t, "/ws/v1/partition/default/applications/active", map[string]string{"partition": partitionNameWithoutClusterID, "state": AppStateActive})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getPartitionApplicationsByState(resp, withCaller(req, "bob", false))
+	var appsDao []*dao.ApplicationDAOInfo
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &appsDao), unmarshalError)
+	assert.Equal(t, 1, len(appsDao))
+	assert.Equal(t, "app-root.b", appsDao[0].ApplicationID)
+}
+
+func assertAuthStatus(t *testing.T, resp *MockResponseWriter, expected int) {
+	if expected == http.StatusOK {
+		// handlers only set the status code on failure
+		assert.Equal(t, 0, resp.statusCode, "unexpected failure: %s", string(resp.outputBytes))
+		return
+	}
+	assert.Equal(t, expected, resp.statusCode, statusCodeError)
+	var errInfo dao.YAPIError
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &errInfo), unmarshalError)
+	assert.Equal(t, Forbidden, errInfo.Description, jsonMessageError)
+}
+
+func TestAdminOnlyRoutes(t *testing.T) {
+	setRESTAuth(t, map[string]string{
+		configs.CMRESTAuthMode:       AuthModeToken,
+		configs.CMRESTAuthTokenFile:  writeTokenFile(t),
+		configs.CMRESTAuthAdminGroup: "admins",
+	})
+	router := newRouter()
+	// these routes cover all queues and cannot be filtered per queue
+	for _, uri := range []string{
+		"/ws/v1/partition/default/usage/users",
+		"/ws/v1/partition/default/usage/user/alice",
+		"/ws/v1/partition/default/usage/groups",
+		"/ws/v1/partition/default/usage/group/dev",
+		"/ws/v1/events/batch",
+		"/ws/v1/events/stream",
+	} {
+		t.Run(uri, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, uri, nil)
+			assert.NilError(t, err)
+			req.Header.Set("Authorization", "Bearer bob-token")
+			resp := &MockResponseWriter{}
+			router.ServeHTTP(resp, req)
+			assertAuthStatus(t, resp, http.StatusForbidden)
+		})
+	}
+}
+
+// TestRoutesAdminOnly lists all routes: a new or changed route must be added here with its access.
+func TestRoutesAdminOnly(t *testing.T) {
+	expected := map[string]bool{
+		"GET /ws/v1/clusters":                                                   false,
+		"GET /ws/v1/metrics":                                                    false,
+		"GET /ws/v1/config":                                                     false,
+		"POST /ws/v1/validate-conf":                                             false,
+		"GET /ws/v1/history/apps":                                               false,
+		"GET /ws/v1/history/containers":                                         false,
+		"GET /ws/v1/history/resources":                                          false,
+		"GET /ws/v1/partitions":                                                 false,
+		"GET /ws/v1/partition/:partition/placementrules":                        false,
+		"GET /ws/v1/partition/:partition/queues":                                false,
+		"GET /ws/v1/partition/:partition/queue/:queue":                          false,
+		"PUT /ws/v1/partition/:partition/queue/:queue/state":                    false,
+		"GET /ws/v1/partition/:partition/nodes":                                 false,
+		"GET /ws/v1/partition/:partition/fragmentation":                         false,
+		"GET /ws/v1/partition/:partition/node/:node":                            false,
+		"GET /ws/v1/partition/:partition/queue/:queue/applications":             false,
+		"GET /ws/v1/partition/:partition/queue/:queue/application/:application": false,
+		"GET /ws/v1/partition/:partition/application/:application":              false,
+		"PUT /ws/v1/partition/:partition/application/:application/state":        false,
+		"PUT /ws/v1/partition/:partition/application/:application/queue":        false,
+		"GET /ws/v1/partition/:partition/applications/:state":                   false,
+		"GET /ws/v1/partition/:partition/queue/:queue/applications/:state":      false,
+		"GET /ws/v1/partition/:partition/usage/users":                           true,
+		"GET /ws/v1/partition/:partition/usage/user/:user":                      true,
+		"GET /ws/v1/partition/:partition/usage/groups":                          true,
+		"GET /ws/v1/partition/:partition/usage/group/:group":                    true,
+		"GET /ws/v1/events/batch":                                               true,
+		"GET /ws/v1/events/stream":                                              true,
+		"GET /ws/v1/scheduler/healthcheck":                                      false,
+		"GET /healthz":                                                          false,
+		"GET /readyz":                                                           false,
+		"GET /ws/v1/scheduler/node-utilizations":                                false,
+		"GET /ws/v1/loggers":                                                    true,
+		"PUT /ws/v1/loggers":                                                    true,
+		"GET /ws/v1/partition/:partition/scheduling":                            true,
+		"PUT /ws/v1/partition/:partition/scheduling":                            true,
+		"POST /ws/v1/partition/:partition/scheduling/step":                      true,
+		"GET /debug/stack":                                                      true,
+		"GET /debug/fullstatedump":                                              true,
+		"GET /debug/pprof/":                                                     true,
+		"GET /debug/pprof/heap":                                                 true,
+		"GET /debug/pprof/threadcreate":                                         true,
+		"GET /debug/pprof/goroutine":                                            true,
+		"GET /debug/pprof/allocs":                                               true,
+		"GET /debug/pprof/block":                                                true,
+		"GET /debug/pprof/mutex":                                                true,
+		"GET /debug/pprof/cmdline":                                              true,
+		"GET /debug/pprof/profile":                                              true,
+		"GET /debug/pprof/symbol":                                               true,
+		"GET /debug/pprof/trace":                                                true,
+		"GET /ws/v1/stack":                                                      false,
+		"GET /ws/v1/fullstatedump":                                              false,
+	}
+	assert.Equal(t, len(expected), len(webRoutes), "routes and expected access differ")
+	for _, webRoute := range webRoutes {
+		key := webRoute.Method + " " + webRoute.Pattern
+		adminOnly, ok := expected[key]
+		assert.Assert(t, ok, "route not listed: %s", key)
+		assert.Equal(t, adminOnly, webRoute.AdminOnly, "unexpected admin only access for route: %s", key)
+	}
+}
+
+func TestNodeAuthorization(t *testing.T) {
+	part := setup(t, configAdminACL, 1)
+	defer schedulerContext.Load().Stop()
+	NewWebApp(schedulerContext.Load(), nil)
+	nodeRes := resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 1000}).ToProto()
+	assert.NilError(t, part.AddNode(objects.NewNode(&si.NodeInfo{NodeID: "node-1", SchedulableResource: nodeRes})))
+	allocRes := resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 100})
+	for _, queue := range []string{"root.a.a1", "root.b"} {
+		appID := "app-" + queue
+		assert.NilError(t, part.AddApplication(newApplication(appID, part.Name, queue, rmID, security.UserGroup{})))
+		_, _, err := part.UpdateAllocation(newAlloc("alloc-"+queue, appID, "node-1", allocRes))
+		assert.NilError(t, err, "allocation should have been added")
+	}
+
+	tests := []struct {
+		name   string
+		user   string
+		admin  bool
+		allocs []string
+	}{
+		{"admin", "admin", true, []string{"alloc-root.a.a1", "alloc-root.b"}},
+		{"queue admin", "alice", false, []string{"alloc-root.a.a1"}},
+		{"no access", "carol", false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := createRequest(t, "/ws/v1/partition/default/nodes", map[string]string{"partition": partitionNameWithoutClusterID})
+			assert.NilError(t, err)
+			resp := &MockResponseWriter{}
+			getPartitionNodes(resp, withCaller(req, tt.user, tt.admin))
+			var nodesDao []*dao.NodeDAOInfo
+			assert.NilError(t, json.Unmarshal(resp.outputBytes, &nodesDao), unmarshalError)
+			assert.Equal(t, 1, len(nodesDao))
+			assertAllocationKeys(t, nodesDao[0].Allocations, tt.allocs)
+
+			req, err = createRequest(t, "/ws/v1/partition/default/node/node-1", map[string]string{"partition": partitionNameWithoutClusterID, "node": "node-1"})
+			assert.NilError(t, err)
+			resp = &MockResponseWriter{}
+			getPartitionNode(resp, withCaller(req, tt.user, tt.admin))
+			var nodeDao *dao.NodeDAOInfo
+			assert.NilError(t, json.Unmarshal(resp.outputBytes, &nodeDao), unmarshalError)
+			assertAllocationKeys(t, nodeDao.Allocations, tt.allocs)
+		})
+	}
+}
+
+func assertAllocationKeys(t *testing.T, allocs []*dao.AllocationDAOInfo, expected []string) {
+	t.Helper()
+	keys := make([]string, 0, len(allocs))
+	for _, alloc := range allocs {
+		keys = append(keys, alloc.AllocationKey)
+	}
+	sort.Strings(keys)
+	assert.DeepEqual(t, expected, keys)
+}
+
+func TestResourceHistoryAuthorization(t *testing.T) {
+	setup(t, configAdminACL, 1)
+	defer schedulerContext.Load().Stop()
+	defer ResetIMHistory()
+	imHistory = history.NewInternalMetricsHistory(5)
+	imHistory.StoreRecord(&history.MetricsRecord{
+		Timestamp: time.Now(),
+		Resources: map[history.SeriesKey]map[string]int64{
+			{Partition: partitionNameWithoutClusterID, Metric: history.MetricAllocated}:                  {"vcore": 3},
+			{Partition: partitionNameWithoutClusterID, Queue: "root.a", Metric: history.MetricAllocated}: {"vcore": 1},
+			{Partition: partitionNameWithoutClusterID, Queue: "root.b", Metric: history.MetricAllocated}: {"vcore": 2},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		user   string
+		admin  bool
+		queues []string
+	}{
+		{"admin", "admin", true, []string{"", "root.a", "root.b"}},
+		{"queue admin", "bob", false, []string{"", "root.b"}},
+		{"no access", "carol", false, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/ws/v1/history/resources", nil)
+			assert.NilError(t, err)
+			resp := &MockResponseWriter{}
+			getResourceHistory(resp, withCaller(req, tt.user, tt.admin))
+			var result []*dao.ResourceHistoryDAOInfo
+			assert.NilError(t, json.Unmarshal(resp.outputBytes, &result), unmarshalError)
+			queues := make([]string, 0, len(result))
+			for _, series := range result {
+				queues = append(queues, series.Queue)
+			}
+			sort.Strings(queues)
+			assert.DeepEqual(t, tt.queues, queues)
+		})
+	}
+}
@@ -65,3 +65,15 @@ type ResourceHistory struct {
 	PreemptedResource   map[string]map[string]int64 `json:"preemptedResource,omitempty"`
 	PlaceholderResource map[string]map[string]int64 `json:"placeholderResource,omitempty"`
 }
+
+// ApplicationStateRequest suspends or resumes an application, the action is suspend or resume.
+// Release is only used when suspending: all allocations of the application are released back to the RM.
+type ApplicationStateRequest struct {
+	Action  string `json:"action"`
+	Release bool   `json:"release,omitempty"`
+}
+
+// ApplicationMoveRequest moves an application to another leaf queue, the queue is the fully qualified queue path.
+type ApplicationMoveRequest struct {
+	Queue string `json:"queue"`
+}
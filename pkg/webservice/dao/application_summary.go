@@ -0,0 +1,36 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dao
+
+// ApplicationSummaryDAOInfo is the summary of a terminated application.
+// The timestamps of the application are in milliseconds, the state log timestamps in nanoseconds.
+type ApplicationSummaryDAOInfo struct {
+	ApplicationID       string                      `json:"applicationID"`
+	SubmissionTime      int64                       `json:"submissionTime"`
+	StartTime           int64                       `json:"startTime"`
+	FinishTime          int64                       `json:"finishTime"`
+	User                string                      `json:"user"`
+	Queue               string                      `json:"queue"`
+	State               string                      `json:"state"`
+	RmID                string                      `json:"rmID"`
+	ResourceUsage       map[string]map[string]int64 `json:"resourceUsage,omitempty"`
+	PreemptedResource   map[string]map[string]int64 `json:"preemptedResource,omitempty"`
+	PlaceholderResource map[string]map[string]int64 `json:"placeholderResource,omitempty"`
+	StateLog            []*StateDAOInfo             `json:"stateLog,omitempty"`
+}
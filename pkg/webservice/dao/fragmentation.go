@@ -0,0 +1,51 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dao
+
+// FragmentationDAOInfo describes how the available resources of a partition are spread over the nodes.
+// Only schedulable nodes are considered. Reserved nodes are included in the resources but not in the largest ask:
+// a reserved node is only available for the reservation.
+type FragmentationDAOInfo struct {
+	Partition     string                          `json:"partition"`
+	Nodes         int                             `json:"nodes"`
+	ReservedNodes int                             `json:"reservedNodes"`
+	Resources     []*ResourceFragmentationDAOInfo `json:"resources,omitempty"`
+	LargestAsk    *LargestAskDAOInfo              `json:"largestAsk,omitempty"`
+}
+
+// ResourceFragmentationDAOInfo reports the fragmentation of one resource type.
+// The fragmentation index is 1 - largest / total available: 0 if all available resources are on a single node,
+// close to 1 if the available resources are spread in small amounts over many nodes.
+type ResourceFragmentationDAOInfo struct {
+	Resource         string  `json:"resource"`
+	TotalAvailable   int64   `json:"totalAvailable"`
+	LargestAvailable int64   `json:"largestAvailable"`
+	LargestNodeID    string  `json:"largestNodeID,omitempty"`
+	Fragmentation    float64 `json:"fragmentation"`
+}
+
+// LargestAskDAOInfo is the largest ask with the requested shape that fits on a single node.
+// The multiple is the size of the largest ask relative to the shape: the shape fits if the multiple is at least 1.
+type LargestAskDAOInfo struct {
+	Shape        map[string]int64 `json:"shape"`
+	Resource     map[string]int64 `json:"resource,omitempty"`
+	Multiple     float64          `json:"multiple"`
+	NodeID       string           `json:"nodeID,omitempty"`
+	FittingNodes int              `json:"fittingNodes"`
+}
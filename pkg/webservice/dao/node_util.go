@@ -19,13 +19,15 @@
 package dao
 
 type PartitionNodesUtilDAOInfo struct {
-	ClusterID     string              `json:"clusterId"` // no omitempty, cluster id should not be empty
-	Partition     string              `json:"partition"` // no omitempty, partition should not be empty
+	ClusterID     string              `json:"clusterId"`         // no omitempty, cluster id should not be empty
+	Partition     string              `json:"partition"`         // no omitempty, partition should not be empty
+	GroupBy       string              `json:"groupBy,omitempty"` // node attribute used to group the nodes
 	NodesUtilList []*NodesUtilDAOInfo `json:"utilizations,omitempty"`
 }
 
 type NodesUtilDAOInfo struct {
 	ResourceType string             `json:"type,omitempty"`
+	Group        string             `json:"group,omitempty"` // value of the group by attribute of the nodes
 	NodesUtil    []*NodeUtilDAOInfo `json:"utilization,omitempty"`
 }
 
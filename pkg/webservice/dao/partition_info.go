@@ -31,6 +31,7 @@ type PartitionInfo struct {
 	TotalContainers         int               `json:"totalContainers,omitempty"`
 	State                   string            `json:"state,omitempty"`
 	LastStateTransitionTime int64             `json:"lastStateTransitionTime,omitempty"`
+	SchedulingPaused        bool              `json:"schedulingPaused,omitempty"`
 }
 
 type PartitionCapacity struct {
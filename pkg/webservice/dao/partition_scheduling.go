@@ -0,0 +1,55 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dao
+
+// PartitionSchedulingDAOInfo shows if the allocation of new asks is paused on the partition.
+// The steps are only set in the response of a single-step request.
+type PartitionSchedulingDAOInfo struct {
+	Partition  string                   `json:"partition"`
+	Paused     bool                     `json:"paused"` // no omitempty, false shows the scheduling status better
+	PausedTime int64                    `json:"pausedTime,omitempty"`
+	Steps      []*SchedulingStepDAOInfo `json:"steps,omitempty"`
+}
+
+// SchedulingStepDAOInfo is the outcome of one scheduling cycle run while the partition is paused.
+// A cycle without decisions did not find an ask that could be allocated. Reservations are not reported as
+// decisions, the number of reservations in the partition after the cycle shows their effect.
+type SchedulingStepDAOInfo struct {
+	Step         int                          `json:"step"`
+	Decisions    []*SchedulingDecisionDAOInfo `json:"decisions,omitempty"`
+	Reservations int                          `json:"reservations"`
+	Duration     int64                        `json:"duration"` // cycle duration in nanoseconds
+}
+
+// SchedulingDecisionDAOInfo is an allocation made in a scheduling step.
+// The released allocation key is set when a placeholder was replaced.
+type SchedulingDecisionDAOInfo struct {
+	Result                string           `json:"result"`
+	ApplicationID         string           `json:"applicationID"`
+	AllocationKey         string           `json:"allocationKey"`
+	QueueName             string           `json:"queueName,omitempty"`
+	NodeID                string           `json:"nodeID,omitempty"`
+	Resource              map[string]int64 `json:"resource,omitempty"`
+	ReleasedAllocationKey string           `json:"releasedAllocationKey,omitempty"`
+}
+
+// PartitionSchedulingRequest changes the scheduling state of a partition, the action is pause or resume.
+type PartitionSchedulingRequest struct {
+	Action string `json:"action"`
+}
@@ -28,6 +28,8 @@ type TemplateInfo struct {
 type PartitionQueueDAOInfo struct {
 	QueueName                string                  `json:"queuename"` // no omitempty, queue name should not be empty
 	Status                   string                  `json:"status,omitempty"`
+	StateChangedByAdmin      bool                    `json:"stateChangedByAdmin,omitempty"`
+	StateTime                int64                   `json:"stateTime,omitempty"`
 	Partition                string                  `json:"partition"` // no omitempty, partition name should not be empty
 	PendingResource          map[string]int64        `json:"pendingResource,omitempty"`
 	MaxResource              map[string]int64        `json:"maxResource,omitempty"`
@@ -60,3 +62,8 @@ type PartitionQueueDAOInfo struct {
 	UnschedAskBackoff        uint64                  `json:"unschedAskBackoff,omitempty"`
 	AskBackoffDelay          string                  `json:"askBackoffDelay,omitempty"`
 }
+
+// QueueStateRequest changes the state of a queue: stop, drain or resume
+type QueueStateRequest struct {
+	Action string `json:"action"`
+}
@@ -28,4 +28,6 @@ type HealthCheckInfo struct {
 	Succeeded        bool   // no omitempty, a false value gives a quick way to understand the result.
 	Description      string `json:",omitempty"`
 	DiagnosisMessage string `json:",omitempty"`
+	Severity         string `json:",omitempty"` // critical or warning, only critical failures make the scheduler unhealthy
+	Probe            string `json:",omitempty"` // liveness or readiness
 }
@@ -66,6 +66,8 @@ const (
 	AppStateActive    = "active"
 	AppStateRejected  = "rejected"
 	AppStateCompleted = "completed"
+	// summaries of the completed applications share the route with the application states
+	AppSummaries = "summaries"
 
 	WSBase    = "/ws/v1"
 	DebugBase = "/debug"
@@ -85,6 +87,7 @@ func init() {
 	allowedAppActiveStatuses[strings.ToLower(objects.Completing.String())] = true
 	allowedAppActiveStatuses[strings.ToLower(objects.Failing.String())] = true
 	allowedAppActiveStatuses[strings.ToLower(objects.Resuming.String())] = true
+	allowedAppActiveStatuses[strings.ToLower(objects.Suspended.String())] = true
 
 	var activeStatuses []string
 	for k := range allowedAppActiveStatuses {
@@ -193,12 +196,67 @@ func validateConf(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func getLoggers(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	if err := json.NewEncoder(w).Encode(getLoggersDAO()); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// setLoggerLevel sets or removes the temporary level of a logger and returns the updated list of loggers
+func setLoggerLevel(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	var request dao.LoggerLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.Level == "" {
+		if !log.ResetLoggerLevel(request.Name) {
+			buildJSONErrorResponse(w, fmt.Sprintf("logger has no temporary level: %s", request.Name), http.StatusNotFound)
+			return
+		}
+	} else {
+		ttl, err := time.ParseDuration(request.TTL)
+		if err != nil {
+			buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err = log.SetLoggerLevel(request.Name, request.Level, ttl); err != nil {
+			buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := json.NewEncoder(w).Encode(getLoggersDAO()); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func getLoggersDAO() []*dao.LoggerDAOInfo {
+	loggers := log.GetLoggers()
+	result := make([]*dao.LoggerDAOInfo, 0, len(loggers))
+	for _, logger := range loggers {
+		info := &dao.LoggerDAOInfo{
+			Name:  logger.Name,
+			Level: logger.Level,
+		}
+		if !logger.Expiry.IsZero() {
+			info.Expiry = logger.Expiry.UnixNano()
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
 func writeHeaders(w http.ResponseWriter, method string) {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	methods := "GET, OPTIONS"
-	if method == http.MethodPost {
+	switch method {
+	case http.MethodPost:
 		methods = "OPTIONS, POST"
+	case http.MethodPut:
+		methods = "OPTIONS, PUT"
 	}
 	w.Header().Set("Access-Control-Allow-Methods", methods)
 	w.Header().Set("Access-Control-Allow-Headers", "X-Requested-With,Content-Type,Accept,Origin")
@@ -458,11 +516,17 @@ func getNodesDAO(entries []*objects.Node) []*dao.NodeDAOInfo {
 	return nodesDAO
 }
 
+// getNodeUtilisations returns the node utilisation distribution of all partitions.
+// The nodes are grouped by the node attribute set in the groupBy query parameter, or in the config map if not set.
 func getNodeUtilisations(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
+	groupBy := configs.GetConfigMap()[configs.CMNodeUtilizationGroupBy]
+	if r.URL.Query().Has("groupBy") {
+		groupBy = r.URL.Query().Get("groupBy")
+	}
 	var result []*dao.PartitionNodesUtilDAOInfo
 	for _, part := range schedulerContext.Load().GetPartitionMapClone() {
-		result = append(result, getPartitionNodesUtilJSON(part))
+		result = append(result, getPartitionNodesUtilJSON(part, groupBy))
 	}
 
 	if err := json.NewEncoder(w).Encode(result); err != nil {
@@ -471,18 +535,28 @@ func getNodeUtilisations(w http.ResponseWriter, r *http.Request) {
 }
 
 // getPartitionNodesUtilJSON retrieves the utilization of all resource types for nodes within a specific partition.
-func getPartitionNodesUtilJSON(partition *scheduler.PartitionContext) *dao.PartitionNodesUtilDAOInfo {
+// The nodes are grouped by the value of the node attribute, nodes without the attribute are in the group "".
+// The list is sorted by group and resource type.
+func getPartitionNodesUtilJSON(partition *scheduler.PartitionContext, groupBy string) *dao.PartitionNodesUtilDAOInfo {
 	type UtilizationBucket struct {
 		NodeCount []int      // 10 buckets, each bucket contains number of nodes
 		NodeList  [][]string // 10 buckets, each bucket contains node name list
 	}
-	resourceBuckets := make(map[string]*UtilizationBucket) // key is resource type, value is UtilizationBucket
+	type bucketKey struct {
+		group        string
+		resourceType string
+	}
+	resourceBuckets := make(map[bucketKey]*UtilizationBucket) // key is group and resource type, value is UtilizationBucket
 
 	// put nodes to buckets
 	for _, node := range partition.GetNodes() {
 		capacity := node.GetCapacity()
 		resourceAllocated := node.GetAllocatedResource()
 		absUsedCapacity := resources.CalculateAbsUsedCapacity(capacity, resourceAllocated)
+		group := ""
+		if groupBy != "" {
+			group = node.GetAttribute(groupBy)
+		}
 
 		// append to bucket based on resource type, only count if node advertises the resource
 		for resourceType := range capacity.Resources {
@@ -493,21 +567,22 @@ func getPartitionNodesUtilJSON(partition *scheduler.PartitionContext) *dao.Parti
 			}
 
 			// create resource bucket if not exist
-			if _, ok := resourceBuckets[resourceType]; !ok {
-				resourceBuckets[resourceType] = &UtilizationBucket{
+			key := bucketKey{group: group, resourceType: resourceType}
+			if _, ok := resourceBuckets[key]; !ok {
+				resourceBuckets[key] = &UtilizationBucket{
 					NodeCount: make([]int, 10),
 					NodeList:  make([][]string, 10),
 				}
 			}
 
-			resourceBuckets[resourceType].NodeCount[idx]++
-			resourceBuckets[resourceType].NodeList[idx] = append(resourceBuckets[resourceType].NodeList[idx], node.NodeID)
+			resourceBuckets[key].NodeCount[idx]++
+			resourceBuckets[key].NodeList[idx] = append(resourceBuckets[key].NodeList[idx], node.NodeID)
 		}
 	}
 
 	// build result
 	var nodesUtilList []*dao.NodesUtilDAOInfo
-	for resourceType, bucket := range resourceBuckets {
+	for key, bucket := range resourceBuckets {
 		var nodesUtil []*dao.NodeUtilDAOInfo
 		for k := 0; k < 10; k++ {
 			util := &dao.NodeUtilDAOInfo{
@@ -518,15 +593,23 @@ func getPartitionNodesUtilJSON(partition *scheduler.PartitionContext) *dao.Parti
 			nodesUtil = append(nodesUtil, util)
 		}
 		nodeUtilization := &dao.NodesUtilDAOInfo{
-			ResourceType: resourceType,
+			ResourceType: key.resourceType,
+			Group:        key.group,
 			NodesUtil:    nodesUtil,
 		}
 		nodesUtilList = append(nodesUtilList, nodeUtilization)
 	}
+	sort.Slice(nodesUtilList, func(i, j int) bool {
+		if nodesUtilList[i].Group != nodesUtilList[j].Group {
+			return nodesUtilList[i].Group < nodesUtilList[j].Group
+		}
+		return nodesUtilList[i].ResourceType < nodesUtilList[j].ResourceType
+	})
 
 	return &dao.PartitionNodesUtilDAOInfo{
 		ClusterID:     partition.RmID,
 		Partition:     common.GetPartitionNameWithoutClusterID(partition.Name),
+		GroupBy:       groupBy,
 		NodesUtilList: nodesUtilList,
 	}
 }
@@ -565,6 +648,37 @@ func getContainerHistory(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// getResourceHistory returns the resource series stored in the internal metrics history.
+// The series can be filtered by partition, queue and metric, the since parameter limits the points
+// returned to those recorded after the timestamp in nanoseconds.
+func getResourceHistory(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+
+	if imHistory == nil {
+		buildJSONErrorResponse(w, "Internal metrics collection is not enabled.", http.StatusInternalServerError)
+		return
+	}
+	query := r.URL.Query()
+	filter := history.SeriesKey{
+		Partition: query.Get("partition"),
+		Queue:     query.Get("queue"),
+		Metric:    query.Get("metric"),
+	}
+	var since time.Time
+	if sinceStr := query.Get("since"); sinceStr != "" {
+		sinceNano, err := strconv.ParseInt(sinceStr, 10, 64)
+		if err != nil {
+			buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		since = time.Unix(0, sinceNano)
+	}
+	result := getResourceHistoryDAO(filterResourceHistory(r, imHistory.Query(filter, since)))
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func getClusterConfig(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
 
@@ -624,6 +738,49 @@ func checkHealthStatus(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func checkLiveness(w http.ResponseWriter, r *http.Request) {
+	writeProbeStatus(w, r, scheduler.ProbeLiveness)
+}
+
+func checkReadiness(w http.ResponseWriter, r *http.Request) {
+	writeProbeStatus(w, r, scheduler.ProbeReadiness)
+}
+
+// writeProbeStatus writes the last health check result for the probe, a failed probe returns 503.
+// Without a health check result, e.g. the periodic health check is disabled, the probe succeeds.
+func writeProbeStatus(w http.ResponseWriter, r *http.Request, probe scheduler.HealthProbe) {
+	writeHeaders(w, r.Method)
+	result := getProbeResult(schedulerContext.Load().GetLastHealthCheckResult(), probe)
+	if !result.Healthy {
+		log.Log(log.SchedHealth).Warn("Scheduler probe failed",
+			zap.Stringer("probe", probe),
+			zap.Any("health check info", result))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getProbeResult filters the health checks for the probe: liveness only includes liveness checks,
+// readiness includes all checks. The probe fails if a critical check failed.
+func getProbeResult(result *dao.SchedulerHealthDAOInfo, probe scheduler.HealthProbe) *dao.SchedulerHealthDAOInfo {
+	probeResult := &dao.SchedulerHealthDAOInfo{Healthy: true}
+	if result == nil {
+		return probeResult
+	}
+	for _, check := range result.HealthChecks {
+		if probe == scheduler.ProbeLiveness && check.Probe != scheduler.ProbeLiveness.String() {
+			continue
+		}
+		if !check.Succeeded && check.Severity == scheduler.SeverityCritical.String() {
+			probeResult.Healthy = false
+		}
+		probeResult.HealthChecks = append(probeResult.HealthChecks, check)
+	}
+	return probeResult
+}
+
 func getPartitions(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
 
@@ -646,6 +803,16 @@ func getPartitionQueues(w http.ResponseWriter, r *http.Request) {
 	var partition = schedulerContext.Load().GetPartitionWithoutClusterID(partitionName)
 	if partition != nil {
 		partitionQueuesDAOInfo = partition.GetPartitionQueues()
+		if filtered, ok := filterQueueDAO(r, partition.GetQueue, partitionQueuesDAOInfo); ok {
+			partitionQueuesDAOInfo = filtered
+		} else {
+			// nothing accessible: return the root without details
+			partitionQueuesDAOInfo = dao.PartitionQueueDAOInfo{
+				QueueName: partitionQueuesDAOInfo.QueueName,
+				Partition: partitionQueuesDAOInfo.Partition,
+				IsManaged: partitionQueuesDAOInfo.IsManaged,
+			}
+		}
 	} else {
 		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
 		return
@@ -684,12 +851,163 @@ func getPartitionQueue(w http.ResponseWriter, r *http.Request) {
 		buildJSONErrorResponse(w, QueueDoesNotExists, http.StatusNotFound)
 		return
 	}
-	queueDao := queue.GetPartitionQueueDAOInfo(r.URL.Query().Has("subtree"))
+	if !canAccessQueue(r, queue) {
+		buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+		return
+	}
+	queueDao, _ := filterQueueDAO(r, partitionContext.GetQueue, queue.GetPartitionQueueDAOInfo(r.URL.Query().Has("subtree")))
 	if err := json.NewEncoder(w).Encode(queueDao); err != nil {
 		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// updateQueueState stops, drains or resumes a queue. The queue is returned with its new state.
+func updateQueueState(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	queueName, err := url.QueryUnescape(vars.ByName("queue"))
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = validateQueue(queueName); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	queue := partitionContext.GetQueue(queueName)
+	if queue == nil {
+		buildJSONErrorResponse(w, QueueDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if !canAccessQueue(r, queue) {
+		buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+		return
+	}
+	var request dao.QueueStateRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch strings.ToLower(request.Action) {
+	case "stop":
+		err = queue.StopQueue()
+	case "drain":
+		err = queue.DrainQueue()
+	case "resume":
+		err = queue.ResumeQueue()
+	default:
+		buildJSONErrorResponse(w, fmt.Sprintf("unknown queue action %q, expected stop, drain or resume", request.Action), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusConflict)
+		return
+	}
+	queueDao, _ := filterQueueDAO(r, partitionContext.GetQueue, queue.GetPartitionQueueDAOInfo(false))
+	if err = json.NewEncoder(w).Encode(queueDao); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func getPartitionScheduling(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	partitionContext := getPartitionFromRequest(w, r)
+	if partitionContext == nil {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getPartitionSchedulingDAO(partitionContext)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func updatePartitionScheduling(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	partitionContext := getPartitionFromRequest(w, r)
+	if partitionContext == nil {
+		return
+	}
+	var request dao.PartitionSchedulingRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch strings.ToLower(request.Action) {
+	case "pause":
+		partitionContext.PauseScheduling()
+	case "resume":
+		partitionContext.ResumeScheduling()
+	default:
+		buildJSONErrorResponse(w, fmt.Sprintf("unknown scheduling action %q, expected pause or resume", request.Action), http.StatusBadRequest)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getPartitionSchedulingDAO(partitionContext)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// stepPartitionScheduling runs scheduling cycles on a paused partition, one cycle unless the count is set.
+func stepPartitionScheduling(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	partitionContext := getPartitionFromRequest(w, r)
+	if partitionContext == nil {
+		return
+	}
+	steps := 1
+	if countStr := r.URL.Query().Get("count"); countStr != "" {
+		var err error
+		steps, err = strconv.Atoi(countStr)
+		if err != nil || steps < 1 || steps > scheduler.MaxSchedulingSteps {
+			buildJSONErrorResponse(w, fmt.Sprintf("invalid value for \"count\": %q, expected a number between 1 and %d", countStr, scheduler.MaxSchedulingSteps), http.StatusBadRequest)
+			return
+		}
+	}
+	stepInfo, err := schedulerContext.Load().StepPartition(partitionContext, steps)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusConflict)
+		return
+	}
+	schedulingInfo := getPartitionSchedulingDAO(partitionContext)
+	schedulingInfo.Steps = stepInfo
+	if err = json.NewEncoder(w).Encode(schedulingInfo); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getPartitionFromRequest returns the partition named in the request, writes the error response if not found.
+func getPartitionFromRequest(w http.ResponseWriter, r *http.Request) *scheduler.PartitionContext {
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return nil
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return nil
+	}
+	return partitionContext
+}
+
+func getPartitionSchedulingDAO(partitionContext *scheduler.PartitionContext) *dao.PartitionSchedulingDAOInfo {
+	schedulingInfo := &dao.PartitionSchedulingDAOInfo{
+		Partition: common.GetPartitionNameWithoutClusterID(partitionContext.Name),
+		Paused:    partitionContext.IsSchedulingPaused(),
+	}
+	if schedulingInfo.Paused {
+		schedulingInfo.PausedTime = partitionContext.GetSchedulingPausedTime().UnixNano()
+	}
+	return schedulingInfo
+}
+
 func getPartitionNodes(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -701,6 +1019,9 @@ func getPartitionNodes(w http.ResponseWriter, r *http.Request) {
 	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(partition)
 	if partitionContext != nil {
 		nodesDao := getNodesDAO(partitionContext.GetNodes())
+		for i, nodeDao := range nodesDao {
+			nodesDao[i] = filterNodeDAO(r, partitionContext.GetApplication, nodeDao)
+		}
 		if err := json.NewEncoder(w).Encode(nodesDao); err != nil {
 			buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -709,6 +1030,46 @@ func getPartitionNodes(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func getPartitionFragmentation(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	shape, err := parseResourceShape(r.URL.Query().Get("shape"))
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(partitionContext.GetFragmentationReport(shape)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// parseResourceShape converts a comma separated list of name:quantity pairs into a resource.
+// Quantities use the same format as the queue configuration. An empty string returns nil.
+func parseResourceShape(shape string) (*resources.Resource, error) {
+	if shape == "" {
+		return nil, nil
+	}
+	conf := make(map[string]string)
+	for _, pair := range strings.Split(shape, ",") {
+		name, quantity, found := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid resource shape %q, expected name:quantity", pair)
+		}
+		conf[name] = strings.TrimSpace(quantity)
+	}
+	return resources.NewResourceFromConf(conf)
+}
+
 func getPartitionNode(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -725,7 +1086,7 @@ func getPartitionNode(w http.ResponseWriter, r *http.Request) {
 			buildJSONErrorResponse(w, NodeDoesNotExists, http.StatusNotFound)
 			return
 		}
-		nodeDao := getNodeDAO(node)
+		nodeDao := filterNodeDAO(r, partitionContext.GetApplication, getNodeDAO(node))
 		if err := json.NewEncoder(w).Encode(nodeDao); err != nil {
 			buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -763,6 +1124,10 @@ func getQueueApplications(w http.ResponseWriter, r *http.Request) {
 		buildJSONErrorResponse(w, QueueDoesNotExists, http.StatusNotFound)
 		return
 	}
+	if !canAccessQueue(r, queue) {
+		buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+		return
+	}
 
 	appsDao := make([]*dao.ApplicationDAOInfo, 0)
 	for _, app := range queue.GetCopyOfApps() {
@@ -809,19 +1174,42 @@ func getPartitionApplicationsByState(w http.ResponseWriter, r *http.Request) {
 		appList = partitionContext.GetRejectedApplications()
 	case AppStateCompleted:
 		appList = partitionContext.GetCompletedApplications()
+	case AppSummaries:
+		getApplicationSummaries(w, r, partitionContext)
+		return
 	default:
-		buildJSONErrorResponse(w, fmt.Sprintf("Only following application states are allowed: %s, %s, %s", AppStateActive, AppStateRejected, AppStateCompleted), http.StatusBadRequest)
+		buildJSONErrorResponse(w, fmt.Sprintf("Only following application states are allowed: %s, %s, %s, %s", AppStateActive, AppStateRejected, AppStateCompleted, AppSummaries), http.StatusBadRequest)
 		return
 	}
 	appsDao := make([]*dao.ApplicationDAOInfo, 0, len(appList))
 	for _, app := range appList {
-		appsDao = append(appsDao, getApplicationDAO(app))
+		if canAccessApplication(r, app) {
+			appsDao = append(appsDao, getApplicationDAO(app))
+		}
 	}
 	if err := json.NewEncoder(w).Encode(appsDao); err != nil {
 		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// getApplicationSummaries returns the summaries of the completed applications that ran, ordered by finish time.
+func getApplicationSummaries(w http.ResponseWriter, r *http.Request, partitionContext *scheduler.PartitionContext) {
+	summaries := make([]*dao.ApplicationSummaryDAOInfo, 0)
+	for _, app := range partitionContext.GetCompletedApplications() {
+		summary := app.GetFinalSummary()
+		if summary == nil || !canAccessApplication(r, app) {
+			continue
+		}
+		summaries = append(summaries, summary.DAO())
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].FinishTime < summaries[j].FinishTime
+	})
+	if err := json.NewEncoder(w).Encode(summaries); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func getApplication(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -862,6 +1250,10 @@ func getApplication(w http.ResponseWriter, r *http.Request) {
 		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
 		return
 	}
+	if !canAccessApplication(r, app) {
+		buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+		return
+	}
 
 	appDao := getApplicationDAO(app)
 	if err := json.NewEncoder(w).Encode(appDao); err != nil {
@@ -869,6 +1261,83 @@ func getApplication(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func updateApplicationState(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	partitionContext := getPartitionFromRequest(w, r)
+	if partitionContext == nil {
+		return
+	}
+	app := partitionContext.GetApplication(httprouter.ParamsFromContext(r.Context()).ByName("application"))
+	if app == nil {
+		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if !canAccessApplication(r, app) {
+		buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+		return
+	}
+	var request dao.ApplicationStateRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var err error
+	switch strings.ToLower(request.Action) {
+	case "suspend":
+		err = partitionContext.SuspendApplication(app.ApplicationID, request.Release)
+	case "resume":
+		err = partitionContext.ResumeApplication(app.ApplicationID)
+	default:
+		buildJSONErrorResponse(w, fmt.Sprintf("unknown application action %q, expected suspend or resume", request.Action), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(getApplicationDAO(app)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func moveApplication(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	partitionContext := getPartitionFromRequest(w, r)
+	if partitionContext == nil {
+		return
+	}
+	app := partitionContext.GetApplication(httprouter.ParamsFromContext(r.Context()).ByName("application"))
+	if app == nil {
+		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if !canAccessApplication(r, app) {
+		buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+		return
+	}
+	var request dao.ApplicationMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	queue := partitionContext.GetQueue(request.Queue)
+	if queue == nil {
+		buildJSONErrorResponse(w, QueueDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if !canAccessQueue(r, queue) {
+		buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+		return
+	}
+	if err := partitionContext.MoveApplication(app.ApplicationID, queue.QueuePath); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getApplicationDAO(app)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func getPartitionRules(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -921,6 +1390,10 @@ func getQueueApplicationsByState(w http.ResponseWriter, r *http.Request) {
 		buildJSONErrorResponse(w, QueueDoesNotExists, http.StatusNotFound)
 		return
 	}
+	if !canAccessQueue(r, queue) {
+		buildJSONErrorResponse(w, Forbidden, http.StatusForbidden)
+		return
+	}
 	if appState != AppStateActive {
 		buildJSONErrorResponse(w, fmt.Sprintf("Only following application states are allowed: %s", AppStateActive), http.StatusBadRequest)
 		return
@@ -953,6 +1426,7 @@ func getPartitionInfoDAO(lists map[string]*scheduler.PartitionContext) []*dao.Pa
 		partitionInfo.LastStateTransitionTime = partitionContext.GetStateTime().UnixNano()
 		partitionInfo.PreemptionEnabled = partitionContext.IsPreemptionEnabled()
 		partitionInfo.QuotaPreemptionEnabled = partitionContext.IsQuotaPreemptionEnabled()
+		partitionInfo.SchedulingPaused = partitionContext.IsSchedulingPaused()
 
 		capacityInfo := dao.PartitionCapacity{}
 		capacity := partitionContext.GetTotalPartitionResource()
@@ -1005,6 +1479,26 @@ func getAppHistoryDAO(records []*history.MetricsRecord) []*dao.ApplicationHistor
 	return result
 }
 
+func getResourceHistoryDAO(series []*history.ResourceSeries) []*dao.ResourceHistoryDAOInfo {
+	result := make([]*dao.ResourceHistoryDAOInfo, 0, len(series))
+	for _, s := range series {
+		points := make([]*dao.ResourceHistoryPointDAO, 0, len(s.Points))
+		for _, point := range s.Points {
+			points = append(points, &dao.ResourceHistoryPointDAO{
+				Timestamp: point.Timestamp.UnixNano(),
+				Resources: point.Resources,
+			})
+		}
+		result = append(result, &dao.ResourceHistoryDAOInfo{
+			Partition: s.Key.Partition,
+			Queue:     s.Key.Queue,
+			Metric:    s.Key.Metric,
+			Points:    points,
+		})
+	}
+	return result
+}
+
 func getPartitionNodesDAO(lists map[string]*scheduler.PartitionContext) []*dao.NodesDAOInfo {
 	result := make([]*dao.NodesDAOInfo, 0, len(lists))
 
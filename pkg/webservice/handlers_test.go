@@ -41,6 +41,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/common/security"
 	"github.com/apache/yunikorn-core/pkg/events"
+	"github.com/apache/yunikorn-core/pkg/log"
 	"github.com/apache/yunikorn-core/pkg/metrics/history"
 	"github.com/apache/yunikorn-core/pkg/scheduler"
 	"github.com/apache/yunikorn-core/pkg/scheduler/objects"
@@ -578,6 +579,139 @@ func TestContainerHistory(t *testing.T) {
 	assert.Equal(t, contHist[4].TotalContainers, "300", "metric 5 should be 300 apps and was not")
 }
 
+func TestResourceHistory(t *testing.T) {
+	// make sure the history is nil when we finish this test
+	defer ResetIMHistory()
+	req, err := http.NewRequest("GET", "/ws/v1/history/resources", strings.NewReader(""))
+	assert.NilError(t, err)
+	resp := &MockResponseWriter{}
+	getResourceHistory(resp, req)
+	assertInternalMetricsDisabled(t, resp)
+
+	imHistory = history.NewInternalMetricsHistory(5)
+	start := time.Now()
+	partitionKey := history.SeriesKey{Partition: "default", Metric: history.MetricNodeUtilization}
+	queueKey := history.SeriesKey{Partition: "default", Queue: "root.a", Metric: history.MetricAllocated}
+	for i := int64(1); i <= 3; i++ {
+		imHistory.StoreRecord(&history.MetricsRecord{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Resources: map[history.SeriesKey]map[string]int64{
+				partitionKey: {"vcore": i * 10},
+				queueKey:     {"vcore": i},
+			},
+		})
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		series int
+		points int
+	}{
+		{"all", "", 2, 3},
+		{"queue", "?queue=root.a", 1, 3},
+		{"metric", "?partition=default&metric=nodeUtilization", 1, 3},
+		{"since", fmt.Sprintf("?since=%d", start.Add(2*time.Second).UnixNano()), 2, 1},
+		{"unknown partition", "?partition=unknown", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err = http.NewRequest("GET", "/ws/v1/history/resources"+tt.query, strings.NewReader(""))
+			assert.NilError(t, err)
+			resp = &MockResponseWriter{}
+			getResourceHistory(resp, req)
+			var series []*dao.ResourceHistoryDAOInfo
+			err = json.Unmarshal(resp.outputBytes, &series)
+			assert.NilError(t, err, unmarshalError)
+			assert.Equal(t, resp.statusCode, 0, "resource history response should have no status")
+			assert.Equal(t, tt.series, len(series))
+			for _, s := range series {
+				assert.Equal(t, tt.points, len(s.Points))
+			}
+		})
+	}
+	// partition level series first, oldest point first
+	req, err = http.NewRequest("GET", "/ws/v1/history/resources", strings.NewReader(""))
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getResourceHistory(resp, req)
+	var series []*dao.ResourceHistoryDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &series)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, "", series[0].Queue)
+	assert.Equal(t, history.MetricNodeUtilization, series[0].Metric)
+	assert.Equal(t, int64(10), series[0].Points[0].Resources["vcore"])
+	assert.Equal(t, start.Add(time.Second).UnixNano(), series[0].Points[0].Timestamp)
+
+	// invalid since
+	req, err = http.NewRequest("GET", "/ws/v1/history/resources?since=yesterday", strings.NewReader(""))
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getResourceHistory(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode)
+}
+
+func assertInternalMetricsDisabled(t *testing.T, resp *MockResponseWriter) {
+	var errInfo dao.YAPIError
+	err := json.Unmarshal(resp.outputBytes, &errInfo)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, http.StatusInternalServerError, resp.statusCode, "history handler returned wrong status")
+	assert.Equal(t, errInfo.Message, "Internal metrics collection is not enabled.", jsonMessageError)
+}
+
+func TestLoggers(t *testing.T) {
+	defer log.ResetLoggerLevel(log.SchedPreemption.String())
+	req, err := http.NewRequest("GET", "/ws/v1/loggers", strings.NewReader(""))
+	assert.NilError(t, err)
+	resp := &MockResponseWriter{}
+	getLoggers(resp, req)
+	var loggers []*dao.LoggerDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &loggers)
+	assert.NilError(t, err, unmarshalError)
+	assert.Assert(t, len(loggers) > 0, "expected loggers")
+	assert.Equal(t, log.Core.String(), loggers[0].Name)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"invalid body", "{", http.StatusBadRequest},
+		{"unknown logger", `{"name":"core.unknown","level":"DEBUG","ttl":"10m"}`, http.StatusBadRequest},
+		{"invalid level", `{"name":"core.scheduler.preemption","level":"verbose","ttl":"10m"}`, http.StatusBadRequest},
+		{"missing TTL", `{"name":"core.scheduler.preemption","level":"DEBUG"}`, http.StatusBadRequest},
+		{"reset without override", `{"name":"core.scheduler.preemption"}`, http.StatusNotFound},
+		{"set level", `{"name":"core.scheduler.preemption","level":"DEBUG","ttl":"10m"}`, 0},
+		{"reset level", `{"name":"core.scheduler.preemption"}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err = http.NewRequest("PUT", "/ws/v1/loggers", strings.NewReader(tt.body))
+			assert.NilError(t, err)
+			resp = &MockResponseWriter{}
+			setLoggerLevel(resp, req)
+			assert.Equal(t, tt.status, resp.statusCode, statusCodeError)
+		})
+	}
+
+	req, err = http.NewRequest("PUT", "/ws/v1/loggers", strings.NewReader(`{"name":"core.scheduler.preemption","level":"debug","ttl":"10m"}`))
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	setLoggerLevel(resp, req)
+	err = json.Unmarshal(resp.outputBytes, &loggers)
+	assert.NilError(t, err, unmarshalError)
+	for _, logger := range loggers {
+		switch logger.Name {
+		case log.SchedPreemption.String():
+			assert.Equal(t, "DEBUG", logger.Level)
+			assert.Assert(t, logger.Expiry > time.Now().UnixNano(), "expiry should be in the future")
+		case log.SchedRequiredNodePreemption.String():
+			assert.Equal(t, "DEBUG", logger.Level, "child logger should inherit the level")
+			assert.Equal(t, int64(0), logger.Expiry)
+		}
+	}
+}
+
 func TestGetConfigYAML(t *testing.T) {
 	ctx, err := scheduler.NewClusterContext(rmID, policyGroup, []byte(startConf))
 	assert.NilError(t, err, "Error when load clusterInfo from config")
@@ -787,7 +921,7 @@ func TestGetPartitionNodesUtilJSON(t *testing.T) {
 	addAllocatedResource(t, node2, "alloc-2", appID, map[string]resources.Quantity{siCommon.Memory: 300, siCommon.CPU: 500, "GPU": 5})
 
 	// assert partition nodes utilization
-	result := getPartitionNodesUtilJSON(partition)
+	result := getPartitionNodesUtilJSON(partition, "")
 	assert.Equal(t, result.ClusterID, rmID)
 	assert.Equal(t, result.Partition, "default")
 	assert.Equal(t, len(result.NodesUtilList), 3, "Should have 3 resource types(CPU/Memory/GPU) in the list.")
@@ -814,6 +948,52 @@ func TestGetPartitionNodesUtilJSON(t *testing.T) {
 	assert.Equal(t, gpuNodesUtil.NodesUtil[4].NodeNames[0], node2.NodeID)
 }
 
+func TestGetPartitionNodesUtilJSONGrouped(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	defer schedulerContext.Load().Stop()
+	for nodeID, instanceType := range map[string]string{"node-1": "large", "node-2": "large", "node-3": "small", "node-4": ""} {
+		attributes := map[string]string{}
+		if instanceType != "" {
+			attributes["instanceType"] = instanceType
+		}
+		node := objects.NewNode(&si.NodeInfo{
+			NodeID:              nodeID,
+			Attributes:          attributes,
+			SchedulableResource: resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.CPU: 1000}).ToProto(),
+		})
+		assert.NilError(t, partition.AddNode(node), "adding node to partition should not fail")
+		if nodeID == "node-1" {
+			addAllocatedResource(t, node, "alloc-1", "app1", map[string]resources.Quantity{siCommon.CPU: 500})
+		}
+	}
+
+	result := getPartitionNodesUtilJSON(partition, "instanceType")
+	assert.Equal(t, "instanceType", result.GroupBy)
+	assert.Equal(t, 3, len(result.NodesUtilList), "expected one entry per group")
+	// sorted by group: nodes without the attribute first
+	groups := []string{"", "large", "small"}
+	for i, nodesUtil := range result.NodesUtilList {
+		assert.Equal(t, groups[i], nodesUtil.Group)
+		assert.Equal(t, siCommon.CPU, nodesUtil.ResourceType)
+	}
+	large := result.NodesUtilList[1]
+	assert.Equal(t, int64(1), large.NodesUtil[0].NumOfNodes)
+	assert.Equal(t, "node-2", large.NodesUtil[0].NodeNames[0])
+	assert.Equal(t, int64(1), large.NodesUtil[4].NumOfNodes)
+	assert.Equal(t, "node-1", large.NodesUtil[4].NodeNames[0])
+
+	// group by from the query parameter
+	req, err := http.NewRequest("GET", "/ws/v1/scheduler/node-utilizations?groupBy=instanceType", strings.NewReader(""))
+	assert.NilError(t, err)
+	resp := &MockResponseWriter{}
+	getNodeUtilisations(resp, req)
+	var partitionNodesUtil []*dao.PartitionNodesUtilDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &partitionNodesUtil)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, 1, len(partitionNodesUtil))
+	assert.Equal(t, 3, len(partitionNodesUtil[0].NodesUtilList))
+}
+
 func TestGetNodeUtilisations(t *testing.T) {
 	// setup
 	NewWebApp(&scheduler.ClusterContext{}, nil)
@@ -1335,6 +1515,255 @@ func TestGetPartitionNodes(t *testing.T) {
 	assertParamsMissing(t, resp)
 }
 
+func TestGetPartitionFragmentation(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	defer schedulerContext.Load().Stop()
+	nodeRes := resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 1000, siCommon.CPU: 64000}).ToProto()
+	for _, nodeID := range []string{"node-1", "node-2"} {
+		node := objects.NewNode(&si.NodeInfo{NodeID: nodeID, Attributes: map[string]string{}, SchedulableResource: nodeRes})
+		assert.NilError(t, partition.AddNode(node), "adding node to partition should not fail")
+		if nodeID == "node-1" {
+			addAllocatedResource(t, node, "alloc-1", "app1", map[string]resources.Quantity{siCommon.Memory: 500, siCommon.CPU: 16000})
+		}
+	}
+
+	req, err := createRequest(t, "/ws/v1/partition/default/fragmentation?shape=vcore:64,memory:1000", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err, "fragmentation request failed")
+	resp := &MockResponseWriter{}
+	getPartitionFragmentation(resp, req)
+	var report dao.FragmentationDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &report)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, 2, report.Nodes)
+	assert.Equal(t, 2, len(report.Resources))
+	assert.Equal(t, siCommon.Memory, report.Resources[0].Resource)
+	assert.Equal(t, int64(1500), report.Resources[0].TotalAvailable)
+	assert.Equal(t, int64(1000), report.Resources[0].LargestAvailable)
+	assert.Equal(t, "node-2", report.Resources[0].LargestNodeID)
+	assert.Equal(t, 1, report.LargestAsk.FittingNodes)
+	assert.Equal(t, "node-2", report.LargestAsk.NodeID)
+	assert.DeepEqual(t, map[string]int64{siCommon.Memory: 1000, siCommon.CPU: 64000}, report.LargestAsk.Resource)
+
+	// invalid shape
+	req, err = createRequest(t, "/ws/v1/partition/default/fragmentation?shape=vcore", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err, "fragmentation request failed")
+	resp = &MockResponseWriter{}
+	getPartitionFragmentation(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	req, err = createRequest(t, "/ws/v1/partition/default/fragmentation?shape=vcore:abc", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err, "fragmentation request failed")
+	resp = &MockResponseWriter{}
+	getPartitionFragmentation(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+
+	req, err = createRequest(t, "/ws/v1/partition/default/fragmentation", map[string]string{"partition": "notexists"})
+	assert.NilError(t, err, "fragmentation request failed")
+	resp = &MockResponseWriter{}
+	getPartitionFragmentation(resp, req)
+	assertPartitionNotExists(t, resp)
+
+	req, err = http.NewRequest("GET", "/ws/v1/partition/default/fragmentation", strings.NewReader(""))
+	assert.NilError(t, err, "fragmentation request failed")
+	resp = &MockResponseWriter{}
+	getPartitionFragmentation(resp, req)
+	assertParamsMissing(t, resp)
+}
+
+func TestUpdateApplicationState(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	defer schedulerContext.Load().Stop()
+	app := addApp(t, "app-1", partition, queueName, false)
+	ask := objects.NewAllocationFromSI(&si.Allocation{
+		AllocationKey:    "alloc-1",
+		ApplicationID:    "app-1",
+		PartitionName:    partition.Name,
+		ResourcePerAlloc: &si.Resource{Resources: map[string]*si.Quantity{"vcore": {Value: 1}}},
+	})
+	err := app.AddAllocationAsk(ask)
+	assert.NilError(t, err, "ask should have been added to app")
+	app.SetState(objects.Running.String())
+
+	stateRequest := func(appID, body string) *MockResponseWriter {
+		req, err := http.NewRequest("PUT", "/ws/v1/partition/default/application/"+appID+"/state", strings.NewReader(body))
+		assert.NilError(t, err, "HTTP request create failed")
+		params := httprouter.Params{
+			httprouter.Param{Key: "partition", Value: partitionNameWithoutClusterID},
+			httprouter.Param{Key: "application", Value: appID},
+		}
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, params))
+		resp := &MockResponseWriter{}
+		updateApplicationState(resp, req)
+		return resp
+	}
+
+	resp := stateRequest("app-1", `{"action":"suspend"}`)
+	var appDao dao.ApplicationDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &appDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, "app-1", appDao.ApplicationID)
+	assert.Equal(t, objects.Suspended.String(), appDao.State)
+	// suspending twice is a conflict
+	resp = stateRequest("app-1", `{"action":"suspend","release":true}`)
+	assert.Equal(t, http.StatusConflict, resp.statusCode, statusCodeError)
+
+	resp = stateRequest("app-1", `{"action":"Resume"}`)
+	err = json.Unmarshal(resp.outputBytes, &appDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, objects.Running.String(), appDao.State)
+	resp = stateRequest("app-1", `{"action":"resume"}`)
+	assert.Equal(t, http.StatusConflict, resp.statusCode, statusCodeError)
+
+	resp = stateRequest("app-1", `{"action":"kill"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	resp = stateRequest("app-1", `not json`)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	resp = stateRequest("app-unknown", `{"action":"suspend"}`)
+	assert.Equal(t, http.StatusNotFound, resp.statusCode, statusCodeError)
+}
+
+func TestMoveApplication(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	defer schedulerContext.Load().Stop()
+	app := addApp(t, "app-1", partition, queueName, false)
+
+	moveRequest := func(appID, body string) *MockResponseWriter {
+		req, err := http.NewRequest("PUT", "/ws/v1/partition/default/application/"+appID+"/queue", strings.NewReader(body))
+		assert.NilError(t, err, "HTTP request create failed")
+		params := httprouter.Params{
+			httprouter.Param{Key: "partition", Value: partitionNameWithoutClusterID},
+			httprouter.Param{Key: "application", Value: appID},
+		}
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, params))
+		resp := &MockResponseWriter{}
+		moveApplication(resp, req)
+		return resp
+	}
+
+	resp := moveRequest("app-1", `{"queue":"root.noapps"}`)
+	var appDao dao.ApplicationDAOInfo
+	err := json.Unmarshal(resp.outputBytes, &appDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, "app-1", appDao.ApplicationID)
+	assert.Equal(t, "root.noapps", appDao.QueueName)
+	assert.Equal(t, "root.noapps", app.GetQueuePath())
+	assert.Assert(t, partition.GetQueue(queueName).GetApplication("app-1") == nil, "app should have been removed from the old queue")
+
+	// moving to the same queue or a parent queue is a conflict
+	resp = moveRequest("app-1", `{"queue":"root.noapps"}`)
+	assert.Equal(t, http.StatusConflict, resp.statusCode, statusCodeError)
+	resp = moveRequest("app-1", `{"queue":"root"}`)
+	assert.Equal(t, http.StatusConflict, resp.statusCode, statusCodeError)
+
+	resp = moveRequest("app-1", `{"queue":"root.unknown"}`)
+	assert.Equal(t, http.StatusNotFound, resp.statusCode, statusCodeError)
+	resp = moveRequest("app-1", `not json`)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	resp = moveRequest("app-unknown", `{"queue":"root.default"}`)
+	assert.Equal(t, http.StatusNotFound, resp.statusCode, statusCodeError)
+}
+
+func TestPartitionScheduling(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	defer schedulerContext.Load().Stop()
+
+	schedulingRequest := func(handler http.HandlerFunc, method, url, body, partitionName string) *MockResponseWriter {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		assert.NilError(t, err, "HTTP request create failed")
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{httprouter.Param{Key: "partition", Value: partitionName}}))
+		resp := &MockResponseWriter{}
+		handler(resp, req)
+		return resp
+	}
+	var schedulingInfo dao.PartitionSchedulingDAOInfo
+
+	resp := schedulingRequest(getPartitionScheduling, "GET", "/ws/v1/partition/default/scheduling", "", partitionNameWithoutClusterID)
+	err := json.Unmarshal(resp.outputBytes, &schedulingInfo)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, partitionNameWithoutClusterID, schedulingInfo.Partition)
+	assert.Assert(t, !schedulingInfo.Paused, "partition should not be paused")
+
+	// stepping requires a paused partition
+	resp = schedulingRequest(stepPartitionScheduling, "POST", "/ws/v1/partition/default/scheduling/step", "", partitionNameWithoutClusterID)
+	assert.Equal(t, http.StatusConflict, resp.statusCode, statusCodeError)
+
+	resp = schedulingRequest(updatePartitionScheduling, "PUT", "/ws/v1/partition/default/scheduling", `{"action":"pause"}`, partitionNameWithoutClusterID)
+	err = json.Unmarshal(resp.outputBytes, &schedulingInfo)
+	assert.NilError(t, err, unmarshalError)
+	assert.Assert(t, schedulingInfo.Paused, "partition should be paused")
+	assert.Assert(t, schedulingInfo.PausedTime > 0, "paused time should be set")
+	assert.Assert(t, partition.IsSchedulingPaused(), "partition should be paused")
+
+	resp = schedulingRequest(stepPartitionScheduling, "POST", "/ws/v1/partition/default/scheduling/step?count=2", "", partitionNameWithoutClusterID)
+	schedulingInfo = dao.PartitionSchedulingDAOInfo{}
+	err = json.Unmarshal(resp.outputBytes, &schedulingInfo)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, 2, len(schedulingInfo.Steps))
+	assert.Equal(t, 2, schedulingInfo.Steps[1].Step)
+	assert.Equal(t, 0, len(schedulingInfo.Steps[0].Decisions), "no decisions expected without asks")
+	for _, count := range []string{"0", "abc", "101"} {
+		resp = schedulingRequest(stepPartitionScheduling, "POST", "/ws/v1/partition/default/scheduling/step?count="+count, "", partitionNameWithoutClusterID)
+		assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	}
+
+	resp = schedulingRequest(updatePartitionScheduling, "PUT", "/ws/v1/partition/default/scheduling", `{"action":"Resume"}`, partitionNameWithoutClusterID)
+	schedulingInfo = dao.PartitionSchedulingDAOInfo{}
+	err = json.Unmarshal(resp.outputBytes, &schedulingInfo)
+	assert.NilError(t, err, unmarshalError)
+	assert.Assert(t, !schedulingInfo.Paused, "partition should be resumed")
+	assert.Equal(t, int64(0), schedulingInfo.PausedTime)
+
+	resp = schedulingRequest(updatePartitionScheduling, "PUT", "/ws/v1/partition/default/scheduling", `{"action":"stop"}`, partitionNameWithoutClusterID)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	resp = schedulingRequest(updatePartitionScheduling, "PUT", "/ws/v1/partition/default/scheduling", `{"action":"pause"}`, "notexists")
+	assertPartitionNotExists(t, resp)
+}
+
+func TestUpdateQueueState(t *testing.T) {
+	setup(t, configDefault, 1)
+	defer schedulerContext.Load().Stop()
+
+	stateRequest := func(queue, body string) *MockResponseWriter {
+		req, err := http.NewRequest("PUT", "/ws/v1/partition/default/queue/"+queue+"/state", strings.NewReader(body))
+		assert.NilError(t, err, "HTTP request create failed")
+		params := httprouter.Params{
+			httprouter.Param{Key: "partition", Value: partitionNameWithoutClusterID},
+			httprouter.Param{Key: "queue", Value: queue},
+		}
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, params))
+		resp := &MockResponseWriter{}
+		updateQueueState(resp, req)
+		return resp
+	}
+
+	resp := stateRequest(queueName, `{"action":"stop"}`)
+	var queueDao dao.PartitionQueueDAOInfo
+	err := json.Unmarshal(resp.outputBytes, &queueDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, queueName, queueDao.QueueName)
+	assert.Equal(t, objects.Stopped.String(), queueDao.Status)
+	assert.Assert(t, queueDao.StateChangedByAdmin, "stop should be flagged as an admin change")
+	assert.Assert(t, queueDao.StateTime > 0, "state time should be set")
+
+	resp = stateRequest(queueName, `{"action":"Drain"}`)
+	err = json.Unmarshal(resp.outputBytes, &queueDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, objects.Draining.String(), queueDao.Status)
+
+	resp = stateRequest(queueName, `{"action":"resume"}`)
+	queueDao = dao.PartitionQueueDAOInfo{}
+	err = json.Unmarshal(resp.outputBytes, &queueDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, objects.Active.String(), queueDao.Status)
+	assert.Assert(t, !queueDao.StateChangedByAdmin, "resume should clear the admin flag")
+
+	resp = stateRequest(queueName, `{"action":"pause"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	resp = stateRequest(queueName, `not json`)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	resp = stateRequest("root.unknown", `{"action":"stop"}`)
+	assert.Equal(t, http.StatusNotFound, resp.statusCode, statusCodeError)
+}
+
 func TestGetPartitionNode(t *testing.T) {
 	partition := setup(t, configDefault, 1)
 	defer schedulerContext.Load().Stop()
@@ -1687,6 +2116,47 @@ func TestGetPartitionApplicationsByStateHandler(t *testing.T) {
 	checkIllegalGetAppsRequest(t, "/ws/v1/partition/default/applications/Active", nil, assertParamsMissing)
 }
 
+func TestGetApplicationSummaries(t *testing.T) {
+	defaultPartition := setup(t, configDefault, 1)
+	defer schedulerContext.Load().Stop()
+	NewWebApp(schedulerContext.Load(), nil)
+
+	// completed without running: no summary
+	addApp(t, "app-1", defaultPartition, "root.default", true)
+	// completed after running
+	app2 := addApp(t, "app-2", defaultPartition, "root.default", false)
+	app2.SetState(objects.Accepted.String())
+	err := app2.HandleApplicationEvent(objects.RunApplication)
+	assert.NilError(t, err, "the app should be running")
+	app2.SetState(objects.Completing.String())
+	err = app2.HandleApplicationEvent(objects.CompleteApplication)
+	assert.NilError(t, err, "the app should have completed")
+	err = common.WaitForCondition(10*time.Millisecond, time.Second, func() bool {
+		return len(defaultPartition.GetCompletedApplications()) == 2
+	})
+	assert.NilError(t, err, "the completed application should have been processed")
+
+	req, err := createRequest(t, "/ws/v1/partition/default/applications/summaries", map[string]string{"partition": partitionNameWithoutClusterID, "state": "summaries"})
+	assert.NilError(t, err)
+	resp := &MockResponseWriter{}
+	getPartitionApplicationsByState(resp, req)
+	var summaries []*dao.ApplicationSummaryDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &summaries)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, 1, len(summaries))
+	summary := summaries[0]
+	assert.Equal(t, "app-2", summary.ApplicationID)
+	assert.Equal(t, "root.default", summary.Queue)
+	assert.Equal(t, objects.Completed.String(), summary.State)
+	assert.Equal(t, app2.StartTime().UnixMilli(), summary.StartTime)
+	assert.Assert(t, summary.FinishTime >= summary.StartTime, "finish time should not be before the start time")
+	states := make([]string, 0, len(summary.StateLog))
+	for _, entry := range summary.StateLog {
+		states = append(states, entry.ApplicationState)
+	}
+	assert.DeepEqual(t, []string{objects.Running.String(), objects.Completed.String()}, states)
+}
+
 func checkGetQueueAppByState(t *testing.T, partition, queue, state, status string, expectedApp []*objects.Application) {
 	var url string
 	if status == "" {
@@ -2035,7 +2505,7 @@ func assertAppStateNotAllow(t *testing.T, resp *MockResponseWriter) {
 	err := json.Unmarshal(resp.outputBytes, &errInfo)
 	assert.NilError(t, err, unmarshalError)
 	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
-	assert.Equal(t, errInfo.Message, "Only following application states are allowed: active, rejected, completed", jsonMessageError)
+	assert.Equal(t, errInfo.Message, "Only following application states are allowed: active, rejected, completed, summaries", jsonMessageError)
 	assert.Equal(t, errInfo.StatusCode, http.StatusBadRequest)
 }
 
@@ -2860,6 +3330,47 @@ func TestCheckHealthStatus(t *testing.T) {
 	})
 }
 
+func TestHealthProbes(t *testing.T) {
+	testSchedulerContext := &scheduler.ClusterContext{}
+	NewWebApp(testSchedulerContext, nil)
+
+	// no health check result: probes succeed
+	resp := runProbe(t, checkLiveness, "/healthz")
+	assert.Equal(t, 0, resp.statusCode, statusCodeError)
+	resp = runProbe(t, checkReadiness, "/readyz")
+	assert.Equal(t, 0, resp.statusCode, statusCodeError)
+
+	testSchedulerContext.SetLastHealthCheckResult(&dao.SchedulerHealthDAOInfo{
+		Healthy: false,
+		HealthChecks: []dao.HealthCheckInfo{
+			{Name: "Scheduling loop", Succeeded: true, Severity: "critical", Probe: "liveness"},
+			{Name: "Failed nodes", Succeeded: false, Severity: "critical", Probe: "readiness"},
+			{Name: "Plugin", Succeeded: false, Severity: "warning", Probe: "liveness"},
+		},
+	})
+	// liveness ignores the failed readiness check, a failed warning does not fail the probe
+	resp = runProbe(t, checkLiveness, "/healthz")
+	assert.Equal(t, 0, resp.statusCode, statusCodeError)
+	var result dao.SchedulerHealthDAOInfo
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &result), unmarshalError)
+	assert.Assert(t, result.Healthy, "liveness should succeed")
+	assert.Equal(t, 2, len(result.HealthChecks))
+
+	resp = runProbe(t, checkReadiness, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.statusCode, statusCodeError)
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &result), unmarshalError)
+	assert.Assert(t, !result.Healthy, "readiness should fail")
+	assert.Equal(t, 3, len(result.HealthChecks))
+}
+
+func runProbe(t *testing.T, probe http.HandlerFunc, url string) *MockResponseWriter {
+	req, err := http.NewRequest("GET", url, strings.NewReader(""))
+	assert.NilError(t, err, "Error while creating the probe request")
+	resp := &MockResponseWriter{}
+	probe(resp, req)
+	return resp
+}
+
 func runHealthCheckTest(t *testing.T, expected *dao.SchedulerHealthDAOInfo) {
 	testSchedulerContext := &scheduler.ClusterContext{}
 	testSchedulerContext.SetLastHealthCheckResult(expected)
@@ -28,6 +28,7 @@ type route struct {
 	Method      string
 	Pattern     string
 	HandlerFunc http.HandlerFunc
+	AdminOnly   bool // restricted to the admin group when authentication is enabled
 }
 
 type routes []route
@@ -35,156 +36,247 @@ type routes []route
 var webRoutes = routes{
 	// endpoints to retrieve general cluster info
 	route{
-		"Cluster",
-		"GET",
-		"/ws/v1/clusters",
-		getClusterInfo,
+		Name:        "Cluster",
+		Method:      "GET",
+		Pattern:     "/ws/v1/clusters",
+		HandlerFunc: getClusterInfo,
 	},
 	route{
-		"Cluster",
-		"GET",
-		"/ws/v1/metrics",
-		getMetrics,
+		Name:        "Cluster",
+		Method:      "GET",
+		Pattern:     "/ws/v1/metrics",
+		HandlerFunc: getMetrics,
 	},
 	route{
-		"Cluster",
-		"GET",
-		"/ws/v1/config",
-		getClusterConfig,
+		Name:        "Cluster",
+		Method:      "GET",
+		Pattern:     "/ws/v1/config",
+		HandlerFunc: getClusterConfig,
 	},
 	route{
-		"Cluster",
-		"POST",
-		"/ws/v1/validate-conf",
-		validateConf,
+		Name:        "Cluster",
+		Method:      "POST",
+		Pattern:     "/ws/v1/validate-conf",
+		HandlerFunc: validateConf,
 	},
 
 	// endpoints to retrieve general scheduler info
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/history/apps",
-		getApplicationHistory,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/history/apps",
+		HandlerFunc: getApplicationHistory,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/history/containers",
-		getContainerHistory,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/history/containers",
+		HandlerFunc: getContainerHistory,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partitions",
-		getPartitions,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/history/resources",
+		HandlerFunc: getResourceHistory,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/placementrules",
-		getPartitionRules,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partitions",
+		HandlerFunc: getPartitions,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/queues",
-		getPartitionQueues,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/placementrules",
+		HandlerFunc: getPartitionRules,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/queue/:queue",
-		getPartitionQueue,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/queues",
+		HandlerFunc: getPartitionQueues,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/nodes",
-		getPartitionNodes,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/queue/:queue",
+		HandlerFunc: getPartitionQueue,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/node/:node",
-		getPartitionNode,
+		Name:        "Scheduler",
+		Method:      "PUT",
+		Pattern:     "/ws/v1/partition/:partition/queue/:queue/state",
+		HandlerFunc: updateQueueState,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/queue/:queue/applications",
-		getQueueApplications,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/nodes",
+		HandlerFunc: getPartitionNodes,
 	},
+	// largest ask of the shape that fits on a single node and per resource fragmentation
+	// query parameter shape: comma separated list of resource name and quantity, e.g. vcore:64,memory:256G
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/queue/:queue/application/:application",
-		getApplication,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/fragmentation",
+		HandlerFunc: getPartitionFragmentation,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/application/:application",
-		getApplication,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/node/:node",
+		HandlerFunc: getPartitionNode,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/applications/:state",
-		getPartitionApplicationsByState,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/queue/:queue/applications",
+		HandlerFunc: getQueueApplications,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/queue/:queue/applications/:state",
-		getQueueApplicationsByState,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/queue/:queue/application/:application",
+		HandlerFunc: getApplication,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/usage/users",
-		getUsersResourceUsage,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/application/:application",
+		HandlerFunc: getApplication,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/usage/user/:user",
-		getUserResourceUsage,
+		Name:        "Scheduler",
+		Method:      "PUT",
+		Pattern:     "/ws/v1/partition/:partition/application/:application/state",
+		HandlerFunc: updateApplicationState,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "PUT",
+		Pattern:     "/ws/v1/partition/:partition/application/:application/queue",
+		HandlerFunc: moveApplication,
 	},
+	// the state "summaries" returns the summaries of the completed applications
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/usage/groups",
-		getGroupsResourceUsage,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/applications/:state",
+		HandlerFunc: getPartitionApplicationsByState,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/partition/:partition/usage/group/:group",
-		getGroupResourceUsage,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/queue/:queue/applications/:state",
+		HandlerFunc: getQueueApplicationsByState,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/events/batch",
-		getEvents,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/usage/users",
+		HandlerFunc: getUsersResourceUsage,
+		AdminOnly:   true,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/events/stream",
-		getStream,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/usage/user/:user",
+		HandlerFunc: getUserResourceUsage,
+		AdminOnly:   true,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/scheduler/healthcheck",
-		checkHealthStatus,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/usage/groups",
+		HandlerFunc: getGroupsResourceUsage,
+		AdminOnly:   true,
 	},
 	route{
-		"Scheduler",
-		"GET",
-		"/ws/v1/scheduler/node-utilizations",
-		getNodeUtilisations,
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/usage/group/:group",
+		HandlerFunc: getGroupResourceUsage,
+		AdminOnly:   true,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/events/batch",
+		HandlerFunc: getEvents,
+		AdminOnly:   true,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/events/stream",
+		HandlerFunc: getStream,
+		AdminOnly:   true,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/scheduler/healthcheck",
+		HandlerFunc: checkHealthStatus,
+	},
+	// probes based on the last health check: liveness checks only or all checks for readiness
+	route{
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/healthz",
+		HandlerFunc: checkLiveness,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/readyz",
+		HandlerFunc: checkReadiness,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/scheduler/node-utilizations",
+		HandlerFunc: getNodeUtilisations,
+	},
+
+	// runtime log level changes are restricted to admins like the debug endpoints
+	route{
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/loggers",
+		HandlerFunc: getLoggers,
+		AdminOnly:   true,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "PUT",
+		Pattern:     "/ws/v1/loggers",
+		HandlerFunc: setLoggerLevel,
+		AdminOnly:   true,
+	},
+
+	// pausing and single-stepping the scheduling of a partition affects all queues and is restricted to admins
+	route{
+		Name:        "Scheduler",
+		Method:      "GET",
+		Pattern:     "/ws/v1/partition/:partition/scheduling",
+		HandlerFunc: getPartitionScheduling,
+		AdminOnly:   true,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "PUT",
+		Pattern:     "/ws/v1/partition/:partition/scheduling",
+		HandlerFunc: updatePartitionScheduling,
+		AdminOnly:   true,
+	},
+	route{
+		Name:        "Scheduler",
+		Method:      "POST",
+		Pattern:     "/ws/v1/partition/:partition/scheduling/step",
+		HandlerFunc: stepPartitionScheduling,
+		AdminOnly:   true,
 	},
 
 	// endpoints to retrieve debug info
@@ -198,78 +290,91 @@ var webRoutes = routes{
 		Method:      "GET",
 		Pattern:     "/debug/stack",
 		HandlerFunc: getStackInfo,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/fullstatedump",
 		HandlerFunc: getFullStateDump,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/",
 		HandlerFunc: pprof.Index,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/heap",
 		HandlerFunc: pprof.Index,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/threadcreate",
 		HandlerFunc: pprof.Index,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/goroutine",
 		HandlerFunc: pprof.Index,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/allocs",
 		HandlerFunc: pprof.Index,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/block",
 		HandlerFunc: pprof.Index,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/mutex",
 		HandlerFunc: pprof.Index,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/cmdline",
 		HandlerFunc: pprof.Cmdline,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/profile",
 		HandlerFunc: pprof.Profile,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/symbol",
 		HandlerFunc: pprof.Symbol,
+		AdminOnly:   true,
 	},
 	route{
 		Name:        "System",
 		Method:      "GET",
 		Pattern:     "/debug/pprof/trace",
 		HandlerFunc: pprof.Trace,
+		AdminOnly:   true,
 	},
 
 	// Deprecated REST calls
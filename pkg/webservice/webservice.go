@@ -29,6 +29,9 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
 	"github.com/apache/yunikorn-core/pkg/metrics/history"
 	"github.com/apache/yunikorn-core/pkg/scheduler"
@@ -37,14 +40,21 @@ import (
 var imHistory *history.InternalMetricsHistory
 var schedulerContext atomic.Pointer[scheduler.ClusterContext]
 
+const tlsCallbackID = "rest-tls"
+
 type WebService struct {
 	httpServer *http.Server
+	reloader   *common.CertReloader
+	tlsFiles   common.TLSFiles
+	started    bool
+
+	locking.Mutex
 }
 
 func newRouter() *httprouter.Router {
 	router := httprouter.New()
 	for _, webRoute := range webRoutes {
-		handler := loggingHandler(webRoute.HandlerFunc, webRoute.Name)
+		handler := loggingHandler(authHandler(webRoute.HandlerFunc, webRoute), webRoute.Name)
 		router.Handler(webRoute.Method, webRoute.Pattern, handler)
 	}
 	return router
@@ -63,17 +73,51 @@ func loggingHandler(inner http.Handler, name string) http.HandlerFunc {
 }
 
 // StartWebApp starts the web app on the default port.
+// TLS is used if a certificate and key are configured in the ConfigMap. A change of the TLS files in the
+// ConfigMap restarts the web app, changes to the content of the files are picked up without a restart.
 func (m *WebService) StartWebApp() {
+	m.Lock()
+	defer m.Unlock()
+	m.startServer(configs.GetRESTTLSFiles())
+	m.started = true
+	configs.AddConfigMapCallback(tlsCallbackID, m.checkTLSConfig)
+}
+
+// startServer starts the HTTP server, must be called while holding the lock.
+// The server is not started if TLS is configured and the certificates cannot be loaded:
+// falling back to plain HTTP is not allowed.
+func (m *WebService) startServer(tlsFiles common.TLSFiles) {
+	m.tlsFiles = tlsFiles
 	router := newRouter()
-	m.httpServer = &http.Server{
+	server := &http.Server{
 		Addr:              ":9080",
 		Handler:           compressResponse(router),
 		ReadHeaderTimeout: 10 * time.Second,
 	}
+	if tlsFiles.Enabled() {
+		reloader, err := common.NewCertReloader(tlsFiles)
+		if err != nil {
+			log.Log(log.REST).Error("Failed to load TLS certificates, web-app not started",
+				zap.String("certFile", tlsFiles.CertFile),
+				zap.Error(err))
+			return
+		}
+		m.reloader = reloader
+		server.TLSConfig = reloader.ServerConfig(false, "h2", "http/1.1")
+	}
+	m.httpServer = server
 
-	log.Log(log.REST).Info("web-app started", zap.Int("port", 9080))
+	log.Log(log.REST).Info("web-app started",
+		zap.Int("port", 9080),
+		zap.Bool("tls", server.TLSConfig != nil))
 	go func() {
-		httpError := m.httpServer.ListenAndServe()
+		var httpError error
+		if server.TLSConfig != nil {
+			// certificates are provided by the TLS config
+			httpError = server.ListenAndServeTLS("", "")
+		} else {
+			httpError = server.ListenAndServe()
+		}
 		if httpError != nil && !errors.Is(httpError, http.ErrServerClosed) {
 			log.Log(log.REST).Error("HTTP serving error",
 				zap.Error(httpError))
@@ -81,6 +125,23 @@ func (m *WebService) StartWebApp() {
 	}()
 }
 
+// checkTLSConfig restarts the server if the TLS files in the ConfigMap have changed
+func (m *WebService) checkTLSConfig() {
+	tlsFiles := configs.GetRESTTLSFiles()
+	m.Lock()
+	defer m.Unlock()
+	if !m.started || tlsFiles == m.tlsFiles {
+		return
+	}
+	log.Log(log.REST).Info("TLS configuration changed, restarting web-app",
+		zap.Bool("tls", tlsFiles.Enabled()))
+	if err := m.stopServer(); err != nil {
+		log.Log(log.REST).Error("Failed to stop web-app for restart", zap.Error(err))
+		return
+	}
+	m.startServer(tlsFiles)
+}
+
 func NewWebApp(context *scheduler.ClusterContext, internalMetrics *history.InternalMetricsHistory) *WebService {
 	m := &WebService{}
 	schedulerContext.Store(context)
@@ -89,11 +150,26 @@ func NewWebApp(context *scheduler.ClusterContext, internalMetrics *history.Inter
 }
 
 func (m *WebService) StopWebApp() error {
+	configs.RemoveConfigMapCallback(tlsCallbackID)
+	m.Lock()
+	defer m.Unlock()
+	m.started = false
+	return m.stopServer()
+}
+
+// stopServer stops the HTTP server, must be called while holding the lock
+func (m *WebService) stopServer() error {
+	if m.reloader != nil {
+		m.reloader.Stop()
+		m.reloader = nil
+	}
 	if m.httpServer != nil {
+		server := m.httpServer
+		m.httpServer = nil
 		// graceful shutdown in 5 seconds
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		return m.httpServer.Shutdown(ctx)
+		return server.Shutdown(ctx)
 	}
 
 	return nil
@@ -21,10 +21,20 @@ package webservice
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -32,6 +42,7 @@ import (
 	"gotest.tools/v3/assert"
 
 	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/metrics/history"
 	"github.com/apache/yunikorn-core/pkg/scheduler"
 )
@@ -147,6 +158,7 @@ func Test_HeaderChecks(t *testing.T) {
 		{"get", "/ws/v1/clusters", http.MethodGet, "GET, OPTIONS"},
 		{"post options", "/ws/v1/validate-conf", http.MethodOptions, "OPTIONS, POST"},
 		{"post", "/ws/v1/validate-conf", http.MethodPost, "OPTIONS, POST"},
+		{"put options", "/ws/v1/loggers", http.MethodOptions, "GET, OPTIONS, PUT"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -389,3 +401,85 @@ func Test_GzipVaryHeaderNotDuplicated(t *testing.T) {
 	assert.Equal(t, len(vary), 1, "expected exactly one Vary header value")
 	assert.Equal(t, strings.TrimSpace(vary[0]), "Accept-Encoding", "unexpected Vary header value")
 }
+
+// writeTestCert writes a self-signed certificate and key for localhost to the directory
+func writeTestCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	assert.NilError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NilError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return cert, certFile, keyFile
+}
+
+func Test_TLS(t *testing.T) {
+	cert, certFile, keyFile := writeTestCert(t, t.TempDir())
+	configs.SetConfigMap(map[string]string{
+		configs.CMRESTTLSCertFile: certFile,
+		configs.CMRESTTLSKeyFile:  keyFile,
+	})
+	defer configs.SetConfigMap(map[string]string{})
+
+	s := NewWebApp(&scheduler.ClusterContext{}, nil)
+	s.StartWebApp()
+	waitForServerReady(t)
+	defer func() {
+		if err := s.StopWebApp(); err != nil {
+			t.Fatal("failed to stop webapp")
+		}
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	tlsClient := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}},
+	}
+	resp, err := tlsClient.Get("https://localhost:9080/ws/v1/clusters")
+	assert.NilError(t, err, "unexpected error executing TLS request")
+	_ = resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK, "expected OK")
+	// plain HTTP is rejected
+	resp, err = http.Get(base + "/ws/v1/clusters")
+	assert.NilError(t, err, "unexpected error executing request")
+	_ = resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusBadRequest, "plain HTTP should be rejected")
+
+	// removing the TLS config restarts the server without TLS
+	configs.SetConfigMap(map[string]string{})
+	err = common.WaitForCondition(10*time.Millisecond, 3*time.Second, func() bool {
+		resp, err = http.Get(base + "/ws/v1/clusters")
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	})
+	assert.NilError(t, err, "webapp should have been restarted without TLS")
+
+	// broken TLS config does not fall back to plain HTTP
+	configs.SetConfigMap(map[string]string{
+		configs.CMRESTTLSCertFile: keyFile,
+		configs.CMRESTTLSKeyFile:  keyFile,
+	})
+	s.Lock()
+	assert.Assert(t, s.httpServer == nil, "server should not be running with broken TLS config")
+	s.Unlock()
+}